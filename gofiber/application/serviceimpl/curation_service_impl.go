@@ -0,0 +1,228 @@
+package serviceimpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/logger"
+)
+
+type CurationServiceImpl struct {
+	actionRepo       repositories.CurationActionRepository
+	faceRepo         repositories.FaceRepository
+	personRepo       repositories.PersonRepository
+	sharedFolderRepo repositories.SharedFolderRepository
+	undoWindow       time.Duration
+}
+
+func NewCurationService(
+	actionRepo repositories.CurationActionRepository,
+	faceRepo repositories.FaceRepository,
+	personRepo repositories.PersonRepository,
+	sharedFolderRepo repositories.SharedFolderRepository,
+	undoWindow time.Duration,
+) services.CurationService {
+	return &CurationServiceImpl{
+		actionRepo:       actionRepo,
+		faceRepo:         faceRepo,
+		personRepo:       personRepo,
+		sharedFolderRepo: sharedFolderRepo,
+		undoWindow:       undoWindow,
+	}
+}
+
+// BulkAssignFacesToPerson assigns the accessible faces to a person and records their previous
+// assignments so the whole operation can be undone. Folder access is loaded once and checked in memory.
+func (s *CurationServiceImpl) BulkAssignFacesToPerson(ctx context.Context, userID uuid.UUID, faceIDs []uuid.UUID, personID uuid.UUID) (*services.BulkAssignResult, error) {
+	// Verify person ownership (persons are user-owned)
+	person, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil || person.UserID != userID {
+		return nil, services.ErrPersonNotFound
+	}
+
+	faces, err := s.faceRepo.GetByIDs(ctx, faceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get faces: %w", err)
+	}
+
+	folders, err := s.sharedFolderRepo.GetFoldersByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user folders: %w", err)
+	}
+	allowed := make(map[uuid.UUID]bool, len(folders))
+	for _, f := range folders {
+		allowed[f.ID] = true
+	}
+
+	result := &services.BulkAssignResult{}
+	found := make(map[uuid.UUID]bool, len(faces))
+	undoData := models.AssignPersonUndoData{PersonID: personID}
+	for _, face := range faces {
+		found[face.ID] = true
+		if !allowed[face.SharedFolderID] {
+			result.Inaccessible = append(result.Inaccessible, face.ID)
+			continue
+		}
+		result.AssignedIDs = append(result.AssignedIDs, face.ID)
+		undoData.Faces = append(undoData.Faces, models.FacePreviousPersonID{
+			FaceID:           face.ID,
+			PreviousPersonID: face.PersonID,
+		})
+	}
+	for _, id := range faceIDs {
+		if !found[id] {
+			result.NotFound = append(result.NotFound, id)
+			found[id] = true // report duplicates once
+		}
+	}
+
+	if len(result.AssignedIDs) == 0 {
+		return result, nil
+	}
+
+	undoJSON, err := json.Marshal(undoData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal undo data: %w", err)
+	}
+
+	action := &models.CurationAction{
+		ID:         uuid.New(),
+		UserID:     userID,
+		ActionType: models.CurationActionAssignPerson,
+		ItemCount:  len(result.AssignedIDs),
+		UndoData:   string(undoJSON),
+		CreatedAt:  time.Now(),
+	}
+
+	// Faces tagged as a redacted person inherit the redaction (not reversed by undo)
+	if err := s.actionRepo.AssignFacesWithAction(ctx, action, result.AssignedIDs, personID, person.Redacted); err != nil {
+		return nil, fmt.Errorf("failed to assign faces: %w", err)
+	}
+	result.Action = action
+
+	s.refreshFaceCounts(ctx, affectedPersons(undoData))
+
+	logger.Face("faces_bulk_assigned", "Assigned faces to person", map[string]interface{}{
+		"user_id":      userID.String(),
+		"person_id":    personID.String(),
+		"face_count":   len(result.AssignedIDs),
+		"inaccessible": len(result.Inaccessible),
+		"not_found":    len(result.NotFound),
+		"action_id":    action.ID.String(),
+	})
+
+	return result, nil
+}
+
+// UndoAction reverses the user's most recent action within the undo window
+func (s *CurationServiceImpl) UndoAction(ctx context.Context, userID uuid.UUID, actionID uuid.UUID) (*models.CurationAction, error) {
+	action, err := s.actionRepo.GetByID(ctx, actionID)
+	if err != nil || action.UserID != userID {
+		return nil, services.ErrActionNotFound
+	}
+
+	if action.UndoneAt != nil {
+		return nil, services.ErrActionAlreadyUndone
+	}
+
+	if time.Since(action.CreatedAt) > s.undoWindow {
+		return nil, services.ErrUndoWindowExpired
+	}
+
+	// Undoing an older action could overwrite changes made by a later one
+	latest, err := s.actionRepo.GetLatestActiveByUser(ctx, userID)
+	if err != nil || latest.ID != action.ID {
+		return nil, services.ErrActionNotLatest
+	}
+
+	// Claim the undo first so concurrent requests can't reverse twice
+	marked, err := s.actionRepo.MarkUndone(ctx, action.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark action undone: %w", err)
+	}
+	if !marked {
+		return nil, services.ErrActionAlreadyUndone
+	}
+
+	switch action.ActionType {
+	case models.CurationActionAssignPerson:
+		if err := s.undoAssignPerson(ctx, action); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported action type: %s", action.ActionType)
+	}
+
+	logger.Face("curation_action_undone", "Undid curation action", map[string]interface{}{
+		"user_id":     userID.String(),
+		"action_id":   action.ID.String(),
+		"action_type": string(action.ActionType),
+		"item_count":  action.ItemCount,
+	})
+
+	return s.actionRepo.GetByID(ctx, action.ID)
+}
+
+// undoAssignPerson restores each face's previous person assignment
+func (s *CurationServiceImpl) undoAssignPerson(ctx context.Context, action *models.CurationAction) error {
+	var undoData models.AssignPersonUndoData
+	if err := json.Unmarshal([]byte(action.UndoData), &undoData); err != nil {
+		return fmt.Errorf("failed to parse undo data: %w", err)
+	}
+
+	// Group faces by previous person so each group is one update
+	groups := make(map[uuid.UUID][]uuid.UUID) // uuid.Nil = previously unassigned
+	for _, f := range undoData.Faces {
+		prev := uuid.Nil
+		if f.PreviousPersonID != nil {
+			prev = *f.PreviousPersonID
+		}
+		groups[prev] = append(groups[prev], f.FaceID)
+	}
+
+	for prev, faceIDs := range groups {
+		var personID *uuid.UUID
+		if prev != uuid.Nil {
+			p := prev
+			personID = &p
+		}
+		if _, err := s.faceRepo.UpdatePersonIDs(ctx, faceIDs, personID); err != nil {
+			return fmt.Errorf("failed to restore faces: %w", err)
+		}
+	}
+
+	s.refreshFaceCounts(ctx, affectedPersons(undoData))
+	return nil
+}
+
+// refreshFaceCounts recalculates cached face counts and search centroids of persons
+func (s *CurationServiceImpl) refreshFaceCounts(ctx context.Context, personIDs []uuid.UUID) {
+	for _, personID := range personIDs {
+		faces, err := s.faceRepo.GetByPerson(ctx, personID)
+		if err != nil {
+			continue
+		}
+		s.personRepo.UpdateFaceCount(ctx, personID, len(faces))
+		s.personRepo.UpdateCentroid(ctx, personID)
+	}
+}
+
+// affectedPersons returns the target person and all previous persons of an assign action
+func affectedPersons(undoData models.AssignPersonUndoData) []uuid.UUID {
+	seen := map[uuid.UUID]bool{undoData.PersonID: true}
+	persons := []uuid.UUID{undoData.PersonID}
+	for _, f := range undoData.Faces {
+		if f.PreviousPersonID != nil && !seen[*f.PreviousPersonID] {
+			seen[*f.PreviousPersonID] = true
+			persons = append(persons, *f.PreviousPersonID)
+		}
+	}
+	return persons
+}
@@ -4,25 +4,39 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"gofiber-template/domain/models"
 	"gofiber-template/domain/repositories"
 	"gofiber-template/domain/services"
 	"gofiber-template/infrastructure/googledrive"
+	"gofiber-template/infrastructure/storage"
 	"gofiber-template/pkg/logger"
 )
 
 type DriveServiceImpl struct {
-	driveClient *googledrive.DriveClient
-	userRepo    repositories.UserRepository
-	photoRepo   repositories.PhotoRepository
-	syncJobRepo repositories.SyncJobRepository
+	driveClient      *googledrive.DriveClient
+	userRepo         repositories.UserRepository
+	photoRepo        repositories.PhotoRepository
+	syncJobRepo      repositories.SyncJobRepository
+	sharedFolderRepo repositories.SharedFolderRepository
+	thumbnailCache   *storage.ThumbnailCache // nil if thumbnail caching is disabled
+
+	// Zip download limits (see SetZipLimits)
+	zipMaxFiles      int
+	zipMaxTotalBytes int64 // 0 = unlimited
+
+	// Root folders are registered as shared folders when set (nil = legacy user-root only)
+	sharedFolderService services.SharedFolderService
 }
 
 func NewDriveService(
@@ -30,15 +44,39 @@ func NewDriveService(
 	userRepo repositories.UserRepository,
 	photoRepo repositories.PhotoRepository,
 	syncJobRepo repositories.SyncJobRepository,
+	sharedFolderRepo repositories.SharedFolderRepository,
+	thumbnailCache *storage.ThumbnailCache,
 ) services.DriveService {
 	return &DriveServiceImpl{
-		driveClient: driveClient,
-		userRepo:    userRepo,
-		photoRepo:   photoRepo,
-		syncJobRepo: syncJobRepo,
+		driveClient:      driveClient,
+		userRepo:         userRepo,
+		photoRepo:        photoRepo,
+		syncJobRepo:      syncJobRepo,
+		sharedFolderRepo: sharedFolderRepo,
+		thumbnailCache:   thumbnailCache,
+		zipMaxFiles:      defaultZipMaxFiles,
 	}
 }
 
+// defaultZipMaxFiles is the zip download file cap used when none is configured
+const defaultZipMaxFiles = 50
+
+// SetZipLimits sets the file cap (below 1 = default) and total size budget in MB (0 = unlimited)
+// of DownloadPhotosAsZip
+func (s *DriveServiceImpl) SetZipLimits(maxFiles int, maxTotalMB int) {
+	if maxFiles < 1 {
+		maxFiles = defaultZipMaxFiles
+	}
+	s.zipMaxFiles = maxFiles
+	s.zipMaxTotalBytes = int64(max(maxTotalMB, 0)) * 1024 * 1024
+}
+
+// SetSharedFolderService sets the shared folder service used to register root folders
+// (set after construction because the shared folder service is created with the workers)
+func (s *DriveServiceImpl) SetSharedFolderService(svc services.SharedFolderService) {
+	s.sharedFolderService = svc
+}
+
 // GetAuthURL generates the OAuth authorization URL
 func (s *DriveServiceImpl) GetAuthURL(state string) string {
 	return s.driveClient.GetAuthURL(state)
@@ -194,12 +232,18 @@ func (s *DriveServiceImpl) ListFolders(ctx context.Context, userID uuid.UUID, pa
 }
 
 // SetRootFolder sets the root folder for sync and registers a webhook
+// Root folders are shared folders: each call adds (or joins) a shared folder, so a user can have
+// several roots (My Drive folders, Shared Drives). The user's root fields keep the latest one for compatibility.
 func (s *DriveServiceImpl) SetRootFolder(ctx context.Context, userID uuid.UUID, folderID string) error {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("failed to get user: %w", err)
 	}
 
+	if s.sharedFolderService != nil {
+		return s.setRootAsSharedFolder(ctx, user, folderID)
+	}
+
 	// Get Drive service
 	expiry := time.Now()
 	if user.DriveTokenExpiry != nil {
@@ -259,6 +303,27 @@ func (s *DriveServiceImpl) SetRootFolder(ctx context.Context, userID uuid.UUID,
 	return s.userRepo.Update(ctx, userID, user)
 }
 
+// setRootAsSharedFolder registers the root folder through the shared folder model
+// (shared folder sync, webhook and photo queries by shared_folder_id)
+func (s *DriveServiceImpl) setRootAsSharedFolder(ctx context.Context, user *models.User, folderID string) error {
+	folder, err := s.sharedFolderService.AddFolder(ctx, user.ID, folderID, "", user.DriveAccessToken, user.DriveRefreshToken, false)
+	if err != nil {
+		return fmt.Errorf("failed to add root folder: %w", err)
+	}
+
+	logger.Drive("root_folder_registered", "Root folder registered as shared folder", map[string]interface{}{
+		"user_id":          user.ID.String(),
+		"drive_folder_id":  folderID,
+		"shared_folder_id": folder.ID.String(),
+	})
+
+	user.DriveRootFolderID = folder.DriveFolderID
+	user.DriveRootFolderName = folder.DriveFolderName
+	user.UpdatedAt = time.Now()
+
+	return s.userRepo.Update(ctx, user.ID, user)
+}
+
 // GetRootFolder gets the root folder ID
 func (s *DriveServiceImpl) GetRootFolder(ctx context.Context, userID uuid.UUID) (string, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -380,11 +445,20 @@ func (s *DriveServiceImpl) GetSyncStatus(ctx context.Context, userID uuid.UUID)
 }
 
 // GetPhotos gets paginated photos for user
+// Queries by shared_folder_id like the shared folder endpoints. The legacy user_id/path-prefix
+// queries are only a compatibility shim for users who have no shared folders yet.
 func (s *DriveServiceImpl) GetPhotos(ctx context.Context, userID uuid.UUID, page, limit int) ([]models.Photo, int64, error) {
 	offset := (page - 1) * limit
 
-	// Shared model: Get user's root folder name and query by path prefix
-	// This allows users to see photos synced by others in the same shared folder
+	folderIDs, err := s.userFolderIDs(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(folderIDs) > 0 {
+		return s.photoRepo.GetBySharedFolders(ctx, folderIDs, offset, limit)
+	}
+
+	// Legacy shared model: Get user's root folder name and query by path prefix
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, 0, err
@@ -402,21 +476,62 @@ func (s *DriveServiceImpl) GetPhotos(ctx context.Context, userID uuid.UUID, page
 // GetPhotosByFolder gets photos by folder path
 func (s *DriveServiceImpl) GetPhotosByFolder(ctx context.Context, userID uuid.UUID, folderPath string, page, limit int) ([]models.Photo, int64, error) {
 	offset := (page - 1) * limit
+
+	folderIDs, err := s.userFolderIDs(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(folderIDs) > 0 {
+		return s.photoRepo.GetBySharedFoldersAndPath(ctx, folderIDs, folderPath, offset, limit)
+	}
+
 	return s.photoRepo.GetByUserAndFolder(ctx, userID, folderPath, offset, limit)
 }
 
 // GetPhotosByFolderId gets photos by folder ID
 func (s *DriveServiceImpl) GetPhotosByFolderId(ctx context.Context, userID uuid.UUID, folderId string, page, limit int) ([]models.Photo, int64, error) {
 	offset := (page - 1) * limit
+
+	folderIDs, err := s.userFolderIDs(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(folderIDs) > 0 {
+		return s.photoRepo.GetBySharedFoldersAndDriveFolderID(ctx, folderIDs, folderId, offset, limit)
+	}
+
 	return s.photoRepo.GetByUserAndFolderId(ctx, userID, folderId, offset, limit)
 }
 
 // SearchPhotos searches photos by folder path (activity name)
 func (s *DriveServiceImpl) SearchPhotos(ctx context.Context, userID uuid.UUID, searchQuery string, page, limit int) ([]models.Photo, int64, error) {
 	offset := (page - 1) * limit
+
+	folderIDs, err := s.userFolderIDs(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(folderIDs) > 0 {
+		return s.photoRepo.SearchByFolderPathInSharedFolders(ctx, folderIDs, searchQuery, offset, limit)
+	}
+
 	return s.photoRepo.SearchByFolderPath(ctx, userID, searchQuery, offset, limit)
 }
 
+// userFolderIDs returns the IDs of the shared folders the user has access to
+func (s *DriveServiceImpl) userFolderIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	folders, err := s.sharedFolderRepo.GetFoldersByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	folderIDs := make([]uuid.UUID, len(folders))
+	for i, f := range folders {
+		folderIDs[i] = f.ID
+	}
+	return folderIDs, nil
+}
+
 // GetPhotoThumbnail gets photo thumbnail from Google Drive
 func (s *DriveServiceImpl) GetPhotoThumbnail(ctx context.Context, userID uuid.UUID, driveFileID string, size int) ([]byte, string, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -433,7 +548,134 @@ func (s *DriveServiceImpl) GetPhotoThumbnail(ctx context.Context, userID uuid.UU
 		expiry = *user.DriveTokenExpiry
 	}
 
-	return s.driveClient.DownloadThumbnail(ctx, user.DriveAccessToken, user.DriveRefreshToken, expiry, driveFileID, size)
+	// Known photo (nil if not synced yet) - used for the ready hint and CDN cache version
+	photo, _ := s.photoRepo.GetByDriveFileIDForUser(ctx, userID, driveFileID)
+
+	data, contentType, err := s.driveClient.DownloadThumbnail(ctx, user.DriveAccessToken, user.DriveRefreshToken, expiry, driveFileID, size)
+	if err != nil {
+		if errors.Is(err, googledrive.ErrThumbnailNotReady) {
+			if photo != nil && !photo.ThumbnailPending {
+				s.photoRepo.UpdateMetadata(ctx, photo.ID, map[string]interface{}{"thumbnail_pending": true})
+			}
+			return nil, "", services.ErrThumbnailNotReady
+		}
+		return nil, "", err
+	}
+
+	if photo != nil && photo.ThumbnailPending {
+		s.photoRepo.UpdateMetadata(ctx, photo.ID, map[string]interface{}{"thumbnail_pending": false})
+	}
+
+	// Persist to CDN in background so subsequent requests can redirect
+	if s.thumbnailCache != nil {
+		if photo != nil {
+			version := thumbnailVersion(photo)
+			go func() {
+				if _, err := s.thumbnailCache.Put(context.Background(), driveFileID, size, version, data, contentType); err != nil && !errors.Is(err, storage.ErrBunnyUnavailable) {
+					logger.DriveError("thumbnail_cache_put_failed", "Failed to cache thumbnail", err, map[string]interface{}{
+						"drive_file_id": driveFileID,
+						"size":          size,
+					})
+				}
+			}()
+		}
+	}
+
+	return data, contentType, nil
+}
+
+// GetCachedThumbnailURL returns the CDN URL of a cached thumbnail, or "" if not cached
+// Only returns a URL if the user has access to the photo's shared folder
+func (s *DriveServiceImpl) GetCachedThumbnailURL(ctx context.Context, userID uuid.UUID, driveFileID string, size int) (string, error) {
+	isUpload := strings.HasPrefix(driveFileID, models.UploadDriveFileIDPrefix)
+	if s.thumbnailCache == nil && !isUpload {
+		return "", nil
+	}
+
+	photo, err := s.photoRepo.GetByDriveFileIDForUser(ctx, userID, driveFileID)
+	if err != nil {
+		return "", nil // Unknown photo - fall back to proxy
+	}
+
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, photo.SharedFolderID)
+	if err != nil || !hasAccess {
+		return "", nil
+	}
+
+	// Uploaded photos are served from Bunny as-is (there is no Drive thumbnail)
+	if photo.Source == models.PhotoSourceUpload {
+		return photo.ThumbnailURL, nil
+	}
+	if s.thumbnailCache == nil {
+		return "", nil
+	}
+
+	url, ok := s.thumbnailCache.Get(ctx, driveFileID, size, thumbnailVersion(photo))
+	if !ok {
+		return "", nil
+	}
+	return url, nil
+}
+
+// thumbnailVersion returns the cache version of a photo (changes when Drive modified time changes)
+func thumbnailVersion(photo *models.Photo) int64 {
+	if photo.DriveModifiedAt != nil {
+		return photo.DriveModifiedAt.Unix()
+	}
+	return 0
+}
+
+// OpenOriginalPhoto streams the original image from Drive using the folder's token.
+// Returns services.ErrPhotoNotFound if the photo is unknown or the user has no access.
+func (s *DriveServiceImpl) OpenOriginalPhoto(ctx context.Context, userID uuid.UUID, driveFileID, rangeHeader string) (*services.PhotoStream, error) {
+	photo, err := s.photoRepo.GetByDriveFileIDForUser(ctx, userID, driveFileID)
+	if err != nil {
+		return nil, services.ErrPhotoNotFound
+	}
+
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, photo.SharedFolderID)
+	if err != nil || !hasAccess {
+		return nil, services.ErrPhotoNotFound
+	}
+
+	folder, err := s.sharedFolderRepo.GetByID(ctx, photo.SharedFolderID)
+	if err != nil {
+		return nil, services.ErrPhotoNotFound
+	}
+
+	expiry := time.Now()
+	if folder.DriveTokenExpiry != nil {
+		expiry = *folder.DriveTokenExpiry
+	}
+	srv, err := s.driveClient.GetDriveServiceWithResourceKey(ctx, folder.DriveAccessToken, folder.DriveRefreshToken, expiry, folder.DriveFolderID, folder.DriveResourceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drive service: %w", err)
+	}
+
+	resp, err := s.driveClient.DownloadFileStream(ctx, srv, driveFileID, rangeHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = photo.MimeType
+	}
+
+	acceptRanges := resp.Header.Get("Accept-Ranges")
+	if acceptRanges == "" {
+		acceptRanges = "bytes"
+	}
+
+	return &services.PhotoStream{
+		Body:          resp.Body,
+		ContentType:   contentType,
+		ContentLength: resp.ContentLength,
+		ContentRange:  resp.Header.Get("Content-Range"),
+		AcceptRanges:  acceptRanges,
+		Partial:       resp.StatusCode == http.StatusPartialContent,
+		FileName:      photo.FileName,
+	}, nil
 }
 
 // DownloadPhotosAsZip downloads multiple photos and returns them as a zip file
@@ -442,8 +684,8 @@ func (s *DriveServiceImpl) DownloadPhotosAsZip(ctx context.Context, userID uuid.
 		return nil, fmt.Errorf("no files to download")
 	}
 
-	if len(driveFileIDs) > 50 {
-		return nil, fmt.Errorf("too many files, maximum is 50")
+	if len(driveFileIDs) > s.zipMaxFiles {
+		return nil, fmt.Errorf("%w: %d selected, maximum is %d", services.ErrZipTooManyFiles, len(driveFileIDs), s.zipMaxFiles)
 	}
 
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -473,10 +715,11 @@ func (s *DriveServiceImpl) DownloadPhotosAsZip(ctx context.Context, userID uuid.
 	// Track filenames to handle duplicates
 	filenameCount := make(map[string]int)
 	totalFiles := len(driveFileIDs)
+	var totalBytes int64
 
 	for i, fileID := range driveFileIDs {
 		// Get file metadata
-		file, err := srv.Files.Get(fileID).Fields("id, name, mimeType").Do()
+		file, err := srv.Files.Get(fileID).Fields("id, name, mimeType, size").Do()
 		if err != nil {
 			logger.DriveError("zip_get_file_failed", "Failed to get file metadata", err, map[string]interface{}{
 				"file_id": fileID,
@@ -485,6 +728,12 @@ func (s *DriveServiceImpl) DownloadPhotosAsZip(ctx context.Context, userID uuid.
 			continue
 		}
 
+		// The zip is held in memory: stop before the selection outgrows the budget
+		totalBytes += file.Size
+		if s.zipMaxTotalBytes > 0 && totalBytes > s.zipMaxTotalBytes {
+			return nil, fmt.Errorf("%w: maximum is %d MB", services.ErrZipTooLarge, s.zipMaxTotalBytes/(1024*1024))
+		}
+
 		// Send progress callback
 		if onProgress != nil {
 			onProgress(services.DownloadProgress{
@@ -556,12 +805,11 @@ func (s *DriveServiceImpl) HandleWebhook(ctx context.Context, channelID, resourc
 	// Try to find user by webhook token first
 	user, err := s.userRepo.GetByDriveWebhookToken(ctx, token)
 	if err != nil {
-		logger.Webhook("webhook_token_not_found", "Webhook token not found for user, might be shared folder token", map[string]interface{}{
-			"error": err.Error(),
-		})
-		// Token not found for user - this is expected for shared folder webhooks
-		// The shared folder handler will handle it separately
-		return fmt.Errorf("webhook token not found: %w", err)
+		// Expected for shared folder webhooks - the handler tries the shared folder service next
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return services.ErrUnknownWebhookToken
+		}
+		return fmt.Errorf("failed to look up webhook token: %w", err)
 	}
 
 	logger.Webhook("webhook_user_found", "Found user for webhook token", map[string]interface{}{
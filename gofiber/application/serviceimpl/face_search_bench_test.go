@@ -0,0 +1,80 @@
+package serviceimpl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	gormpostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"gofiber-template/infrastructure/postgres"
+)
+
+// BenchmarkFaceSearch compares the single-query and fan-out face search on a real database, to
+// pick FACE_SEARCH_FANOUT_MIN_FOLDERS for it. Needs a DSN of a database with synced faces:
+//
+//	FACE_SEARCH_BENCH_DSN="host=... user=... dbname=..." go test ./application/serviceimpl \
+//		-run '^$' -bench FaceSearch -benchtime 20x
+//
+// The folders searched are the ones with the most faces. The threshold is the smallest folder
+// count at which fan-out/N is faster than single/N; if fan-out never wins, set 0.
+func BenchmarkFaceSearch(b *testing.B) {
+	dsn := os.Getenv("FACE_SEARCH_BENCH_DSN")
+	if dsn == "" {
+		b.Skip("FACE_SEARCH_BENCH_DSN not set")
+	}
+	db, err := gorm.Open(gormpostgres.Open(dsn), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		b.Fatalf("connect: %v", err)
+	}
+	ctx := context.Background()
+
+	var folderIDs []uuid.UUID
+	if err := db.Raw(`SELECT shared_folder_id FROM faces WHERE shared_folder_id IS NOT NULL
+		GROUP BY shared_folder_id ORDER BY COUNT(*) DESC LIMIT 64`).Scan(&folderIDs).Error; err != nil || len(folderIDs) == 0 {
+		b.Skipf("no folders with faces (err: %v)", err)
+	}
+	var faceID uuid.UUID
+	if err := db.Raw(`SELECT id FROM faces WHERE NOT redacted LIMIT 1`).Scan(&faceID).Error; err != nil {
+		b.Fatalf("pick face: %v", err)
+	}
+
+	repo := postgres.NewFaceRepository(db)
+	face, err := repo.GetByID(ctx, faceID)
+	if err != nil {
+		b.Fatalf("load face: %v", err)
+	}
+	concurrency, err := strconv.Atoi(os.Getenv("FACE_SEARCH_FANOUT_CONCURRENCY"))
+	if err != nil || concurrency < 1 {
+		concurrency = 4
+	}
+	s := &FaceServiceImpl{faceRepo: repo, searchFanOut: FaceSearchFanOut{Concurrency: concurrency}}
+
+	const limit, threshold = 20, 0.6 // Search defaults (FACE_SEARCH_DEFAULT_LIMIT / _THRESHOLD_PERCENT)
+	for _, n := range []int{1, 2, 4, 8, 16, 32, 64} {
+		if n > len(folderIDs) {
+			break
+		}
+		folders := folderIDs[:n]
+
+		b.Run(fmt.Sprintf("single/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.SearchSimilarBySharedFolders(ctx, folders, face.Embedding, limit, threshold); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("fanout/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := s.searchSimilarFanOut(ctx, folders, face.Embedding, limit, threshold); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
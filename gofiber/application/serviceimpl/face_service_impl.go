@@ -3,6 +3,9 @@ package serviceimpl
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pgvector/pgvector-go"
@@ -21,6 +24,14 @@ type FaceServiceImpl struct {
 	userRepo         repositories.UserRepository
 	sharedFolderRepo repositories.SharedFolderRepository
 	faceClient       *faceapi.FaceClient
+	searchFanOut     FaceSearchFanOut
+}
+
+// FaceSearchFanOut controls per-folder parallel vector search for users with many folders.
+// MinFolders is the crossover measured by BenchmarkFaceSearch on the deployment's data.
+type FaceSearchFanOut struct {
+	MinFolders  int // Fan out when searching at least this many folders (0 = always one query)
+	Concurrency int // Max per-folder queries in flight
 }
 
 func NewFaceService(
@@ -30,7 +41,11 @@ func NewFaceService(
 	userRepo repositories.UserRepository,
 	sharedFolderRepo repositories.SharedFolderRepository,
 	faceClient *faceapi.FaceClient,
+	searchFanOut FaceSearchFanOut,
 ) services.FaceService {
+	if searchFanOut.Concurrency < 1 {
+		searchFanOut.Concurrency = 1
+	}
 	return &FaceServiceImpl{
 		faceRepo:         faceRepo,
 		photoRepo:        photoRepo,
@@ -38,7 +53,92 @@ func NewFaceService(
 		userRepo:         userRepo,
 		sharedFolderRepo: sharedFolderRepo,
 		faceClient:       faceClient,
+		searchFanOut:     searchFanOut,
+	}
+}
+
+// searchSimilarInFolders runs the vector search over the folders, either as one query or
+// (for many folders) as concurrent per-folder queries whose results are merged by similarity.
+// Per-folder queries can use the folder's index instead of scanning across all folders.
+func (s *FaceServiceImpl) searchSimilarInFolders(ctx context.Context, folderIDs []uuid.UUID, embedding pgvector.Vector, limit int, threshold float64) ([]repositories.FaceSearchResult, error) {
+	start := time.Now()
+	fanOut := s.searchFanOut.MinFolders > 0 && len(folderIDs) >= s.searchFanOut.MinFolders
+
+	var results []repositories.FaceSearchResult
+	var err error
+	if fanOut {
+		results, err = s.searchSimilarFanOut(ctx, folderIDs, embedding, limit, threshold)
+	} else {
+		results, err = s.faceRepo.SearchSimilarBySharedFolders(ctx, folderIDs, embedding, limit, threshold)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Latency per path, to compare single-query and fan-out and tune FACE_SEARCH_FANOUT_MIN_FOLDERS
+	logger.Debug(logger.CategoryFace, "face_search_timing", "Face search completed", map[string]interface{}{
+		"fan_out":      fanOut,
+		"folder_count": len(folderIDs),
+		"results":      len(results),
+		"duration_ms":  time.Since(start).Milliseconds(),
+	})
+
+	return results, nil
+}
+
+// searchSimilarFanOut searches each folder concurrently (bounded) and keeps the overall top results
+func (s *FaceServiceImpl) searchSimilarFanOut(ctx context.Context, folderIDs []uuid.UUID, embedding pgvector.Vector, limit int, threshold float64) ([]repositories.FaceSearchResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		merged   []repositories.FaceSearchResult
+		firstErr error
+	)
+	sem := make(chan struct{}, s.searchFanOut.Concurrency)
+
+	for _, folderID := range folderIDs {
+		wg.Add(1)
+		go func(folderID uuid.UUID) {
+			defer wg.Done()
+
+			sem <- struct{}{} // Acquire semaphore
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			// Each folder's top N covers its share of the overall top N
+			results, err := s.faceRepo.SearchSimilarBySharedFolders(ctx, []uuid.UUID{folderID}, embedding, limit, threshold)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			merged = append(merged, results...)
+		}(folderID)
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Similarity > merged[j].Similarity
+	})
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged, nil
 }
 
 // DetectFaces detects all faces in an uploaded image and returns their bounding boxes
@@ -53,9 +153,13 @@ func (s *FaceServiceImpl) DetectFaces(ctx context.Context, imageData []byte, mim
 		return nil, services.ErrNoFacesDetected
 	}
 
-	// Convert to detected faces
-	faces := make([]services.DetectedFace, len(result.Faces))
-	for i, f := range result.Faces {
+	return toDetectedFaces(result.Faces), nil
+}
+
+// toDetectedFaces converts Face API detections, indexed in detection order
+func toDetectedFaces(detected []faceapi.DetectedFace) []services.DetectedFace {
+	faces := make([]services.DetectedFace, len(detected))
+	for i, f := range detected {
 		faces[i] = services.DetectedFace{
 			Index:      i,
 			BboxX:      f.BboxX,
@@ -66,13 +170,47 @@ func (s *FaceServiceImpl) DetectFaces(ctx context.Context, imageData []byte, mim
 			Embedding:  f.Embedding,
 		}
 	}
+	return faces
+}
+
+// queryEmbedding extracts the faces of an uploaded image and returns the embedding of the
+// selected face (services.BestFaceIndex picks the most prominent one)
+func (s *FaceServiceImpl) queryEmbedding(ctx context.Context, imageData []byte, mimeType string, faceIndex int) (pgvector.Vector, error) {
+	// Call face API to extract embedding from uploaded image
+	result, err := s.faceClient.ExtractFacesFromBytes(ctx, imageData, mimeType)
+	if err != nil {
+		return pgvector.Vector{}, fmt.Errorf("failed to extract faces from image: %w", err)
+	}
+
+	if len(result.Faces) == 0 {
+		return pgvector.Vector{}, services.ErrNoFacesDetected
+	}
+
+	if faceIndex == services.BestFaceIndex {
+		faceIndex = mostProminentFace(result.Faces)
+	}
+
+	// Validate face index
+	if faceIndex < 0 || faceIndex >= len(result.Faces) {
+		return pgvector.Vector{}, services.ErrInvalidFaceIndex
+	}
 
-	return faces, nil
+	return pgvector.NewVector(result.Faces[faceIndex].Embedding), nil
 }
 
 // SearchByImageWithIndex searches for similar faces using a specific face from the uploaded image
 func (s *FaceServiceImpl) SearchByImageWithIndex(ctx context.Context, userID uuid.UUID, imageData []byte, mimeType string, faceIndex int, limit int, threshold float64) ([]services.FaceSearchResult, error) {
-	// Call face API to extract embedding from uploaded image
+	embedding, err := s.queryEmbedding(ctx, imageData, mimeType, faceIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.searchUserFolders(ctx, userID, embedding, limit, threshold)
+}
+
+// DetectAndSearch detects the faces of an uploaded image and searches with one of them, calling
+// the Face API once (instead of DetectFaces followed by SearchByImageWithIndex)
+func (s *FaceServiceImpl) DetectAndSearch(ctx context.Context, userID uuid.UUID, imageData []byte, mimeType string, faceIndex int, limit int, threshold float64) (*services.DetectSearchResult, error) {
 	result, err := s.faceClient.ExtractFacesFromBytes(ctx, imageData, mimeType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract faces from image: %w", err)
@@ -82,17 +220,27 @@ func (s *FaceServiceImpl) SearchByImageWithIndex(ctx context.Context, userID uui
 		return nil, services.ErrNoFacesDetected
 	}
 
-	// Validate face index
+	if faceIndex == services.BestFaceIndex {
+		faceIndex = mostProminentFace(result.Faces)
+	}
 	if faceIndex < 0 || faceIndex >= len(result.Faces) {
 		return nil, services.ErrInvalidFaceIndex
 	}
 
-	// Use the selected face for search
-	queryFace := result.Faces[faceIndex]
+	results, err := s.searchUserFolders(ctx, userID, pgvector.NewVector(result.Faces[faceIndex].Embedding), limit, threshold)
+	if err != nil {
+		return nil, err
+	}
 
-	// Convert embedding to pgvector
-	embedding := pgvector.NewVector(queryFace.Embedding)
+	return &services.DetectSearchResult{
+		Faces:     toDetectedFaces(result.Faces),
+		FaceIndex: faceIndex,
+		Results:   results,
+	}, nil
+}
 
+// searchUserFolders searches all shared folders the user can access with a query embedding
+func (s *FaceServiceImpl) searchUserFolders(ctx context.Context, userID uuid.UUID, embedding pgvector.Vector, limit int, threshold float64) ([]services.FaceSearchResult, error) {
 	// Get user's accessible shared folders
 	folders, err := s.sharedFolderRepo.GetFoldersByUser(ctx, userID)
 	if err != nil {
@@ -110,7 +258,7 @@ func (s *FaceServiceImpl) SearchByImageWithIndex(ctx context.Context, userID uui
 	}
 
 	// Search for similar faces in user's shared folders
-	searchResults, err := s.faceRepo.SearchSimilarBySharedFolders(ctx, folderIDs, embedding, limit, threshold)
+	searchResults, err := s.searchSimilarInFolders(ctx, folderIDs, embedding, limit, threshold)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search similar faces: %w", err)
 	}
@@ -128,9 +276,183 @@ func (s *FaceServiceImpl) SearchByImageWithIndex(ctx context.Context, userID uui
 	return results, nil
 }
 
+// StreamSearchByImage is SearchByImageWithIndex with results delivered per folder as each
+// folder's search finishes (up to the fan-out concurrency run at once). Face extraction and
+// folder lookup errors are returned before anything is sent; the channel is closed when every
+// folder is done, after a batch with Err, or when ctx is cancelled (the caller must cancel ctx
+// if it stops reading).
+func (s *FaceServiceImpl) StreamSearchByImage(ctx context.Context, userID uuid.UUID, imageData []byte, mimeType string, faceIndex int, limit int, threshold float64) (<-chan services.FaceSearchBatch, int, error) {
+	embedding, err := s.queryEmbedding(ctx, imageData, mimeType, faceIndex)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	folders, err := s.sharedFolderRepo.GetFoldersByUser(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get user folders: %w", err)
+	}
+
+	batches := make(chan services.FaceSearchBatch)
+	go func() {
+		defer close(batches)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, s.searchFanOut.Concurrency)
+		for _, folder := range folders {
+			wg.Add(1)
+			go func(folderID uuid.UUID) {
+				defer wg.Done()
+
+				select {
+				case sem <- struct{}{}: // Acquire semaphore
+				case <-ctx.Done():
+					return
+				}
+				defer func() { <-sem }()
+
+				found, err := s.faceRepo.SearchSimilarBySharedFolders(ctx, []uuid.UUID{folderID}, embedding, limit, threshold)
+				batch := services.FaceSearchBatch{FolderID: folderID}
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					batch.Err = fmt.Errorf("failed to search similar faces: %w", err)
+				}
+				for _, r := range found {
+					batch.Results = append(batch.Results, services.FaceSearchResult{
+						Face:       r.Face,
+						Photo:      r.Photo,
+						Similarity: r.Similarity,
+					})
+				}
+
+				select {
+				case batches <- batch:
+					if batch.Err != nil {
+						cancel() // Stop the remaining folders: the stream ends with the error
+					}
+				case <-ctx.Done():
+				}
+			}(folder.ID)
+		}
+		wg.Wait()
+	}()
+
+	return batches, len(folders), nil
+}
+
+// SearchMyFace searches all of the user's folders with the best face of a selfie and groups
+// the matches by folder, folders with the most matches first
+func (s *FaceServiceImpl) SearchMyFace(ctx context.Context, userID uuid.UUID, imageData []byte, mimeType string, limit int, threshold float64) ([]services.FolderFaceMatches, error) {
+	results, err := s.SearchByImageWithIndex(ctx, userID, imageData, mimeType, services.BestFaceIndex, limit, threshold)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return []services.FolderFaceMatches{}, nil
+	}
+
+	folders, err := s.sharedFolderRepo.GetFoldersByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user folders: %w", err)
+	}
+	folderNames := make(map[uuid.UUID]string, len(folders))
+	for _, f := range folders {
+		folderNames[f.ID] = f.DriveFolderName
+	}
+
+	// Results are sorted by similarity, so each group stays sorted too
+	groups := []services.FolderFaceMatches{}
+	groupIndex := make(map[uuid.UUID]int)
+	for _, r := range results {
+		i, ok := groupIndex[r.Photo.SharedFolderID]
+		if !ok {
+			i = len(groups)
+			groupIndex[r.Photo.SharedFolderID] = i
+			groups = append(groups, services.FolderFaceMatches{
+				FolderID:   r.Photo.SharedFolderID,
+				FolderName: folderNames[r.Photo.SharedFolderID],
+			})
+		}
+		groups[i].Matches = append(groups[i].Matches, r)
+	}
+
+	sort.SliceStable(groups, func(a, b int) bool {
+		return len(groups[a].Matches) > len(groups[b].Matches)
+	})
+
+	return groups, nil
+}
+
 // SearchByImage searches for similar faces by uploading an image (uses first face - legacy)
 func (s *FaceServiceImpl) SearchByImage(ctx context.Context, userID uuid.UUID, imageData []byte, mimeType string, limit int, threshold float64) ([]services.FaceSearchResult, error) {
-	return s.SearchByImageWithIndex(ctx, userID, imageData, mimeType, 0, limit, threshold)
+	return s.SearchByImageWithIndex(ctx, userID, imageData, mimeType, services.BestFaceIndex, limit, threshold)
+}
+
+// mostProminentFace returns the index of the face most likely to be the uploader's: the largest
+// bounding box weighted by detection confidence, so a small sharp face in the background loses
+// to the selfie taker's face. faces must not be empty.
+func mostProminentFace(faces []faceapi.DetectedFace) int {
+	best, bestScore := 0, -1.0
+	for i, face := range faces {
+		score := face.BboxWidth * face.BboxHeight * face.Confidence
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// SearchByPerson searches the user's folders with a person's centroid. Persons tagged before
+// centroids existed get theirs computed on first search. Redacted persons can't be searched.
+func (s *FaceServiceImpl) SearchByPerson(ctx context.Context, userID uuid.UUID, personID uuid.UUID, limit int, threshold float64) ([]services.FaceSearchResult, error) {
+	person, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil || person.UserID != userID || person.Redacted {
+		return nil, services.ErrPersonNotFound
+	}
+
+	if person.Centroid == nil {
+		if err := s.personRepo.UpdateCentroid(ctx, personID); err != nil {
+			return nil, fmt.Errorf("failed to compute person centroid: %w", err)
+		}
+		if person, err = s.personRepo.GetByID(ctx, personID); err != nil {
+			return nil, services.ErrPersonNotFound
+		}
+		if person.Centroid == nil {
+			return nil, services.ErrPersonHasNoFaces
+		}
+	}
+
+	folders, err := s.sharedFolderRepo.GetFoldersByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user folders: %w", err)
+	}
+	if len(folders) == 0 {
+		return []services.FaceSearchResult{}, nil
+	}
+
+	folderIDs := make([]uuid.UUID, len(folders))
+	for i, f := range folders {
+		folderIDs[i] = f.ID
+	}
+
+	searchResults, err := s.searchSimilarInFolders(ctx, folderIDs, *person.Centroid, limit, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search similar faces: %w", err)
+	}
+
+	results := make([]services.FaceSearchResult, len(searchResults))
+	for i, r := range searchResults {
+		results[i] = services.FaceSearchResult{
+			Face:       r.Face,
+			Photo:      r.Photo,
+			Similarity: r.Similarity,
+		}
+	}
+	return results, nil
 }
 
 // SearchByFaceID searches for similar faces using an existing face's embedding
@@ -149,6 +471,9 @@ func (s *FaceServiceImpl) SearchByFaceID(ctx context.Context, userID uuid.UUID,
 	if !hasAccess {
 		return nil, fmt.Errorf("face not found")
 	}
+	if sourceFace.Redacted {
+		return nil, services.ErrFaceNotFound
+	}
 
 	// Get user's accessible shared folders
 	folders, err := s.sharedFolderRepo.GetFoldersByUser(ctx, userID)
@@ -167,7 +492,7 @@ func (s *FaceServiceImpl) SearchByFaceID(ctx context.Context, userID uuid.UUID,
 	}
 
 	// Search for similar faces using the source face's embedding
-	searchResults, err := s.faceRepo.SearchSimilarBySharedFolders(ctx, folderIDs, sourceFace.Embedding, limit+1, threshold)
+	searchResults, err := s.searchSimilarInFolders(ctx, folderIDs, sourceFace.Embedding, limit+1, threshold)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search similar faces: %w", err)
 	}
@@ -228,8 +553,167 @@ func (s *FaceServiceImpl) GetFacesByPerson(ctx context.Context, userID uuid.UUID
 	return s.faceRepo.GetByPerson(ctx, personID)
 }
 
-// GetFaces returns paginated faces for a user
-func (s *FaceServiceImpl) GetFaces(ctx context.Context, userID uuid.UUID, page, limit int) ([]models.Face, int64, error) {
+// Face clustering bounds: faces compared per run (the comparison is quadratic), nearest neighbors
+// linked per face and sample faces returned per cluster
+const (
+	clusterMaxFaces    = 2000
+	clusterNeighbors   = 10
+	clusterSampleFaces = 4
+)
+
+// ClusterFaces groups a folder's unassigned faces into suggested persons. Faces are linked to
+// their nearest neighbors at or above threshold and linked faces are merged (union-find), so
+// a cluster is every face reachable through such links.
+func (s *FaceServiceImpl) ClusterFaces(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, threshold float64) (*services.FaceClusterResult, error) {
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify access: %w", err)
+	}
+	if !hasAccess {
+		return nil, services.ErrFolderNotFound
+	}
+
+	rows, err := s.faceRepo.FindUnassignedNeighbors(ctx, folderID, threshold, clusterMaxFaces, clusterNeighbors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find similar faces: %w", err)
+	}
+
+	confidence := make(map[uuid.UUID]float64)
+	parent := make(map[uuid.UUID]uuid.UUID)
+	var find func(id uuid.UUID) uuid.UUID
+	find = func(id uuid.UUID) uuid.UUID {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+	for _, row := range rows {
+		if _, ok := parent[row.FaceID]; !ok {
+			parent[row.FaceID] = row.FaceID
+			confidence[row.FaceID] = row.Confidence
+		}
+	}
+	for _, row := range rows {
+		if row.NeighborID == nil {
+			continue
+		}
+		if _, ok := parent[*row.NeighborID]; !ok {
+			continue
+		}
+		if a, b := find(row.FaceID), find(*row.NeighborID); a != b {
+			parent[a] = b
+		}
+	}
+
+	groups := make(map[uuid.UUID][]uuid.UUID)
+	for id := range parent {
+		root := find(id)
+		groups[root] = append(groups[root], id)
+	}
+
+	clusters := make([]services.FaceCluster, 0)
+	var sampleIDs []uuid.UUID
+	for _, ids := range groups {
+		if len(ids) < 2 {
+			continue
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			return confidence[ids[i]] > confidence[ids[j]]
+		})
+		clusters = append(clusters, services.FaceCluster{FaceIDs: ids})
+		sampleIDs = append(sampleIDs, ids[:min(len(ids), clusterSampleFaces)]...)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].FaceIDs) != len(clusters[j].FaceIDs) {
+			return len(clusters[i].FaceIDs) > len(clusters[j].FaceIDs)
+		}
+		return confidence[clusters[i].FaceIDs[0]] > confidence[clusters[j].FaceIDs[0]]
+	})
+
+	// Samples carry the photo thumbnail and bounding box for "Is this the same person?" prompts
+	samples, err := s.faceRepo.GetByIDs(ctx, sampleIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sample faces: %w", err)
+	}
+	photoIDs := make([]uuid.UUID, len(samples))
+	for i, face := range samples {
+		photoIDs[i] = face.PhotoID
+	}
+	photos, err := s.photoRepo.GetByIDs(ctx, photoIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sample photos: %w", err)
+	}
+	photoByID := make(map[uuid.UUID]models.Photo, len(photos))
+	for _, photo := range photos {
+		photoByID[photo.ID] = photo
+	}
+	faceByID := make(map[uuid.UUID]models.Face, len(samples))
+	for _, face := range samples {
+		face.Photo = photoByID[face.PhotoID]
+		faceByID[face.ID] = face
+	}
+	for i := range clusters {
+		for _, id := range clusters[i].FaceIDs[:min(len(clusters[i].FaceIDs), clusterSampleFaces)] {
+			if face, ok := faceByID[id]; ok {
+				clusters[i].Samples = append(clusters[i].Samples, face)
+			}
+		}
+	}
+
+	logger.Face("faces_clustered", "Clustered unassigned faces of folder", map[string]interface{}{
+		"user_id":   userID.String(),
+		"folder_id": folderID.String(),
+		"threshold": threshold,
+		"faces":     len(parent),
+		"clusters":  len(clusters),
+	})
+
+	return &services.FaceClusterResult{
+		Clusters:        clusters,
+		FacesConsidered: len(parent),
+		MaxFaces:        clusterMaxFaces,
+	}, nil
+}
+
+// GetPersonFolders lists the shared folders a person appears in, most photos first.
+// Only folders the user can still access are counted.
+func (s *FaceServiceImpl) GetPersonFolders(ctx context.Context, userID uuid.UUID, personID uuid.UUID) ([]services.PersonFolder, error) {
+	person, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil || person.UserID != userID {
+		return nil, services.ErrPersonNotFound
+	}
+
+	folders, err := s.sharedFolderRepo.GetFoldersByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user folders: %w", err)
+	}
+
+	folderIDs := make([]uuid.UUID, len(folders))
+	folderNames := make(map[uuid.UUID]string, len(folders))
+	for i, f := range folders {
+		folderIDs[i] = f.ID
+		folderNames[f.ID] = f.DriveFolderName
+	}
+
+	counts, err := s.faceRepo.CountByPersonPerFolder(ctx, personID, folderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count person faces: %w", err)
+	}
+
+	result := make([]services.PersonFolder, len(counts))
+	for i, c := range counts {
+		result[i] = services.PersonFolder{
+			FolderID:   c.SharedFolderID,
+			FolderName: folderNames[c.SharedFolderID],
+			PhotoCount: c.PhotoCount,
+			FaceCount:  c.FaceCount,
+		}
+	}
+	return result, nil
+}
+
+// GetFaces returns paginated faces for a user, optionally filtered by person assignment
+func (s *FaceServiceImpl) GetFaces(ctx context.Context, userID uuid.UUID, page, limit int, assigned *bool) ([]models.Face, int64, error) {
 	// Get user's accessible shared folders
 	folders, err := s.sharedFolderRepo.GetFoldersByUser(ctx, userID)
 	if err != nil {
@@ -247,9 +731,172 @@ func (s *FaceServiceImpl) GetFaces(ctx context.Context, userID uuid.UUID, page,
 	}
 
 	offset := (page - 1) * limit
+	if assigned != nil {
+		if *assigned {
+			return s.faceRepo.GetAssignedBySharedFolders(ctx, folderIDs, offset, limit)
+		}
+		return s.faceRepo.GetUnassignedBySharedFolders(ctx, folderIDs, offset, limit)
+	}
 	return s.faceRepo.GetBySharedFolders(ctx, folderIDs, offset, limit)
 }
 
+// GetLowConfidenceFaces returns faces in a folder detected below maxConfidence
+func (s *FaceServiceImpl) GetLowConfidenceFaces(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, maxConfidence float64, page, limit int) ([]models.Face, int64, error) {
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to verify access: %w", err)
+	}
+	if !hasAccess {
+		return nil, 0, services.ErrFolderNotFound
+	}
+
+	offset := (page - 1) * limit
+	return s.faceRepo.GetLowConfidenceBySharedFolder(ctx, folderID, maxConfidence, offset, limit)
+}
+
+// DeleteFaces deletes faces the user has access to (all-or-nothing access check)
+// and updates the cached face counts of the affected photos and persons
+func (s *FaceServiceImpl) DeleteFaces(ctx context.Context, userID uuid.UUID, faceIDs []uuid.UUID) (*services.DeleteFacesResult, error) {
+	faces, err := s.faceRepo.GetByIDs(ctx, faceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get faces: %w", err)
+	}
+	if len(faces) == 0 {
+		return nil, services.ErrFaceNotFound
+	}
+
+	// Verify access once per folder
+	checked := make(map[uuid.UUID]bool)
+	for _, f := range faces {
+		if checked[f.SharedFolderID] {
+			continue
+		}
+		hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, f.SharedFolderID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify access: %w", err)
+		}
+		if !hasAccess {
+			return nil, services.ErrFaceNotFound
+		}
+		checked[f.SharedFolderID] = true
+	}
+
+	ids := make([]uuid.UUID, len(faces))
+	perPhoto := make(map[uuid.UUID]int)
+	persons := make(map[uuid.UUID]bool)
+	for i, f := range faces {
+		ids[i] = f.ID
+		perPhoto[f.PhotoID]++
+		if f.PersonID != nil {
+			persons[*f.PersonID] = true
+		}
+	}
+
+	deleted, err := s.faceRepo.DeleteByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete faces: %w", err)
+	}
+
+	result := &services.DeleteFacesResult{DeletedCount: deleted}
+
+	// Keep cached counts consistent (best effort - the faces are already gone)
+	for photoID, n := range perPhoto {
+		if err := s.photoRepo.DecrementFaceCount(ctx, photoID, n); err != nil {
+			logger.FaceError("photo_face_count_update_failed", "Failed to update photo face count", err, map[string]interface{}{
+				"photo_id": photoID.String(),
+			})
+			continue
+		}
+		result.PhotosUpdated++
+	}
+	for personID := range persons {
+		remaining, err := s.faceRepo.GetByPerson(ctx, personID)
+		if err != nil {
+			continue
+		}
+		if err := s.personRepo.UpdateFaceCount(ctx, personID, len(remaining)); err == nil {
+			result.PersonsUpdated++
+		}
+		s.refreshCentroid(ctx, personID)
+	}
+
+	logger.Face("faces_deleted", "Faces deleted", map[string]interface{}{
+		"user_id":         userID.String(),
+		"requested":       len(faceIDs),
+		"deleted":         deleted,
+		"photos_updated":  result.PhotosUpdated,
+		"persons_updated": result.PersonsUpdated,
+	})
+
+	return result, nil
+}
+
+// RedactPerson marks a person as redacted and excludes all their faces from search
+func (s *FaceServiceImpl) RedactPerson(ctx context.Context, userID uuid.UUID, personID uuid.UUID) (int64, error) {
+	person, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil || person.UserID != userID {
+		return 0, services.ErrPersonNotFound
+	}
+
+	if err := s.personRepo.MarkRedacted(ctx, personID); err != nil {
+		return 0, fmt.Errorf("failed to redact person: %w", err)
+	}
+
+	redacted, err := s.faceRepo.RedactByPerson(ctx, personID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to redact faces: %w", err)
+	}
+
+	logger.Face("person_redacted", "Person redacted", map[string]interface{}{
+		"user_id":        userID.String(),
+		"person_id":      personID.String(),
+		"faces_redacted": redacted,
+	})
+
+	return redacted, nil
+}
+
+// RedactFaces excludes specific faces from search (all-or-nothing access check)
+func (s *FaceServiceImpl) RedactFaces(ctx context.Context, userID uuid.UUID, faceIDs []uuid.UUID) (int64, error) {
+	faces, err := s.faceRepo.GetByIDs(ctx, faceIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get faces: %w", err)
+	}
+	if len(faces) == 0 {
+		return 0, services.ErrFaceNotFound
+	}
+
+	checked := make(map[uuid.UUID]bool)
+	ids := make([]uuid.UUID, len(faces))
+	for i, f := range faces {
+		ids[i] = f.ID
+		if checked[f.SharedFolderID] {
+			continue
+		}
+		hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, f.SharedFolderID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to verify access: %w", err)
+		}
+		if !hasAccess {
+			return 0, services.ErrFaceNotFound
+		}
+		checked[f.SharedFolderID] = true
+	}
+
+	redacted, err := s.faceRepo.RedactByIDs(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to redact faces: %w", err)
+	}
+
+	logger.Face("faces_redacted", "Faces redacted", map[string]interface{}{
+		"user_id":   userID.String(),
+		"requested": len(faceIDs),
+		"redacted":  redacted,
+	})
+
+	return redacted, nil
+}
+
 // AssignFaceToPerson assigns a face to a person
 func (s *FaceServiceImpl) AssignFaceToPerson(ctx context.Context, userID uuid.UUID, faceID uuid.UUID, personID uuid.UUID) error {
 	// Get face
@@ -278,7 +925,110 @@ func (s *FaceServiceImpl) AssignFaceToPerson(ctx context.Context, userID uuid.UU
 	}
 
 	// Assign face to person
-	return s.faceRepo.UpdatePersonID(ctx, faceID, &personID)
+	if err := s.faceRepo.UpdatePersonID(ctx, faceID, &personID); err != nil {
+		return err
+	}
+
+	s.refreshCentroid(ctx, personID)
+	if face.PersonID != nil && *face.PersonID != personID {
+		s.refreshCentroid(ctx, *face.PersonID)
+	}
+
+	// Faces tagged as a redacted person inherit the redaction
+	if person.Redacted {
+		if _, err := s.faceRepo.RedactByIDs(ctx, []uuid.UUID{faceID}); err != nil {
+			return fmt.Errorf("failed to redact face: %w", err)
+		}
+	}
+	return nil
+}
+
+// MergePersons moves all faces of mergeID to keepID, recomputes keepID's face count and centroid
+// and deletes mergeID. Both persons must belong to the user.
+func (s *FaceServiceImpl) MergePersons(ctx context.Context, userID uuid.UUID, keepID uuid.UUID, mergeID uuid.UUID) (*models.Person, error) {
+	if keepID == mergeID {
+		return nil, services.ErrSamePerson
+	}
+	for _, id := range []uuid.UUID{keepID, mergeID} {
+		person, err := s.personRepo.GetByID(ctx, id)
+		if err != nil || person.UserID != userID {
+			return nil, services.ErrPersonNotFound
+		}
+	}
+
+	moved, err := s.personRepo.Merge(ctx, keepID, mergeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge persons: %w", err)
+	}
+
+	logger.Face("persons_merged", "Merged persons", map[string]interface{}{
+		"user_id":     userID.String(),
+		"keep_id":     keepID.String(),
+		"merged_id":   mergeID.String(),
+		"faces_moved": moved,
+	})
+
+	return s.personRepo.GetByID(ctx, keepID)
+}
+
+// SplitPerson moves the given faces of a person to a new person. Every face must currently be
+// tagged as the person. The new person inherits the redaction of the source.
+func (s *FaceServiceImpl) SplitPerson(ctx context.Context, userID uuid.UUID, personID uuid.UUID, faceIDs []uuid.UUID, name string) (*models.Person, error) {
+	source, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil || source.UserID != userID {
+		return nil, services.ErrPersonNotFound
+	}
+
+	unique := make(map[uuid.UUID]bool, len(faceIDs))
+	for _, id := range faceIDs {
+		unique[id] = true
+	}
+	faces, err := s.faceRepo.GetByIDs(ctx, faceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get faces: %w", err)
+	}
+	if len(faces) != len(unique) {
+		return nil, services.ErrFaceNotFound
+	}
+	for _, f := range faces {
+		if f.PersonID == nil || *f.PersonID != personID {
+			return nil, services.ErrFaceNotFound
+		}
+	}
+
+	now := time.Now()
+	newPerson := &models.Person{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Name:       name,
+		Redacted:   source.Redacted,
+		RedactedAt: source.RedactedAt,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	moved, err := s.personRepo.Split(ctx, personID, faceIDs, newPerson)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split person: %w", err)
+	}
+
+	logger.Face("person_split", "Split faces into a new person", map[string]interface{}{
+		"user_id":       userID.String(),
+		"source_id":     personID.String(),
+		"new_person_id": newPerson.ID.String(),
+		"faces_moved":   moved,
+	})
+
+	return s.personRepo.GetByID(ctx, newPerson.ID)
+}
+
+// refreshCentroid recomputes a person's search centroid after their faces changed (best effort:
+// a stale centroid only makes search by person slightly less accurate)
+func (s *FaceServiceImpl) refreshCentroid(ctx context.Context, personID uuid.UUID) {
+	if err := s.personRepo.UpdateCentroid(ctx, personID); err != nil {
+		logger.FaceError("person_centroid_update_failed", "Failed to update person centroid", err, map[string]interface{}{
+			"person_id": personID.String(),
+		})
+	}
 }
 
 // RemoveFaceFromPerson removes a face from its assigned person
@@ -299,7 +1049,14 @@ func (s *FaceServiceImpl) RemoveFaceFromPerson(ctx context.Context, userID uuid.
 	}
 
 	// Remove person assignment
-	return s.faceRepo.UpdatePersonID(ctx, faceID, nil)
+	if err := s.faceRepo.UpdatePersonID(ctx, faceID, nil); err != nil {
+		return err
+	}
+
+	if face.PersonID != nil {
+		s.refreshCentroid(ctx, *face.PersonID)
+	}
+	return nil
 }
 
 // GetFaceCount returns the total number of faces for a user
@@ -323,6 +1080,43 @@ func (s *FaceServiceImpl) GetFaceCount(ctx context.Context, userID uuid.UUID) (i
 	return s.faceRepo.CountBySharedFolders(ctx, folderIDs)
 }
 
+// GetFaceIndexStatus reports the face processing backlog of the user's folders, so search results
+// can say they may be incomplete while an import is still being processed
+func (s *FaceServiceImpl) GetFaceIndexStatus(ctx context.Context, userID uuid.UUID) (*services.FaceIndexStatus, error) {
+	folders, err := s.sharedFolderRepo.GetFoldersByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user folders: %w", err)
+	}
+
+	folderIDs := make([]uuid.UUID, len(folders))
+	for i, f := range folders {
+		folderIDs[i] = f.ID
+	}
+
+	counts, err := s.photoRepo.CountFaceQueueBySharedFolders(ctx, folderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count queued photos: %w", err)
+	}
+
+	status := &services.FaceIndexStatus{Folders: []services.FolderIndexStatus{}}
+	for _, f := range folders {
+		if pending := counts[f.ID]; pending > 0 {
+			status.Pending += pending
+			status.Folders = append(status.Folders, services.FolderIndexStatus{
+				FolderID:   f.ID,
+				FolderName: f.DriveFolderName,
+				Pending:    pending,
+			})
+		}
+	}
+	sort.Slice(status.Folders, func(i, j int) bool {
+		return status.Folders[i].Pending > status.Folders[j].Pending
+	})
+	status.Complete = status.Pending == 0
+
+	return status, nil
+}
+
 // GetProcessingStats returns face processing statistics
 func (s *FaceServiceImpl) GetProcessingStats(ctx context.Context, userID uuid.UUID) (*services.FaceProcessingStats, error) {
 	logger.Face("get_processing_stats", "GetProcessingStats called", map[string]interface{}{
@@ -454,6 +1248,39 @@ func (s *FaceServiceImpl) RetryFailedPhotos(ctx context.Context, userID uuid.UUI
 	return count, nil
 }
 
+// ReprocessFolder deletes all faces of a folder and queues its photos for face processing again.
+// Person tags of the deleted faces are lost; the new faces have to be tagged again.
+func (s *FaceServiceImpl) ReprocessFolder(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (int64, error) {
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to verify access: %w", err)
+	}
+	if !hasAccess {
+		return 0, services.ErrFolderNotFound
+	}
+
+	folder, err := s.sharedFolderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return 0, services.ErrFolderNotFound
+	}
+	// The worker never picks up photos of these folders, so the faces would be deleted for nothing
+	if !folder.FaceProcessingEnabled || folder.FaceProcessingDeferred {
+		return 0, services.ErrFaceProcessingDisabled
+	}
+
+	count, err := s.photoRepo.ResetAllToPendingForFolder(ctx, folderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset folder photos: %w", err)
+	}
+
+	logger.Face("reprocess_folder", "Queued all photos of folder for face reprocessing", map[string]interface{}{
+		"user_id":     userID.String(),
+		"folder_id":   folderID.String(),
+		"reset_count": count,
+	})
+	return count, nil
+}
+
 // GetPendingPhotos returns photos with pending face status for debugging
 func (s *FaceServiceImpl) GetPendingPhotos(ctx context.Context, userID uuid.UUID, limit int) ([]models.Photo, error) {
 	logger.Face("get_pending_photos", "GetPendingPhotos called", map[string]interface{}{
@@ -532,10 +1359,11 @@ func (s *FaceServiceImpl) ResetStuckProcessing(ctx context.Context) (int64, erro
 }
 
 // ResetPhotosToPending resets specific photos to pending status for reprocessing
-func (s *FaceServiceImpl) ResetPhotosToPending(ctx context.Context, userID uuid.UUID, photoIDs []uuid.UUID) (int64, error) {
+func (s *FaceServiceImpl) ResetPhotosToPending(ctx context.Context, userID uuid.UUID, photoIDs []uuid.UUID, force bool) (int64, error) {
 	logger.Face("reset_photos_start", "ResetPhotosToPending called", map[string]interface{}{
 		"user_id":     userID.String(),
 		"photo_count": len(photoIDs),
+		"force":       force,
 	})
 
 	if len(photoIDs) == 0 {
@@ -578,6 +1406,16 @@ func (s *FaceServiceImpl) ResetPhotosToPending(ctx context.Context, userID uuid.
 			continue
 		}
 
+		// Forget the last model check so the worker doesn't skip a faceless photo
+		if force && photo.LastFaceCheckModelVersion != "" {
+			if err := s.photoRepo.UpdateMetadata(ctx, photoID, map[string]interface{}{"last_face_check_model_version": ""}); err != nil {
+				logger.FaceError("reset_photo_failed", "Failed to clear face model check", err, map[string]interface{}{
+					"photo_id": photoID.String(),
+				})
+				continue
+			}
+		}
+
 		// Reset to pending
 		err = s.photoRepo.UpdateFaceStatus(ctx, photoID, models.FaceStatusPending, 0)
 		if err != nil {
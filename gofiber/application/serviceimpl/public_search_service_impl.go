@@ -0,0 +1,148 @@
+package serviceimpl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+	"gofiber-template/domain/services"
+	"gofiber-template/infrastructure/faceapi"
+	"gofiber-template/pkg/logger"
+)
+
+type PublicSearchServiceImpl struct {
+	linkRepo         repositories.PublicSearchLinkRepository
+	faceRepo         repositories.FaceRepository
+	sharedFolderRepo repositories.SharedFolderRepository
+	faceClient       *faceapi.FaceClient
+}
+
+func NewPublicSearchService(
+	linkRepo repositories.PublicSearchLinkRepository,
+	faceRepo repositories.FaceRepository,
+	sharedFolderRepo repositories.SharedFolderRepository,
+	faceClient *faceapi.FaceClient,
+) services.PublicSearchService {
+	return &PublicSearchServiceImpl{
+		linkRepo:         linkRepo,
+		faceRepo:         faceRepo,
+		sharedFolderRepo: sharedFolderRepo,
+		faceClient:       faceClient,
+	}
+}
+
+// CreateLink mints a new public search link for a folder owned by the user
+func (s *PublicSearchServiceImpl) CreateLink(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, expiresIn time.Duration, maxResults int) (*models.PublicSearchLink, error) {
+	folder, err := s.sharedFolderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, services.ErrFolderNotFound
+	}
+
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify access: %w", err)
+	}
+	if !hasAccess {
+		return nil, services.ErrFolderNotFound
+	}
+	if folder.TokenOwnerID != userID {
+		return nil, services.ErrNotFolderOwner
+	}
+
+	token, err := generatePublicSearchToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	link := &models.PublicSearchLink{
+		ID:             uuid.New(),
+		SharedFolderID: folderID,
+		CreatedBy:      userID,
+		Token:          token,
+		ExpiresAt:      time.Now().Add(expiresIn),
+		MaxResults:     maxResults,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.linkRepo.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to create link: %w", err)
+	}
+
+	logger.Face("public_search_link_created", "Public search link created", map[string]interface{}{
+		"link_id":     link.ID.String(),
+		"folder_id":   folderID.String(),
+		"user_id":     userID.String(),
+		"expires_at":  link.ExpiresAt,
+		"max_results": maxResults,
+	})
+
+	return link, nil
+}
+
+// Search runs face search restricted to the link's folder
+func (s *PublicSearchServiceImpl) Search(ctx context.Context, token string, imageData []byte, mimeType string, threshold float64) (*models.PublicSearchLink, []services.FaceSearchResult, error) {
+	link, err := s.linkRepo.GetByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, services.ErrPublicLinkNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link.IsExpired() {
+		return nil, nil, services.ErrPublicLinkExpired
+	}
+
+	result, err := s.faceClient.ExtractFacesFromBytes(ctx, imageData, mimeType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to extract faces from image: %w", err)
+	}
+	if len(result.Faces) == 0 {
+		return nil, nil, services.ErrNoFacesDetected
+	}
+
+	limit := link.MaxResults
+	if limit <= 0 {
+		limit = services.DefaultPublicSearchResults
+	}
+
+	// A selfie can catch people behind the uploader; search with the most prominent face
+	embedding := pgvector.NewVector(result.Faces[mostProminentFace(result.Faces)].Embedding)
+	searchResults, err := s.faceRepo.SearchSimilarBySharedFolders(ctx, []uuid.UUID{link.SharedFolderID}, embedding, limit, threshold)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search similar faces: %w", err)
+	}
+
+	results := make([]services.FaceSearchResult, len(searchResults))
+	for i, r := range searchResults {
+		results[i] = services.FaceSearchResult{
+			Face:       r.Face,
+			Photo:      r.Photo,
+			Similarity: r.Similarity,
+		}
+	}
+
+	logger.Face("public_search", "Public face search", map[string]interface{}{
+		"link_id":   link.ID.String(),
+		"folder_id": link.SharedFolderID.String(),
+		"results":   len(results),
+	})
+
+	return link, results, nil
+}
+
+// generatePublicSearchToken returns a URL-safe random token
+func generatePublicSearchToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
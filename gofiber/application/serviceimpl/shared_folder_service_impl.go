@@ -1,26 +1,35 @@
 package serviceimpl
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"google.golang.org/api/googleapi"
+	"gorm.io/gorm"
 
+	"gofiber-template/domain/dto"
 	"gofiber-template/domain/models"
 	"gofiber-template/domain/repositories"
 	"gofiber-template/domain/services"
 	"gofiber-template/infrastructure/googledrive"
+	"gofiber-template/infrastructure/storage"
 	"gofiber-template/infrastructure/worker"
 	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/scheduler"
+	"gofiber-template/pkg/utils"
 )
 
 // Error codes for frontend handling
 const (
-	ErrCodeGoogleTokenExpired = "GOOGLE_TOKEN_EXPIRED"
+	ErrCodeGoogleTokenExpired  = "GOOGLE_TOKEN_EXPIRED"
+	ErrCodeFolderLimitExceeded = "FOLDER_LIMIT_EXCEEDED"
 )
 
 // GoogleTokenError represents an error when Google OAuth token is invalid/expired
@@ -33,6 +42,17 @@ func (e *GoogleTokenError) Error() string {
 	return e.Message
 }
 
+// FolderLimitError represents an error when a user has reached the max number of folders
+type FolderLimitError struct {
+	Code    string
+	Message string
+	Limit   int
+}
+
+func (e *FolderLimitError) Error() string {
+	return e.Message
+}
+
 // isGoogleAuthError checks if error is related to Google OAuth authentication
 func isGoogleAuthError(err error) bool {
 	if err == nil {
@@ -57,39 +77,11 @@ func wrapGoogleAuthError(err error) error {
 }
 
 // GoogleAPIErrorDetails contains detailed information from Google API errors
-type GoogleAPIErrorDetails struct {
-	HTTPStatusCode int                    `json:"http_status_code"`
-	ErrorMessage   string                 `json:"error_message"`
-	ErrorBody      string                 `json:"error_body,omitempty"`
-	ErrorDetails   []googleapi.ErrorItem  `json:"error_details,omitempty"`
-	RawError       string                 `json:"raw_error"`
-}
+type GoogleAPIErrorDetails = googledrive.APIErrorDetails
 
 // parseGoogleAPIError extracts detailed error information from Google API errors
 func parseGoogleAPIError(err error) *GoogleAPIErrorDetails {
-	if err == nil {
-		return nil
-	}
-
-	details := &GoogleAPIErrorDetails{
-		RawError: err.Error(),
-	}
-
-	// Try to extract googleapi.Error details
-	if apiErr, ok := err.(*googleapi.Error); ok {
-		details.HTTPStatusCode = apiErr.Code
-		details.ErrorMessage = apiErr.Message
-		details.ErrorBody = apiErr.Body
-		details.ErrorDetails = apiErr.Errors
-	} else {
-		// Try to find wrapped googleapi.Error
-		errStr := err.Error()
-		if strings.Contains(errStr, "googleapi:") {
-			details.ErrorMessage = errStr
-		}
-	}
-
-	return details
+	return googledrive.ParseAPIError(err)
 }
 
 // logGoogleAPIError logs detailed Google API error information
@@ -172,6 +164,13 @@ type SharedFolderServiceImpl struct {
 	userRepo         repositories.UserRepository
 	driveClient      *googledrive.DriveClient
 	syncWorker       *worker.SyncWorker
+	exportWorker     *worker.ExportWorker     // nil if export storage is not configured
+	eventScheduler   scheduler.EventScheduler // Runs per-folder sync schedules
+	uploadStorage    storage.BunnyStorage     // Stores photos uploaded directly to the app
+
+	// Config
+	maxFoldersPerUser    int           // 0 = unlimited
+	webhookRenewalWindow time.Duration // Renew webhook channels expiring within this window
 }
 
 func NewSharedFolderService(
@@ -181,20 +180,71 @@ func NewSharedFolderService(
 	userRepo repositories.UserRepository,
 	driveClient *googledrive.DriveClient,
 	syncWorker *worker.SyncWorker,
+	exportWorker *worker.ExportWorker,
+	eventScheduler scheduler.EventScheduler,
+	uploadStorage storage.BunnyStorage,
+	maxFoldersPerUser int,
+	webhookRenewalWindowHours int,
 ) services.SharedFolderService {
+	if webhookRenewalWindowHours <= 0 {
+		webhookRenewalWindowHours = 24
+	}
+
 	return &SharedFolderServiceImpl{
-		sharedFolderRepo: sharedFolderRepo,
-		syncJobRepo:      syncJobRepo,
-		photoRepo:        photoRepo,
-		userRepo:         userRepo,
-		driveClient:      driveClient,
-		syncWorker:       syncWorker,
+		sharedFolderRepo:  sharedFolderRepo,
+		syncJobRepo:       syncJobRepo,
+		photoRepo:         photoRepo,
+		userRepo:          userRepo,
+		driveClient:       driveClient,
+		syncWorker:        syncWorker,
+		exportWorker:      exportWorker,
+		eventScheduler:    eventScheduler,
+		uploadStorage:     uploadStorage,
+		maxFoldersPerUser: maxFoldersPerUser,
+
+		webhookRenewalWindow: time.Duration(webhookRenewalWindowHours) * time.Hour,
+	}
+}
+
+// checkFolderLimit returns a FolderLimitError if the user already reached the max number of folders
+// Admins are exempt from the limit
+func (s *SharedFolderServiceImpl) checkFolderLimit(ctx context.Context, userID uuid.UUID) error {
+	if s.maxFoldersPerUser <= 0 {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Role == "admin" {
+		return nil
+	}
+
+	count, err := s.sharedFolderRepo.CountFoldersByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to count user folders: %w", err)
+	}
+
+	if count >= int64(s.maxFoldersPerUser) {
+		logger.Drive("folder_limit_exceeded", "User reached max folders limit", map[string]interface{}{
+			"user_id":      userID.String(),
+			"folder_count": count,
+			"limit":        s.maxFoldersPerUser,
+		})
+		return &FolderLimitError{
+			Code:    ErrCodeFolderLimitExceeded,
+			Message: fmt.Sprintf("เพิ่มโฟลเดอร์ได้สูงสุด %d โฟลเดอร์ต่อผู้ใช้", s.maxFoldersPerUser),
+			Limit:   s.maxFoldersPerUser,
+		}
 	}
+
+	return nil
 }
 
 // AddFolder adds a new shared folder or joins an existing one
 // Returns immediately after creating sync job - photos sync in background via WebSocket updates
-func (s *SharedFolderServiceImpl) AddFolder(ctx context.Context, userID uuid.UUID, driveFolderID, resourceKey string, accessToken, refreshToken string) (*models.SharedFolder, error) {
+func (s *SharedFolderServiceImpl) AddFolder(ctx context.Context, userID uuid.UUID, driveFolderID, resourceKey string, accessToken, refreshToken string, deferFaceProcessing bool) (*models.SharedFolder, error) {
 	logger.Drive("add_folder_start", "Starting add folder process", map[string]interface{}{
 		"user_id":          userID.String(),
 		"drive_folder_id":  driveFolderID,
@@ -277,6 +327,11 @@ func (s *SharedFolderServiceImpl) AddFolder(ctx context.Context, userID uuid.UUI
 			return existingFolder, nil
 		}
 
+		// Enforce per-user folder limit before joining
+		if err := s.checkFolderLimit(ctx, userID); err != nil {
+			return nil, err
+		}
+
 		// Add user access to existing folder
 		access := &models.UserFolderAccess{
 			ID:             uuid.New(),
@@ -300,6 +355,11 @@ func (s *SharedFolderServiceImpl) AddFolder(ctx context.Context, userID uuid.UUI
 		return existingFolder, nil
 	}
 
+	// Enforce per-user folder limit before creating
+	if err := s.checkFolderLimit(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// Folder doesn't exist - create new one
 	logger.Drive("creating_new_folder", "Folder not found, creating new one", map[string]interface{}{
 		"drive_folder_id": driveFolderID,
@@ -371,6 +431,7 @@ func (s *SharedFolderServiceImpl) AddFolder(ctx context.Context, userID uuid.UUI
 		WebhookToken:      webhookToken,
 		PageToken:         "", // Will be set after full sync completes
 		CreatedAt:         time.Now(),
+		FaceProcessingDeferred: deferFaceProcessing,
 		UpdatedAt:         time.Now(),
 	}
 
@@ -463,6 +524,7 @@ func (s *SharedFolderServiceImpl) AddFolder(ctx context.Context, userID uuid.UUI
 		folder.WebhookChannelID = channel.Id
 		folder.WebhookResourceID = channel.ResourceId
 		folder.WebhookExpiry = &expiry
+		folder.WebhookSigned = true
 		folder.PageToken = startPageToken
 		folder.UpdatedAt = time.Now()
 
@@ -522,6 +584,141 @@ func (s *SharedFolderServiceImpl) GetFolderByID(ctx context.Context, userID uuid
 	return s.sharedFolderRepo.GetByID(ctx, folderID)
 }
 
+// GetFolderTree builds the sub-folder tree of a shared folder from its photos' drive_folder_path values
+// The root node is the shared folder; photo counts of each node include its descendants
+func (s *SharedFolderServiceImpl) GetFolderTree(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (*services.FolderTreeNode, error) {
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil || !hasAccess {
+		return nil, services.ErrFolderNotFound
+	}
+
+	folder, err := s.sharedFolderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, services.ErrFolderNotFound
+	}
+
+	counts, err := s.photoRepo.CountByFolderPathInSharedFolder(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count photos by folder: %w", err)
+	}
+
+	return buildFolderTree(folder.DriveFolderName, counts), nil
+}
+
+// buildFolderTree nests "/"-separated paths into a tree (empty segments are ignored)
+func buildFolderTree(rootName string, counts map[string]int64) *services.FolderTreeNode {
+	root := &services.FolderTreeNode{Name: rootName, Children: []*services.FolderTreeNode{}}
+	nodes := map[string]*services.FolderTreeNode{"": root}
+
+	for path, count := range counts {
+		node := root
+		nodePath := ""
+		for _, segment := range strings.Split(path, "/") {
+			segment = strings.TrimSpace(segment)
+			if segment == "" {
+				continue
+			}
+			if nodePath == "" {
+				nodePath = segment
+			} else {
+				nodePath += "/" + segment
+			}
+
+			child, ok := nodes[nodePath]
+			if !ok {
+				child = &services.FolderTreeNode{Name: segment, Path: nodePath, Children: []*services.FolderTreeNode{}}
+				nodes[nodePath] = child
+				node.Children = append(node.Children, child)
+			}
+			node = child
+		}
+		node.PhotoCount += count
+	}
+
+	sumFolderTree(root)
+	return root
+}
+
+// sumFolderTree sets TotalPhotoCount bottom-up and sorts children by name
+func sumFolderTree(node *services.FolderTreeNode) int64 {
+	total := node.PhotoCount
+	for _, child := range node.Children {
+		total += sumFolderTree(child)
+	}
+	node.TotalPhotoCount = total
+
+	sort.Slice(node.Children, func(i, j int) bool {
+		return node.Children[i].Name < node.Children[j].Name
+	})
+	return total
+}
+
+// folderDashboardTopSubFolders is how many sub-folders the folder dashboard ranks
+const folderDashboardTopSubFolders = 10
+
+// GetFolderDashboard returns a folder's photo, face and people totals, the date range its
+// photos cover and its largest sub-folders
+func (s *SharedFolderServiceImpl) GetFolderDashboard(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (*services.FolderDashboard, error) {
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil || !hasAccess {
+		return nil, services.ErrFolderNotFound
+	}
+
+	folder, err := s.sharedFolderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, services.ErrFolderNotFound
+	}
+
+	stats, err := s.photoRepo.GetFolderStats(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder stats: %w", err)
+	}
+
+	counts, err := s.photoRepo.CountByFolderPathInSharedFolder(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count photos by folder: %w", err)
+	}
+
+	return &services.FolderDashboard{
+		FolderID:        folder.ID,
+		FolderName:      folder.DriveFolderName,
+		TotalPhotos:     stats.TotalPhotos,
+		PhotosWithFaces: stats.PhotosWithFaces,
+		TotalFaces:      stats.TotalFaces,
+		DistinctPeople:  stats.DistinctPersons,
+		FirstPhotoAt:    stats.FirstPhotoAt,
+		LastPhotoAt:     stats.LastPhotoAt,
+		TopSubFolders:   topSubFolders(folder.DriveFolderName, counts, folderDashboardTopSubFolders),
+	}, nil
+}
+
+// topSubFolders ranks sub-folder paths by their direct photo count (largest first, then by path).
+// Photos directly in the shared folder (empty path or the folder's own name) are not a sub-folder.
+func topSubFolders(rootName string, counts map[string]int64, limit int) []services.FolderDashboardSubFolder {
+	subFolders := make([]services.FolderDashboardSubFolder, 0, len(counts))
+	for path, count := range counts {
+		if strings.TrimSpace(path) == "" || path == rootName {
+			continue
+		}
+		subFolders = append(subFolders, services.FolderDashboardSubFolder{
+			Name:       path[strings.LastIndex(path, "/")+1:],
+			Path:       path,
+			PhotoCount: count,
+		})
+	}
+
+	sort.Slice(subFolders, func(i, j int) bool {
+		if subFolders[i].PhotoCount != subFolders[j].PhotoCount {
+			return subFolders[i].PhotoCount > subFolders[j].PhotoCount
+		}
+		return subFolders[i].Path < subFolders[j].Path
+	})
+	if len(subFolders) > limit {
+		subFolders = subFolders[:limit]
+	}
+	return subFolders
+}
+
 // RemoveUserAccess removes user's access to a folder
 func (s *SharedFolderServiceImpl) RemoveUserAccess(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) error {
 	// Verify user has access
@@ -591,251 +788,1127 @@ func (s *SharedFolderServiceImpl) TriggerSync(ctx context.Context, userID uuid.U
 	return nil
 }
 
-// createSyncJob creates a new sync job for a folder
-// Returns nil if a job already exists (no error, just skips)
-func (s *SharedFolderServiceImpl) createSyncJob(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) error {
-	// Check if there's already a pending or running job for this folder
-	hasExisting, err := s.syncJobRepo.HasPendingOrRunningJobForFolder(ctx, folderID)
-	if err != nil {
-		logger.SyncError("check_existing_job_failed", "Failed to check for existing job", err, map[string]interface{}{
-			"folder_id": folderID.String(),
-		})
-		// Continue anyway - worst case we create a duplicate
-	}
-	if hasExisting {
-		logger.Sync("sync_job_skipped", "Skipping - job already pending/running for folder", map[string]interface{}{
-			"folder_id": folderID.String(),
-		})
-		// Trigger worker anyway in case existing job is stuck
-		if s.syncWorker != nil {
-			s.syncWorker.TriggerSync()
-		}
-		return nil
+// TriggerDryRunSync queues a dry-run sync of a folder: the worker counts what a sync would add,
+// update and delete without writing photos. forceFullSync previews a full sync (including orphan
+// cleanup) without resetting the folder's sync state.
+func (s *SharedFolderServiceImpl) TriggerDryRunSync(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, forceFullSync bool) (*models.SyncJob, error) {
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil || !hasAccess {
+		return nil, services.ErrFolderNotFound
 	}
 
-	// Create metadata JSON
-	metadata := worker.SyncJobMetadata{
+	metadataJSON, err := json.Marshal(worker.SyncJobMetadata{
 		SharedFolderID: folderID,
-	}
-	metadataJSON, err := json.Marshal(metadata)
+		DryRun:         true,
+		ForceFull:      forceFullSync,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	// Create sync job
 	now := time.Now()
 	job := &models.SyncJob{
-		ID:        uuid.New(),
-		UserID:    userID,
-		JobType:   models.SyncJobTypeDriveSync,
-		Status:    models.SyncJobStatusPending,
-		Metadata:  string(metadataJSON),
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:             uuid.New(),
+		UserID:         userID,
+		JobType:        models.SyncJobTypeDriveSync,
+		Status:         models.SyncJobStatusPending,
+		SharedFolderID: &folderID,
+		Metadata:       string(metadataJSON),
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 
 	if err := s.syncJobRepo.Create(ctx, job); err != nil {
-		return fmt.Errorf("failed to create sync job: %w", err)
+		return nil, fmt.Errorf("failed to create sync job: %w", err)
 	}
 
-	// Trigger sync worker immediately
 	if s.syncWorker != nil {
 		s.syncWorker.TriggerSync()
 	}
 
-	return nil
+	logger.Sync("dry_run_sync_created", "Created dry-run sync job for folder", map[string]interface{}{
+		"job_id":          job.ID.String(),
+		"folder_id":       folderID.String(),
+		"user_id":         userID.String(),
+		"force_full_sync": forceFullSync,
+	})
+	return job, nil
 }
 
-// GetSyncStatus returns the current sync status of a folder
-func (s *SharedFolderServiceImpl) GetSyncStatus(ctx context.Context, folderID uuid.UUID) (*models.SharedFolder, error) {
-	return s.sharedFolderRepo.GetByID(ctx, folderID)
-}
+// ErrInvalidSyncSchedule is returned for a sync schedule that is not a valid cron expression
+var ErrInvalidSyncSchedule = errors.New("invalid sync schedule")
 
-// HandleWebhook handles webhook notifications for shared folders
-func (s *SharedFolderServiceImpl) HandleWebhook(ctx context.Context, channelID, resourceID, resourceState, token string) error {
-	logger.Webhook("shared_folder_webhook_received", "SharedFolder HandleWebhook", map[string]interface{}{
-		"channel_id":     channelID,
-		"resource_state": resourceState,
-		"token_length":   len(token),
-	})
+// SetSyncSchedule sets the folder's sync cron schedule (empty clears it) and updates the scheduler
+func (s *SharedFolderServiceImpl) SetSyncSchedule(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, cronExpr string) (*models.SharedFolder, error) {
+	cronExpr = strings.TrimSpace(cronExpr)
+	if cronExpr != "" {
+		if err := scheduler.ValidateCronExpression(cronExpr); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSyncSchedule, err)
+		}
+	}
 
-	// Find shared folder by webhook token
-	folder, err := s.sharedFolderRepo.GetByWebhookToken(ctx, token)
-	if err != nil {
-		logger.Webhook("shared_folder_webhook_token_not_found", "Webhook token not found for shared folder", map[string]interface{}{
-			"error": err.Error(),
-		})
-		return fmt.Errorf("webhook token not found: %w", err)
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil || !hasAccess {
+		return nil, services.ErrFolderNotFound
 	}
 
-	logger.Webhook("shared_folder_found", "Found shared folder for webhook token", map[string]interface{}{
-		"folder_id":   folder.ID.String(),
-		"folder_name": folder.DriveFolderName,
+	// Map update so clearing the schedule writes the empty value
+	if err := s.sharedFolderRepo.UpdateMetadata(ctx, folderID, map[string]interface{}{
+		"sync_schedule_cron": cronExpr,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update folder: %w", err)
+	}
+
+	if err := s.registerSyncSchedule(folderID, cronExpr); err != nil {
+		return nil, err
+	}
+
+	logger.Sync("sync_schedule_updated", "Updated folder sync schedule", map[string]interface{}{
+		"folder_id": folderID.String(),
+		"user_id":   userID.String(),
+		"cron":      cronExpr,
 	})
 
-	// Only trigger sync for "change" events, NOT "sync" events
-	// "sync" is just Google's test notification when webhook is registered
-	// "change" is the actual notification when files/folders change
-	if resourceState == "change" {
-		logger.Webhook("shared_folder_trigger_sync", "Triggering sync for shared folder (change event)", map[string]interface{}{
-			"folder_id":      folder.ID.String(),
-			"resource_state": resourceState,
-		})
+	return s.sharedFolderRepo.GetByID(ctx, folderID)
+}
 
-		// Get the token owner to trigger sync
-		if err := s.triggerSyncForFolder(ctx, folder); err != nil {
-			logger.WebhookError("shared_folder_sync_failed", "Failed to trigger sync", err, map[string]interface{}{
+// RegisterSyncSchedules adds a scheduler job for every folder with a sync schedule
+func (s *SharedFolderServiceImpl) RegisterSyncSchedules(ctx context.Context) (int, error) {
+	folders, err := s.sharedFolderRepo.GetFoldersWithSyncSchedule(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get scheduled folders: %w", err)
+	}
+
+	registered := 0
+	for _, folder := range folders {
+		if err := s.registerSyncSchedule(folder.ID, folder.SyncScheduleCron); err != nil {
+			logger.SchedulerError("sync_schedule_register_failed", "Failed to register folder sync schedule", err, map[string]interface{}{
 				"folder_id": folder.ID.String(),
+				"cron":      folder.SyncScheduleCron,
 			})
-			return fmt.Errorf("failed to trigger sync: %w", err)
+			continue
 		}
-		logger.Webhook("shared_folder_sync_triggered", "Sync triggered successfully for shared folder", map[string]interface{}{
-			"folder_id": folder.ID.String(),
-		})
-	} else if resourceState == "sync" {
-		// "sync" is just Google testing if our endpoint works - no action needed
-		logger.Webhook("shared_folder_webhook_sync_ack", "Received sync acknowledgment (no action)", map[string]interface{}{
-			"folder_id":      folder.ID.String(),
-			"resource_state": resourceState,
-		})
-	} else {
-		logger.Webhook("shared_folder_webhook_ignored", "Ignoring webhook with unknown state", map[string]interface{}{
-			"folder_id":      folder.ID.String(),
-			"resource_state": resourceState,
-		})
+		registered++
 	}
 
-	return nil
+	return registered, nil
 }
 
-// RegisterWebhook registers a webhook for an existing folder
-func (s *SharedFolderServiceImpl) RegisterWebhook(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) error {
-	// Verify user has access
-	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
-	if err != nil {
-		return fmt.Errorf("failed to check access: %w", err)
-	}
-	if !hasAccess {
-		return fmt.Errorf("folder not found")
-	}
+// syncScheduleJobID returns the scheduler job ID of a folder's sync schedule
+func syncScheduleJobID(folderID uuid.UUID) string {
+	return "folder-sync-" + folderID.String()
+}
 
-	// Get folder
-	folder, err := s.sharedFolderRepo.GetByID(ctx, folderID)
-	if err != nil {
-		return fmt.Errorf("failed to get folder: %w", err)
+// registerSyncSchedule replaces the folder's scheduler job (empty cron only removes it)
+func (s *SharedFolderServiceImpl) registerSyncSchedule(folderID uuid.UUID, cronExpr string) error {
+	if s.eventScheduler == nil {
+		return fmt.Errorf("scheduler not available")
 	}
 
-	// Get Drive service using folder's tokens (pass expired time to force token refresh)
-	srv, err := s.driveClient.GetDriveService(ctx, folder.DriveAccessToken, folder.DriveRefreshToken, time.Time{})
-	if err != nil {
-		return fmt.Errorf("failed to get drive service: %w", err)
+	jobID := syncScheduleJobID(folderID)
+	if _, exists := s.eventScheduler.GetJob(jobID); exists {
+		s.eventScheduler.RemoveJob(jobID)
 	}
 
-	// Get start page token
-	startPageToken, err := s.driveClient.GetStartPageToken(ctx, srv)
-	if err != nil {
-		return fmt.Errorf("failed to get start page token: %w", err)
+	if cronExpr == "" {
+		return nil
 	}
 
-	// Generate new webhook token if not exists
-	webhookToken := folder.WebhookToken
-	if webhookToken == "" {
-		webhookToken = uuid.New().String()
+	if err := s.eventScheduler.AddJob(jobID, cronExpr, func() {
+		s.runScheduledSync(folderID)
+	}); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSyncSchedule, err)
 	}
+	return nil
+}
 
-	// Register webhook
-	channelID := uuid.New().String()
-	channel, err := s.driveClient.WatchChanges(ctx, srv, channelID, webhookToken, startPageToken)
+// runScheduledSync creates a sync job through the normal path (skipped if one is already pending/running)
+func (s *SharedFolderServiceImpl) runScheduledSync(folderID uuid.UUID) {
+	ctx := context.Background()
+
+	folder, err := s.sharedFolderRepo.GetByID(ctx, folderID)
 	if err != nil {
-		return fmt.Errorf("failed to register webhook: %w", err)
+		// Folder was removed - drop its schedule
+		s.eventScheduler.RemoveJob(syncScheduleJobID(folderID))
+		return
 	}
 
-	// Update folder with webhook info
-	expiry := time.UnixMilli(channel.Expiration)
-	folder.WebhookToken = webhookToken
-	folder.WebhookChannelID = channel.Id
-	folder.WebhookResourceID = channel.ResourceId
-	folder.WebhookExpiry = &expiry
-	folder.PageToken = startPageToken
-	folder.UpdatedAt = time.Now()
+	if folder.DriveRefreshToken == "" {
+		logger.SchedulerWarn("scheduled_sync_skipped", "Skipping scheduled sync - folder has no Drive tokens", map[string]interface{}{
+			"folder_id": folderID.String(),
+		})
+		return
+	}
 
-	if err := s.sharedFolderRepo.Update(ctx, folder.ID, folder); err != nil {
-		return fmt.Errorf("failed to update folder: %w", err)
+	if err := s.createSyncJob(ctx, folder.TokenOwnerID, folderID); err != nil {
+		logger.SchedulerError("scheduled_sync_failed", "Failed to create scheduled sync job", err, map[string]interface{}{
+			"folder_id": folderID.String(),
+		})
+		return
 	}
 
-	logger.Webhook("webhook_registered", "Webhook registered for folder", map[string]interface{}{
-		"folder_id":   folder.ID.String(),
-		"folder_name": folder.DriveFolderName,
-		"channel_id":  channel.Id,
-		"expires":     expiry.Format(time.RFC3339),
+	logger.Sync("scheduled_sync_created", "Created scheduled sync job for folder", map[string]interface{}{
+		"folder_id": folderID.String(),
+		"cron":      folder.SyncScheduleCron,
 	})
-
-	return nil
 }
 
-// triggerSyncForFolder triggers sync for a specific shared folder
-// Returns nil if a job already exists (no error, just skips to avoid duplicates)
-func (s *SharedFolderServiceImpl) triggerSyncForFolder(ctx context.Context, folder *models.SharedFolder) error {
-	// Check if there's already a pending or running job for this folder
-	hasExisting, err := s.syncJobRepo.HasPendingOrRunningJobForFolder(ctx, folder.ID)
-	if err != nil {
-		logger.SyncError("check_existing_job_failed", "Failed to check for existing job", err, map[string]interface{}{
-			"folder_id": folder.ID.String(),
-		})
-		// Continue anyway - worst case we create a duplicate
-	}
-	if hasExisting {
-		logger.Sync("webhook_sync_job_skipped", "Skipping webhook sync - job already pending/running", map[string]interface{}{
-			"folder_id": folder.ID.String(),
-		})
-		// Trigger worker anyway in case existing job is stuck
-		if s.syncWorker != nil {
-			s.syncWorker.TriggerSync()
-		}
-		return nil
+// ErrSyncNotRunning is returned when cancelling a folder that has no running sync job
+var ErrSyncNotRunning = errors.New("no running sync job for this folder")
+
+// CancelSync stops the folder's running sync job. The cancel is flagged on the job, so the
+// instance running it stops it on its next poll; a job running here is stopped right away.
+// The worker saves a checkpoint so the next sync of the folder resumes where this one stopped.
+func (s *SharedFolderServiceImpl) CancelSync(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (*models.SyncJob, error) {
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil || !hasAccess {
+		return nil, services.ErrFolderNotFound
 	}
 
-	// Create sync job using the token owner's ID
-	metadata := worker.SyncJobMetadata{
-		SharedFolderID: folder.ID,
+	job, err := s.syncJobRepo.GetLatestForFolder(ctx, folderID, models.SyncJobStatusRunning)
+	if err != nil {
+		return nil, ErrSyncNotRunning
 	}
-	metadataJSON, err := json.Marshal(metadata)
+	requested, err := s.syncJobRepo.RequestCancel(ctx, job.ID)
 	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+		return nil, fmt.Errorf("failed to cancel sync job: %w", err)
+	}
+	if !requested {
+		return nil, ErrSyncNotRunning // Finished meanwhile
+	}
+	if s.syncWorker != nil {
+		s.syncWorker.CancelJob(job.ID)
+	}
+
+	logger.Sync("sync_cancel_requested", "Sync cancellation requested", map[string]interface{}{
+		"job_id":    job.ID.String(),
+		"folder_id": folderID.String(),
+		"user_id":   userID.String(),
+	})
+
+	return job, nil
+}
+
+// EnableFaceProcessing turns off deferred face processing for a folder and
+// queues all skipped photos for the face worker
+func (s *SharedFolderServiceImpl) EnableFaceProcessing(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (int64, error) {
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil || !hasAccess {
+		return 0, fmt.Errorf("folder not found")
+	}
+
+	// Clear the flag first so photos synced from now on go straight to pending
+	if err := s.sharedFolderRepo.UpdateMetadata(ctx, folderID, map[string]interface{}{
+		"face_processing_deferred": false,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to update folder: %w", err)
+	}
+
+	enabled, err := s.photoRepo.ResetSkippedToPending(ctx, folderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enable face processing: %w", err)
+	}
+
+	logger.Face("face_processing_enabled", "Enabled face processing for folder", map[string]interface{}{
+		"folder_id": folderID.String(),
+		"user_id":   userID.String(),
+		"photos":    enabled,
+	})
+
+	return enabled, nil
+}
+
+// SetFaceProcessingEnabled switches face processing for a folder on or off.
+// Switching it on also clears deferred processing and queues the folder's skipped photos.
+func (s *SharedFolderServiceImpl) SetFaceProcessingEnabled(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, enabled bool) (int64, error) {
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil || !hasAccess {
+		return 0, services.ErrFolderNotFound
+	}
+
+	// Map update so false is written too
+	updates := map[string]interface{}{
+		"face_processing_enabled": enabled,
+	}
+	if enabled {
+		updates["face_processing_deferred"] = false
+	}
+	if err := s.sharedFolderRepo.UpdateMetadata(ctx, folderID, updates); err != nil {
+		return 0, fmt.Errorf("failed to update folder: %w", err)
+	}
+
+	var requeued int64
+	if enabled {
+		requeued, err = s.photoRepo.ResetSkippedToPending(ctx, folderID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to requeue skipped photos: %w", err)
+		}
+	}
+
+	logger.Face("face_processing_toggled", "Updated folder face processing", map[string]interface{}{
+		"folder_id": folderID.String(),
+		"user_id":   userID.String(),
+		"enabled":   enabled,
+		"requeued":  requeued,
+	})
+
+	return requeued, nil
+}
+
+// ErrUploadStorageUnavailable is returned when photos can't be uploaded because Bunny storage is not configured
+var ErrUploadStorageUnavailable = errors.New("upload storage is not configured")
+
+// UploadPhoto stores an uploaded image on Bunny and creates its photo row (source upload).
+// The face worker picks it up like a synced photo but downloads it from Bunny.
+func (s *SharedFolderServiceImpl) UploadPhoto(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, fileName, contentType string, data []byte) (*models.Photo, error) {
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil || !hasAccess {
+		return nil, services.ErrFolderNotFound
+	}
+
+	folder, err := s.sharedFolderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, services.ErrFolderNotFound
+	}
+
+	if s.uploadStorage == nil || !s.uploadStorage.Health().Configured {
+		return nil, ErrUploadStorageUnavailable
+	}
+
+	photoID := uuid.New()
+	storagePath := fmt.Sprintf("uploads/%s/%s%s", folderID, photoID, strings.ToLower(filepath.Ext(fileName)))
+
+	fileURL, err := s.uploadStorage.UploadFile(bytes.NewReader(data), storagePath, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload photo: %w", err)
+	}
+
+	faceStatus := models.FaceStatusPending
+	if folder.FaceProcessingDeferred || !folder.FaceProcessingEnabled {
+		faceStatus = models.FaceStatusSkipped
+	}
+
+	// Images without EXIF (or non-JPEG uploads) simply have no capture metadata
+	exif, _ := utils.ParseExif(data)
+	if exif == nil {
+		exif = &utils.ExifMetadata{}
+	}
+
+	now := time.Now()
+	photo := &models.Photo{
+		ID:             photoID,
+		SharedFolderID: folderID,
+		Source:         models.PhotoSourceUpload,
+		StoragePath:    storagePath,
+		DriveFileID:    models.UploadDriveFileIDPrefix + photoID.String(),
+		FileName:       fileName,
+		MimeType:       contentType,
+		MediaType:      models.MediaTypeImage,
+		FileSize:       int64(len(data)),
+		Width:          exif.Width,
+		Height:         exif.Height,
+		TakenAt:        exif.TakenAt,
+		CameraMake:     exif.CameraMake,
+		CameraModel:    exif.CameraModel,
+		ThumbnailURL:   fileURL,
+		WebViewURL:     fileURL,
+		FaceStatus:     faceStatus,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.photoRepo.Create(ctx, photo); err != nil {
+		// Don't leave an unreferenced file behind
+		s.uploadStorage.DeleteFile(storagePath)
+		return nil, fmt.Errorf("failed to create photo: %w", err)
+	}
+
+	logger.Drive("photo_uploaded", "Photo uploaded to folder", map[string]interface{}{
+		"photo_id":  photoID.String(),
+		"folder_id": folderID.String(),
+		"user_id":   userID.String(),
+		"file_name": fileName,
+		"size":      len(data),
+	})
+
+	return photo, nil
+}
+
+// ErrPhotoNotTrashed is returned when restoring a photo that is not in the trash
+var ErrPhotoNotTrashed = errors.New("photo is not in the trash")
+
+// RestorePhoto clears a photo's trashed state in the database. Drive is not changed, so a
+// photo still trashed or missing in Drive is trashed again by the next sync of its folder.
+// Faces are kept while trashed; a face job interrupted by the trash is queued again.
+func (s *SharedFolderServiceImpl) RestorePhoto(ctx context.Context, userID uuid.UUID, photoID uuid.UUID) (*models.Photo, error) {
+	photo, err := s.photoRepo.GetByID(ctx, photoID)
+	if err != nil || photo == nil {
+		return nil, services.ErrPhotoNotFound
+	}
+
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, photo.SharedFolderID)
+	if err != nil || !hasAccess {
+		return nil, services.ErrPhotoNotFound
+	}
+
+	if !photo.IsTrashed {
+		return nil, ErrPhotoNotTrashed
+	}
+
+	restored, err := s.photoRepo.RestoreByID(ctx, photo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore photo: %w", err)
+	}
+	if !restored {
+		// Restored concurrently (e.g. by a sync that found it in Drive again)
+		return nil, ErrPhotoNotTrashed
+	}
+
+	// The face worker skips trashed photos, so a claimed photo would stay "processing" forever
+	if photo.FaceStatus == models.FaceStatusProcessing {
+		if err := s.photoRepo.UpdateMetadata(ctx, photo.ID, map[string]interface{}{"face_status": models.FaceStatusPending}); err != nil {
+			logger.SyncError("photo_restore_requeue_failed", "Failed to re-queue restored photo for face processing", err, map[string]interface{}{
+				"photo_id": photo.ID.String(),
+			})
+		}
+	}
+
+	logger.Sync("photo_restored", "Photo restored from trash", map[string]interface{}{
+		"photo_id":  photo.ID.String(),
+		"folder_id": photo.SharedFolderID.String(),
+		"user_id":   userID.String(),
+	})
+
+	return s.photoRepo.GetByID(ctx, photo.ID)
+}
+
+// ErrInvalidOrphanPolicy is returned for an orphan policy other than delete, trash or empty
+var ErrInvalidOrphanPolicy = errors.New("invalid orphan policy")
+
+// SetOrphanPolicy sets the folder's orphan cleanup policy (empty = use the global default)
+func (s *SharedFolderServiceImpl) SetOrphanPolicy(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, policy models.OrphanPolicy) error {
+	if policy != "" && !policy.IsValid() {
+		return ErrInvalidOrphanPolicy
+	}
+
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil || !hasAccess {
+		return services.ErrFolderNotFound
+	}
+
+	// Map update so the empty (global default) value is written too
+	if err := s.sharedFolderRepo.UpdateMetadata(ctx, folderID, map[string]interface{}{
+		"orphan_policy": string(policy),
+	}); err != nil {
+		return fmt.Errorf("failed to update folder: %w", err)
+	}
+
+	logger.Sync("orphan_policy_updated", "Updated folder orphan policy", map[string]interface{}{
+		"folder_id":     folderID.String(),
+		"user_id":       userID.String(),
+		"orphan_policy": string(policy),
+	})
+
+	return nil
+}
+
+// SetExcludePaths sets the sub-folder paths sync skips. Paths are trimmed of spaces and slashes
+// and deduplicated. Photos already synced under a new exclude path are kept (orphan cleanup
+// ignores them); files under a removed path are indexed by the next full sync.
+func (s *SharedFolderServiceImpl) SetExcludePaths(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, paths []string) ([]string, error) {
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil || !hasAccess {
+		return nil, services.ErrFolderNotFound
+	}
+
+	normalized := make([]string, 0, len(paths))
+	seen := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		path = strings.Trim(strings.TrimSpace(path), "/")
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		normalized = append(normalized, path)
+	}
+
+	pathsJSON, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode exclude paths: %w", err)
+	}
+	if err := s.sharedFolderRepo.UpdateMetadata(ctx, folderID, map[string]interface{}{
+		"exclude_paths": string(pathsJSON),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update folder: %w", err)
+	}
+
+	logger.Sync("exclude_paths_updated", "Updated folder exclude paths", map[string]interface{}{
+		"folder_id":     folderID.String(),
+		"user_id":       userID.String(),
+		"exclude_paths": normalized,
+	})
+
+	return normalized, nil
+}
+
+// RefreshPhotoMetadata re-fetches thumbnailLink/webViewLink/name for existing photos, and
+// backfills EXIF capture metadata (taken at, camera) of photos that have none yet.
+// Only updates those fields - no face reprocessing, no orphan cleanup
+func (s *SharedFolderServiceImpl) RefreshPhotoMetadata(ctx context.Context, folderID uuid.UUID) (int, error) {
+	folder, err := s.sharedFolderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return 0, fmt.Errorf("folder not found")
+	}
+
+	logger.Sync("refresh_photo_metadata_start", "Refreshing photo metadata", map[string]interface{}{
+		"folder_id":   folder.ID.String(),
+		"folder_name": folder.DriveFolderName,
+	})
+
+	// Refresh the token if needed
+	tokenInfo, wasRefreshed, err := s.driveClient.RefreshTokenIfNeeded(ctx, folder.DriveAccessToken, folder.DriveRefreshToken, time.Time{})
+	if err != nil {
+		return 0, wrapGoogleAuthError(fmt.Errorf("failed to refresh token: %w", err))
+	}
+
+	accessToken := tokenInfo.AccessToken
+	refreshToken := folder.DriveRefreshToken
+	if tokenInfo.RefreshToken != "" {
+		refreshToken = tokenInfo.RefreshToken
+	}
+
+	if wasRefreshed {
+		if err := s.sharedFolderRepo.UpdateTokens(ctx, folder.ID, accessToken, refreshToken, &tokenInfo.Expiry, folder.TokenOwnerID); err != nil {
+			logger.SyncError("refresh_metadata_token_save_failed", "Failed to save refreshed token", err, map[string]interface{}{
+				"folder_id": folder.ID.String(),
+			})
+		}
+	}
+
+	srv, err := s.driveClient.GetDriveServiceWithResourceKey(ctx, accessToken, refreshToken, tokenInfo.Expiry, folder.DriveFolderID, folder.DriveResourceKey)
+	if err != nil {
+		return 0, wrapGoogleAuthError(fmt.Errorf("failed to get drive service: %w", err))
+	}
+
+	// Load existing photos indexed by Drive file ID
+	existing := make(map[string]models.Photo)
+	const pageSize = 500
+	for offset := 0; ; offset += pageSize {
+		photos, _, err := s.photoRepo.GetBySharedFolder(ctx, folder.ID, "", offset, pageSize)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get photos: %w", err)
+		}
+		for _, p := range photos {
+			existing[p.DriveFileID] = p
+		}
+		if len(photos) < pageSize {
+			break
+		}
+	}
+
+	if len(existing) == 0 {
+		return 0, nil
+	}
+
+	// Batch-fetch current metadata via Files.List
+	driveFiles, err := s.driveClient.ListAllImagesRecursive(ctx, srv, folder.DriveFolderID)
+	if err != nil {
+		return 0, wrapGoogleAuthError(fmt.Errorf("failed to list drive files: %w", err))
+	}
+
+	updated := 0
+	for _, f := range driveFiles {
+		photo, ok := existing[f.ID]
+		if !ok {
+			continue // New files are handled by sync
+		}
+
+		// Backfill capture metadata of photos synced before it was requested
+		// (Drive only returns it with GOOGLE_DRIVE_CAPTURE_IMAGE_METADATA on)
+		captureUpdated := false
+		if photo.TakenAt == nil && photo.CameraModel == "" && (f.TakenAt != nil || f.CameraModel != "") {
+			if err := s.photoRepo.UpdateCaptureMetadata(ctx, photo.ID, f.TakenAt, f.CameraMake, f.CameraModel, f.Width, f.Height); err != nil {
+				logger.SyncError("refresh_capture_metadata_failed", "Failed to update photo capture metadata", err, map[string]interface{}{
+					"photo_id":      photo.ID.String(),
+					"drive_file_id": f.ID,
+				})
+			} else {
+				captureUpdated = true
+			}
+		}
+
+		if photo.ThumbnailURL == f.ThumbnailURL && photo.WebViewURL == f.WebViewURL && photo.FileName == f.Name {
+			if captureUpdated {
+				updated++
+			}
+			continue
+		}
+
+		updates := map[string]interface{}{
+			"thumbnail_url":     f.ThumbnailURL,
+			"thumbnail_pending": f.ThumbnailURL == "",
+			"web_view_url":      f.WebViewURL,
+			"file_name":         f.Name,
+			"updated_at":        time.Now(),
+		}
+		if err := s.photoRepo.UpdateMetadata(ctx, photo.ID, updates); err != nil {
+			logger.SyncError("refresh_photo_metadata_failed", "Failed to update photo metadata", err, map[string]interface{}{
+				"photo_id":      photo.ID.String(),
+				"drive_file_id": f.ID,
+			})
+			continue
+		}
+		updated++
+	}
+
+	logger.Sync("refresh_photo_metadata_complete", "Photo metadata refreshed", map[string]interface{}{
+		"folder_id":     folder.ID.String(),
+		"total_photos":  len(existing),
+		"drive_files":   len(driveFiles),
+		"updated_count": updated,
+	})
+
+	return updated, nil
+}
+
+// CreateExport creates an async zip export job for a folder
+// The archive is built server-side and uploaded to storage; progress is broadcast via WebSocket
+func (s *SharedFolderServiceImpl) CreateExport(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, folderPath string) (*models.SyncJob, error) {
+	if s.exportWorker == nil {
+		return nil, fmt.Errorf("export storage is not configured")
+	}
+
+	// Verify user has access
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+	if !hasAccess {
+		return nil, fmt.Errorf("folder not found")
+	}
+
+	metadata := worker.ExportJobMetadata{
+		SharedFolderID: folderID,
+		FolderPath:     folderPath,
+	}
+	metadataJSON, _ := json.Marshal(metadata)
+
+	job := &models.SyncJob{
+		ID:        uuid.New(),
+		UserID:    userID,
+		JobType:   models.SyncJobTypeZipExport,
+		Status:    models.SyncJobStatusPending,
+		Metadata:  string(metadataJSON),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.syncJobRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create export job: %w", err)
+	}
+
+	logger.Sync("export_job_created", "Created zip export job", map[string]interface{}{
+		"job_id":      job.ID.String(),
+		"folder_id":   folderID.String(),
+		"user_id":     userID.String(),
+		"folder_path": folderPath,
+	})
+
+	s.exportWorker.TriggerExport()
+
+	return job, nil
+}
+
+// GetExport returns an export job owned by the user
+func (s *SharedFolderServiceImpl) GetExport(ctx context.Context, userID uuid.UUID, exportID uuid.UUID) (*models.SyncJob, error) {
+	job, err := s.syncJobRepo.GetByID(ctx, exportID)
+	if err != nil {
+		return nil, fmt.Errorf("export not found")
+	}
+
+	if job.JobType != models.SyncJobTypeZipExport || job.UserID != userID {
+		return nil, fmt.Errorf("export not found")
+	}
+
+	return job, nil
+}
+
+// ReconcileFaceCounts fixes photos whose cached face_count no longer matches their faces rows
+// (e.g. after individual faces were deleted or face processing partially failed)
+func (s *SharedFolderServiceImpl) ReconcileFaceCounts(ctx context.Context, folderID uuid.UUID) (int64, error) {
+	if _, err := s.sharedFolderRepo.GetByID(ctx, folderID); err != nil {
+		return 0, services.ErrFolderNotFound
+	}
+
+	fixed, err := s.photoRepo.ReconcileFaceCounts(ctx, &folderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reconcile face counts: %w", err)
+	}
+
+	logger.Face("face_count_reconciled", "Reconciled photo face counts", map[string]interface{}{
+		"folder_id": folderID.String(),
+		"fixed":     fixed,
+	})
+
+	return fixed, nil
+}
+
+// GetPhotoRevisions lists a photo's revisions from Drive (read live, nothing is stored).
+// Uploaded photos have no Drive file, so they report unsupported like files without canReadRevisions.
+func (s *SharedFolderServiceImpl) GetPhotoRevisions(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, photoID uuid.UUID) ([]googledrive.DriveRevision, bool, error) {
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil || !hasAccess {
+		return nil, false, services.ErrFolderNotFound
+	}
+
+	photo, err := s.photoRepo.GetByID(ctx, photoID)
+	if err != nil || photo == nil || photo.SharedFolderID != folderID {
+		return nil, false, services.ErrPhotoNotFound
+	}
+	if photo.Source == models.PhotoSourceUpload {
+		return []googledrive.DriveRevision{}, false, nil
+	}
+
+	folder, err := s.sharedFolderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, false, services.ErrFolderNotFound
+	}
+
+	tokenInfo, wasRefreshed, err := s.driveClient.RefreshTokenIfNeeded(ctx, folder.DriveAccessToken, folder.DriveRefreshToken, time.Time{})
+	if err != nil {
+		return nil, false, wrapGoogleAuthError(fmt.Errorf("failed to refresh token: %w", err))
+	}
+
+	accessToken := tokenInfo.AccessToken
+	refreshToken := folder.DriveRefreshToken
+	if tokenInfo.RefreshToken != "" {
+		refreshToken = tokenInfo.RefreshToken
+	}
+
+	if wasRefreshed {
+		if err := s.sharedFolderRepo.UpdateTokens(ctx, folder.ID, accessToken, refreshToken, &tokenInfo.Expiry, folder.TokenOwnerID); err != nil {
+			logger.SyncError("photo_revisions_token_save_failed", "Failed to save refreshed token", err, map[string]interface{}{
+				"folder_id": folder.ID.String(),
+			})
+		}
+	}
+
+	srv, err := s.driveClient.GetDriveServiceWithResourceKey(ctx, accessToken, refreshToken, tokenInfo.Expiry, folder.DriveFolderID, folder.DriveResourceKey)
+	if err != nil {
+		return nil, false, wrapGoogleAuthError(fmt.Errorf("failed to get drive service: %w", err))
+	}
+	ctx = googledrive.WithAccount(ctx, folder.TokenOwnerID)
+
+	revisions, supported, err := s.driveClient.ListRevisions(ctx, srv, photo.DriveFileID)
+	if err != nil {
+		if googledrive.IsAccessDenied(err) {
+			// The file is gone or no longer readable with the folder's token
+			return []googledrive.DriveRevision{}, false, nil
+		}
+		return nil, false, wrapGoogleAuthError(err)
+	}
+	return revisions, supported, nil
+}
+
+// reconcileSampleSize is how many Drive file IDs of each kind a reconcile report lists
+const reconcileSampleSize = 50
+
+// Reconcile lists the folder's Drive files like a full sync and compares them with its
+// non-trashed photos, reporting drift without changing photos or the page token
+func (s *SharedFolderServiceImpl) Reconcile(ctx context.Context, folderID uuid.UUID) (*services.FolderReconcileReport, error) {
+	folder, err := s.sharedFolderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, services.ErrFolderNotFound
+	}
+
+	tokenInfo, wasRefreshed, err := s.driveClient.RefreshTokenIfNeeded(ctx, folder.DriveAccessToken, folder.DriveRefreshToken, time.Time{})
+	if err != nil {
+		return nil, wrapGoogleAuthError(fmt.Errorf("failed to refresh token: %w", err))
+	}
+
+	accessToken := tokenInfo.AccessToken
+	refreshToken := folder.DriveRefreshToken
+	if tokenInfo.RefreshToken != "" {
+		refreshToken = tokenInfo.RefreshToken
+	}
+
+	if wasRefreshed {
+		if err := s.sharedFolderRepo.UpdateTokens(ctx, folder.ID, accessToken, refreshToken, &tokenInfo.Expiry, folder.TokenOwnerID); err != nil {
+			logger.SyncError("reconcile_token_save_failed", "Failed to save refreshed token", err, map[string]interface{}{
+				"folder_id": folder.ID.String(),
+			})
+		}
+	}
+
+	srv, err := s.driveClient.GetDriveServiceWithResourceKey(ctx, accessToken, refreshToken, tokenInfo.Expiry, folder.DriveFolderID, folder.DriveResourceKey)
+	if err != nil {
+		return nil, wrapGoogleAuthError(fmt.Errorf("failed to get drive service: %w", err))
+	}
+
+	// Listing a large folder is many Drive calls: share the owner's rate limit with the workers
+	ctx = googledrive.WithAccount(ctx, folder.TokenOwnerID)
+
+	files, err := s.driveClient.ListAllImagesRecursive(ctx, srv, folder.DriveFolderID)
+	if err != nil {
+		return nil, wrapGoogleAuthError(fmt.Errorf("failed to list files: %w", err))
+	}
+
+	// Sync never indexes files under exclude paths, so they are not missing
+	var folderPathMap map[string]string
+	if len(folder.ExcludePaths) > 0 {
+		allFolders, err := s.driveClient.ListAllFoldersRecursive(ctx, srv, folder.DriveFolderID)
+		if err != nil {
+			return nil, wrapGoogleAuthError(fmt.Errorf("failed to list folders: %w", err))
+		}
+		folderPathMap = s.driveClient.BuildFolderPathMap(allFolders, folder.DriveFolderID)
 	}
 
+	dbIDs, err := s.photoRepo.GetDriveFileIDsBySharedFolder(ctx, folder.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get photos: %w", err)
+	}
+	inDB := make(map[string]bool, len(dbIDs))
+	for _, id := range dbIDs {
+		inDB[id] = true
+	}
+
+	report := &services.FolderReconcileReport{
+		FolderID:       folder.ID,
+		DriveFileCount: len(files),
+		DBPhotoCount:   len(dbIDs),
+		MissingSample:  []string{},
+		OrphanedSample: []string{},
+		CheckedAt:      time.Now(),
+	}
+
+	inDrive := make(map[string]bool, len(files))
+	for _, file := range files {
+		inDrive[file.ID] = true
+		if inDB[file.ID] {
+			continue
+		}
+		if folderPathMap != nil && folder.IsPathExcluded(folderPathMap[file.ParentID]) {
+			report.ExcludedInDrive++
+			continue
+		}
+		report.MissingInDB++
+		if len(report.MissingSample) < reconcileSampleSize {
+			report.MissingSample = append(report.MissingSample, file.ID)
+		}
+	}
+
+	for _, id := range dbIDs {
+		if inDrive[id] {
+			continue
+		}
+		report.OrphanedInDB++
+		if len(report.OrphanedSample) < reconcileSampleSize {
+			report.OrphanedSample = append(report.OrphanedSample, id)
+		}
+	}
+
+	logger.Sync("folder_reconciled", "Compared Drive listing with folder photos", map[string]interface{}{
+		"folder_id":         folder.ID.String(),
+		"drive_file_count":  report.DriveFileCount,
+		"db_photo_count":    report.DBPhotoCount,
+		"missing_in_db":     report.MissingInDB,
+		"orphaned_in_db":    report.OrphanedInDB,
+		"excluded_in_drive": report.ExcludedInDrive,
+	})
+
+	return report, nil
+}
+
+// Errors returned by RetrySyncJob
+var (
+	ErrSyncJobNotFound   = errors.New("sync job not found")
+	ErrSyncJobNotFailed  = errors.New("only failed sync jobs can be retried")
+	ErrSyncJobInProgress = errors.New("folder already has a pending or running sync job")
+)
+
+// ListFailedSyncJobs returns failed drive sync jobs, most recent first
+func (s *SharedFolderServiceImpl) ListFailedSyncJobs(ctx context.Context, page, limit int) ([]models.SyncJob, int64, error) {
+	offset := (page - 1) * limit
+	jobs, total, err := s.syncJobRepo.GetFailedJobs(ctx, models.SyncJobTypeDriveSync, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get failed sync jobs: %w", err)
+	}
+	return jobs, total, nil
+}
+
+// RetrySyncJob requeues a failed drive sync job and wakes the sync worker
+func (s *SharedFolderServiceImpl) RetrySyncJob(ctx context.Context, jobID uuid.UUID) (*models.SyncJob, error) {
+	job, err := s.syncJobRepo.GetByID(ctx, jobID)
+	if err != nil || job.JobType != models.SyncJobTypeDriveSync {
+		return nil, ErrSyncJobNotFound
+	}
+
+	if job.Status != models.SyncJobStatusFailed {
+		return nil, ErrSyncJobNotFailed
+	}
+
+	// Don't run two syncs for the same folder at once
+	folderID := dto.SyncJobFolderID(job)
+	if folderID != uuid.Nil {
+		hasExisting, err := s.syncJobRepo.HasPendingOrRunningJobForFolder(ctx, folderID)
+		if err == nil && hasExisting {
+			return nil, ErrSyncJobInProgress
+		}
+	}
+
+	// Metadata is kept, so a full sync resumes from its last checkpoint
+	if err := s.syncJobRepo.Requeue(ctx, job.ID); err != nil {
+		return nil, fmt.Errorf("failed to requeue sync job: %w", err)
+	}
+
+	logger.Sync("sync_job_retried", "Requeued failed sync job", map[string]interface{}{
+		"job_id":     job.ID.String(),
+		"folder_id":  folderID.String(),
+		"last_error": job.LastError,
+	})
+
+	if s.syncWorker != nil {
+		s.syncWorker.TriggerSync()
+	}
+
+	return s.syncJobRepo.GetByID(ctx, job.ID)
+}
+
+// ListUserFailedSyncJobs returns failed drive sync jobs of the folders the user can access, most recent first
+func (s *SharedFolderServiceImpl) ListUserFailedSyncJobs(ctx context.Context, userID uuid.UUID, page, limit int) ([]models.SyncJob, int64, error) {
+	offset := (page - 1) * limit
+	jobs, total, err := s.syncJobRepo.GetFailedByUser(ctx, userID, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get failed sync jobs: %w", err)
+	}
+	return jobs, total, nil
+}
+
+// RetryUserSyncJob requeues a failed sync job of a folder the user can access
+// Jobs of other folders are reported as not found
+func (s *SharedFolderServiceImpl) RetryUserSyncJob(ctx context.Context, userID uuid.UUID, jobID uuid.UUID) (*models.SyncJob, error) {
+	job, err := s.syncJobRepo.GetByID(ctx, jobID)
+	if err != nil || job.JobType != models.SyncJobTypeDriveSync {
+		return nil, ErrSyncJobNotFound
+	}
+
+	folderID := dto.SyncJobFolderID(job)
+	if folderID == uuid.Nil {
+		return nil, ErrSyncJobNotFound
+	}
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil || !hasAccess {
+		return nil, ErrSyncJobNotFound
+	}
+
+	return s.RetrySyncJob(ctx, jobID)
+}
+
+// createSyncJob creates a new sync job for a folder
+// Returns nil if a job is already pending (no error, just skips). A running job still gets one
+// pending job queued behind it so changes made during the run are picked up afterwards.
+func (s *SharedFolderServiceImpl) createSyncJob(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) error {
+	// Create metadata JSON
+	metadata := worker.SyncJobMetadata{
+		SharedFolderID: folderID,
+	}
+
+	// Resume a cancelled full sync from its checkpoint
+	if cancelled, err := s.syncJobRepo.GetLatestForFolder(ctx, folderID); err == nil && cancelled.Status == models.SyncJobStatusCancelled {
+		var checkpoint worker.SyncJobMetadata
+		if json.Unmarshal([]byte(cancelled.Metadata), &checkpoint) == nil && checkpoint.LastProcessedID != "" {
+			metadata.LastProcessedID = checkpoint.LastProcessedID
+			metadata.ProcessedFiles = checkpoint.ProcessedFiles
+			logger.Sync("sync_resume_checkpoint", "Resuming cancelled sync from checkpoint", map[string]interface{}{
+				"folder_id":       folderID.String(),
+				"cancelled_job":   cancelled.ID.String(),
+				"processed_files": checkpoint.ProcessedFiles,
+			})
+		}
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	// Create sync job
 	now := time.Now()
 	job := &models.SyncJob{
-		ID:        uuid.New(),
-		UserID:    folder.TokenOwnerID, // Use token owner
-		JobType:   models.SyncJobTypeDriveSync,
-		Status:    models.SyncJobStatusPending,
-		Metadata:  string(metadataJSON),
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:             uuid.New(),
+		UserID:         userID,
+		JobType:        models.SyncJobTypeDriveSync,
+		Status:         models.SyncJobStatusPending,
+		SharedFolderID: &folderID,
+		Metadata:       string(metadataJSON),
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 
-	if err := s.syncJobRepo.Create(ctx, job); err != nil {
+	created, err := s.syncJobRepo.CreatePendingForFolder(ctx, job)
+	if err != nil {
 		return fmt.Errorf("failed to create sync job: %w", err)
 	}
 
-	// Trigger sync worker immediately
+	if created {
+		logger.Sync("sync_job_created", "Created sync job for shared folder", map[string]interface{}{
+			"job_id":    job.ID.String(),
+			"folder_id": folderID.String(),
+		})
+	} else {
+		logger.Sync("sync_job_skipped", "Skipping - sync job already pending for folder", map[string]interface{}{
+			"folder_id": folderID.String(),
+		})
+	}
+
+	// Trigger sync worker immediately (also nudges an existing pending job)
 	if s.syncWorker != nil {
 		s.syncWorker.TriggerSync()
 	}
 
-	logger.Sync("webhook_sync_job_created", "Created sync job for shared folder", map[string]interface{}{
-		"job_id":    job.ID.String(),
-		"folder_id": folder.ID.String(),
+	return nil
+}
+
+// GetSyncStatus returns the current sync status of a folder
+func (s *SharedFolderServiceImpl) GetSyncStatus(ctx context.Context, folderID uuid.UUID) (*models.SharedFolder, error) {
+	return s.sharedFolderRepo.GetByID(ctx, folderID)
+}
+
+// HandleWebhook handles webhook notifications for shared folders
+func (s *SharedFolderServiceImpl) HandleWebhook(ctx context.Context, channelID, resourceID, resourceState, token string) error {
+	logger.Webhook("shared_folder_webhook_received", "SharedFolder HandleWebhook", map[string]interface{}{
+		"channel_id":     channelID,
+		"resource_state": resourceState,
+		"token_length":   len(token),
+	})
+
+	// Find shared folder by webhook token
+	folder, err := s.sharedFolderRepo.GetByWebhookToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return services.ErrUnknownWebhookToken
+		}
+		return fmt.Errorf("failed to look up webhook token: %w", err)
+	}
+
+	logger.Webhook("shared_folder_found", "Found shared folder for webhook token", map[string]interface{}{
+		"folder_id":   folder.ID.String(),
+		"folder_name": folder.DriveFolderName,
+	})
+
+	// Only trigger sync for "change" events, NOT "sync" events
+	// "sync" is just Google's test notification when webhook is registered
+	// "change" is the actual notification when files/folders change
+	if resourceState == "change" {
+		logger.Webhook("shared_folder_trigger_sync", "Triggering sync for shared folder (change event)", map[string]interface{}{
+			"folder_id":      folder.ID.String(),
+			"resource_state": resourceState,
+		})
+
+		// Get the token owner to trigger sync
+		if err := s.triggerSyncForFolder(ctx, folder); err != nil {
+			logger.WebhookError("shared_folder_sync_failed", "Failed to trigger sync", err, map[string]interface{}{
+				"folder_id": folder.ID.String(),
+			})
+			return fmt.Errorf("failed to trigger sync: %w", err)
+		}
+		logger.Webhook("shared_folder_sync_triggered", "Sync triggered successfully for shared folder", map[string]interface{}{
+			"folder_id": folder.ID.String(),
+		})
+	} else if resourceState == "sync" {
+		// "sync" is just Google testing if our endpoint works - no action needed
+		logger.Webhook("shared_folder_webhook_sync_ack", "Received sync acknowledgment (no action)", map[string]interface{}{
+			"folder_id":      folder.ID.String(),
+			"resource_state": resourceState,
+		})
+	} else {
+		logger.Webhook("shared_folder_webhook_ignored", "Ignoring webhook with unknown state", map[string]interface{}{
+			"folder_id":      folder.ID.String(),
+			"resource_state": resourceState,
+		})
+	}
+
+	return nil
+}
+
+// RegisterWebhook registers a webhook for an existing folder
+func (s *SharedFolderServiceImpl) RegisterWebhook(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) error {
+	// Verify user has access
+	hasAccess, err := s.sharedFolderRepo.HasUserAccess(ctx, userID, folderID)
+	if err != nil {
+		return fmt.Errorf("failed to check access: %w", err)
+	}
+	if !hasAccess {
+		return fmt.Errorf("folder not found")
+	}
+
+	// Get folder
+	folder, err := s.sharedFolderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return fmt.Errorf("failed to get folder: %w", err)
+	}
+
+	// Get Drive service using folder's tokens (pass expired time to force token refresh)
+	srv, err := s.driveClient.GetDriveService(ctx, folder.DriveAccessToken, folder.DriveRefreshToken, time.Time{})
+	if err != nil {
+		return fmt.Errorf("failed to get drive service: %w", err)
+	}
+
+	// Get start page token
+	startPageToken, err := s.driveClient.GetStartPageToken(ctx, srv)
+	if err != nil {
+		return fmt.Errorf("failed to get start page token: %w", err)
+	}
+
+	// Generate new webhook token if not exists
+	webhookToken := folder.WebhookToken
+	if webhookToken == "" {
+		webhookToken = uuid.New().String()
+	}
+
+	// Register webhook
+	channelID := uuid.New().String()
+	channel, err := s.driveClient.WatchChanges(ctx, srv, channelID, webhookToken, startPageToken)
+	if err != nil {
+		return fmt.Errorf("failed to register webhook: %w", err)
+	}
+
+	// Update folder with webhook info
+	expiry := time.UnixMilli(channel.Expiration)
+	folder.WebhookToken = webhookToken
+	folder.WebhookChannelID = channel.Id
+	folder.WebhookResourceID = channel.ResourceId
+	folder.WebhookExpiry = &expiry
+	folder.WebhookSigned = true
+	folder.PageToken = startPageToken
+	folder.UpdatedAt = time.Now()
+
+	if err := s.sharedFolderRepo.Update(ctx, folder.ID, folder); err != nil {
+		return fmt.Errorf("failed to update folder: %w", err)
+	}
+
+	logger.Webhook("webhook_registered", "Webhook registered for folder", map[string]interface{}{
+		"folder_id":   folder.ID.String(),
+		"folder_name": folder.DriveFolderName,
+		"channel_id":  channel.Id,
+		"expires":     expiry.Format(time.RFC3339),
 	})
+
 	return nil
 }
 
-// RenewExpiringWebhooks renews webhooks that are about to expire
+// triggerSyncForFolder triggers sync for a specific shared folder using the token owner's ID
+// Webhook bursts collapse into the folder's single pending job (see createSyncJob)
+func (s *SharedFolderServiceImpl) triggerSyncForFolder(ctx context.Context, folder *models.SharedFolder) error {
+	return s.createSyncJob(ctx, folder.TokenOwnerID, folder.ID)
+}
+
+// RenewExpiringWebhooks renews webhooks that are about to expire, and legacy channels registered
+// before channel tokens were signed (their notifications are rejected while signing is required)
 // Returns the number of renewed and failed webhooks
 func (s *SharedFolderServiceImpl) RenewExpiringWebhooks(ctx context.Context) (renewed int, failed int, err error) {
-	// Get folders with webhooks expiring in the next 2 days
-	expiryThreshold := time.Now().Add(48 * time.Hour)
+	// Get folders with webhooks expiring within the renewal window
+	expiryThreshold := time.Now().Add(s.webhookRenewalWindow)
 
 	logger.Scheduler("webhook_renewal_start", "Starting webhook renewal check", map[string]interface{}{
 		"expiry_threshold": expiryThreshold.Format(time.RFC3339),
@@ -914,9 +1987,24 @@ func (s *SharedFolderServiceImpl) renewWebhookForFolder(ctx context.Context, fol
 		return fmt.Errorf("failed to get drive service: %w", err)
 	}
 
-	// Stop the old webhook first (ignore errors - it might already be expired)
-	if folder.WebhookChannelID != "" && folder.WebhookResourceID != "" {
-		_ = s.driveClient.StopWatch(ctx, srv, folder.WebhookChannelID, folder.WebhookResourceID)
+	// Stop the old channel first so renewed folders don't leave live channels behind in Google.
+	// A failure is only logged - the channel may already be expired
+	oldChannelID := folder.WebhookChannelID
+	if oldChannelID != "" && folder.WebhookResourceID != "" {
+		if err := s.driveClient.StopWatch(ctx, srv, oldChannelID, folder.WebhookResourceID); err != nil {
+			logger.SchedulerWarn("webhook_stop_failed", "Failed to stop old webhook channel", map[string]interface{}{
+				"folder_id":   folder.ID.String(),
+				"channel_id":  oldChannelID,
+				"resource_id": folder.WebhookResourceID,
+				"error":       err.Error(),
+			})
+		} else {
+			logger.Scheduler("webhook_stopped", "Stopped old webhook channel", map[string]interface{}{
+				"folder_id":   folder.ID.String(),
+				"channel_id":  oldChannelID,
+				"resource_id": folder.WebhookResourceID,
+			})
+		}
 	}
 
 	// Get new start page token
@@ -937,6 +2025,7 @@ func (s *SharedFolderServiceImpl) renewWebhookForFolder(ctx context.Context, fol
 	folder.WebhookChannelID = channel.Id
 	folder.WebhookResourceID = channel.ResourceId
 	folder.WebhookExpiry = &expiry
+	folder.WebhookSigned = true
 	folder.PageToken = startPageToken
 	folder.UpdatedAt = time.Now()
 
@@ -944,5 +2033,70 @@ func (s *SharedFolderServiceImpl) renewWebhookForFolder(ctx context.Context, fol
 		return fmt.Errorf("failed to update folder: %w", err)
 	}
 
+	logger.Scheduler("webhook_registered", "Registered new webhook channel", map[string]interface{}{
+		"folder_id":      folder.ID.String(),
+		"channel_id":     channel.Id,
+		"resource_id":    channel.ResourceId,
+		"old_channel_id": oldChannelID,
+		"expiry":         expiry.Format(time.RFC3339),
+	})
+
+	return nil
+}
+
+// StopWebhookChannel stops a Drive watch channel by ID using the folder's tokens. It is a manual
+// cleanup for channels the renewal job does not know about (e.g. left behind by failed renewals)
+func (s *SharedFolderServiceImpl) StopWebhookChannel(ctx context.Context, folderID uuid.UUID, channelID, resourceID string) error {
+	folder, err := s.sharedFolderRepo.GetByID(ctx, folderID)
+	if err != nil || folder == nil {
+		return services.ErrFolderNotFound
+	}
+
+	tokenInfo, wasRefreshed, err := s.driveClient.RefreshTokenIfNeeded(ctx, folder.DriveAccessToken, folder.DriveRefreshToken, time.Time{})
+	if err != nil {
+		return wrapGoogleAuthError(fmt.Errorf("failed to refresh token: %w", err))
+	}
+
+	accessToken := tokenInfo.AccessToken
+	refreshToken := folder.DriveRefreshToken
+	if tokenInfo.RefreshToken != "" {
+		refreshToken = tokenInfo.RefreshToken
+	}
+
+	if wasRefreshed {
+		if err := s.sharedFolderRepo.UpdateTokens(ctx, folder.ID, accessToken, refreshToken, &tokenInfo.Expiry, folder.TokenOwnerID); err != nil {
+			logger.SchedulerWarn("webhook_stop_token_save_failed", "Failed to save refreshed token", map[string]interface{}{
+				"folder_id": folder.ID.String(),
+				"error":     err.Error(),
+			})
+		}
+	}
+
+	srv, err := s.driveClient.GetDriveService(ctx, accessToken, refreshToken, tokenInfo.Expiry)
+	if err != nil {
+		return wrapGoogleAuthError(fmt.Errorf("failed to get drive service: %w", err))
+	}
+	ctx = googledrive.WithAccount(ctx, folder.TokenOwnerID)
+
+	// Stopping the folder's active channel silences its notifications until the next renewal
+	active := channelID == folder.WebhookChannelID
+	if err := s.driveClient.StopWatch(ctx, srv, channelID, resourceID); err != nil {
+		logger.SchedulerWarn("webhook_manual_stop_failed", "Failed to stop webhook channel", map[string]interface{}{
+			"folder_id":   folder.ID.String(),
+			"channel_id":  channelID,
+			"resource_id": resourceID,
+			"active":      active,
+			"error":       err.Error(),
+		})
+		return fmt.Errorf("failed to stop channel: %w", err)
+	}
+
+	logger.Scheduler("webhook_manual_stopped", "Stopped webhook channel manually", map[string]interface{}{
+		"folder_id":   folder.ID.String(),
+		"channel_id":  channelID,
+		"resource_id": resourceID,
+		"active":      active,
+	})
+
 	return nil
 }
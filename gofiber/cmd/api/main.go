@@ -49,10 +49,10 @@ import (
 
 func main() {
 	// Initialize logger
+	// Output mode (file/stdout/both) is applied once config is loaded
 	if err := logger.Init("logs", true); err != nil {
 		fmt.Printf("Warning: Failed to initialize logger: %v\n", err)
 	}
-	logger.Startup("logger_init", "Logger initialized - logs will be written to ./logs/", nil)
 
 	// Initialize DI container
 	container := di.NewContainer()
@@ -70,6 +70,7 @@ func main() {
 	app := fiber.New(fiber.Config{
 		ErrorHandler: middleware.ErrorHandler(),
 		AppName:      container.GetConfig().App.Name,
+		BodyLimit:    container.GetConfig().App.BodyLimitMB * 1024 * 1024,
 	})
 
 	// Setup middleware
@@ -93,6 +94,7 @@ func main() {
 	services := container.GetHandlerServices()
 	repos := container.GetHandlerRepositories()
 	h := handlers.NewHandlers(services, repos, container.GetConfig())
+	h.Face.SetFaceWorker(container.FaceWorker)
 
 	// Create health handler (for detailed health check)
 	healthHandler := handlers.NewHealthHandler(
@@ -100,6 +102,8 @@ func main() {
 		container.RedisClient,
 		container.FaceClient,
 		container.PhotoRepository,
+		container.BunnyStorage,
+		container.GoogleDrive.AccountLimiter(),
 	)
 
 	// Setup routes
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gofiber-template/domain/models"
+)
+
+// BulkAssignFacesRequest is the request for assigning many faces to a person
+type BulkAssignFacesRequest struct {
+	FaceIDs  []uuid.UUID `json:"face_ids" validate:"required,min=1"`
+	PersonID uuid.UUID   `json:"person_id" validate:"required"`
+}
+
+// CurationActionResponse is the DTO for a recorded curation action
+type CurationActionResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	ActionType string     `json:"action_type"`
+	ItemCount  int        `json:"item_count"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UndoneAt   *time.Time `json:"undone_at,omitempty"`
+	UndoBefore time.Time  `json:"undo_before"` // Undo is rejected after this time
+}
+
+// BulkAssignFacesResponse reports which faces were assigned and which were skipped
+type BulkAssignFacesResponse struct {
+	Action        *CurationActionResponse `json:"action"` // null when nothing was assigned
+	AssignedCount int                     `json:"assigned_count"`
+	Inaccessible  []uuid.UUID             `json:"inaccessible_face_ids"`
+	NotFound      []uuid.UUID             `json:"not_found_face_ids"`
+}
+
+// CurationActionToResponse converts a CurationAction to response DTO
+func CurationActionToResponse(action *models.CurationAction, undoWindow time.Duration) *CurationActionResponse {
+	if action == nil {
+		return nil
+	}
+	return &CurationActionResponse{
+		ID:         action.ID,
+		ActionType: string(action.ActionType),
+		ItemCount:  action.ItemCount,
+		CreatedAt:  action.CreatedAt,
+		UndoneAt:   action.UndoneAt,
+		UndoBefore: action.CreatedAt.Add(undoWindow),
+	}
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gofiber-template/domain/models"
+)
+
+// CreateExportRequest is the request for creating a folder zip export
+type CreateExportRequest struct {
+	FolderPath string `json:"folder_path,omitempty"` // Optional sub-folder filter
+}
+
+// ExportJobResponse is the DTO for zip export job status
+type ExportJobResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	SharedFolderID uuid.UUID  `json:"shared_folder_id"`
+	FolderPath     string     `json:"folder_path,omitempty"`
+	Status         string     `json:"status"`
+	TotalFiles     int        `json:"total_files"`
+	ProcessedFiles int        `json:"processed_files"`
+	FailedFiles    int        `json:"failed_files"`
+	DownloadURL    string     `json:"download_url,omitempty"`
+	FileSize       int64      `json:"file_size,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+}
+
+// ExportJobToResponse converts a zip export SyncJob to response DTO
+func ExportJobToResponse(job *models.SyncJob) *ExportJobResponse {
+	if job == nil {
+		return nil
+	}
+
+	var metadata struct {
+		SharedFolderID uuid.UUID `json:"shared_folder_id"`
+		FolderPath     string    `json:"folder_path"`
+		DownloadURL    string    `json:"download_url"`
+		FileSize       int64     `json:"file_size"`
+	}
+	if job.Metadata != "" {
+		json.Unmarshal([]byte(job.Metadata), &metadata)
+	}
+
+	return &ExportJobResponse{
+		ID:             job.ID,
+		SharedFolderID: metadata.SharedFolderID,
+		FolderPath:     metadata.FolderPath,
+		Status:         string(job.Status),
+		TotalFiles:     job.TotalItems,
+		ProcessedFiles: job.ProcessedItems,
+		FailedFiles:    job.FailedItems,
+		DownloadURL:    metadata.DownloadURL,
+		FileSize:       metadata.FileSize,
+		LastError:      job.LastError,
+		CreatedAt:      job.CreatedAt,
+		CompletedAt:    job.CompletedAt,
+	}
+}
@@ -157,12 +157,21 @@ func PhotoToPhotoResponse(photo *models.Photo) *PhotoResponse {
 		DriveFileID:     photo.DriveFileID,
 		FileName:        photo.FileName,
 		MimeType:        photo.MimeType,
+		MediaType:       string(photo.MediaType),
+		Source:          string(photo.Source),
 		ThumbnailURL:    photo.ThumbnailURL,
 		WebViewURL:      photo.WebViewURL,
 		DriveFolderPath: photo.DriveFolderPath,
 		FaceStatus:      string(photo.FaceStatus),
 		FaceCount:       photo.FaceCount,
+		ThumbnailReady:  !photo.ThumbnailPending,
 		CreatedAt:       photo.CreatedAt,
+		TakenAt:         photo.TakenAt,
+		CameraMake:      photo.CameraMake,
+		CameraModel:     photo.CameraModel,
+		Width:           photo.Width,
+		Height:          photo.Height,
+		TrashedAt:       photo.TrashedAt,
 	}
 }
 
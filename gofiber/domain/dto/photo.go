@@ -13,12 +13,27 @@ type PhotoResponse struct {
 	DriveFileID     string    `json:"drive_file_id"`
 	FileName        string    `json:"file_name"`
 	MimeType        string    `json:"mime_type"`
+	MediaType       string    `json:"media_type"` // "image" or "video"
+	Source          string    `json:"source"`     // "drive" or "upload" (uploaded directly to the app)
 	ThumbnailURL    string    `json:"thumbnail_url"`
 	WebViewURL      string    `json:"web_view_url"`
 	DriveFolderPath string    `json:"drive_folder_path"`
 	FaceStatus      string    `json:"face_status"`
 	FaceCount       int       `json:"face_count"`
+	ThumbnailReady  bool      `json:"thumbnail_ready"` // false = placeholder, thumbnail endpoint answers 202 until ready
 	CreatedAt       time.Time `json:"created_at"`
+
+	// Capture metadata from EXIF (omitted when unknown)
+	TakenAt     *time.Time `json:"taken_at,omitempty"`
+	CameraMake  string     `json:"camera_make,omitempty"`
+	CameraModel string     `json:"camera_model,omitempty"`
+	Width       int        `json:"width,omitempty"`
+	Height      int        `json:"height,omitempty"`
+
+	TrashedAt *time.Time `json:"trashed_at,omitempty"` // Only set for trashed photos
+
+	// Short-lived signed thumbnail URL (only set when signed URLs are enabled)
+	SignedThumbnailURL string `json:"signed_thumbnail_url,omitempty"`
 }
 
 // PhotoListResponse is the DTO for paginated photo list
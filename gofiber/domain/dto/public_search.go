@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+// CreatePublicSearchLinkRequest is the request for minting a public event search link
+type CreatePublicSearchLinkRequest struct {
+	ExpiresInHours int `json:"expires_in_hours"`
+	MaxResults     int `json:"max_results"`
+}
+
+// PublicSearchLinkResponse is the DTO for a public search link (returned to the folder owner)
+type PublicSearchLinkResponse struct {
+	ID             uuid.UUID `json:"id"`
+	SharedFolderID uuid.UUID `json:"shared_folder_id"`
+	Token          string    `json:"token"`
+	SearchPath     string    `json:"search_path"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	MaxResults     int       `json:"max_results"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PublicSearchMatchResponse is a match shown to anonymous users (thumbnail only, no Drive IDs or tokens)
+type PublicSearchMatchResponse struct {
+	PhotoID      string  `json:"photo_id"`
+	FileName     string  `json:"file_name"`
+	ThumbnailURL string  `json:"thumbnail_url"`
+	BboxX        float64 `json:"bbox_x"`
+	BboxY        float64 `json:"bbox_y"`
+	BboxWidth    float64 `json:"bbox_width"`
+	BboxHeight   float64 `json:"bbox_height"`
+	Similarity   float64 `json:"similarity"`
+}
+
+// PublicSearchLinkToResponse converts a PublicSearchLink to response DTO
+func PublicSearchLinkToResponse(link *models.PublicSearchLink) *PublicSearchLinkResponse {
+	if link == nil {
+		return nil
+	}
+	return &PublicSearchLinkResponse{
+		ID:             link.ID,
+		SharedFolderID: link.SharedFolderID,
+		Token:          link.Token,
+		SearchPath:     "/api/v1/public/events/" + link.Token + "/search",
+		ExpiresAt:      link.ExpiresAt,
+		MaxResults:     link.MaxResults,
+		CreatedAt:      link.CreatedAt,
+	}
+}
@@ -29,15 +29,55 @@ type SharedFolderResponse struct {
 	Children        []SubFolderInfo `json:"children,omitempty"`
 	CreatedAt       time.Time       `json:"created_at"`
 
+	// Classified last sync error: token_expired, permission_denied, rate_limited, drive_unavailable, unknown
+	LastSyncErrorCode   string                  `json:"last_sync_error_code,omitempty"`
+	LastSyncErrorDetail *models.SyncErrorDetail `json:"last_sync_error_detail,omitempty"` // Parsed Google API error
+
 	// Webhook status
 	WebhookStatus string     `json:"webhook_status"`           // "active", "expiring", "expired", "inactive"
 	WebhookExpiry *time.Time `json:"webhook_expiry,omitempty"` // When webhook expires
+
+	// Face processing
+	FaceProcessingDeferred bool `json:"face_processing_deferred"` // Photos are skipped until face processing is enabled
+	FaceProcessingEnabled  bool `json:"face_processing_enabled"`  // false = face search is off for this folder
+
+	// Scheduled sync (cron, UTC) in addition to webhooks
+	SyncScheduleCron string `json:"sync_schedule_cron,omitempty"`
+
+	// Orphan cleanup policy override ("delete", "trash"; empty = global default)
+	OrphanPolicy string `json:"orphan_policy,omitempty"`
+
+	// Sub-folder paths skipped by sync (relative to the folder)
+	ExcludePaths []string `json:"exclude_paths,omitempty"`
 }
 
 // AddFolderRequest is the request for adding a new folder
 type AddFolderRequest struct {
 	DriveFolderID    string `json:"drive_folder_id" validate:"required"`
 	DriveResourceKey string `json:"drive_resource_key,omitempty"` // For older shared folders (pre-2021)
+
+	// Defer face processing on first sync (photos are created as skipped until enabled)
+	DeferFaceProcessing bool `json:"defer_face_processing,omitempty"`
+}
+
+// UpdateFolderRequest updates folder settings (omitted fields are left unchanged)
+type UpdateFolderRequest struct {
+	FaceProcessingEnabled *bool `json:"face_processing_enabled,omitempty"` // Switching on queues skipped photos for face processing
+}
+
+// SetSyncScheduleRequest sets a folder's periodic sync schedule
+type SetSyncScheduleRequest struct {
+	Cron string `json:"cron"` // 5-field cron expression in UTC (e.g. "0 * * * *"); empty clears the schedule
+}
+
+// SetOrphanPolicyRequest sets what full sync does with photos missing from Drive
+type SetOrphanPolicyRequest struct {
+	Policy string `json:"policy" validate:"omitempty,oneof=delete trash"` // "delete", "trash", or empty to use the global default
+}
+
+// SetExcludePathsRequest sets the sub-folder paths sync skips (replaces the current list)
+type SetExcludePathsRequest struct {
+	Paths []string `json:"paths" validate:"max=50,dive,required,max=500"` // Relative to the folder, e.g. "_raw" or "2567/archive"; empty clears
 }
 
 // SharedFolderListResponse is the response for listing folders
@@ -68,6 +108,15 @@ func SharedFolderToResponse(folder *models.SharedFolder, photoCount, userCount i
 		CreatedAt:       folder.CreatedAt,
 		WebhookStatus:   webhookStatus,
 		WebhookExpiry:   folder.WebhookExpiry,
+
+		LastSyncErrorCode:   string(folder.LastErrorCode),
+		LastSyncErrorDetail: folder.LastErrorDetail,
+
+		FaceProcessingDeferred: folder.FaceProcessingDeferred,
+		FaceProcessingEnabled:  folder.FaceProcessingEnabled,
+		SyncScheduleCron:       folder.SyncScheduleCron,
+		OrphanPolicy:           string(folder.OrphanPolicy),
+		ExcludePaths:           folder.ExcludePaths,
 	}
 }
 
@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gofiber-template/domain/models"
+)
+
+// FailedSyncJobResponse is the DTO for a failed sync job (admin dead-letter list and a user's own failed jobs)
+type FailedSyncJobResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	SharedFolderID uuid.UUID  `json:"shared_folder_id"`
+	FolderName     string     `json:"folder_name,omitempty"`
+	Status         string     `json:"status"`
+	LastError      string     `json:"last_error"`
+	TotalFiles     int        `json:"total_files"`
+	ProcessedFiles int        `json:"processed_files"`
+	FailedFiles    int        `json:"failed_files"`
+	CreatedAt      time.Time  `json:"created_at"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+}
+
+// SyncJobFolderID returns a sync job's shared folder ID (from metadata for jobs older than the column)
+func SyncJobFolderID(job *models.SyncJob) uuid.UUID {
+	if job.SharedFolderID != nil {
+		return *job.SharedFolderID
+	}
+	var metadata struct {
+		SharedFolderID uuid.UUID `json:"shared_folder_id"`
+	}
+	if job.Metadata != "" {
+		json.Unmarshal([]byte(job.Metadata), &metadata)
+	}
+	return metadata.SharedFolderID
+}
+
+// FailedSyncJobToResponse converts a SyncJob to response DTO (folder may be nil if deleted)
+func FailedSyncJobToResponse(job *models.SyncJob, folder *models.SharedFolder) FailedSyncJobResponse {
+	resp := FailedSyncJobResponse{
+		ID:             job.ID,
+		UserID:         job.UserID,
+		SharedFolderID: SyncJobFolderID(job),
+		Status:         string(job.Status),
+		LastError:      job.LastError,
+		TotalFiles:     job.TotalItems,
+		ProcessedFiles: job.ProcessedItems,
+		FailedFiles:    job.FailedItems,
+		CreatedAt:      job.CreatedAt,
+		StartedAt:      job.StartedAt,
+		CompletedAt:    job.CompletedAt,
+	}
+	if folder != nil {
+		resp.FolderName = folder.DriveFolderName
+	}
+	return resp
+}
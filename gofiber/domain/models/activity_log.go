@@ -13,6 +13,8 @@ const (
 	ActivitySyncStarted   ActivityType = "sync_started"
 	ActivitySyncCompleted ActivityType = "sync_completed"
 	ActivitySyncFailed    ActivityType = "sync_failed"
+	ActivitySyncCancelled ActivityType = "sync_cancelled"
+	ActivitySyncDryRun    ActivityType = "sync_dry_run" // Preview of what a sync would change (nothing written)
 
 	// Photo activities
 	ActivityPhotosAdded    ActivityType = "photos_added"
@@ -36,8 +38,9 @@ const (
 	ActivityWebhookExpired  ActivityType = "webhook_expired"
 
 	// Error activities
-	ActivityTokenExpired ActivityType = "token_expired"
-	ActivitySyncError    ActivityType = "sync_error"
+	ActivityTokenExpired  ActivityType = "token_expired"
+	ActivityAccessRevoked ActivityType = "access_revoked" // Token owner lost access to the Drive folder
+	ActivitySyncError     ActivityType = "sync_error"
 )
 
 // ActivityLog stores all sync activities for audit and debugging
@@ -77,6 +80,7 @@ type ActivityDetails struct {
 	NewFolderName  string   `json:"new_folder_name,omitempty"`
 	DriveFileID    string   `json:"drive_file_id,omitempty"`
 	DriveFolderID  string   `json:"drive_folder_id,omitempty"`
+	OutOfScope     bool     `json:"out_of_scope,omitempty"` // Photo moved outside the synced folder
 
 	// Sync info
 	JobID         string `json:"job_id,omitempty"`
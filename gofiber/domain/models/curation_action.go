@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CurationActionType string
+
+const (
+	CurationActionAssignPerson CurationActionType = "assign_person" // Bulk assign faces to a person
+)
+
+// CurationAction records a reversible bulk curation operation so it can be undone
+type CurationAction struct {
+	ID     uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;index"`
+
+	ActionType CurationActionType `gorm:"not null;index"`
+	ItemCount  int                `gorm:"default:0"`
+
+	// Previous state needed to reverse the action (JSON, shape depends on ActionType)
+	UndoData string `gorm:"type:jsonb"`
+
+	UndoneAt *time.Time
+
+	CreatedAt time.Time `gorm:"index"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID"`
+}
+
+func (CurationAction) TableName() string {
+	return "curation_actions"
+}
+
+// AssignPersonUndoData is the UndoData for CurationActionAssignPerson
+type AssignPersonUndoData struct {
+	PersonID uuid.UUID              `json:"person_id"`
+	Faces    []FacePreviousPersonID `json:"faces"`
+}
+
+// FacePreviousPersonID is a face's person assignment before the action
+type FacePreviousPersonID struct {
+	FaceID           uuid.UUID  `json:"face_id"`
+	PreviousPersonID *uuid.UUID `json:"previous_person_id,omitempty"`
+}
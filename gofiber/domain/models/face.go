@@ -28,6 +28,9 @@ type Face struct {
 	// Person identification (optional - set when user tags the face)
 	PersonID *uuid.UUID `gorm:"type:uuid;index"`
 
+	// Privacy opt-out: redacted faces are excluded from all similarity searches (photo stays viewable)
+	Redacted bool `gorm:"default:false;index"`
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
 )
 
 type Person struct {
@@ -17,6 +18,14 @@ type Person struct {
 	// Stats (cached)
 	FaceCount int `gorm:"default:0"` // Number of faces tagged as this person
 
+	// Averaged embedding of the person's faces for search by person (nil = no faces).
+	// Faces far from the plain average are left out so one mis-tagged face doesn't skew it.
+	Centroid *pgvector.Vector `gorm:"type:vector(512)" json:"-"`
+
+	// Privacy opt-out: all faces of a redacted person (including ones tagged later) are redacted
+	Redacted   bool `gorm:"default:false"`
+	RedactedAt *time.Time
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,13 +14,66 @@ const (
 	FaceStatusProcessing FaceProcessingStatus = "processing"
 	FaceStatusCompleted  FaceProcessingStatus = "completed"
 	FaceStatusFailed     FaceProcessingStatus = "failed"
+	FaceStatusSkipped    FaceProcessingStatus = "skipped" // Deferred until face processing is enabled for the folder (always for videos)
 )
 
+type PhotoSource string
+
+const (
+	PhotoSourceDrive  PhotoSource = "drive"  // Synced from Google Drive
+	PhotoSourceUpload PhotoSource = "upload" // Uploaded to the app directly (stored on Bunny)
+)
+
+// UploadDriveFileIDPrefix prefixes the placeholder DriveFileID of uploaded photos (the column is unique and not null)
+const UploadDriveFileIDPrefix = "upload:"
+
+// PhotoSort is the order of a photo listing (empty = PhotoSortCreatedDesc)
+type PhotoSort string
+
+const (
+	PhotoSortCreatedDesc  PhotoSort = "created_desc" // Newest in Drive first
+	PhotoSortCreatedAsc   PhotoSort = "created_asc"
+	PhotoSortNameAsc      PhotoSort = "name_asc"
+	PhotoSortNameDesc     PhotoSort = "name_desc"
+	PhotoSortModifiedDesc PhotoSort = "modified_desc" // Last modified in Drive first
+	PhotoSortTakenDesc    PhotoSort = "taken_desc"    // Capture time (EXIF) first, Drive creation time when unknown
+	PhotoSortTakenAsc     PhotoSort = "taken_asc"
+)
+
+// IsValid reports whether the sort is a known value (empty counts as the default)
+func (s PhotoSort) IsValid() bool {
+	switch s {
+	case "", PhotoSortCreatedDesc, PhotoSortCreatedAsc, PhotoSortNameAsc, PhotoSortNameDesc, PhotoSortModifiedDesc,
+		PhotoSortTakenDesc, PhotoSortTakenAsc:
+		return true
+	}
+	return false
+}
+
+type MediaType string
+
+const (
+	MediaTypeImage MediaType = "image"
+	MediaTypeVideo MediaType = "video"
+)
+
+// MediaTypeFromMime returns the media type stored for a Drive file's mime type
+func MediaTypeFromMime(mimeType string) MediaType {
+	if strings.HasPrefix(mimeType, "video/") {
+		return MediaTypeVideo
+	}
+	return MediaTypeImage
+}
+
 type Photo struct {
 	ID             uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	SharedFolderID uuid.UUID  `gorm:"type:uuid;not null;index"` // Reference to shared folder
 	UserID         *uuid.UUID `gorm:"type:uuid;index"`          // Deprecated: kept for migration, nullable
 
+	// Where the photo comes from; uploaded photos have no Drive file (placeholder DriveFileID)
+	Source      PhotoSource `gorm:"default:'drive';index"`
+	StoragePath string      // Bunny storage path of uploaded photos
+
 	// Google Drive metadata
 	DriveFileID     string `gorm:"uniqueIndex;not null"` // Google Drive file ID
 	DriveFolderID   string `gorm:"index"`                // Parent folder ID in Drive
@@ -28,21 +82,39 @@ type Photo struct {
 	// File info (cached from Drive)
 	FileName      string
 	MimeType      string
+	MediaType     MediaType `gorm:"default:'image';index"` // image or video (videos skip face processing)
 	FileSize      int64
 	Width         int
 	Height        int
 	ThumbnailURL  string // Google Drive thumbnail URL
 	WebViewURL    string // Google Drive web view URL
 
+	// Drive has not generated a thumbnail yet (fresh uploads) - cleared once one is served
+	ThumbnailPending bool `gorm:"default:false"`
+
 	// Timestamps from Drive
 	DriveCreatedAt  *time.Time // Original creation time in Drive
 	DriveModifiedAt *time.Time // Last modified time in Drive
 
+	// Capture metadata from EXIF (Drive's imageMediaMetadata, or parsed from uploaded files).
+	// Empty for images without EXIF and for Drive photos synced with image metadata capture off
+	TakenAt     *time.Time `gorm:"index"` // Camera capture time
+	CameraMake  string
+	CameraModel string
+
 	// Face processing
 	FaceStatus      FaceProcessingStatus `gorm:"default:'pending';index"`
 	FaceCount       int                  `gorm:"default:0"` // Number of faces detected
 	FaceProcessedAt *time.Time
 
+	// Face model ("model@version") of the last successful extraction; a faceless photo re-queued
+	// under the same model is not sent to the Face API again
+	LastFaceCheckModelVersion string
+
+	// SHA-256 (hex) of the image bytes the face worker downloaded, for duplicate detection.
+	// Empty until the photo is face processed (never set for videos)
+	ContentHash string `gorm:"index"`
+
 	// Soft delete (Google Drive trash)
 	IsTrashed bool       `gorm:"default:false;index"` // True if in Google Drive trash
 	TrashedAt *time.Time // When moved to trash
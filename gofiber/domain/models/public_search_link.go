@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PublicSearchLink lets anonymous attendees run face search on one folder (e.g. a public event)
+type PublicSearchLink struct {
+	ID             uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	SharedFolderID uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedBy      uuid.UUID `gorm:"type:uuid;not null"`
+
+	// Opaque random token used in the public URL
+	Token string `gorm:"type:varchar(64);not null;uniqueIndex"`
+
+	ExpiresAt  time.Time `gorm:"not null"`
+	MaxResults int       `gorm:"default:0"` // Max matches returned per search
+
+	CreatedAt time.Time
+
+	// Relations
+	SharedFolder SharedFolder `gorm:"foreignKey:SharedFolderID"`
+}
+
+func (PublicSearchLink) TableName() string {
+	return "public_search_links"
+}
+
+// IsExpired reports whether the link can no longer be used
+func (l *PublicSearchLink) IsExpired() bool {
+	return time.Now().After(l.ExpiresAt)
+}
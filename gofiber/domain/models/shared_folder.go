@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,8 +13,42 @@ const (
 	SyncStatusIdle     SyncStatus = "idle"
 	SyncStatusSyncing  SyncStatus = "syncing"
 	SyncStatusError    SyncStatus = "error"
+
+	// The token owner can no longer read the folder in Drive (un-shared or removed); someone with access must reconnect
+	SyncStatusAccessRevoked SyncStatus = "access_revoked"
+)
+
+// SyncErrorCode classifies a folder's last sync error so clients can tell users what to do about it
+type SyncErrorCode string
+
+const (
+	SyncErrorTokenExpired     SyncErrorCode = "token_expired"     // Google Drive must be reconnected
+	SyncErrorPermissionDenied SyncErrorCode = "permission_denied" // The token owner can't read the folder any more
+	SyncErrorRateLimited      SyncErrorCode = "rate_limited"      // Drive quota exceeded, a later sync usually succeeds
+	SyncErrorDriveUnavailable SyncErrorCode = "drive_unavailable" // Google-side failure (5xx)
+	SyncErrorUnknown          SyncErrorCode = "unknown"
+)
+
+// SyncErrorDetail is the parsed Google API error behind a folder's last sync error
+type SyncErrorDetail struct {
+	HTTPStatus int    `json:"http_status,omitempty"`
+	Reason     string `json:"reason,omitempty"`  // First Google error reason, e.g. insufficientFilePermissions
+	Message    string `json:"message,omitempty"` // Google's error message
+}
+
+// OrphanPolicy decides what full sync does with photos that are no longer in the Drive listing
+type OrphanPolicy string
+
+const (
+	OrphanPolicyDelete OrphanPolicy = "delete" // Hard delete photos and their faces
+	OrphanPolicyTrash  OrphanPolicy = "trash"  // Mark trashed (restorable), purged after the retention period
 )
 
+// IsValid reports whether the policy is a known value
+func (p OrphanPolicy) IsValid() bool {
+	return p == OrphanPolicyDelete || p == OrphanPolicyTrash
+}
+
 // SharedFolder represents a Google Drive folder that is synced by the server
 type SharedFolder struct {
 	ID               uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
@@ -28,6 +63,7 @@ type SharedFolder struct {
 	WebhookResourceID string     // Resource ID from Google
 	WebhookToken      string     `gorm:"uniqueIndex"` // Token for webhook verification
 	WebhookExpiry     *time.Time // When webhook expires
+	WebhookSigned     bool       `gorm:"not null;default:false"` // Channel registered with a signed token (false = legacy, re-registered by the renewal job)
 
 	// Sync info
 	PageToken    string     // Page token for incremental sync
@@ -35,6 +71,26 @@ type SharedFolder struct {
 	SyncStatus   SyncStatus `gorm:"default:'idle'"` // Current sync status
 	LastError    string     // Last error message (if any)
 
+	// Classified last error; detail is only set when the error came from the Google API
+	LastErrorCode   SyncErrorCode
+	LastErrorDetail *SyncErrorDetail `gorm:"type:jsonb;serializer:json"`
+
+	// Face processing
+	FaceProcessingDeferred bool `gorm:"default:false"` // New photos are created as skipped until enabled
+	FaceProcessingEnabled  bool `gorm:"default:true"`  // false = no face search for this folder (photos stay skipped)
+
+	// Scheduled sync (in addition to webhooks), e.g. "0 * * * *"; empty = no schedule
+	SyncScheduleCron string
+
+	// Orphan cleanup
+	OrphanPolicy OrphanPolicy // Empty = use the global ORPHAN_POLICY
+
+	// Sub-folder paths (relative to this folder, e.g. "_raw" or "2567/archive") that sync skips
+	ExcludePaths []string `gorm:"type:jsonb;serializer:json"`
+
+	// Cached number of non-trashed photos (maintained by the photo repository, reconciled periodically)
+	PhotoCount int64 `gorm:"default:0"`
+
 	// OAuth tokens (from user who added this folder)
 	DriveAccessToken  string     // Google Drive access token
 	DriveRefreshToken string     // Google Drive refresh token
@@ -53,6 +109,27 @@ func (SharedFolder) TableName() string {
 	return "shared_folders"
 }
 
+// IsPathExcluded reports whether a synced folder path ("<folder name>/sub/dir", as stored in
+// DriveFolderPath) is one of the exclude paths or below one
+func (f *SharedFolder) IsPathExcluded(folderPath string) bool {
+	if len(f.ExcludePaths) == 0 {
+		return false
+	}
+
+	// Exclude paths are relative to the shared folder, so drop its name from the path
+	_, relPath, found := strings.Cut(folderPath, "/")
+	if !found {
+		return false // The shared folder itself
+	}
+
+	for _, excluded := range f.ExcludePaths {
+		if relPath == excluded || strings.HasPrefix(relPath, excluded+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // UserFolderAccess represents a user's access to a shared folder
 type UserFolderAccess struct {
 	ID             uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
@@ -11,6 +11,7 @@ type SyncJobType string
 const (
 	SyncJobTypeDriveSync   SyncJobType = "drive_sync"    // Sync files from Google Drive
 	SyncJobTypeFaceProcess SyncJobType = "face_process"  // Process faces in photos
+	SyncJobTypeZipExport   SyncJobType = "zip_export"    // Build folder zip archive to storage
 )
 
 type SyncJobStatus string
@@ -31,6 +32,12 @@ type SyncJob struct {
 	JobType SyncJobType   `gorm:"not null;index" json:"job_type"`
 	Status  SyncJobStatus `gorm:"default:'pending';index" json:"status"`
 
+	// Higher priority jobs are claimed first; equal priorities run oldest first
+	Priority int `gorm:"not null;default:0" json:"priority"`
+
+	// Folder of a drive sync job (mirrors metadata.shared_folder_id so per-folder lookups can use an index)
+	SharedFolderID *uuid.UUID `gorm:"type:uuid;index" json:"shared_folder_id,omitempty"`
+
 	// Progress tracking
 	TotalItems     int `gorm:"default:0" json:"total_files"`     // Total items to process
 	ProcessedItems int `gorm:"default:0" json:"processed_files"` // Items processed so far
@@ -43,6 +50,9 @@ type SyncJob struct {
 	// Error info
 	LastError string `gorm:"type:text" json:"last_error,omitempty"`
 
+	// Set by a cancel request; the instance running the job polls it and stops the job
+	CancelRequested bool `gorm:"not null;default:false" json:"cancel_requested"`
+
 	// Metadata (JSON for additional job-specific data)
 	Metadata string `gorm:"type:jsonb" json:"-"` // e.g., {"folder_id": "xxx", "page_token": "yyy"}
 
@@ -57,6 +67,21 @@ func (SyncJob) TableName() string {
 	return "sync_jobs"
 }
 
+// SyncJobSnapshot holds the Drive listing of a running full sync so a job resumed after a
+// worker restart continues on the same file order without re-walking the folder tree.
+// It is looked up by folder, so a new job resuming a cancelled one reuses the same listing.
+type SyncJobSnapshot struct {
+	JobID          uuid.UUID `gorm:"primaryKey;type:uuid"`  // Job that listed the folder
+	SharedFolderID uuid.UUID `gorm:"type:uuid;uniqueIndex"` // At most one snapshot per folder
+	FolderPaths    string    `gorm:"type:jsonb"`            // Drive folder ID -> path (null if folder listing failed)
+	Files          string    `gorm:"type:jsonb"`            // Ordered list of files to sync
+	CreatedAt      time.Time
+}
+
+func (SyncJobSnapshot) TableName() string {
+	return "sync_job_snapshots"
+}
+
 // DriveWebhookLog stores incoming webhook events from Google Drive
 type DriveWebhookLog struct {
 	ID     uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SyncMode string
+
+const (
+	SyncModeFull        SyncMode = "full"
+	SyncModeIncremental SyncMode = "incremental"
+)
+
+// SyncSummary is a per-job record written when a sync completes (for analytics)
+type SyncSummary struct {
+	ID             uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	JobID          uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	SharedFolderID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Mode           SyncMode  `gorm:"not null"`
+
+	DurationMs   int64
+	NewCount     int
+	UpdatedCount int
+	DeletedCount int
+	FailedCount  int
+
+	CompletedAt time.Time `gorm:"not null;index"`
+	CreatedAt   time.Time
+}
+
+func (SyncSummary) TableName() string {
+	return "sync_summaries"
+}
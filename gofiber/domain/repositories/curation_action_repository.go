@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gofiber-template/domain/models"
+)
+
+type CurationActionRepository interface {
+	Create(ctx context.Context, action *models.CurationAction) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.CurationAction, error)
+
+	// Most recent action of a user that has not been undone
+	GetLatestActiveByUser(ctx context.Context, userID uuid.UUID) (*models.CurationAction, error)
+
+	// AssignFacesWithAction assigns faces to a person (optionally redacting them) and records
+	// the action in a single transaction
+	AssignFacesWithAction(ctx context.Context, action *models.CurationAction, faceIDs []uuid.UUID, personID uuid.UUID, redact bool) error
+
+	// MarkUndone sets undone_at if not already set; returns false if it was already undone
+	MarkUndone(ctx context.Context, id uuid.UUID) (bool, error)
+}
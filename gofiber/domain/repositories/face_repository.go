@@ -13,26 +13,53 @@ type FaceRepository interface {
 	Create(ctx context.Context, face *models.Face) error
 	CreateBatch(ctx context.Context, faces []*models.Face) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Face, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.Face, error)
 	GetByPhoto(ctx context.Context, photoID uuid.UUID) ([]models.Face, error)
 	GetByPerson(ctx context.Context, personID uuid.UUID) ([]models.Face, error)
+	CountByPersonPerFolder(ctx context.Context, personID uuid.UUID, folderIDs []uuid.UUID) ([]PersonFolderCount, error) // Non-trashed photos only, most photos first
 	GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.Face, int64, error)
 
 	// SharedFolder-based queries
 	GetBySharedFolders(ctx context.Context, folderIDs []uuid.UUID, offset, limit int) ([]models.Face, int64, error)
+	GetUnassignedBySharedFolders(ctx context.Context, folderIDs []uuid.UUID, offset, limit int) ([]models.Face, int64, error) // person_id IS NULL (labeling queue)
+	GetAssignedBySharedFolders(ctx context.Context, folderIDs []uuid.UUID, offset, limit int) ([]models.Face, int64, error)   // person_id IS NOT NULL (review)
 	CountBySharedFolders(ctx context.Context, folderIDs []uuid.UUID) (int64, error)
+	GetLowConfidenceBySharedFolder(ctx context.Context, folderID uuid.UUID, maxConfidence float64, offset, limit int) ([]models.Face, int64, error) // confidence < max, with Photo preloaded
 
-	// Vector search - find similar faces
+	// Vector search - find similar faces (redacted faces are always excluded)
 	SearchSimilar(ctx context.Context, userID uuid.UUID, embedding pgvector.Vector, limit int, threshold float64) ([]FaceSearchResult, error)
 	SearchSimilarByFolderPathPrefix(ctx context.Context, pathPrefix string, embedding pgvector.Vector, limit int, threshold float64) ([]FaceSearchResult, error)
 	SearchSimilarBySharedFolders(ctx context.Context, folderIDs []uuid.UUID, embedding pgvector.Vector, limit int, threshold float64) ([]FaceSearchResult, error)
+	// Nearest neighbors among a folder's unassigned faces (clustering input), see FaceNeighbor
+	FindUnassignedNeighbors(ctx context.Context, folderID uuid.UUID, threshold float64, maxFaces, neighbors int) ([]FaceNeighbor, error)
 
 	Update(ctx context.Context, id uuid.UUID, face *models.Face) error
 	UpdatePersonID(ctx context.Context, id uuid.UUID, personID *uuid.UUID) error
+	UpdatePersonIDs(ctx context.Context, ids []uuid.UUID, personID *uuid.UUID) (int64, error)
+	RedactByIDs(ctx context.Context, ids []uuid.UUID) (int64, error)
+	RedactByPerson(ctx context.Context, personID uuid.UUID) (int64, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteByPhoto(ctx context.Context, photoID uuid.UUID) error
+	DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error)
 	Count(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 
+// PersonFolderCount is how often a person appears in one shared folder
+type PersonFolderCount struct {
+	SharedFolderID uuid.UUID
+	PhotoCount     int64
+	FaceCount      int64
+}
+
+// FaceNeighbor is a pair of similar unassigned faces in one folder. Every candidate face is listed
+// once with NeighborID = nil too, so faces without neighbors above the threshold are still known.
+type FaceNeighbor struct {
+	FaceID     uuid.UUID
+	Confidence float64
+	NeighborID *uuid.UUID
+	Similarity float64
+}
+
 // FaceSearchResult represents a face search result with similarity score
 type FaceSearchResult struct {
 	Face       models.Face
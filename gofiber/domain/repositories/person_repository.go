@@ -14,6 +14,12 @@ type PersonRepository interface {
 	GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.Person, int64, error)
 	Update(ctx context.Context, id uuid.UUID, person *models.Person) error
 	UpdateFaceCount(ctx context.Context, id uuid.UUID, count int) error
+	UpdateCentroid(ctx context.Context, id uuid.UUID) error // Recompute the centroid from the person's current faces
+	// Move all faces of mergeID to keepID and delete mergeID (one transaction); returns faces moved
+	Merge(ctx context.Context, keepID, mergeID uuid.UUID) (int64, error)
+	// Create newPerson and move the given faces of sourceID to it (one transaction); returns faces moved
+	Split(ctx context.Context, sourceID uuid.UUID, faceIDs []uuid.UUID, newPerson *models.Person) (int64, error)
+	MarkRedacted(ctx context.Context, id uuid.UUID) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	Count(ctx context.Context, userID uuid.UUID) (int64, error)
 }
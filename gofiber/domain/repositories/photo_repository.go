@@ -2,54 +2,97 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"gofiber-template/domain/models"
 )
 
+// FolderPhotoStats aggregates a shared folder's non-trashed photos
+type FolderPhotoStats struct {
+	TotalPhotos     int64
+	PhotosWithFaces int64
+	TotalFaces      int64      // Sum of photos' face_count
+	DistinctPersons int64      // People with at least one non-redacted face in the folder
+	FirstPhotoAt    *time.Time // Earliest Drive creation time (nil if no photos)
+	LastPhotoAt     *time.Time
+}
+
+// PhotoDuplicateGroup is a set of photos with the same content hash
+type PhotoDuplicateGroup struct {
+	ContentHash string
+	Photos      []models.Photo
+}
+
 type PhotoRepository interface {
 	// CRUD
 	Create(ctx context.Context, photo *models.Photo) error
 	CreateBatch(ctx context.Context, photos []*models.Photo) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Photo, error)
 	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.Photo, error)
-	GetByDriveFileID(ctx context.Context, driveFileID string) (*models.Photo, error)
+	GetByDriveFileID(ctx context.Context, folderID uuid.UUID, driveFileID string) (*models.Photo, error)
+	GetByDriveFileIDForUser(ctx context.Context, userID uuid.UUID, driveFileID string) (*models.Photo, error)           // Photo of the file in a folder the user can access
+	GetByDriveFileIDs(ctx context.Context, folderID uuid.UUID, driveFileIDs []string) (map[string]*models.Photo, error) // Keyed by Drive file ID; missing IDs are absent
 	Update(ctx context.Context, id uuid.UUID, photo *models.Photo) error
 	UpdateFaceStatus(ctx context.Context, id uuid.UUID, status models.FaceProcessingStatus, faceCount int) error
+	DecrementFaceCount(ctx context.Context, id uuid.UUID, n int) error // Never goes below 0
+	ReconcileFaceCounts(ctx context.Context, folderID *uuid.UUID) (int64, error) // Recompute face_count from faces rows, optionally by folder; returns photos fixed
 	UpdateFolderPath(ctx context.Context, driveFolderID string, newPath string) (int64, error)
+	UpdateMetadata(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error
+	UpdateCaptureMetadata(ctx context.Context, id uuid.UUID, takenAt *time.Time, cameraMake, cameraModel string, width, height int) error // Unknown values (nil, "", 0) are left as they are
 	Delete(ctx context.Context, id uuid.UUID) error
 
 	// SharedFolder-based queries
-	GetBySharedFolder(ctx context.Context, folderID uuid.UUID, offset, limit int) ([]models.Photo, int64, error)
-	GetBySharedFolderAndPath(ctx context.Context, folderID uuid.UUID, folderPath string, offset, limit int) ([]models.Photo, int64, error)
+	GetBySharedFolder(ctx context.Context, folderID uuid.UUID, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error)
+	GetBySharedFolderAndPath(ctx context.Context, folderID uuid.UUID, folderPath string, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error)
+	GetBySharedFolderAndDateRange(ctx context.Context, folderID uuid.UUID, folderPath string, from, to *time.Time, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error) // drive_created_at in [from, to]; nil bounds and "" path are open
 	GetBySharedFolderAndDriveFolderID(ctx context.Context, folderID uuid.UUID, driveFolderID string, offset, limit int) ([]models.Photo, int64, error)
-	SearchByFolderPathInSharedFolder(ctx context.Context, folderID uuid.UUID, searchQuery string, offset, limit int) ([]models.Photo, int64, error)
+	SearchByFolderPathInSharedFolder(ctx context.Context, folderID uuid.UUID, searchQuery string, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error)
+	SearchByFileName(ctx context.Context, folderID uuid.UUID, searchQuery string, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error)          // Case-insensitive substring of file_name
+	SearchByFileNameOrFolderPath(ctx context.Context, folderID uuid.UUID, searchQuery string, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error) // Matches file_name or drive_folder_path
 	GetFolderPathsInSharedFolder(ctx context.Context, folderID uuid.UUID) ([]string, error)
+	CountByFolderPathInSharedFolder(ctx context.Context, folderID uuid.UUID) (map[string]int64, error) // Photo count per drive_folder_path
 	CountBySharedFolder(ctx context.Context, folderID uuid.UUID) (int64, error)
 	CountBySharedFolderAndFaceStatus(ctx context.Context, folderID uuid.UUID, status models.FaceProcessingStatus) (int64, error)
+	CountFaceQueueBySharedFolders(ctx context.Context, folderIDs []uuid.UUID) (map[uuid.UUID]int64, error) // Pending + processing photos per folder (only folders that have any)
+	GetFolderStats(ctx context.Context, folderID uuid.UUID) (*FolderPhotoStats, error) // Photo, face and people totals in one query
+	GetDriveFileIDsBySharedFolder(ctx context.Context, folderID uuid.UUID) ([]string, error) // Drive file IDs of non-trashed Drive-backed photos
+	FindDuplicatesBySharedFolder(ctx context.Context, folderID uuid.UUID, offset, limit int) ([]PhotoDuplicateGroup, int64, error) // Groups of non-trashed photos sharing a content hash, largest first
 
 	// Multi-folder queries (for users with access to multiple folders)
 	GetBySharedFolders(ctx context.Context, folderIDs []uuid.UUID, offset, limit int) ([]models.Photo, int64, error)
 	GetBySharedFoldersAndPath(ctx context.Context, folderIDs []uuid.UUID, folderPath string, offset, limit int) ([]models.Photo, int64, error)
+	GetBySharedFoldersAndDriveFolderID(ctx context.Context, folderIDs []uuid.UUID, driveFolderID string, offset, limit int) ([]models.Photo, int64, error)
+	SearchByFolderPathInSharedFolders(ctx context.Context, folderIDs []uuid.UUID, searchQuery string, offset, limit int) ([]models.Photo, int64, error)
 
 	// Face processing
 	GetPendingFaceProcessing(ctx context.Context, folderID uuid.UUID, limit int) ([]models.Photo, error)
 	GetByFaceStatus(ctx context.Context, status models.FaceProcessingStatus, limit int) ([]models.Photo, error)
-	GetPendingBySharedFolders(ctx context.Context, folderIDs []uuid.UUID, limit int) ([]models.Photo, error)
+	GetPendingBySharedFolders(ctx context.Context, folderIDs []uuid.UUID, limit int) ([]models.Photo, error) // Excludes folders with face processing disabled
+	GetPendingForFaceWorker(ctx context.Context, limit int) ([]models.Photo, error)                           // Oldest pending photos of folders with face processing enabled
 	ResetFailedToPending(ctx context.Context, folderID *uuid.UUID) (int64, error)                      // Reset failed photos to pending, optionally by folder
 	ResetStuckProcessingToPending(ctx context.Context, stuckThresholdMinutes int) (int64, error)     // Reset photos stuck in processing for too long
+	ResetSkippedToPending(ctx context.Context, folderID uuid.UUID) (int64, error)                    // Queue skipped photos of a folder for face processing
+	ResetAllToPendingForFolder(ctx context.Context, folderID uuid.UUID) (int64, error)               // Delete a folder's faces and queue all its photos (not videos) again
 
 	// Soft delete (trash) operations
 	// SetTrashedByDriveFileID returns (wasUpdated, error) - wasUpdated is true if state actually changed
 	SetTrashedByDriveFileID(ctx context.Context, driveFileID string, isTrashed bool) (bool, error)
 	SetTrashedByDriveFolderID(ctx context.Context, driveFolderID string, isTrashed bool) (int64, error)
+	TrashNotInDriveIDsForFolder(ctx context.Context, folderID uuid.UUID, driveFileIDs []string) (int64, error) // Orphan cleanup with the trash policy
+	PurgeTrashedBefore(ctx context.Context, cutoff time.Time) (int64, error)                                  // Hard delete photos (and faces) trashed before cutoff
+	GetTrashedBySharedFolder(ctx context.Context, folderID uuid.UUID, offset, limit int) ([]models.Photo, int64, error) // Most recently trashed first
+	TrashByID(ctx context.Context, id uuid.UUID) (bool, error)                                                       // Sets is_trashed/trashed_at; false if it was already trashed
+	RestoreByID(ctx context.Context, id uuid.UUID) (bool, error)                                                     // Clears is_trashed/trashed_at; false if it was not trashed
 
 	// Delete operations (hard delete)
 	DeleteByDriveFileID(ctx context.Context, driveFileID string) error
 	DeleteByDriveFolderID(ctx context.Context, driveFolderID string) (int64, error)
 	DeleteNotInDriveIDsForFolder(ctx context.Context, folderID uuid.UUID, driveFileIDs []string) (int64, error)
+	CountNotInDriveIDsForFolder(ctx context.Context, folderID uuid.UUID, driveFileIDs []string, includeTrashed bool) (int64, error) // Orphans a cleanup would touch (dry run)
 
-	// Legacy methods (for migration/compatibility - can be removed later)
+	// Deprecated: legacy user_id methods, only used as a compatibility shim by DriveService for users
+	// without shared folders. Photos are migrated to shared folders on startup; use the shared folder queries.
 	GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.Photo, int64, error)
 	GetByUserAndFolder(ctx context.Context, userID uuid.UUID, folderPath string, offset, limit int) ([]models.Photo, int64, error)
 	GetByUserAndFolderId(ctx context.Context, userID uuid.UUID, folderId string, offset, limit int) ([]models.Photo, int64, error)
@@ -60,7 +103,7 @@ type PhotoRepository interface {
 	CountByFaceStatus(ctx context.Context, userID uuid.UUID, status models.FaceProcessingStatus) (int64, error)
 	GetFolderPaths(ctx context.Context, userID uuid.UUID) ([]string, error)
 
-	// Shared folder methods (query by folder path prefix) - Legacy
+	// Deprecated: shared folder methods by folder path prefix (name-based, can match unrelated folders)
 	GetByFolderPathPrefix(ctx context.Context, pathPrefix string, offset, limit int) ([]models.Photo, int64, error)
 	CountByFolderPathPrefix(ctx context.Context, pathPrefix string) (int64, error)
 	CountByFolderPathPrefixAndFaceStatus(ctx context.Context, pathPrefix string, status models.FaceProcessingStatus) (int64, error)
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+
+	"gofiber-template/domain/models"
+)
+
+type PublicSearchLinkRepository interface {
+	Create(ctx context.Context, link *models.PublicSearchLink) error
+	GetByToken(ctx context.Context, token string) (*models.PublicSearchLink, error)
+}
@@ -19,6 +19,8 @@ type SharedFolderRepository interface {
 	Update(ctx context.Context, id uuid.UUID, folder *models.SharedFolder) error
 	UpdateMetadata(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error
 	UpdateSyncStatus(ctx context.Context, id uuid.UUID, status models.SyncStatus, lastError string) error
+	// UpdateSyncError is UpdateSyncStatus plus the classified error (detail may be nil)
+	UpdateSyncError(ctx context.Context, id uuid.UUID, status models.SyncStatus, lastError string, code models.SyncErrorCode, detail *models.SyncErrorDetail) error
 	UpdateTokens(ctx context.Context, id uuid.UUID, accessToken, refreshToken string, expiry *time.Time, ownerID uuid.UUID) error
 	ResetSyncState(ctx context.Context, id uuid.UUID) error // Reset PageToken and LastSyncedAt for force full sync
 	Delete(ctx context.Context, id uuid.UUID) error
@@ -33,7 +35,12 @@ type SharedFolderRepository interface {
 
 	// Count
 	CountUsers(ctx context.Context, folderID uuid.UUID) (int64, error)
+	ReconcilePhotoCounts(ctx context.Context) (int64, error) // Recompute cached photo_count from photos; returns folders fixed
+	CountFoldersByUser(ctx context.Context, userID uuid.UUID) (int64, error)
 
 	// Webhook management
 	GetFoldersWithExpiringWebhooks(ctx context.Context, expiryThreshold time.Time) ([]models.SharedFolder, error)
+
+	// Scheduled sync
+	GetFoldersWithSyncSchedule(ctx context.Context) ([]models.SharedFolder, error)
 }
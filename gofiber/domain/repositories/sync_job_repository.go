@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"gofiber-template/domain/models"
@@ -12,10 +13,44 @@ type SyncJobRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.SyncJob, error)
 	GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.SyncJob, int64, error)
 	GetLatestByUserAndType(ctx context.Context, userID uuid.UUID, jobType models.SyncJobType) (*models.SyncJob, error)
-	GetPendingJobs(ctx context.Context, jobType models.SyncJobType, limit int) ([]models.SyncJob, error)
+
+	// ClaimPendingJobs claims up to limit runnable pending jobs (priority DESC, created_at ASC) and marks them
+	// running in the same transaction, so concurrent workers never receive the same job
+	ClaimPendingJobs(ctx context.Context, jobType models.SyncJobType, limit int) ([]models.SyncJob, error)
+
+	// ClaimPendingSyncJobs is ClaimPendingJobs for drive sync jobs that will run in the given mode
+	// (full or incremental, decided from the folder's sync state), so the worker can claim exactly
+	// as many jobs as that mode has free slots
+	ClaimPendingSyncJobs(ctx context.Context, mode models.SyncMode, limit int) ([]models.SyncJob, error)
+
 	HasPendingOrRunningJobForFolder(ctx context.Context, folderID uuid.UUID) (bool, error)
+	CountActive(ctx context.Context, jobType models.SyncJobType) (int64, error)          // Pending and running jobs of a type
+	GetPendingByFolder(ctx context.Context, folderID uuid.UUID) (*models.SyncJob, error) // Oldest pending drive sync job of a folder
+
+	// CreatePendingForFolder creates a pending drive sync job unless the folder already has one (dry runs don't count)
+	// Returns false (and does not create) when a pending job exists; safe against concurrent callers
+	CreatePendingForFolder(ctx context.Context, job *models.SyncJob) (bool, error)
+	GetLatestForFolder(ctx context.Context, folderID uuid.UUID, statuses ...models.SyncJobStatus) (*models.SyncJob, error) // Latest drive sync job of a folder, optionally filtered by status
+	GetFailedJobs(ctx context.Context, jobType models.SyncJobType, offset, limit int) ([]models.SyncJob, int64, error)
+	GetFailedByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.SyncJob, int64, error) // Failed drive sync jobs of folders the user can access
+	Requeue(ctx context.Context, id uuid.UUID) error                                                           // Reset a job to pending and clear its error/timing
 	Update(ctx context.Context, id uuid.UUID, job *models.SyncJob) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status models.SyncJobStatus) error
 	UpdateProgress(ctx context.Context, id uuid.UUID, processed, failed int) error
 	Delete(ctx context.Context, id uuid.UUID) error
+
+	// DeleteFinishedBefore deletes completed/failed/cancelled drive sync jobs last updated before cutoff,
+	// always keeping the keepPerFolder most recent finished jobs of each folder
+	DeleteFinishedBefore(ctx context.Context, cutoff time.Time, keepPerFolder int) (int64, error)
+
+	// RequestCancel flags a running job for cancellation; the instance running it stops it on its
+	// next poll. Returns false when the job is not running
+	RequestCancel(ctx context.Context, id uuid.UUID) (bool, error)
+	IsCancelRequested(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// Full sync listing snapshots, one per folder (reused when a job resumes after a worker restart
+	// or a new job resumes a cancelled one). SaveSnapshot replaces the folder's previous snapshot
+	SaveSnapshot(ctx context.Context, snapshot *models.SyncJobSnapshot) error
+	GetSnapshot(ctx context.Context, folderID uuid.UUID) (*models.SyncJobSnapshot, error)
+	DeleteSnapshot(ctx context.Context, folderID uuid.UUID) error
 }
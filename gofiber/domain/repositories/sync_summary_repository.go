@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gofiber-template/domain/models"
+)
+
+// SyncSummaryDaily is the aggregate of sync summaries for one day
+type SyncSummaryDaily struct {
+	Date             time.Time `json:"date"`
+	SyncCount        int64     `json:"sync_count"`
+	FullCount        int64     `json:"full_count"`
+	IncrementalCount int64     `json:"incremental_count"`
+	NewCount         int64     `json:"new_count"`
+	UpdatedCount     int64     `json:"updated_count"`
+	DeletedCount     int64     `json:"deleted_count"`
+	FailedCount      int64     `json:"failed_count"`
+	TotalDurationMs  int64     `json:"total_duration_ms"`
+}
+
+type SyncSummaryRepository interface {
+	Create(ctx context.Context, summary *models.SyncSummary) error
+
+	// Per-day totals across all folders for summaries completed in [from, to)
+	AggregateDaily(ctx context.Context, from, to time.Time) ([]SyncSummaryDaily, error)
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gofiber-template/domain/models"
+)
+
+var (
+	ErrActionNotFound      = errors.New("action not found")
+	ErrActionAlreadyUndone = errors.New("action already undone")
+	ErrUndoWindowExpired   = errors.New("undo window has expired")
+	ErrActionNotLatest     = errors.New("only the most recent action can be undone")
+	ErrPersonNotFound      = errors.New("person not found")
+)
+
+// BulkAssignResult reports a partially successful bulk assignment
+type BulkAssignResult struct {
+	Action       *models.CurationAction // nil when no face could be assigned
+	AssignedIDs  []uuid.UUID
+	Inaccessible []uuid.UUID // Faces in folders the user cannot access
+	NotFound     []uuid.UUID
+}
+
+// CurationService handles bulk curation operations that can be undone
+type CurationService interface {
+	// Assign many faces to a person in one action (recorded for undo).
+	// Accessible faces are assigned; inaccessible and missing ones are reported, not fatal.
+	BulkAssignFacesToPerson(ctx context.Context, userID uuid.UUID, faceIDs []uuid.UUID, personID uuid.UUID) (*BulkAssignResult, error)
+
+	// Reverse the user's most recent action if still within the undo window
+	UndoAction(ctx context.Context, userID uuid.UUID, actionID uuid.UUID) (*models.CurationAction, error)
+}
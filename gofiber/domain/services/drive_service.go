@@ -2,11 +2,26 @@ package services
 
 import (
 	"context"
+	"errors"
+	"io"
 
 	"github.com/google/uuid"
 	"gofiber-template/domain/models"
 )
 
+// ErrThumbnailNotReady means Drive has not generated the thumbnail yet; clients should retry shortly
+var ErrThumbnailNotReady = errors.New("thumbnail not ready yet")
+
+// ErrUnknownWebhookToken means a webhook's channel token matches no user or folder
+var ErrUnknownWebhookToken = errors.New("unknown webhook token")
+
+// ErrZipTooManyFiles and ErrZipTooLarge mean a zip download exceeds the configured limits;
+// the wrapped error message names the limit
+var (
+	ErrZipTooManyFiles = errors.New("too many files for one zip download")
+	ErrZipTooLarge     = errors.New("selected files exceed the zip download size limit")
+)
+
 // DriveFolder represents a folder from Google Drive
 type DriveFolder struct {
 	ID       string `json:"id"`
@@ -22,6 +37,17 @@ type DownloadProgress struct {
 	FileName string `json:"fileName"`
 }
 
+// PhotoStream is an original photo streamed from Google Drive
+type PhotoStream struct {
+	Body          io.ReadCloser
+	ContentType   string
+	ContentLength int64  // -1 if unknown (response is sent chunked)
+	ContentRange  string // Set for partial (206) responses
+	AcceptRanges  string
+	Partial       bool
+	FileName      string
+}
+
 // DownloadProgressCallback is called for each file downloaded
 type DownloadProgressCallback func(progress DownloadProgress)
 
@@ -49,6 +75,8 @@ type DriveService interface {
 	GetPhotosByFolderId(ctx context.Context, userID uuid.UUID, folderId string, page, limit int) ([]models.Photo, int64, error)
 	SearchPhotos(ctx context.Context, userID uuid.UUID, searchQuery string, page, limit int) ([]models.Photo, int64, error)
 	GetPhotoThumbnail(ctx context.Context, userID uuid.UUID, driveFileID string, size int) ([]byte, string, error)
+	GetCachedThumbnailURL(ctx context.Context, userID uuid.UUID, driveFileID string, size int) (string, error)
+	OpenOriginalPhoto(ctx context.Context, userID uuid.UUID, driveFileID, rangeHeader string) (*PhotoStream, error)
 	DownloadPhotosAsZip(ctx context.Context, userID uuid.UUID, driveFileIDs []string, onProgress DownloadProgressCallback) ([]byte, error)
 
 	// Webhook
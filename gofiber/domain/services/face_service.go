@@ -11,11 +11,51 @@ import (
 
 // Custom errors for face service
 var (
-	ErrNoFacesDetected   = errors.New("no faces detected in the uploaded image")
-	ErrFaceNotFound      = errors.New("face not found")
-	ErrInvalidFaceIndex  = errors.New("invalid face index")
+	ErrNoFacesDetected  = errors.New("no faces detected in the uploaded image")
+	ErrFaceNotFound     = errors.New("face not found")
+	ErrInvalidFaceIndex = errors.New("invalid face index")
+	ErrFolderNotFound   = errors.New("folder not found")
+	ErrPhotoNotFound    = errors.New("photo not found")
+	ErrPersonHasNoFaces = errors.New("person has no faces")
+	ErrSamePerson       = errors.New("cannot merge a person into itself")
+
+	ErrFaceProcessingDisabled = errors.New("face processing is disabled for this folder")
 )
 
+// BestFaceIndex makes SearchByImageWithIndex search with the most prominent face of the image
+// (largest bounding box weighted by detection confidence) instead of a fixed index
+const BestFaceIndex = -1
+
+// FolderFaceMatches is the face search matches in one shared folder
+type FolderFaceMatches struct {
+	FolderID   uuid.UUID
+	FolderName string
+	Matches    []FaceSearchResult // Most similar first
+}
+
+// FaceIndexStatus tells whether face search over the user's folders sees every photo yet.
+// While photos are still queued for face processing (e.g. right after an import), matches in
+// them can't be found, so results may be incomplete.
+type FaceIndexStatus struct {
+	Complete bool                // No photo of any folder is waiting for face processing
+	Pending  int64               // Photos pending or in face processing across the folders
+	Folders  []FolderIndexStatus // Only folders that are not ready, most pending first
+}
+
+// FolderIndexStatus is the face processing backlog of one folder
+type FolderIndexStatus struct {
+	FolderID   uuid.UUID `json:"folder_id"`
+	FolderName string    `json:"folder_name"`
+	Pending    int64     `json:"pending"`
+}
+
+// FaceSearchBatch is the matches of one folder in a streamed face search
+type FaceSearchBatch struct {
+	FolderID uuid.UUID
+	Results  []FaceSearchResult // The folder's top matches, most similar first
+	Err      error              // Set on the last batch if the search failed
+}
+
 // FaceSearchResult represents a face search result
 type FaceSearchResult struct {
 	Face       models.Face
@@ -23,6 +63,27 @@ type FaceSearchResult struct {
 	Similarity float64
 }
 
+// PersonFolder is a shared folder a person appears in
+type PersonFolder struct {
+	FolderID   uuid.UUID `json:"folder_id"`
+	FolderName string    `json:"folder_name"`
+	PhotoCount int64     `json:"photo_count"`
+	FaceCount  int64     `json:"face_count"`
+}
+
+// FaceCluster is a suggested group of unassigned faces that likely show the same person
+type FaceCluster struct {
+	FaceIDs []uuid.UUID   // Most confident first
+	Samples []models.Face // Up to a few most confident faces with Photo loaded; the first is the representative
+}
+
+// FaceClusterResult is the outcome of clustering a folder's unassigned faces
+type FaceClusterResult struct {
+	Clusters        []FaceCluster // Largest first, only groups of two or more faces
+	FacesConsidered int           // Unassigned faces compared (the most confident ones if the folder has more)
+	MaxFaces        int           // Upper bound of FacesConsidered per run
+}
+
 // DetectedFace represents a face detected in an uploaded image
 type DetectedFace struct {
 	Index      int       `json:"index"`
@@ -34,6 +95,13 @@ type DetectedFace struct {
 	Embedding  []float32 `json:"-"` // Hidden from JSON, used internally
 }
 
+// DetectSearchResult is the faces detected in an uploaded image and the matches of one of them
+type DetectSearchResult struct {
+	Faces     []DetectedFace
+	FaceIndex int // Index of the face that was searched (resolved when BestFaceIndex was requested)
+	Results   []FaceSearchResult
+}
+
 // FaceService handles face-related operations
 type FaceService interface {
 	// Detect faces in an uploaded image (returns all faces with bounding boxes)
@@ -42,9 +110,23 @@ type FaceService interface {
 	// Search by uploading a photo with face index selection
 	SearchByImageWithIndex(ctx context.Context, userID uuid.UUID, imageData []byte, mimeType string, faceIndex int, limit int, threshold float64) ([]FaceSearchResult, error)
 
-	// Search by uploading a photo (uses first face - legacy)
+	// Detect all faces of an uploaded photo and search with one of them (BestFaceIndex = most prominent),
+	// extracting faces only once
+	DetectAndSearch(ctx context.Context, userID uuid.UUID, imageData []byte, mimeType string, faceIndex int, limit int, threshold float64) (*DetectSearchResult, error)
+
+	// Search by uploading a photo, delivering matches per folder as each folder's search finishes.
+	// Returns the number of folders searched; the channel is closed when all are done.
+	StreamSearchByImage(ctx context.Context, userID uuid.UUID, imageData []byte, mimeType string, faceIndex int, limit int, threshold float64) (<-chan FaceSearchBatch, int, error)
+
+	// Find the user in all accessible folders from a selfie (best face), matches grouped by folder
+	SearchMyFace(ctx context.Context, userID uuid.UUID, imageData []byte, mimeType string, limit int, threshold float64) ([]FolderFaceMatches, error)
+
+	// Search by uploading a photo (uses the most prominent face - legacy)
 	SearchByImage(ctx context.Context, userID uuid.UUID, imageData []byte, mimeType string, limit int, threshold float64) ([]FaceSearchResult, error)
 
+	// Search with the centroid of all faces tagged as a person (finds them at more angles than one face)
+	SearchByPerson(ctx context.Context, userID uuid.UUID, personID uuid.UUID, limit int, threshold float64) ([]FaceSearchResult, error)
+
 	// Search by existing face ID
 	SearchByFaceID(ctx context.Context, userID uuid.UUID, faceID uuid.UUID, limit int, threshold float64) ([]FaceSearchResult, error)
 
@@ -54,8 +136,31 @@ type FaceService interface {
 	// Get faces for a person
 	GetFacesByPerson(ctx context.Context, userID uuid.UUID, personID uuid.UUID) ([]models.Face, error)
 
+	// Get the folders (accessible to the user) a person's faces appear in, with counts
+	GetPersonFolders(ctx context.Context, userID uuid.UUID, personID uuid.UUID) ([]PersonFolder, error)
+
 	// Get all faces with pagination
-	GetFaces(ctx context.Context, userID uuid.UUID, page, limit int) ([]models.Face, int64, error)
+	GetFaces(ctx context.Context, userID uuid.UUID, page, limit int, assigned *bool) ([]models.Face, int64, error)
+
+	// Get faces in a folder below a confidence threshold (review queue for false positives)
+	GetLowConfidenceFaces(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, maxConfidence float64, page, limit int) ([]models.Face, int64, error)
+
+	// Group a folder's unassigned faces by similarity into suggested persons
+	ClusterFaces(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, threshold float64) (*FaceClusterResult, error)
+
+	// Delete faces (e.g. false positives); all faces must be in folders the user can access.
+	// Keeps photo and person face counts consistent.
+	DeleteFaces(ctx context.Context, userID uuid.UUID, faceIDs []uuid.UUID) (*DeleteFacesResult, error)
+
+	// Privacy opt-out: redact a person (all their current and future faces) or specific faces
+	RedactPerson(ctx context.Context, userID uuid.UUID, personID uuid.UUID) (int64, error)
+	RedactFaces(ctx context.Context, userID uuid.UUID, faceIDs []uuid.UUID) (int64, error)
+
+	// Merge mergeID into keepID: all faces move to keepID and mergeID is deleted
+	MergePersons(ctx context.Context, userID uuid.UUID, keepID uuid.UUID, mergeID uuid.UUID) (*models.Person, error)
+
+	// Split: move some of a person's faces to a new person with the given name
+	SplitPerson(ctx context.Context, userID uuid.UUID, personID uuid.UUID, faceIDs []uuid.UUID, name string) (*models.Person, error)
 
 	// Assign face to a person
 	AssignFaceToPerson(ctx context.Context, userID uuid.UUID, faceID uuid.UUID, personID uuid.UUID) error
@@ -66,6 +171,9 @@ type FaceService interface {
 	// Get face count
 	GetFaceCount(ctx context.Context, userID uuid.UUID) (int64, error)
 
+	// Whether face search over the user's folders is complete or photos are still being processed
+	GetFaceIndexStatus(ctx context.Context, userID uuid.UUID) (*FaceIndexStatus, error)
+
 	// Get processing stats
 	GetProcessingStats(ctx context.Context, userID uuid.UUID) (*FaceProcessingStats, error)
 
@@ -78,13 +186,23 @@ type FaceService interface {
 	// Get ALL pending photos globally (admin only)
 	GetAllPendingPhotos(ctx context.Context, limit int) ([]models.Photo, error)
 
-	// Reset photos to pending status (force reprocess)
-	ResetPhotosToPending(ctx context.Context, userID uuid.UUID, photoIDs []uuid.UUID) (int64, error)
+	// Reset photos to pending status (reprocess); force also re-extracts photos the current model found faceless
+	ResetPhotosToPending(ctx context.Context, userID uuid.UUID, photoIDs []uuid.UUID, force bool) (int64, error)
+
+	// Delete a folder's faces and queue all its photos again (e.g. after a face model upgrade)
+	ReprocessFolder(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (int64, error)
 
 	// Reset photos stuck in "processing" status back to "pending" (admin only)
 	ResetStuckProcessing(ctx context.Context) (int64, error)
 }
 
+// DeleteFacesResult contains the outcome of a bulk face delete
+type DeleteFacesResult struct {
+	DeletedCount   int64 `json:"deleted_count"`
+	PhotosUpdated  int   `json:"photos_updated"`
+	PersonsUpdated int   `json:"persons_updated"`
+}
+
 // FaceProcessingStats contains face processing statistics
 type FaceProcessingStats struct {
 	TotalPhotos     int64 `json:"total_photos"`
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+// Custom errors for public search
+var (
+	ErrPublicLinkNotFound = errors.New("public search link not found")
+	ErrPublicLinkExpired  = errors.New("public search link has expired")
+	ErrNotFolderOwner     = errors.New("only the folder owner can create public search links")
+)
+
+// DefaultPublicSearchResults is used when a link has no explicit result cap
+const DefaultPublicSearchResults = 20
+
+// PublicSearchService handles anonymous, token-gated face search scoped to one folder
+type PublicSearchService interface {
+	// Mint a public search link for a folder (folder owner only)
+	CreateLink(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, expiresIn time.Duration, maxResults int) (*models.PublicSearchLink, error)
+
+	// Search the link's folder using the first face in the uploaded image
+	Search(ctx context.Context, token string, imageData []byte, mimeType string, threshold float64) (*models.PublicSearchLink, []FaceSearchResult, error)
+}
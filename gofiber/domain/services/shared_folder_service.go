@@ -2,21 +2,117 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"gofiber-template/domain/models"
+	"gofiber-template/infrastructure/googledrive"
 )
 
+// FolderTreeNode is a sub-folder in a shared folder's folder tree
+type FolderTreeNode struct {
+	Name            string            `json:"name"`
+	Path            string            `json:"path"`              // Full drive_folder_path ("" for the shared folder itself)
+	PhotoCount      int64             `json:"photo_count"`       // Photos directly in this folder
+	TotalPhotoCount int64             `json:"total_photo_count"` // Photos in this folder and all descendants
+	Children        []*FolderTreeNode `json:"children"`
+}
+
+// FolderDashboard is the aggregate statistics of one shared folder (non-trashed photos only)
+type FolderDashboard struct {
+	FolderID        uuid.UUID                  `json:"folder_id"`
+	FolderName      string                     `json:"folder_name"`
+	TotalPhotos     int64                      `json:"total_photos"`
+	PhotosWithFaces int64                      `json:"photos_with_faces"`
+	TotalFaces      int64                      `json:"total_faces"`
+	DistinctPeople  int64                      `json:"distinct_people"`
+	FirstPhotoAt    *time.Time                 `json:"first_photo_at"` // Earliest/latest Drive creation time
+	LastPhotoAt     *time.Time                 `json:"last_photo_at"`
+	TopSubFolders   []FolderDashboardSubFolder `json:"top_subfolders"` // Largest sub-folders by direct photo count
+}
+
+// FolderDashboardSubFolder is a sub-folder ranked on the folder dashboard
+type FolderDashboardSubFolder struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	PhotoCount int64  `json:"photo_count"`
+}
+
+// FolderReconcileReport compares a folder's Drive listing with its photos (nothing is changed)
+type FolderReconcileReport struct {
+	FolderID        uuid.UUID `json:"folder_id"`
+	DriveFileCount  int       `json:"drive_file_count"`  // Media files listed in Drive, excluded paths included
+	DBPhotoCount    int       `json:"db_photo_count"`    // Non-trashed photos synced from Drive
+	MissingInDB     int       `json:"missing_in_db"`     // In Drive but not indexed (a full sync would add them)
+	OrphanedInDB    int       `json:"orphaned_in_db"`    // Indexed but gone from Drive (a full sync would trash or delete them)
+	ExcludedInDrive int       `json:"excluded_in_drive"` // Not indexed because they are under exclude paths (not counted as missing)
+
+	// Up to 50 Drive file IDs of each kind, for investigation
+	MissingSample  []string  `json:"missing_sample"`
+	OrphanedSample []string  `json:"orphaned_sample"`
+	CheckedAt      time.Time `json:"checked_at"`
+}
+
 type SharedFolderService interface {
 	// Folder management
-	AddFolder(ctx context.Context, userID uuid.UUID, driveFolderID, resourceKey string, accessToken, refreshToken string) (*models.SharedFolder, error)
+	AddFolder(ctx context.Context, userID uuid.UUID, driveFolderID, resourceKey string, accessToken, refreshToken string, deferFaceProcessing bool) (*models.SharedFolder, error)
 	GetUserFolders(ctx context.Context, userID uuid.UUID) ([]models.SharedFolder, error)
 	GetFolderByID(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (*models.SharedFolder, error)
+	GetFolderTree(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (*FolderTreeNode, error)
+	GetFolderDashboard(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (*FolderDashboard, error)
 	RemoveUserAccess(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) error
 
 	// Sync operations
 	TriggerSync(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, forceFullSync bool) error
+	TriggerDryRunSync(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, forceFullSync bool) (*models.SyncJob, error) // Preview counts only, nothing is written
 	GetSyncStatus(ctx context.Context, folderID uuid.UUID) (*models.SharedFolder, error)
+	CancelSync(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (*models.SyncJob, error)
+
+	// Scheduled sync: set/clear a folder's cron schedule, and register all schedules on startup
+	SetSyncSchedule(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, cronExpr string) (*models.SharedFolder, error)
+	RegisterSyncSchedules(ctx context.Context) (registered int, err error)
+
+	// Refresh thumbnail/webView links and names of existing photos (no face reprocessing)
+	RefreshPhotoMetadata(ctx context.Context, folderID uuid.UUID) (updated int, err error)
+
+	// Enable face processing for a folder added with deferred face processing (skipped -> pending)
+	EnableFaceProcessing(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (enabled int64, err error)
+
+	// Switch face processing for a folder on/off; switching on queues its skipped photos (skipped -> pending)
+	SetFaceProcessingEnabled(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, enabled bool) (requeued int64, err error)
+
+	// Upload a photo directly into a folder (stored on Bunny, not Drive) and queue it for face processing
+	UploadPhoto(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, fileName, contentType string, data []byte) (*models.Photo, error)
+
+	// Take a trashed photo out of the trash in our database only (the Drive file is not touched)
+	RestorePhoto(ctx context.Context, userID uuid.UUID, photoID uuid.UUID) (*models.Photo, error)
+
+	// Set how full sync handles photos missing from Drive (empty policy = use the global default)
+	SetOrphanPolicy(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, policy models.OrphanPolicy) error
+
+	// Set the sub-folder paths sync skips; returns the normalized list that was saved
+	SetExcludePaths(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, paths []string) ([]string, error)
+
+	// List a photo's Drive revisions; supported is false for uploaded photos and files whose revisions can't be read
+	GetPhotoRevisions(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, photoID uuid.UUID) (revisions []googledrive.DriveRevision, supported bool, err error)
+
+	// Compare the Drive listing with the folder's photos without changing anything (admin diagnostics)
+	Reconcile(ctx context.Context, folderID uuid.UUID) (*FolderReconcileReport, error)
+
+	// Recompute photos' face_count from the actual faces rows (admin maintenance)
+	ReconcileFaceCounts(ctx context.Context, folderID uuid.UUID) (fixed int64, err error)
+
+	// Zip export (async, built server-side and uploaded to storage)
+	CreateExport(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, folderPath string) (*models.SyncJob, error)
+	GetExport(ctx context.Context, userID uuid.UUID, exportID uuid.UUID) (*models.SyncJob, error)
+
+	// Failed sync jobs (admin dead-letter view)
+	ListFailedSyncJobs(ctx context.Context, page, limit int) ([]models.SyncJob, int64, error)
+	RetrySyncJob(ctx context.Context, jobID uuid.UUID) (*models.SyncJob, error)
+
+	// Failed sync jobs of the user's folders (retry requires access to the job's folder)
+	ListUserFailedSyncJobs(ctx context.Context, userID uuid.UUID, page, limit int) ([]models.SyncJob, int64, error)
+	RetryUserSyncJob(ctx context.Context, userID uuid.UUID, jobID uuid.UUID) (*models.SyncJob, error)
 
 	// Webhook handling
 	HandleWebhook(ctx context.Context, channelID, resourceID, resourceState, token string) error
@@ -26,4 +122,7 @@ type SharedFolderService interface {
 
 	// Webhook maintenance
 	RenewExpiringWebhooks(ctx context.Context) (renewed int, failed int, err error)
+
+	// Stop a Drive watch channel with the folder's tokens (admin remediation)
+	StopWebhookChannel(ctx context.Context, folderID uuid.UUID, channelID, resourceID string) error
 }
@@ -53,16 +53,36 @@ type HealthResponse struct {
 	Version string `json:"version"`
 }
 
-// NewFaceClient creates a new face API client
-func NewFaceClient(baseURL string) *FaceClient {
+// ModelVersion identifies the face model that served the request ("model@version"), empty if unknown
+func (h *HealthResponse) ModelVersion() string {
+	if h.Model == "" && h.Version == "" {
+		return ""
+	}
+	return h.Model + "@" + h.Version
+}
+
+// defaultTimeout is the request timeout used when none is configured
+const defaultTimeout = 120 * time.Second
+
+// NewFaceClient creates a new face API client. Face processing can take long on CPU-only
+// inference, so timeout should cover the largest images (<= 0 uses 120s)
+func NewFaceClient(baseURL string, timeout time.Duration) *FaceClient {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
 	return &FaceClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: 120 * time.Second, // Face processing can take time, especially on CPU
+			Timeout: timeout,
 		},
 	}
 }
 
+// Timeout returns the per-request timeout
+func (c *FaceClient) Timeout() time.Duration {
+	return c.httpClient.Timeout
+}
+
 // ExtractFaces extracts faces from an image URL
 func (c *FaceClient) ExtractFaces(ctx context.Context, imageURL string) (*ExtractResponse, error) {
 	reqBody := ExtractRequest{
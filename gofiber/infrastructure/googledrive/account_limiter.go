@@ -0,0 +1,168 @@
+package googledrive
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// accountKey carries the Google account (token owner) a Drive call is made for
+type accountKey struct{}
+
+// WithAccount tags ctx with the token owner whose Drive quota the calls made with it use.
+// Calls on an untagged context are not rate limited.
+func WithAccount(ctx context.Context, ownerID uuid.UUID) context.Context {
+	return context.WithValue(ctx, accountKey{}, ownerID)
+}
+
+func accountFromContext(ctx context.Context) uuid.UUID {
+	ownerID, _ := ctx.Value(accountKey{}).(uuid.UUID)
+	return ownerID
+}
+
+// accountIdleAfter is how long an unused bucket is kept (a full bucket is the same as no bucket)
+const accountIdleAfter = 10 * time.Minute
+
+// AccountLimiter is a token bucket per Google account, shared by every worker that calls Drive,
+// so concurrent syncs and face processing of folders owned by one user stay within that user's quota
+type AccountLimiter struct {
+	mu      sync.Mutex
+	rate    float64 // Tokens added per second (0 = unlimited)
+	burst   float64 // Bucket size
+	buckets map[uuid.UUID]*accountBucket
+}
+
+type accountBucket struct {
+	tokens   float64
+	updated  time.Time
+	waiting  int   // Calls currently blocked on this bucket
+	waits    int64 // Calls that had to wait since the bucket was created
+	lastUsed time.Time
+}
+
+// AccountUsage is the limiter state of one account (for the health endpoint)
+type AccountUsage struct {
+	OwnerID     uuid.UUID `json:"owner_id"`
+	Utilization float64   `json:"utilization"` // Share of the burst in use, 0-1
+	Waiting     int       `json:"waiting"`
+	TotalWaits  int64     `json:"total_waits"`
+}
+
+// AccountLimiterStats summarizes the limiter (for the health endpoint)
+type AccountLimiterStats struct {
+	Enabled       bool           `json:"enabled"`
+	RatePerSecond float64        `json:"rate_per_second"`
+	Burst         int            `json:"burst"`
+	Accounts      []AccountUsage `json:"accounts"`
+}
+
+// NewAccountLimiter creates a limiter allowing ratePerSecond Drive calls per account with bursts up to burst
+func NewAccountLimiter(ratePerSecond float64, burst int) *AccountLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &AccountLimiter{
+		rate:    ratePerSecond,
+		burst:   float64(burst),
+		buckets: make(map[uuid.UUID]*accountBucket),
+	}
+}
+
+// Wait blocks until the account tagged on ctx may make one Drive call, or ctx is done
+func (l *AccountLimiter) Wait(ctx context.Context) error {
+	ownerID := accountFromContext(ctx)
+	if l == nil || l.rate <= 0 || ownerID == uuid.Nil {
+		return nil
+	}
+
+	counted := false
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		b := l.bucket(ownerID, now)
+		if b.tokens >= 1 {
+			b.tokens--
+			if counted {
+				b.waiting--
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		if !counted {
+			counted = true
+			b.waiting++
+			b.waits++
+		}
+		delay := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			l.mu.Lock()
+			l.bucket(ownerID, time.Now()).waiting--
+			l.mu.Unlock()
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// bucket returns the refilled bucket of an account, creating it full. Caller holds l.mu.
+func (l *AccountLimiter) bucket(ownerID uuid.UUID, now time.Time) *accountBucket {
+	b, ok := l.buckets[ownerID]
+	if !ok {
+		l.pruneIdle(now)
+		b = &accountBucket{tokens: l.burst, updated: now}
+		l.buckets[ownerID] = b
+	}
+
+	b.tokens += now.Sub(b.updated).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.updated = now
+	b.lastUsed = now
+	return b
+}
+
+// pruneIdle drops buckets unused for accountIdleAfter. Caller holds l.mu.
+func (l *AccountLimiter) pruneIdle(now time.Time) {
+	for ownerID, b := range l.buckets {
+		if b.waiting == 0 && now.Sub(b.lastUsed) > accountIdleAfter {
+			delete(l.buckets, ownerID)
+		}
+	}
+}
+
+// Stats returns the current utilization of every tracked account, busiest first
+func (l *AccountLimiter) Stats() AccountLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := AccountLimiterStats{
+		Enabled:       l.rate > 0,
+		RatePerSecond: l.rate,
+		Burst:         int(l.burst),
+		Accounts:      make([]AccountUsage, 0, len(l.buckets)),
+	}
+
+	now := time.Now()
+	for ownerID, b := range l.buckets {
+		// Refill without touching the bucket, so reading stats doesn't keep idle accounts alive
+		tokens := min(b.tokens+now.Sub(b.updated).Seconds()*l.rate, l.burst)
+		stats.Accounts = append(stats.Accounts, AccountUsage{
+			OwnerID:     ownerID,
+			Utilization: 1 - tokens/l.burst,
+			Waiting:     b.waiting,
+			TotalWaits:  b.waits,
+		})
+	}
+	sort.Slice(stats.Accounts, func(i, j int) bool {
+		return stats.Accounts[i].Utilization > stats.Accounts[j].Utilization
+	})
+
+	return stats
+}
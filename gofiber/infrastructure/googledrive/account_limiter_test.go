@@ -0,0 +1,137 @@
+package googledrive
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// tryWait reports whether a call for ownerID is allowed within d
+func tryWait(t *testing.T, l *AccountLimiter, ownerID uuid.UUID, d time.Duration) bool {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(WithAccount(context.Background(), ownerID), d)
+	defer cancel()
+	err := l.Wait(ctx)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait: unexpected error %v", err)
+	}
+	return err == nil
+}
+
+func TestAccountLimiterBurst(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+	}{
+		{name: "burst of one", rate: 1, burst: 1},
+		{name: "burst of five", rate: 1, burst: 5},
+		{name: "burst below one is raised to one", rate: 1, burst: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewAccountLimiter(tt.rate, tt.burst)
+			owner := uuid.New()
+
+			want := max(tt.burst, 1)
+			for i := 0; i < want; i++ {
+				if !tryWait(t, l, owner, 10*time.Millisecond) {
+					t.Fatalf("call %d of the burst was delayed", i+1)
+				}
+			}
+			// At 1/s the next token is a second away
+			if tryWait(t, l, owner, 20*time.Millisecond) {
+				t.Fatalf("call %d went through past the burst", want+1)
+			}
+		})
+	}
+}
+
+func TestAccountLimiterRefill(t *testing.T) {
+	l := NewAccountLimiter(50, 1) // One token every 20ms
+	owner := uuid.New()
+
+	if !tryWait(t, l, owner, 10*time.Millisecond) {
+		t.Fatal("first call was delayed")
+	}
+
+	start := time.Now()
+	if !tryWait(t, l, owner, time.Second) {
+		t.Fatal("call after the burst never got a refilled token")
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("call after the burst waited %v, want about 20ms", elapsed)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !tryWait(t, l, owner, 5*time.Millisecond) {
+		t.Error("bucket did not refill while idle")
+	}
+}
+
+func TestAccountLimiterPerAccountIsolation(t *testing.T) {
+	l := NewAccountLimiter(1, 2)
+	busy, other := uuid.New(), uuid.New()
+
+	for i := 0; i < 2; i++ {
+		tryWait(t, l, busy, 10*time.Millisecond)
+	}
+	if tryWait(t, l, busy, 10*time.Millisecond) {
+		t.Fatal("exhausted account was not limited")
+	}
+
+	for i := 0; i < 2; i++ {
+		if !tryWait(t, l, other, 10*time.Millisecond) {
+			t.Fatalf("call %d of another account was delayed by the exhausted one", i+1)
+		}
+	}
+}
+
+func TestAccountLimiterUnlimited(t *testing.T) {
+	tests := []struct {
+		name    string
+		limiter *AccountLimiter
+		owner   uuid.UUID
+	}{
+		{name: "nil limiter", limiter: nil, owner: uuid.New()},
+		{name: "rate zero", limiter: NewAccountLimiter(0, 1), owner: uuid.New()},
+		{name: "untagged context", limiter: NewAccountLimiter(1, 1), owner: uuid.Nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				if !tryWait(t, tt.limiter, tt.owner, 5*time.Millisecond) {
+					t.Fatalf("call %d was limited", i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestAccountLimiterCancelledWaitStats(t *testing.T) {
+	l := NewAccountLimiter(1, 1)
+	owner := uuid.New()
+
+	tryWait(t, l, owner, 10*time.Millisecond)
+	if tryWait(t, l, owner, 10*time.Millisecond) {
+		t.Fatal("second call was not limited")
+	}
+
+	stats := l.Stats()
+	if !stats.Enabled || stats.Burst != 1 || len(stats.Accounts) != 1 {
+		t.Fatalf("stats = %+v, want one enabled account with burst 1", stats)
+	}
+	usage := stats.Accounts[0]
+	if usage.OwnerID != owner || usage.Waiting != 0 || usage.TotalWaits != 1 {
+		t.Errorf("usage = %+v, want no waiting calls and one total wait", usage)
+	}
+	if usage.Utilization < 0.9 {
+		t.Errorf("utilization = %v, want about 1 right after the burst", usage.Utilization)
+	}
+}
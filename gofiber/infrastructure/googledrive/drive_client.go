@@ -3,6 +3,7 @@ package googledrive
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,16 +13,35 @@ import (
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 
 	"gofiber-template/pkg/config"
+	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/utils"
 )
 
+// ErrThumbnailNotReady means Drive has not generated a thumbnail yet (common for fresh uploads)
+var ErrThumbnailNotReady = errors.New("thumbnail not ready yet")
+
 // DriveClient handles Google Drive API operations
 type DriveClient struct {
 	config      *oauth2.Config
 	webhookURL  string
 	httpClient  *http.Client
+
+	webhookSigner *utils.WebhookTokenSigner // Signs channel tokens so webhook calls can be verified
+
+	mimePrefixes []string // Synced file types (e.g. "image/", "video/")
+
+	captureImageMetadata bool // Request imageMediaMetadata (dimensions) when listing files and changes
+
+	maxAttempts    int           // Attempts per List/Get call on rate-limit and 5xx errors
+	retryBaseDelay time.Duration // Backoff doubles each retry: base, 2x base, 4x base...
+
+	thumbnailTimeout time.Duration // Limit for fetching one thumbnail image (metadata lookup excluded)
+
+	limiter *AccountLimiter // Per Google account call rate (accounts tagged with WithAccount)
 }
 
 // DriveFile represents a file/folder from Google Drive
@@ -36,6 +56,25 @@ type DriveFile struct {
 	ParentID     string
 	CreatedTime  time.Time
 	ModifiedTime time.Time
+	Width        int // Image dimensions, only set when image metadata capture is enabled
+	Height       int
+
+	// EXIF capture metadata, only set when image metadata capture is enabled and the image has it
+	TakenAt     *time.Time
+	CameraMake  string
+	CameraModel string
+}
+
+// DriveRevision is one stored version of a Drive file's content
+type DriveRevision struct {
+	ID               string    `json:"id"`
+	ModifiedTime     time.Time `json:"modified_time"`
+	MimeType         string    `json:"mime_type"`
+	Size             int64     `json:"size"`
+	OriginalFilename string    `json:"original_filename,omitempty"`
+	MD5Checksum      string    `json:"md5_checksum,omitempty"`
+	KeepForever      bool      `json:"keep_forever"`
+	ModifiedBy       string    `json:"modified_by,omitempty"` // Display name of the last modifying user
 }
 
 // DriveFolder represents a folder from Google Drive
@@ -83,13 +122,68 @@ func NewDriveClient(cfg config.GoogleDriveConfig) *DriveClient {
 		Endpoint: google.Endpoint,
 	}
 
+	mimePrefixes := cfg.SyncMimePrefixes
+	if len(mimePrefixes) == 0 {
+		mimePrefixes = []string{"image/"}
+	}
+
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	retryBaseDelay := time.Duration(cfg.RetryBaseMs) * time.Millisecond
+	if retryBaseDelay <= 0 {
+		retryBaseDelay = time.Second
+	}
+	thumbnailTimeout := time.Duration(cfg.ThumbnailTimeoutSeconds) * time.Second
+	if thumbnailTimeout <= 0 {
+		thumbnailTimeout = 30 * time.Second
+	}
+
 	return &DriveClient{
 		config:     oauthConfig,
 		webhookURL: cfg.WebhookURL,
 		httpClient: &http.Client{Timeout: 30 * time.Second},
+
+		webhookSigner: utils.NewWebhookTokenSigner(cfg.WebhookSecret),
+
+		mimePrefixes: mimePrefixes,
+
+		captureImageMetadata: cfg.CaptureImageMetadata,
+
+		maxAttempts:    maxAttempts,
+		retryBaseDelay: retryBaseDelay,
+
+		thumbnailTimeout: thumbnailTimeout,
+
+		limiter: NewAccountLimiter(float64(cfg.AccountRatePerSecond), cfg.AccountBurst),
 	}
 }
 
+// AccountLimiter returns the per-account Drive call limiter (for health reporting)
+func (c *DriveClient) AccountLimiter() *AccountLimiter {
+	return c.limiter
+}
+
+// IsSupportedMimeType reports whether files of this mime type are synced
+func (c *DriveClient) IsSupportedMimeType(mimeType string) bool {
+	for _, prefix := range c.mimePrefixes {
+		if strings.HasPrefix(mimeType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// mimeTypeQuery builds the Drive query clause matching the synced mime types
+func (c *DriveClient) mimeTypeQuery() string {
+	clauses := make([]string, len(c.mimePrefixes))
+	for i, prefix := range c.mimePrefixes {
+		clauses[i] = fmt.Sprintf("mimeType contains '%s'", strings.ReplaceAll(prefix, "'", "\\'"))
+	}
+	return "(" + strings.Join(clauses, " or ") + ")"
+}
+
 // GetAuthURL generates the OAuth authorization URL
 func (c *DriveClient) GetAuthURL(state string) string {
 	return c.config.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.ApprovalForce)
@@ -217,7 +311,11 @@ func (c *DriveClient) ListFolders(ctx context.Context, srv *drive.Service, paren
 			call = call.PageToken(pageToken)
 		}
 
-		result, err := call.Do()
+		var result *drive.FileList
+		err := c.withRetry(ctx, "files.list", func() (err error) {
+			result, err = call.Do()
+			return err
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to list folders: %w", err)
 		}
@@ -246,14 +344,36 @@ func (c *DriveClient) ListFolders(ctx context.Context, srv *drive.Service, paren
 	return folders, nil
 }
 
-// ListImages lists all image files in the given folder (recursive)
+// fileFields adds imageMediaMetadata to a file field selection when image metadata capture is on.
+// The metadata block (camera, location, EXIF...) is large, so it is only requested when used.
+func (c *DriveClient) fileFields(fields string) string {
+	if c.captureImageMetadata {
+		return fields + ", imageMediaMetadata(width, height, time, cameraMake, cameraModel)"
+	}
+	return fields
+}
+
+// ImageTakenAt returns the EXIF capture time Drive reports for an image (nil if missing or unparsable).
+// Drive passes the EXIF value through without a time zone, so it is stored as UTC wall clock time.
+func ImageTakenAt(m *drive.FileImageMediaMetadata) *time.Time {
+	if m == nil || m.Time == "" {
+		return nil
+	}
+	t, err := utils.ParseExifTime(m.Time, "")
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// ListImages lists the synced media files (images, and videos if enabled) in the given folder
 func (c *DriveClient) ListImages(ctx context.Context, srv *drive.Service, folderID string, pageToken string) ([]DriveFile, string, error) {
-	// Query for images in the folder
-	query := fmt.Sprintf("'%s' in parents and trashed=false and (mimeType contains 'image/')", folderID)
+	// Query for synced media types in the folder
+	query := fmt.Sprintf("'%s' in parents and trashed=false and %s", folderID, c.mimeTypeQuery())
 
 	call := srv.Files.List().
 		Q(query).
-		Fields("nextPageToken, files(id, name, mimeType, size, description, thumbnailLink, webViewLink, parents, createdTime, modifiedTime, imageMediaMetadata)").
+		Fields(googleapi.Field("nextPageToken, files(" + c.fileFields("id, name, mimeType, size, description, thumbnailLink, webViewLink, parents, createdTime, modifiedTime") + ")")).
 		PageSize(100).
 		SupportsAllDrives(true).
 		IncludeItemsFromAllDrives(true)
@@ -262,7 +382,11 @@ func (c *DriveClient) ListImages(ctx context.Context, srv *drive.Service, folder
 		call = call.PageToken(pageToken)
 	}
 
-	result, err := call.Do()
+	var result *drive.FileList
+	err := c.withRetry(ctx, "files.list", func() (err error) {
+		result, err = call.Do()
+		return err
+	})
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to list images: %w", err)
 	}
@@ -276,7 +400,7 @@ func (c *DriveClient) ListImages(ctx context.Context, srv *drive.Service, folder
 			parentID = f.Parents[0]
 		}
 
-		files = append(files, DriveFile{
+		file := DriveFile{
 			ID:           f.Id,
 			Name:         f.Name,
 			MimeType:     f.MimeType,
@@ -287,7 +411,15 @@ func (c *DriveClient) ListImages(ctx context.Context, srv *drive.Service, folder
 			ParentID:     parentID,
 			CreatedTime:  createdTime,
 			ModifiedTime: modifiedTime,
-		})
+		}
+		if f.ImageMediaMetadata != nil {
+			file.Width = int(f.ImageMediaMetadata.Width)
+			file.Height = int(f.ImageMediaMetadata.Height)
+			file.TakenAt = ImageTakenAt(f.ImageMediaMetadata)
+			file.CameraMake = f.ImageMediaMetadata.CameraMake
+			file.CameraModel = f.ImageMediaMetadata.CameraModel
+		}
+		files = append(files, file)
 	}
 
 	return files, result.NextPageToken, nil
@@ -331,10 +463,14 @@ func (c *DriveClient) ListAllImagesRecursive(ctx context.Context, srv *drive.Ser
 
 // GetFile gets a single file's metadata
 func (c *DriveClient) GetFile(ctx context.Context, srv *drive.Service, fileID string) (*DriveFile, error) {
-	f, err := srv.Files.Get(fileID).
+	call := srv.Files.Get(fileID).
 		Fields("id, name, mimeType, size, description, thumbnailLink, webViewLink, parents, createdTime, modifiedTime").
-		SupportsAllDrives(true).
-		Do()
+		SupportsAllDrives(true)
+	var f *drive.File
+	err := c.withRetry(ctx, "files.get", func() (err error) {
+		f, err = call.Do()
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file: %w", err)
 	}
@@ -360,8 +496,70 @@ func (c *DriveClient) GetFile(ctx context.Context, srv *drive.Service, fileID st
 	}, nil
 }
 
+// ListRevisions returns a file's revisions, oldest first. supported is false (with no error)
+// when the caller lacks the canReadRevisions capability on the file.
+func (c *DriveClient) ListRevisions(ctx context.Context, srv *drive.Service, fileID string) (revisions []DriveRevision, supported bool, err error) {
+	var f *drive.File
+	err = c.withRetry(ctx, "files.get", func() (err error) {
+		f, err = srv.Files.Get(fileID).Fields("capabilities/canReadRevisions").SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get file capabilities: %w", err)
+	}
+	if f.Capabilities == nil || !f.Capabilities.CanReadRevisions {
+		return []DriveRevision{}, false, nil
+	}
+
+	revisions = []DriveRevision{}
+	pageToken := ""
+	for {
+		call := srv.Revisions.List(fileID).
+			Fields("nextPageToken, revisions(id, modifiedTime, mimeType, size, originalFilename, md5Checksum, keepForever, lastModifyingUser/displayName)").
+			PageSize(200).
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		var result *drive.RevisionList
+		err = c.withRetry(ctx, "revisions.list", func() (err error) {
+			result, err = call.Do()
+			return err
+		})
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to list revisions: %w", err)
+		}
+
+		for _, r := range result.Revisions {
+			modifiedTime, _ := time.Parse(time.RFC3339, r.ModifiedTime)
+			revision := DriveRevision{
+				ID:               r.Id,
+				ModifiedTime:     modifiedTime,
+				MimeType:         r.MimeType,
+				Size:             r.Size,
+				OriginalFilename: r.OriginalFilename,
+				MD5Checksum:      r.Md5Checksum,
+				KeepForever:      r.KeepForever,
+			}
+			if r.LastModifyingUser != nil {
+				revision.ModifiedBy = r.LastModifyingUser.DisplayName
+			}
+			revisions = append(revisions, revision)
+		}
+
+		pageToken = result.NextPageToken
+		if pageToken == "" {
+			return revisions, true, nil
+		}
+	}
+}
+
 // DownloadFile downloads a file's content
 func (c *DriveClient) DownloadFile(ctx context.Context, srv *drive.Service, fileID string) (io.ReadCloser, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := srv.Files.Get(fileID).Download()
 	if err != nil {
 		return nil, fmt.Errorf("failed to download file: %w", err)
@@ -369,6 +567,20 @@ func (c *DriveClient) DownloadFile(ctx context.Context, srv *drive.Service, file
 	return resp.Body, nil
 }
 
+// DownloadFileStream opens a file's content for streaming, forwarding an optional HTTP Range header.
+// The caller must close the response body. Status is 206 when Drive served a partial range.
+func (c *DriveClient) DownloadFileStream(ctx context.Context, srv *drive.Service, fileID, rangeHeader string) (*http.Response, error) {
+	call := srv.Files.Get(fileID).SupportsAllDrives(true).Context(ctx)
+	if rangeHeader != "" {
+		call.Header().Set("Range", rangeHeader)
+	}
+	resp, err := call.Download()
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	return resp, nil
+}
+
 // DownloadThumbnail downloads a file's thumbnail using authenticated HTTP client
 func (c *DriveClient) DownloadThumbnail(ctx context.Context, accessToken, refreshToken string, expiry time.Time, fileID string, size int) ([]byte, string, error) {
 	// Create authenticated HTTP client
@@ -387,15 +599,19 @@ func (c *DriveClient) DownloadThumbnail(ctx context.Context, accessToken, refres
 	}
 
 	// Get file metadata with thumbnail link
-	file, err := srv.Files.Get(fileID).
-		Fields("id, thumbnailLink, mimeType").
-		Do()
+	var file *drive.File
+	err = c.withRetry(ctx, "files.get", func() (err error) {
+		file, err = srv.Files.Get(fileID).
+			Fields("id, thumbnailLink, mimeType").
+			Do()
+		return err
+	})
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get file metadata: %w", err)
 	}
 
 	if file.ThumbnailLink == "" {
-		return nil, "", fmt.Errorf("no thumbnail available for file %s", fileID)
+		return nil, "", fmt.Errorf("%w: file %s", ErrThumbnailNotReady, fileID)
 	}
 
 	// Modify thumbnail URL to get desired resolution
@@ -404,37 +620,108 @@ func (c *DriveClient) DownloadThumbnail(ctx context.Context, accessToken, refres
 		thumbnailURL = strings.Replace(thumbnailURL, "=s220", fmt.Sprintf("=s%d", size), 1)
 	}
 
-	// Fetch thumbnail with authenticated client
-	resp, err := httpClient.Get(thumbnailURL)
+	// Fetch thumbnail with authenticated client. Thumbnail links of fresh uploads briefly answer
+	// 403, so 403/429/5xx are retried a few times with a short backoff
+	var status int
+	for attempt := 1; ; attempt++ {
+		data, contentType, code, err := c.fetchThumbnail(ctx, httpClient, thumbnailURL)
+		if err != nil {
+			return nil, "", err
+		}
+		if code == http.StatusOK {
+			return data, contentType, nil
+		}
+		status = code
+		if !isRetryableThumbnailStatus(code) || attempt >= thumbnailFetchAttempts {
+			break
+		}
+
+		delay := thumbnailRetryBaseDelay << (attempt - 1)
+		logger.Drive("thumbnail_retry", "Thumbnail fetch failed, retrying", map[string]interface{}{
+			"file_id":  fileID,
+			"status":   code,
+			"attempt":  attempt,
+			"delay_ms": delay.Milliseconds(),
+		})
+		select {
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	if status == http.StatusNotFound || status == http.StatusForbidden {
+		// Link exists but the image is still being generated (or not yet readable)
+		return nil, "", fmt.Errorf("%w: file %s", ErrThumbnailNotReady, fileID)
+	}
+	return nil, "", fmt.Errorf("failed to fetch thumbnail: status %d", status)
+}
+
+// Thumbnail image fetches: attempts on 403/429/5xx and the first backoff delay (doubled per retry)
+const (
+	thumbnailFetchAttempts  = 3
+	thumbnailRetryBaseDelay = 500 * time.Millisecond
+)
+
+// isRetryableThumbnailStatus reports whether a thumbnail link status may succeed on a retry
+func isRetryableThumbnailStatus(status int) bool {
+	return status == http.StatusForbidden || status == http.StatusTooManyRequests || status >= 500
+}
+
+// fetchThumbnail downloads a thumbnail link once. The body is only read on 200; other statuses
+// are returned without an error so the caller can decide whether to retry. The request follows
+// ctx, so an abandoned request (e.g. a gallery scrolled past) stops downloading
+func (c *DriveClient) fetchThumbnail(ctx context.Context, httpClient *http.Client, thumbnailURL string) ([]byte, string, int, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, "", 0, err
+	}
+	fetchCtx, cancel := context.WithTimeout(ctx, c.thumbnailTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(fetchCtx, http.MethodGet, thumbnailURL, nil)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("failed to create thumbnail request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to fetch thumbnail: %w", err)
+		return nil, "", 0, fmt.Errorf("failed to fetch thumbnail: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, "", fmt.Errorf("failed to fetch thumbnail: status %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", resp.StatusCode, nil
 	}
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read thumbnail: %w", err)
+		return nil, "", 0, fmt.Errorf("failed to read thumbnail: %w", err)
 	}
 
-	contentType := resp.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "image/jpeg"
-	}
+	return data, thumbnailContentType(resp.Header.Get("Content-Type"), data), resp.StatusCode, nil
+}
 
-	return data, contentType, nil
+// thumbnailContentType trusts an image/* Content-Type header and otherwise sniffs the bytes,
+// since Drive serves png/webp thumbnails too; jpeg is only the last resort
+func thumbnailContentType(header string, data []byte) string {
+	if strings.HasPrefix(header, "image/") {
+		return header
+	}
+	if sniffed := http.DetectContentType(data); strings.HasPrefix(sniffed, "image/") {
+		return sniffed
+	}
+	return "image/jpeg"
 }
 
 // GetFileDownloadURL returns a direct download URL for a file
 // This URL can be used by external services to download the file
 func (c *DriveClient) GetFileDownloadURL(ctx context.Context, srv *drive.Service, fileID string) (string, error) {
 	// Get file metadata with webContentLink
-	file, err := srv.Files.Get(fileID).
-		Fields("id, webContentLink, thumbnailLink").
-		Do()
+	var file *drive.File
+	err := c.withRetry(ctx, "files.get", func() (err error) {
+		file, err = srv.Files.Get(fileID).
+			Fields("id, webContentLink, thumbnailLink").
+			Do()
+		return err
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get file: %w", err)
 	}
@@ -464,7 +751,11 @@ func (c *DriveClient) GetFolderPath(ctx context.Context, srv *drive.Service, fol
 
 	currentID := folderID
 	for currentID != "" {
-		folder, err := srv.Files.Get(currentID).Fields("id, name, parents").SupportsAllDrives(true).Do()
+		var folder *drive.File
+		err := c.withRetry(ctx, "files.get", func() (err error) {
+			folder, err = srv.Files.Get(currentID).Fields("id, name, parents").SupportsAllDrives(true).Do()
+			return err
+		})
 		if err != nil {
 			return "", fmt.Errorf("failed to get folder: %w", err)
 		}
@@ -492,7 +783,11 @@ func (c *DriveClient) ListAllFoldersRecursive(ctx context.Context, srv *drive.Se
 	var allFolders []DriveFolder
 
 	// Get root folder info first
-	rootFolder, err := srv.Files.Get(rootFolderID).Fields("id, name, parents").SupportsAllDrives(true).Do()
+	var rootFolder *drive.File
+	err := c.withRetry(ctx, "files.get", func() (err error) {
+		rootFolder, err = srv.Files.Get(rootFolderID).Fields("id, name, parents").SupportsAllDrives(true).Do()
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get root folder: %w", err)
 	}
@@ -626,7 +921,7 @@ func (c *DriveClient) WatchChanges(ctx context.Context, srv *drive.Service, chan
 		Id:         channelID,
 		Type:       "web_hook",
 		Address:    c.webhookURL,
-		Token:      webhookToken,
+		Token:      c.webhookSigner.Sign(webhookToken, channelID),
 		Expiration: time.Now().Add(7 * 24 * time.Hour).UnixMilli(), // 7 days
 	}
 
@@ -649,35 +944,76 @@ func (c *DriveClient) StopWatch(ctx context.Context, srv *drive.Service, channel
 	return srv.Channels.Stop(channel).Do()
 }
 
-// GetChanges gets changes since the given start page token
-func (c *DriveClient) GetChanges(ctx context.Context, srv *drive.Service, startPageToken string) ([]*drive.Change, string, error) {
-	var changes []*drive.Change
-	pageToken := startPageToken
+// Safety limits for GetChanges so a pathological change stream cannot loop forever
+const (
+	maxChangePages     = 100 // 100 pages x 100 changes = 10,000 changes per call
+	changeLogEveryPage = 10  // Log accumulated change count every N pages
+)
 
-	for {
-		result, err := srv.Changes.List(pageToken).
-			Fields("nextPageToken, newStartPageToken, changes(fileId, removed, time, file(id, name, mimeType, trashed, parents, thumbnailLink, webViewLink, createdTime, modifiedTime, size))").
+// GetChanges gets changes since the given start page token.
+// All returned changes are held in memory, so it stops once maxChanges changes were read
+// (checked per 100-change page; maxChanges <= 0 means no cap) or after maxChangePages pages,
+// and returns hasMore=true together with the page token to resume from. A lower cap bounds
+// memory per call at the cost of more calls for a large backlog. The returned token skips
+// every returned change, so callers must only persist it after all of them are applied.
+func (c *DriveClient) GetChanges(ctx context.Context, srv *drive.Service, startPageToken string, maxChanges int) (changes []*drive.Change, pageToken string, hasMore bool, err error) {
+	pageToken = startPageToken
+
+	for page := 1; ; page++ {
+		call := srv.Changes.List(pageToken).
+			Fields(googleapi.Field("nextPageToken, newStartPageToken, changes(fileId, removed, time, file(" + c.fileFields("id, name, mimeType, trashed, parents, thumbnailLink, webViewLink, createdTime, modifiedTime, size") + "))")).
 			PageSize(100).
 			SupportsAllDrives(true).
-			IncludeItemsFromAllDrives(true).
-			Do()
+			IncludeItemsFromAllDrives(true)
+		var result *drive.ChangeList
+		err := c.withRetry(ctx, "changes.list", func() (err error) {
+			result, err = call.Do()
+			return err
+		})
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to get changes: %w", err)
+			return nil, "", false, fmt.Errorf("failed to get changes: %w", err)
 		}
 
 		changes = append(changes, result.Changes...)
 
 		if result.NewStartPageToken != "" {
-			return changes, result.NewStartPageToken, nil
+			return changes, result.NewStartPageToken, false, nil
 		}
 
+		if result.NextPageToken == "" {
+			// Drive returned neither token - keep the current one so changes are re-read next run
+			logger.Sync("get_changes_no_token", "Changes list returned no page token", map[string]interface{}{
+				"pages":        page,
+				"change_count": len(changes),
+			})
+			return changes, pageToken, false, nil
+		}
 		pageToken = result.NextPageToken
-		if pageToken == "" {
-			break
+
+		if page%changeLogEveryPage == 0 {
+			logger.Sync("get_changes_progress", "Accumulating changes", map[string]interface{}{
+				"pages":        page,
+				"change_count": len(changes),
+			})
+		}
+
+		if maxChanges > 0 && len(changes) >= maxChanges {
+			logger.Sync("get_changes_change_limit", "Reached max changes, remaining changes deferred to next call", map[string]interface{}{
+				"pages":        page,
+				"change_count": len(changes),
+				"max_changes":  maxChanges,
+			})
+			return changes, pageToken, true, nil
 		}
-	}
 
-	return changes, "", nil
+		if page >= maxChangePages {
+			logger.Sync("get_changes_page_limit", "Reached max change pages, remaining changes deferred to next run", map[string]interface{}{
+				"pages":        page,
+				"change_count": len(changes),
+			})
+			return changes, pageToken, true, nil
+		}
+	}
 }
 
 // GetStartPageToken gets the start page token for change tracking
@@ -0,0 +1,85 @@
+package googledrive
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+
+	"gofiber-template/domain/models"
+)
+
+// APIErrorDetails contains detailed information from Google API errors
+type APIErrorDetails struct {
+	HTTPStatusCode int                   `json:"http_status_code"`
+	ErrorMessage   string                `json:"error_message"`
+	ErrorBody      string                `json:"error_body,omitempty"`
+	ErrorDetails   []googleapi.ErrorItem `json:"error_details,omitempty"`
+	RawError       string                `json:"raw_error"`
+}
+
+// ParseAPIError extracts detailed error information from Google API errors (wrapped ones included)
+func ParseAPIError(err error) *APIErrorDetails {
+	if err == nil {
+		return nil
+	}
+
+	details := &APIErrorDetails{
+		RawError: err.Error(),
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		details.HTTPStatusCode = apiErr.Code
+		details.ErrorMessage = apiErr.Message
+		details.ErrorBody = apiErr.Body
+		details.ErrorDetails = apiErr.Errors
+	} else if strings.Contains(details.RawError, "googleapi:") {
+		details.ErrorMessage = details.RawError
+	}
+
+	return details
+}
+
+// isTokenError reports whether err means the OAuth token is expired or revoked
+func isTokenError(err error) bool {
+	errStr := err.Error()
+	return strings.Contains(errStr, "401") ||
+		strings.Contains(errStr, "Invalid Credentials") ||
+		strings.Contains(errStr, "invalid_grant") ||
+		strings.Contains(errStr, "Token has been expired or revoked")
+}
+
+// ClassifyError maps a sync error to what the user can do about it. The detail is nil unless
+// the error came from the Google API
+func ClassifyError(err error) (models.SyncErrorCode, *models.SyncErrorDetail) {
+	if err == nil {
+		return "", nil
+	}
+
+	var detail *models.SyncErrorDetail
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		details := ParseAPIError(err)
+		detail = &models.SyncErrorDetail{
+			HTTPStatus: details.HTTPStatusCode,
+			Message:    details.ErrorMessage,
+		}
+		if len(details.ErrorDetails) > 0 {
+			detail.Reason = details.ErrorDetails[0].Reason
+		}
+	}
+
+	switch {
+	case isTokenError(err):
+		return models.SyncErrorTokenExpired, detail
+	case IsAccessDenied(err):
+		return models.SyncErrorPermissionDenied, detail
+	case apiErr != nil && apiErr.Code >= http.StatusInternalServerError:
+		return models.SyncErrorDriveUnavailable, detail
+	case isRetryableError(err):
+		return models.SyncErrorRateLimited, detail
+	}
+	return models.SyncErrorUnknown, detail
+}
@@ -0,0 +1,96 @@
+package googledrive
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/googleapi"
+
+	"gofiber-template/pkg/logger"
+)
+
+// maxRetryDelay caps the exponential backoff between Drive API attempts
+const maxRetryDelay = 32 * time.Second
+
+// withRetry runs a Drive API call, retrying rate-limit (403/429) and 5xx responses with
+// exponential backoff and jitter. Each attempt first waits for the account's rate limiter. After the last attempt the original error is returned
+// unchanged so callers can still detect token errors (401) from its message.
+func (c *DriveClient) withRetry(ctx context.Context, op string, call func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		// Every attempt counts against the account's quota, retries included
+		if waitErr := c.limiter.Wait(ctx); waitErr != nil {
+			return waitErr
+		}
+		err = call()
+		if err == nil || attempt >= c.maxAttempts || !isRetryableError(err) {
+			return err
+		}
+
+		delay := c.backoff(attempt)
+		logger.Drive("drive_api_retry", "Drive API rate limited or unavailable, retrying", map[string]interface{}{
+			"operation": op,
+			"attempt":   attempt,
+			"delay_ms":  delay.Milliseconds(),
+			"error":     err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// backoff returns the delay after the given failed attempt (1-based): base, 2x base, 4x base...
+// plus up to 50% random jitter so concurrent syncs don't retry in lockstep
+func (c *DriveClient) backoff(attempt int) time.Duration {
+	delay := c.retryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+// IsAccessDenied reports whether a Drive API error means the caller cannot read the file:
+// 403 for reasons other than rate limits, or 404 (Drive hides files the caller can't see).
+// These are permanent for the current token, so they are never retried.
+func IsAccessDenied(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.Code {
+	case http.StatusNotFound:
+		return true
+	case http.StatusForbidden:
+		return !isRetryableError(err)
+	}
+	return false
+}
+
+// isRetryableError reports whether a Drive API error is a rate limit or a server-side failure
+func isRetryableError(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch {
+	case apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= 500:
+		return true
+	case apiErr.Code == http.StatusForbidden:
+		// 403 is also returned for permission errors, which must not be retried
+		for _, item := range apiErr.Errors {
+			if item.Reason == "userRateLimitExceeded" || item.Reason == "rateLimitExceeded" {
+				return true
+			}
+		}
+	}
+	return false
+}
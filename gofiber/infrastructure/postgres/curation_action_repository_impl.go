@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+)
+
+type CurationActionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCurationActionRepository(db *gorm.DB) repositories.CurationActionRepository {
+	return &CurationActionRepositoryImpl{db: db}
+}
+
+func (r *CurationActionRepositoryImpl) Create(ctx context.Context, action *models.CurationAction) error {
+	return r.db.WithContext(ctx).Create(action).Error
+}
+
+func (r *CurationActionRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.CurationAction, error) {
+	var action models.CurationAction
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&action).Error
+	if err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+func (r *CurationActionRepositoryImpl) GetLatestActiveByUser(ctx context.Context, userID uuid.UUID) (*models.CurationAction, error) {
+	var action models.CurationAction
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND undone_at IS NULL", userID).
+		Order("created_at DESC").
+		First(&action).Error
+	if err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+func (r *CurationActionRepositoryImpl) AssignFacesWithAction(ctx context.Context, action *models.CurationAction, faceIDs []uuid.UUID, personID uuid.UUID, redact bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"person_id":  personID,
+			"updated_at": time.Now(),
+		}
+		if redact {
+			updates["redacted"] = true
+		}
+		if err := tx.Model(&models.Face{}).Where("id IN ?", faceIDs).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Create(action).Error
+	})
+}
+
+func (r *CurationActionRepositoryImpl) MarkUndone(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.CurationAction{}).
+		Where("id = ? AND undone_at IS NULL", id).
+		Update("undone_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
@@ -2,12 +2,15 @@ package postgres
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/google/uuid"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	gormlogger "gorm.io/gorm/logger"
 
 	"gofiber-template/domain/models"
+	"gofiber-template/pkg/logger"
 )
 
 type DatabaseConfig struct {
@@ -24,7 +27,7 @@ func NewDatabase(config DatabaseConfig) (*gorm.DB, error) {
 		config.Host, config.User, config.Password, config.DBName, config.Port, config.SSLMode)
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: gormlogger.Default.LogMode(gormlogger.Info),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %v", err)
@@ -58,8 +61,12 @@ func Migrate(db *gorm.DB) error {
 		&models.News{},
 		&models.NewsPhoto{},
 		&models.SyncJob{},
+		&models.SyncJobSnapshot{},
 		&models.DriveWebhookLog{},
 		&models.ActivityLog{},
+		&models.CurationAction{},
+		&models.SyncSummary{},
+		&models.PublicSearchLink{},
 	); err != nil {
 		return fmt.Errorf("failed to run auto migrations: %v", err)
 	}
@@ -70,6 +77,11 @@ func Migrate(db *gorm.DB) error {
 		return fmt.Errorf("failed to run shared folder migrations: %v", err)
 	}
 
+	// Move photos that only have a user_id (legacy user-root sync) into shared folders
+	if err := runLegacyPhotoMigrations(db); err != nil {
+		return fmt.Errorf("failed to run legacy photo migrations: %v", err)
+	}
+
 	return nil
 }
 
@@ -115,6 +127,25 @@ func runSharedFolderMigrations(db *gorm.DB) error {
 			ALTER TABLE faces ADD CONSTRAINT fk_faces_shared_folder
 				FOREIGN KEY (shared_folder_id) REFERENCES shared_folders(id);
 		EXCEPTION WHEN duplicate_object THEN NULL; END $$`,
+
+		// Faces: Replaced by idx_faces_photo_region (rounded bbox, see dedupeFaceRegions)
+		`DROP INDEX IF EXISTS idx_faces_photo_bbox`,
+
+		// Photos: Trigram index for case-insensitive filename search (LIKE '%...%')
+		// pg_trgm may be unavailable to the database user; search then falls back to a scan of the folder
+		`DO $$ BEGIN
+			CREATE EXTENSION IF NOT EXISTS pg_trgm;
+			CREATE INDEX IF NOT EXISTS idx_photos_file_name_trgm ON photos USING gin (LOWER(file_name) gin_trgm_ops);
+		EXCEPTION WHEN others THEN NULL; END $$`,
+
+		// Sync job snapshots: Rows from before snapshots were keyed by folder can't be found anymore
+		`DELETE FROM sync_job_snapshots WHERE shared_folder_id IS NULL`,
+
+		// Sync jobs: Copy the folder out of metadata into the indexed column
+		`UPDATE sync_jobs SET shared_folder_id = (metadata->>'shared_folder_id')::uuid
+			WHERE shared_folder_id IS NULL AND job_type = 'drive_sync'
+				AND metadata->>'shared_folder_id' IS NOT NULL
+				AND metadata->>'shared_folder_id' <> '00000000-0000-0000-0000-000000000000'`,
 	}
 
 	for _, sql := range migrations {
@@ -123,5 +154,243 @@ func runSharedFolderMigrations(db *gorm.DB) error {
 		}
 	}
 
+	// Faces: One face per photo region so re-inserting after a crash/retry is a no-op
+	if err := dedupeFaceRegions(db); err != nil {
+		return fmt.Errorf("migration failed: dedupe face regions, error: %v", err)
+	}
+
 	return nil
-}
\ No newline at end of file
+}
+
+// faceRegionIndexExprs are the key columns of idx_faces_photo_region. The bbox is normalized 0-1,
+// so rounding to 4 decimals (0.01% of the image) absorbs float noise between detection runs of
+// the same photo without merging faces that are actually distinct.
+var faceRegionIndexExprs = []string{
+	"photo_id",
+	"(ROUND(bbox_x::numeric, 4))",
+	"(ROUND(bbox_y::numeric, 4))",
+	"(ROUND(bbox_width::numeric, 4))",
+	"(ROUND(bbox_height::numeric, 4))",
+}
+
+// dedupeFaceRegions removes faces that repeat a region of their photo and then creates the unique
+// index that keeps them out. The earliest face of each region is kept; it takes over a person
+// assignment (and redaction) of a removed duplicate, and photo and person face counts are
+// recomputed. Skipped once the index exists, so it only scans the faces on the first startup.
+func dedupeFaceRegions(db *gorm.DB) error {
+	var exists bool
+	if err := db.Raw(`SELECT to_regclass('idx_faces_photo_region') IS NOT NULL`).Scan(&exists).Error; err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`CREATE TEMP TABLE face_region_dups ON COMMIT DROP AS
+			SELECT id, keep_id, photo_id, person_id, redacted, rn FROM (
+				SELECT id, photo_id, person_id, redacted,
+					ROW_NUMBER() OVER w AS rn,
+					FIRST_VALUE(id) OVER w AS keep_id
+				FROM faces
+				WINDOW w AS (PARTITION BY ` + strings.Join(faceRegionIndexExprs, ", ") + ` ORDER BY created_at, id)
+			) ranked WHERE rn > 1`).Error; err != nil {
+			return err
+		}
+
+		var dupCount int64
+		if err := tx.Raw(`SELECT COUNT(*) FROM face_region_dups`).Scan(&dupCount).Error; err != nil {
+			return err
+		}
+
+		if dupCount > 0 {
+			var personIDs []uuid.UUID
+			if err := tx.Raw(`SELECT DISTINCT person_id FROM face_region_dups WHERE person_id IS NOT NULL`).
+				Scan(&personIDs).Error; err != nil {
+				return err
+			}
+
+			statements := []string{
+				// The kept face takes the first duplicate's person if it has none
+				`UPDATE faces k SET person_id = d.person_id
+					FROM (SELECT DISTINCT ON (keep_id) keep_id, person_id FROM face_region_dups
+						WHERE person_id IS NOT NULL ORDER BY keep_id, rn) d
+					WHERE k.id = d.keep_id AND k.person_id IS NULL`,
+				// A redacted duplicate keeps the region redacted
+				`UPDATE faces k SET redacted = true
+					FROM face_region_dups d
+					WHERE k.id = d.keep_id AND d.redacted AND NOT k.redacted`,
+				`DELETE FROM faces f USING face_region_dups d WHERE f.id = d.id`,
+				`UPDATE photos SET face_count = (SELECT COUNT(*) FROM faces WHERE faces.photo_id = photos.id)
+					WHERE id IN (SELECT photo_id FROM face_region_dups)`,
+			}
+			for _, sql := range statements {
+				if err := tx.Exec(sql).Error; err != nil {
+					return err
+				}
+			}
+			if len(personIDs) > 0 {
+				if err := refreshPersonStats(tx, personIDs); err != nil {
+					return err
+				}
+			}
+
+			logger.Startup("face_duplicates_removed", "Removed faces repeating a region of their photo", map[string]interface{}{
+				"deleted": dupCount,
+			})
+		}
+
+		return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_faces_photo_region ON faces(` +
+			strings.Join(faceRegionIndexExprs, ", ") + `)`).Error
+	})
+}
+
+// runLegacyPhotoMigrations attaches legacy user_id-only photos to a shared folder for the user's root folder
+// A shared folder (with the user's tokens and access) is created when none exists for that root yet.
+// Idempotent: only photos without shared_folder_id are touched, so it is safe to run on every startup.
+// Runs in one transaction so a failure leaves no half-merged photos behind.
+func runLegacyPhotoMigrations(db *gorm.DB) error {
+	setup := []string{
+		// Shared folder per legacy root folder (one per Drive folder even if several users picked it)
+		`INSERT INTO shared_folders (id, drive_folder_id, drive_folder_name, drive_folder_path, webhook_token,
+				sync_status, drive_access_token, drive_refresh_token, drive_token_expiry, token_owner_id, created_at, updated_at)
+			SELECT DISTINCT ON (u.drive_root_folder_id)
+				gen_random_uuid(), u.drive_root_folder_id,
+				COALESCE(NULLIF(u.drive_root_folder_name, ''), u.drive_root_folder_id),
+				COALESCE(NULLIF(u.drive_root_folder_name, ''), u.drive_root_folder_id),
+				gen_random_uuid()::text, 'idle',
+				u.drive_access_token, u.drive_refresh_token, u.drive_token_expiry, u.id, NOW(), NOW()
+			FROM users u
+			WHERE COALESCE(u.drive_root_folder_id, '') <> ''
+				AND EXISTS (SELECT 1 FROM photos p WHERE p.user_id = u.id AND p.shared_folder_id IS NULL)
+			ORDER BY u.drive_root_folder_id, u.updated_at DESC
+		ON CONFLICT (drive_folder_id) DO NOTHING`,
+
+		// Give each legacy user access to their root's shared folder
+		`INSERT INTO user_folder_access (id, user_id, shared_folder_id, root_path, created_at)
+			SELECT gen_random_uuid(), u.id, sf.id, '', NOW()
+			FROM users u
+			JOIN shared_folders sf ON sf.drive_folder_id = u.drive_root_folder_id
+			WHERE EXISTS (SELECT 1 FROM photos p WHERE p.user_id = u.id AND p.shared_folder_id IS NULL)
+				AND NOT EXISTS (SELECT 1 FROM user_folder_access a WHERE a.user_id = u.id AND a.shared_folder_id = sf.id)`,
+	}
+
+	attach := []string{
+		// Attach the photos (rows that would clash were merged away by mergeLegacyPhotoDuplicates)
+		`UPDATE photos p SET shared_folder_id = sf.id
+			FROM users u
+			JOIN shared_folders sf ON sf.drive_folder_id = u.drive_root_folder_id
+			WHERE p.user_id = u.id AND p.shared_folder_id IS NULL`,
+
+		// Faces follow their photo's folder
+		`UPDATE faces f SET shared_folder_id = p.shared_folder_id
+			FROM photos p
+			WHERE f.photo_id = p.id AND f.shared_folder_id IS NULL AND p.shared_folder_id IS NOT NULL`,
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, sql := range setup {
+			if err := tx.Exec(sql).Error; err != nil {
+				return fmt.Errorf("migration failed: %s, error: %v", sql[:50], err)
+			}
+		}
+		if err := mergeLegacyPhotoDuplicates(tx); err != nil {
+			return fmt.Errorf("migration failed: merge legacy photo duplicates, error: %v", err)
+		}
+		for _, sql := range attach {
+			if err := tx.Exec(sql).Error; err != nil {
+				return fmt.Errorf("migration failed: %s, error: %v", sql[:50], err)
+			}
+		}
+		return nil
+	})
+}
+
+// mergeLegacyPhotoDuplicates removes legacy photos whose (shared_folder_id, drive_file_id) would
+// clash with idx_photos_folder_drive_file once attached: the file is already in the target folder
+// (the user also joined it under the shared-folder model) or several legacy users picked the same
+// root. The kept row is the attached photo if there is one, else the first legacy row in
+// duplicateKeepOrder. News references move to it; faces move too when it has none of its own,
+// otherwise the duplicate's faces are deleted and their persons recounted.
+func mergeLegacyPhotoDuplicates(tx *gorm.DB) error {
+	if err := tx.Exec(`CREATE TEMP TABLE legacy_photo_dups ON COMMIT DROP AS
+		WITH targets AS (
+			SELECT p.id, p.drive_file_id, sf.id AS folder_id, p.is_trashed, p.created_at
+			FROM photos p
+			JOIN users u ON p.user_id = u.id
+			JOIN shared_folders sf ON sf.drive_folder_id = u.drive_root_folder_id
+			WHERE p.shared_folder_id IS NULL
+		), candidates AS (
+			SELECT p.id, p.drive_file_id, p.shared_folder_id AS folder_id, 0 AS legacy, p.is_trashed, p.created_at
+			FROM photos p
+			WHERE p.shared_folder_id IN (SELECT folder_id FROM targets)
+			UNION ALL
+			SELECT id, drive_file_id, folder_id, 1, is_trashed, created_at FROM targets
+		)
+		SELECT id, keep_id FROM (
+			SELECT id,
+				ROW_NUMBER() OVER w AS rn,
+				FIRST_VALUE(id) OVER w AS keep_id
+			FROM candidates
+			WINDOW w AS (PARTITION BY folder_id, drive_file_id ORDER BY legacy, ` + duplicateKeepOrder + `)
+		) ranked WHERE rn > 1`).Error; err != nil {
+		return err
+	}
+
+	var dupCount int64
+	if err := tx.Raw(`SELECT COUNT(*) FROM legacy_photo_dups`).Scan(&dupCount).Error; err != nil {
+		return err
+	}
+	if dupCount == 0 {
+		return nil
+	}
+
+	moves := []string{
+		`UPDATE news_photos np SET photo_id = d.keep_id
+			FROM legacy_photo_dups d WHERE np.photo_id = d.id`,
+
+		// Faces of one duplicate per kept photo move over if the kept photo has none
+		`UPDATE faces f SET photo_id = d.keep_id
+			FROM legacy_photo_dups d
+			WHERE f.photo_id = d.id
+				AND NOT EXISTS (SELECT 1 FROM faces k WHERE k.photo_id = d.keep_id)
+				AND d.id = (SELECT d2.id FROM legacy_photo_dups d2
+					WHERE d2.keep_id = d.keep_id
+						AND EXISTS (SELECT 1 FROM faces f2 WHERE f2.photo_id = d2.id)
+					ORDER BY d2.id LIMIT 1)`,
+	}
+	for _, sql := range moves {
+		if err := tx.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+
+	var personIDs []uuid.UUID
+	if err := tx.Raw(`SELECT DISTINCT f.person_id FROM faces f
+		JOIN legacy_photo_dups d ON f.photo_id = d.id
+		WHERE f.person_id IS NOT NULL`).Scan(&personIDs).Error; err != nil {
+		return err
+	}
+
+	deletes := []string{
+		`DELETE FROM faces f USING legacy_photo_dups d WHERE f.photo_id = d.id`,
+		`DELETE FROM photos p USING legacy_photo_dups d WHERE p.id = d.id`,
+		`UPDATE photos SET face_count = (SELECT COUNT(*) FROM faces WHERE faces.photo_id = photos.id)
+			WHERE id IN (SELECT keep_id FROM legacy_photo_dups)`,
+	}
+	for _, sql := range deletes {
+		if err := tx.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	if len(personIDs) > 0 {
+		if err := refreshPersonStats(tx, personIDs); err != nil {
+			return err
+		}
+	}
+
+	logger.Startup("legacy_photo_duplicates_merged", "Merged legacy photos already present in their shared folder", map[string]interface{}{
+		"deleted": dupCount,
+	})
+	return nil
+}
@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pgvector/pgvector-go"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"gofiber-template/domain/models"
 	"gofiber-template/domain/repositories"
@@ -19,15 +21,31 @@ func NewFaceRepository(db *gorm.DB) repositories.FaceRepository {
 	return &FaceRepositoryImpl{db: db}
 }
 
+// faceRegionConflict skips inserting a face that already exists for the same photo region
+// (unique index idx_faces_photo_region), so re-processing after a crash or retry is a no-op
+var faceRegionConflict = clause.OnConflict{
+	Columns:   faceRegionConflictColumns(),
+	DoNothing: true,
+}
+
+// faceRegionConflictColumns returns the index expressions as the ON CONFLICT target
+func faceRegionConflictColumns() []clause.Column {
+	columns := make([]clause.Column, len(faceRegionIndexExprs))
+	for i, expr := range faceRegionIndexExprs {
+		columns[i] = clause.Column{Name: expr, Raw: true}
+	}
+	return columns
+}
+
 func (r *FaceRepositoryImpl) Create(ctx context.Context, face *models.Face) error {
-	return r.db.WithContext(ctx).Create(face).Error
+	return r.db.WithContext(ctx).Clauses(faceRegionConflict).Create(face).Error
 }
 
 func (r *FaceRepositoryImpl) CreateBatch(ctx context.Context, faces []*models.Face) error {
 	if len(faces) == 0 {
 		return nil
 	}
-	return r.db.WithContext(ctx).CreateInBatches(faces, 50).Error
+	return r.db.WithContext(ctx).Clauses(faceRegionConflict).CreateInBatches(faces, 50).Error
 }
 
 func (r *FaceRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Face, error) {
@@ -39,6 +57,15 @@ func (r *FaceRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models
 	return &face, nil
 }
 
+func (r *FaceRepositoryImpl) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.Face, error) {
+	var faces []models.Face
+	if len(ids) == 0 {
+		return faces, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&faces).Error
+	return faces, err
+}
+
 func (r *FaceRepositoryImpl) GetByPhoto(ctx context.Context, photoID uuid.UUID) ([]models.Face, error) {
 	var faces []models.Face
 	err := r.db.WithContext(ctx).Where("photo_id = ?", photoID).Find(&faces).Error
@@ -51,6 +78,29 @@ func (r *FaceRepositoryImpl) GetByPerson(ctx context.Context, personID uuid.UUID
 	return faces, err
 }
 
+// CountByPersonPerFolder counts a person's faces and photos per shared folder, limited to folderIDs
+func (r *FaceRepositoryImpl) CountByPersonPerFolder(ctx context.Context, personID uuid.UUID, folderIDs []uuid.UUID) ([]repositories.PersonFolderCount, error) {
+	var counts []repositories.PersonFolderCount
+	if len(folderIDs) == 0 {
+		return counts, nil
+	}
+
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT p.shared_folder_id,
+			COUNT(DISTINCT f.photo_id) AS photo_count,
+			COUNT(*) AS face_count
+		FROM faces f
+		JOIN photos p ON p.id = f.photo_id
+		WHERE f.person_id = ?
+			AND p.shared_folder_id IN ?
+			AND p.is_trashed = false
+		GROUP BY p.shared_folder_id
+		ORDER BY photo_count DESC
+	`, personID, folderIDs).Scan(&counts).Error
+
+	return counts, err
+}
+
 func (r *FaceRepositoryImpl) GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.Face, int64, error) {
 	var faces []models.Face
 	var total int64
@@ -86,6 +136,7 @@ func (r *FaceRepositoryImpl) SearchSimilar(ctx context.Context, userID uuid.UUID
 		FROM faces f
 		JOIN photos p ON f.photo_id = p.id
 		WHERE f.user_id = ?
+		AND f.redacted = false
 		AND 1 - (f.embedding <=> ?) >= ?
 		ORDER BY f.embedding <=> ?
 		LIMIT ?
@@ -136,6 +187,7 @@ func (r *FaceRepositoryImpl) SearchSimilarByFolderPathPrefix(ctx context.Context
 		FROM faces f
 		JOIN photos p ON f.photo_id = p.id
 		WHERE p.drive_folder_path LIKE ?
+		AND f.redacted = false
 		AND 1 - (f.embedding <=> ?) >= ?
 		ORDER BY f.embedding <=> ?
 		LIMIT ?
@@ -179,6 +231,36 @@ func (r *FaceRepositoryImpl) UpdatePersonID(ctx context.Context, id uuid.UUID, p
 	return r.db.WithContext(ctx).Model(&models.Face{}).Where("id = ?", id).Update("person_id", personID).Error
 }
 
+func (r *FaceRepositoryImpl) UpdatePersonIDs(ctx context.Context, ids []uuid.UUID, personID *uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Model(&models.Face{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"person_id":  personID,
+		"updated_at": time.Now(),
+	})
+	return result.RowsAffected, result.Error
+}
+
+func (r *FaceRepositoryImpl) RedactByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Model(&models.Face{}).Where("id IN ? AND redacted = ?", ids, false).Updates(map[string]interface{}{
+		"redacted":   true,
+		"updated_at": time.Now(),
+	})
+	return result.RowsAffected, result.Error
+}
+
+func (r *FaceRepositoryImpl) RedactByPerson(ctx context.Context, personID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Face{}).Where("person_id = ? AND redacted = ?", personID, false).Updates(map[string]interface{}{
+		"redacted":   true,
+		"updated_at": time.Now(),
+	})
+	return result.RowsAffected, result.Error
+}
+
 func (r *FaceRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Face{}).Error
 }
@@ -187,6 +269,14 @@ func (r *FaceRepositoryImpl) DeleteByPhoto(ctx context.Context, photoID uuid.UUI
 	return r.db.WithContext(ctx).Where("photo_id = ?", photoID).Delete(&models.Face{}).Error
 }
 
+func (r *FaceRepositoryImpl) DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&models.Face{})
+	return result.RowsAffected, result.Error
+}
+
 func (r *FaceRepositoryImpl) Count(ctx context.Context, userID uuid.UUID) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&models.Face{}).Where("user_id = ?", userID).Count(&count).Error
@@ -216,6 +306,42 @@ func (r *FaceRepositoryImpl) GetBySharedFolders(ctx context.Context, folderIDs [
 	return faces, total, err
 }
 
+// GetUnassignedBySharedFolders returns faces not yet assigned to a person
+func (r *FaceRepositoryImpl) GetUnassignedBySharedFolders(ctx context.Context, folderIDs []uuid.UUID, offset, limit int) ([]models.Face, int64, error) {
+	return r.getBySharedFoldersWhere(ctx, folderIDs, "person_id IS NULL", offset, limit)
+}
+
+// GetAssignedBySharedFolders returns faces already assigned to a person
+func (r *FaceRepositoryImpl) GetAssignedBySharedFolders(ctx context.Context, folderIDs []uuid.UUID, offset, limit int) ([]models.Face, int64, error) {
+	return r.getBySharedFoldersWhere(ctx, folderIDs, "person_id IS NOT NULL", offset, limit)
+}
+
+// getBySharedFoldersWhere returns paginated faces in shared folders matching an extra condition
+func (r *FaceRepositoryImpl) getBySharedFoldersWhere(ctx context.Context, folderIDs []uuid.UUID, condition string, offset, limit int) ([]models.Face, int64, error) {
+	var faces []models.Face
+	var total int64
+
+	if len(folderIDs) == 0 {
+		return faces, 0, nil
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.Face{}).
+		Where("shared_folder_id IN ?", folderIDs).
+		Where(condition)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&faces).Error
+
+	return faces, total, err
+}
+
 // CountBySharedFolders returns total face count for multiple shared folders
 func (r *FaceRepositoryImpl) CountBySharedFolders(ctx context.Context, folderIDs []uuid.UUID) (int64, error) {
 	var count int64
@@ -226,7 +352,31 @@ func (r *FaceRepositoryImpl) CountBySharedFolders(ctx context.Context, folderIDs
 	return count, err
 }
 
+// GetLowConfidenceBySharedFolder returns faces in a folder detected below a confidence threshold (least confident first)
+func (r *FaceRepositoryImpl) GetLowConfidenceBySharedFolder(ctx context.Context, folderID uuid.UUID, maxConfidence float64, offset, limit int) ([]models.Face, int64, error) {
+	var faces []models.Face
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Face{}).
+		Where("shared_folder_id = ?", folderID).
+		Where("confidence < ?", maxConfidence)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Photo").
+		Order("confidence ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&faces).Error
+
+	return faces, total, err
+}
+
 // SearchSimilarBySharedFolders finds faces similar to the given embedding filtered by shared folder IDs
+// (redacted faces and trashed photos are skipped)
 func (r *FaceRepositoryImpl) SearchSimilarBySharedFolders(ctx context.Context, folderIDs []uuid.UUID, embedding pgvector.Vector, limit int, threshold float64) ([]repositories.FaceSearchResult, error) {
 	var results []repositories.FaceSearchResult
 
@@ -245,6 +395,8 @@ func (r *FaceRepositoryImpl) SearchSimilarBySharedFolders(ctx context.Context, f
 		FROM faces f
 		JOIN photos p ON f.photo_id = p.id
 		WHERE f.shared_folder_id IN ?
+		AND f.redacted = false
+		AND p.is_trashed = false
 		AND 1 - (f.embedding <=> ?) >= ?
 		ORDER BY f.embedding <=> ?
 		LIMIT ?
@@ -279,3 +431,38 @@ func (r *FaceRepositoryImpl) SearchSimilarBySharedFolders(ctx context.Context, f
 
 	return results, nil
 }
+
+// FindUnassignedNeighbors returns, for the maxFaces most confident unassigned and unredacted faces
+// on non-trashed photos of a folder, up to neighbors nearest other candidates with similarity at
+// or above threshold. Each candidate also gets one row without a neighbor. Distances are computed
+// in the database so embeddings are never loaded; maxFaces bounds the quadratic cost.
+func (r *FaceRepositoryImpl) FindUnassignedNeighbors(ctx context.Context, folderID uuid.UUID, threshold float64, maxFaces, neighbors int) ([]repositories.FaceNeighbor, error) {
+	var rows []repositories.FaceNeighbor
+	err := r.db.WithContext(ctx).Raw(`
+		WITH candidates AS (
+			SELECT f.id, f.confidence, f.embedding
+			FROM faces f
+			JOIN photos p ON f.photo_id = p.id
+			WHERE f.shared_folder_id = ?
+			AND f.person_id IS NULL
+			AND f.redacted = false
+			AND p.is_trashed = false
+			ORDER BY f.confidence DESC
+			LIMIT ?
+		)
+		SELECT c.id AS face_id, c.confidence, NULL::uuid AS neighbor_id, 0 AS similarity
+		FROM candidates c
+		UNION ALL
+		SELECT c.id, c.confidence, n.id, n.similarity
+		FROM candidates c
+		CROSS JOIN LATERAL (
+			SELECT o.id, 1 - (o.embedding <=> c.embedding) AS similarity
+			FROM candidates o
+			WHERE o.id <> c.id
+			ORDER BY o.embedding <=> c.embedding
+			LIMIT ?
+		) n
+		WHERE n.similarity >= ?
+	`, folderID, maxFaces, neighbors, threshold).Scan(&rows).Error
+	return rows, err
+}
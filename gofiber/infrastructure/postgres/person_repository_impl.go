@@ -65,6 +65,102 @@ func (r *PersonRepositoryImpl) UpdateFaceCount(ctx context.Context, id uuid.UUID
 		}).Error
 }
 
+// centroidMinSimilarity is how close (cosine similarity) a face must be to the plain average of a
+// person's faces to count towards the centroid. Mis-tagged faces (another person, a poster in the
+// background) sit far from the average and are dropped; if every face is dropped, which only
+// happens with two or three very different faces, the plain average is kept.
+const centroidMinSimilarity = 0.3
+
+// personCentroidExpr computes a person's centroid in an UPDATE of persons (argument: centroidMinSimilarity).
+// It is NULL when the person has no faces.
+const personCentroidExpr = `COALESCE(
+	(SELECT AVG(f.embedding) FROM faces f
+		WHERE f.person_id = persons.id
+		AND 1 - (f.embedding <=> (SELECT AVG(g.embedding) FROM faces g WHERE g.person_id = persons.id)) >= ?),
+	(SELECT AVG(g.embedding) FROM faces g WHERE g.person_id = persons.id))`
+
+// UpdateCentroid recomputes a person's centroid from their current faces (see personCentroidExpr)
+func (r *PersonRepositoryImpl) UpdateCentroid(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Exec(
+		`UPDATE persons SET centroid = `+personCentroidExpr+`, updated_at = ? WHERE id = ?`,
+		centroidMinSimilarity, time.Now(), id,
+	).Error
+}
+
+// refreshPersonStats recalculates the cached face count and centroid of persons from their faces
+func refreshPersonStats(tx *gorm.DB, ids []uuid.UUID) error {
+	return tx.Exec(`UPDATE persons SET face_count = (SELECT COUNT(*) FROM faces WHERE faces.person_id = persons.id),
+		centroid = `+personCentroidExpr+`, updated_at = ? WHERE id IN ?`, centroidMinSimilarity, time.Now(), ids).Error
+}
+
+// Merge moves every face of mergeID to keepID and deletes mergeID. Moved faces inherit the
+// redaction if keepID is redacted; faces already redacted stay redacted.
+func (r *PersonRepositoryImpl) Merge(ctx context.Context, keepID, mergeID uuid.UUID) (int64, error) {
+	var moved int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var keep models.Person
+		if err := tx.Where("id = ?", keepID).First(&keep).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"person_id":  keepID,
+			"updated_at": time.Now(),
+		}
+		if keep.Redacted {
+			updates["redacted"] = true
+		}
+		result := tx.Model(&models.Face{}).Where("person_id = ?", mergeID).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		moved = result.RowsAffected
+
+		if err := tx.Where("id = ?", mergeID).Delete(&models.Person{}).Error; err != nil {
+			return err
+		}
+		return refreshPersonStats(tx, []uuid.UUID{keepID})
+	})
+	return moved, err
+}
+
+// Split creates newPerson and moves the given faces to it; faces not tagged as sourceID are
+// left alone. Face counts and centroids of both persons are recalculated.
+func (r *PersonRepositoryImpl) Split(ctx context.Context, sourceID uuid.UUID, faceIDs []uuid.UUID, newPerson *models.Person) (int64, error) {
+	var moved int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(newPerson).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Face{}).
+			Where("id IN ? AND person_id = ?", faceIDs, sourceID).
+			Updates(map[string]interface{}{
+				"person_id":  newPerson.ID,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		moved = result.RowsAffected
+
+		return refreshPersonStats(tx, []uuid.UUID{sourceID, newPerson.ID})
+	})
+	return moved, err
+}
+
+func (r *PersonRepositoryImpl) MarkRedacted(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).
+		Model(&models.Person{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"redacted":    true,
+			"redacted_at": now,
+			"updated_at":  now,
+		}).Error
+}
+
 func (r *PersonRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Person{}).Error
 }
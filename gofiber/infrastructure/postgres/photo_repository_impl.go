@@ -9,6 +9,7 @@ import (
 
 	"gofiber-template/domain/models"
 	"gofiber-template/domain/repositories"
+	"gofiber-template/pkg/logger"
 )
 
 type PhotoRepositoryImpl struct {
@@ -20,14 +21,35 @@ func NewPhotoRepository(db *gorm.DB) repositories.PhotoRepository {
 }
 
 func (r *PhotoRepositoryImpl) Create(ctx context.Context, photo *models.Photo) error {
-	return r.db.WithContext(ctx).Create(photo).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(photo).Error; err != nil {
+			return err
+		}
+		if photo.IsTrashed {
+			return nil
+		}
+		return applyFolderPhotoCounts(tx, map[uuid.UUID]int64{photo.SharedFolderID: 1}, 1)
+	})
 }
 
 func (r *PhotoRepositoryImpl) CreateBatch(ctx context.Context, photos []*models.Photo) error {
 	if len(photos) == 0 {
 		return nil
 	}
-	return r.db.WithContext(ctx).CreateInBatches(photos, 100).Error
+
+	counts := make(map[uuid.UUID]int64)
+	for _, photo := range photos {
+		if !photo.IsTrashed {
+			counts[photo.SharedFolderID]++
+		}
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(photos, 100).Error; err != nil {
+			return err
+		}
+		return applyFolderPhotoCounts(tx, counts, 1)
+	})
 }
 
 func (r *PhotoRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Photo, error) {
@@ -48,15 +70,69 @@ func (r *PhotoRepositoryImpl) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]
 	return photos, nil
 }
 
-func (r *PhotoRepositoryImpl) GetByDriveFileID(ctx context.Context, driveFileID string) (*models.Photo, error) {
+// duplicateKeepOrder picks the row read among photos sharing a drive_file_id: non-trashed
+// first, then the oldest
+const duplicateKeepOrder = "is_trashed, created_at, id"
+
+// GetByDriveFileID returns the folder's photo of a Drive file, so a sync never touches another
+// folder's photo. drive_file_id is unique; several rows can only exist in a database whose
+// unique index is missing (rows left from the per-user folder model). Those are logged and the
+// first in duplicateKeepOrder is returned
+func (r *PhotoRepositoryImpl) GetByDriveFileID(ctx context.Context, folderID uuid.UUID, driveFileID string) (*models.Photo, error) {
+	var photos []models.Photo
+	err := r.db.WithContext(ctx).Where("shared_folder_id = ? AND drive_file_id = ?", folderID, driveFileID).
+		Order(duplicateKeepOrder).Limit(2).Find(&photos).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(photos) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if len(photos) > 1 {
+		logger.Warn(logger.CategoryDB, "photo_drive_file_duplicate", "Several photos of one folder share a Drive file ID", map[string]interface{}{
+			"folder_id":     folderID.String(),
+			"drive_file_id": driveFileID,
+			"photo_id":      photos[0].ID.String(),
+			"duplicate_id":  photos[1].ID.String(),
+		})
+	}
+	return &photos[0], nil
+}
+
+// GetByDriveFileIDForUser returns the photo of a Drive file if it is in a folder the user can access
+func (r *PhotoRepositoryImpl) GetByDriveFileIDForUser(ctx context.Context, userID uuid.UUID, driveFileID string) (*models.Photo, error) {
 	var photo models.Photo
-	err := r.db.WithContext(ctx).Where("drive_file_id = ?", driveFileID).First(&photo).Error
+	accessible := r.db.Model(&models.UserFolderAccess{}).Select("shared_folder_id").Where("user_id = ?", userID)
+	err := r.db.WithContext(ctx).
+		Where("drive_file_id = ? AND shared_folder_id IN (?)", driveFileID, accessible).
+		Order(duplicateKeepOrder).First(&photo).Error
 	if err != nil {
 		return nil, err
 	}
 	return &photo, nil
 }
 
+// GetByDriveFileIDs loads the folder's photos of many Drive files in one query
+func (r *PhotoRepositoryImpl) GetByDriveFileIDs(ctx context.Context, folderID uuid.UUID, driveFileIDs []string) (map[string]*models.Photo, error) {
+	photos := make(map[string]*models.Photo, len(driveFileIDs))
+	if len(driveFileIDs) == 0 {
+		return photos, nil
+	}
+
+	var rows []models.Photo
+	if err := r.db.WithContext(ctx).Where("shared_folder_id = ? AND drive_file_id IN ?", folderID, driveFileIDs).
+		Order(duplicateKeepOrder).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		// Rows are in keep order: the first one of a file wins, like GetByDriveFileID
+		if _, ok := photos[rows[i].DriveFileID]; !ok {
+			photos[rows[i].DriveFileID] = &rows[i]
+		}
+	}
+	return photos, nil
+}
+
 func (r *PhotoRepositoryImpl) GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.Photo, int64, error) {
 	var photos []models.Photo
 	var total int64
@@ -180,12 +256,29 @@ func (r *PhotoRepositoryImpl) GetByFaceStatus(ctx context.Context, status models
 	return photos, err
 }
 
+// faceProcessingDisabledFolders matches photos of folders with face processing switched off
+const faceProcessingDisabledFolders = "shared_folder_id IN (SELECT id FROM shared_folders WHERE face_processing_enabled = false)"
+
+func (r *PhotoRepositoryImpl) GetPendingForFaceWorker(ctx context.Context, limit int) ([]models.Photo, error) {
+	var photos []models.Photo
+	err := r.db.WithContext(ctx).
+		Where("face_status = ?", models.FaceStatusPending).
+		Where("is_trashed = ?", false).
+		Not(faceProcessingDisabledFolders).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&photos).Error
+
+	return photos, err
+}
+
 func (r *PhotoRepositoryImpl) GetPendingBySharedFolders(ctx context.Context, folderIDs []uuid.UUID, limit int) ([]models.Photo, error) {
 	var photos []models.Photo
 	err := r.db.WithContext(ctx).
 		Where("shared_folder_id IN ?", folderIDs).
 		Where("face_status = ?", models.FaceStatusPending).
 		Where("is_trashed = ?", false).
+		Not(faceProcessingDisabledFolders).
 		Order("created_at ASC").
 		Limit(limit).
 		Find(&photos).Error
@@ -207,6 +300,54 @@ func (r *PhotoRepositoryImpl) UpdateFaceStatus(ctx context.Context, id uuid.UUID
 	return r.db.WithContext(ctx).Model(&models.Photo{}).Where("id = ?", id).Updates(updates).Error
 }
 
+// UpdateCaptureMetadata stores EXIF capture metadata; values that are unknown (nil, "", 0) keep the stored ones
+func (r *PhotoRepositoryImpl) UpdateCaptureMetadata(ctx context.Context, id uuid.UUID, takenAt *time.Time, cameraMake, cameraModel string, width, height int) error {
+	updates := map[string]interface{}{
+		"updated_at": time.Now(),
+	}
+	if takenAt != nil {
+		updates["taken_at"] = takenAt
+	}
+	if cameraMake != "" {
+		updates["camera_make"] = cameraMake
+	}
+	if cameraModel != "" {
+		updates["camera_model"] = cameraModel
+	}
+	if width > 0 && height > 0 {
+		updates["width"] = width
+		updates["height"] = height
+	}
+	if len(updates) == 1 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&models.Photo{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *PhotoRepositoryImpl) DecrementFaceCount(ctx context.Context, id uuid.UUID, n int) error {
+	return r.db.WithContext(ctx).Model(&models.Photo{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"face_count": gorm.Expr("GREATEST(face_count - ?, 0)", n),
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// ReconcileFaceCounts sets face_count to the actual number of faces rows for photos where they differ
+func (r *PhotoRepositoryImpl) ReconcileFaceCounts(ctx context.Context, folderID *uuid.UUID) (int64, error) {
+	actualCount := "(SELECT count(*) FROM faces WHERE faces.photo_id = photos.id)"
+
+	query := r.db.WithContext(ctx).Model(&models.Photo{}).
+		Where("face_count <> " + actualCount)
+	if folderID != nil {
+		query = query.Where("shared_folder_id = ?", *folderID)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"face_count": gorm.Expr(actualCount),
+		"updated_at": time.Now(),
+	})
+	return result.RowsAffected, result.Error
+}
+
 // UpdateFolderPath updates the folder path for all photos with the given drive_folder_id
 // Only updates photos where the path actually changed
 func (r *PhotoRepositoryImpl) UpdateFolderPath(ctx context.Context, driveFolderID string, newPath string) (int64, error) {
@@ -220,8 +361,13 @@ func (r *PhotoRepositoryImpl) UpdateFolderPath(ctx context.Context, driveFolderI
 	return result.RowsAffected, result.Error
 }
 
+// UpdateMetadata updates photo fields using a map (ensures all fields are updated)
+func (r *PhotoRepositoryImpl) UpdateMetadata(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&models.Photo{}).Where("id = ?", id).Updates(updates).Error
+}
+
 func (r *PhotoRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Photo{}).Error
+	return r.deleteWithCounts(ctx, "id = ?", id)
 }
 
 // SetTrashedByDriveFileID sets the trashed status for a photo by its Drive file ID
@@ -237,11 +383,8 @@ func (r *PhotoRepositoryImpl) SetTrashedByDriveFileID(ctx context.Context, drive
 	} else {
 		updates["trashed_at"] = nil
 	}
-	result := r.db.WithContext(ctx).Model(&models.Photo{}).
-		Where("drive_file_id = ?", driveFileID).
-		Where("is_trashed = ?", !isTrashed). // Only update if state needs to change
-		Updates(updates)
-	return result.RowsAffected > 0, result.Error
+	affected, err := r.setTrashedWithCounts(ctx, isTrashed, updates, "drive_file_id = ?", driveFileID)
+	return affected > 0, err
 }
 
 // SetTrashedByDriveFolderID sets the trashed status for all photos in a folder
@@ -257,15 +400,153 @@ func (r *PhotoRepositoryImpl) SetTrashedByDriveFolderID(ctx context.Context, dri
 	} else {
 		updates["trashed_at"] = nil
 	}
-	result := r.db.WithContext(ctx).Model(&models.Photo{}).
-		Where("drive_folder_id = ?", driveFolderID).
-		Where("is_trashed = ?", !isTrashed). // Only update photos that need state change
-		Updates(updates)
-	return result.RowsAffected, result.Error
+	return r.setTrashedWithCounts(ctx, isTrashed, updates, "drive_folder_id = ?", driveFolderID)
+}
+
+// TrashNotInDriveIDsForFolder marks photos of a folder that are not in the Drive listing as trashed
+// Faces are kept so a photo that reappears is restored as it was
+func (r *PhotoRepositoryImpl) TrashNotInDriveIDsForFolder(ctx context.Context, folderID uuid.UUID, driveFileIDs []string) (int64, error) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"is_trashed": true,
+		"trashed_at": &now,
+		"updated_at": now,
+	}
+	// Uploaded photos are not in Drive, so they are never orphans
+	if len(driveFileIDs) == 0 {
+		return r.setTrashedWithCounts(ctx, true, updates, "shared_folder_id = ? AND source = ?", folderID, models.PhotoSourceDrive)
+	}
+	return r.setTrashedWithCounts(ctx, true, updates, "shared_folder_id = ? AND source = ? AND drive_file_id NOT IN ?", folderID, models.PhotoSourceDrive, driveFileIDs)
+}
+
+// GetTrashedBySharedFolder lists a folder's trashed photos, most recently trashed first
+func (r *PhotoRepositoryImpl) GetTrashedBySharedFolder(ctx context.Context, folderID uuid.UUID, offset, limit int) ([]models.Photo, int64, error) {
+	var photos []models.Photo
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Photo{}).
+		Where("shared_folder_id = ?", folderID).
+		Where("is_trashed = ?", true)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("trashed_at DESC NULLS LAST").
+		Offset(offset).
+		Limit(limit).
+		Find(&photos).Error
+
+	return photos, total, err
+}
+
+// TrashByID moves one photo to the trash (and out of its folder's photo count)
+func (r *PhotoRepositoryImpl) TrashByID(ctx context.Context, id uuid.UUID) (bool, error) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"is_trashed": true,
+		"trashed_at": &now,
+		"updated_at": now,
+	}
+	affected, err := r.setTrashedWithCounts(ctx, true, updates, "id = ?", id)
+	return affected > 0, err
+}
+
+// RestoreByID takes one photo out of the trash (and back into its folder's photo count)
+func (r *PhotoRepositoryImpl) RestoreByID(ctx context.Context, id uuid.UUID) (bool, error) {
+	updates := map[string]interface{}{
+		"is_trashed": false,
+		"trashed_at": nil,
+		"updated_at": time.Now(),
+	}
+	affected, err := r.setTrashedWithCounts(ctx, false, updates, "id = ?", id)
+	return affected > 0, err
+}
+
+// PurgeTrashedBefore hard deletes photos (and their faces) that have been trashed since before cutoff
+func (r *PhotoRepositoryImpl) PurgeTrashedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	var totalDeleted int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var photoIDs []uuid.UUID
+		if err := tx.Model(&models.Photo{}).
+			Where("is_trashed = ? AND trashed_at < ?", true, cutoff).
+			Pluck("id", &photoIDs).Error; err != nil {
+			return err
+		}
+
+		if len(photoIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Where("photo_id IN ?", photoIDs).Delete(&models.Face{}).Error; err != nil {
+			return err
+		}
+
+		// Trashed photos are not part of the cached photo_count, so no count update
+		result := tx.Where("id IN ?", photoIDs).Delete(&models.Photo{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		totalDeleted = result.RowsAffected
+		return nil
+	})
+
+	return totalDeleted, err
+}
+
+// setTrashedWithCounts applies a trash state change to matching photos that need it and
+// adjusts their folders' cached photo counts (trashed photos are not counted)
+func (r *PhotoRepositoryImpl) setTrashedWithCounts(ctx context.Context, isTrashed bool, updates map[string]interface{}, query string, args ...interface{}) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Photos changing state, by folder (only update if state needs to change)
+		var rows []folderPhotoCount
+		if err := tx.Model(&models.Photo{}).
+			Select("shared_folder_id, COUNT(*) AS count").
+			Where(query, args...).
+			Where("is_trashed = ?", !isTrashed).
+			Group("shared_folder_id").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Photo{}).
+			Where(query, args...).
+			Where("is_trashed = ?", !isTrashed).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+
+		sign := int64(1)
+		if isTrashed {
+			sign = -1
+		}
+		return applyFolderPhotoCounts(tx, folderPhotoCountMap(rows), sign)
+	})
+	return affected, err
 }
 
 func (r *PhotoRepositoryImpl) DeleteByDriveFileID(ctx context.Context, driveFileID string) error {
-	return r.db.WithContext(ctx).Where("drive_file_id = ?", driveFileID).Delete(&models.Photo{}).Error
+	return r.deleteWithCounts(ctx, "drive_file_id = ?", driveFileID)
+}
+
+// deleteWithCounts deletes matching photos and decrements their folders' cached photo counts
+func (r *PhotoRepositoryImpl) deleteWithCounts(ctx context.Context, query string, args ...interface{}) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		counts, err := activePhotoCounts(tx, query, args...)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where(query, args...).Delete(&models.Photo{}).Error; err != nil {
+			return err
+		}
+		return applyFolderPhotoCounts(tx, counts, -1)
+	})
 }
 
 func (r *PhotoRepositoryImpl) DeleteByFolderID(ctx context.Context, userID uuid.UUID, folderID string) (int64, error) {
@@ -288,6 +569,11 @@ func (r *PhotoRepositoryImpl) DeleteByFolderID(ctx context.Context, userID uuid.
 			return err
 		}
 
+		counts, err := activePhotoCounts(tx, "user_id = ? AND drive_folder_id = ?", userID, folderID)
+		if err != nil {
+			return err
+		}
+
 		// Now delete the photos
 		result := tx.Where("user_id = ? AND drive_folder_id = ?", userID, folderID).Delete(&models.Photo{})
 		if result.Error != nil {
@@ -295,7 +581,7 @@ func (r *PhotoRepositoryImpl) DeleteByFolderID(ctx context.Context, userID uuid.
 		}
 
 		totalDeleted = result.RowsAffected
-		return nil
+		return applyFolderPhotoCounts(tx, counts, -1)
 	})
 
 	return totalDeleted, err
@@ -329,6 +615,11 @@ func (r *PhotoRepositoryImpl) DeleteNotInDriveIDs(ctx context.Context, userID uu
 			return err
 		}
 
+		counts, err := activePhotoCounts(tx, "id IN ?", photoIDs)
+		if err != nil {
+			return err
+		}
+
 		// Now delete the photos
 		var result *gorm.DB
 		if len(driveFileIDs) == 0 {
@@ -342,7 +633,7 @@ func (r *PhotoRepositoryImpl) DeleteNotInDriveIDs(ctx context.Context, userID uu
 		}
 
 		totalDeleted = result.RowsAffected
-		return nil
+		return applyFolderPhotoCounts(tx, counts, -1)
 	})
 
 	return totalDeleted, err
@@ -424,7 +715,26 @@ func (r *PhotoRepositoryImpl) CountByFolderPathPrefixAndFaceStatus(ctx context.C
 // SharedFolder-based methods (new)
 // ============================================
 
-func (r *PhotoRepositoryImpl) GetBySharedFolder(ctx context.Context, folderID uuid.UUID, offset, limit int) ([]models.Photo, int64, error) {
+// photoOrderClauses maps listing sorts to fixed ORDER BY clauses, so request values never reach the SQL
+var photoOrderClauses = map[models.PhotoSort]string{
+	models.PhotoSortCreatedDesc:  "drive_created_at DESC",
+	models.PhotoSortCreatedAsc:   "drive_created_at ASC",
+	models.PhotoSortNameAsc:      "file_name ASC, id ASC",
+	models.PhotoSortNameDesc:     "file_name DESC, id DESC",
+	models.PhotoSortModifiedDesc: "drive_modified_at DESC",
+	models.PhotoSortTakenDesc:    "COALESCE(taken_at, drive_created_at) DESC",
+	models.PhotoSortTakenAsc:     "COALESCE(taken_at, drive_created_at) ASC",
+}
+
+// photoOrder returns the ORDER BY clause of a sort; unknown and empty sorts use the newest first
+func photoOrder(sort models.PhotoSort) string {
+	if clause, ok := photoOrderClauses[sort]; ok {
+		return clause
+	}
+	return photoOrderClauses[models.PhotoSortCreatedDesc]
+}
+
+func (r *PhotoRepositoryImpl) GetBySharedFolder(ctx context.Context, folderID uuid.UUID, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error) {
 	var photos []models.Photo
 	var total int64
 
@@ -437,7 +747,7 @@ func (r *PhotoRepositoryImpl) GetBySharedFolder(ctx context.Context, folderID uu
 	}
 
 	err := query.
-		Order("drive_created_at DESC").
+		Order(photoOrder(sort)).
 		Offset(offset).
 		Limit(limit).
 		Find(&photos).Error
@@ -445,7 +755,7 @@ func (r *PhotoRepositoryImpl) GetBySharedFolder(ctx context.Context, folderID uu
 	return photos, total, err
 }
 
-func (r *PhotoRepositoryImpl) GetBySharedFolderAndPath(ctx context.Context, folderID uuid.UUID, folderPath string, offset, limit int) ([]models.Photo, int64, error) {
+func (r *PhotoRepositoryImpl) GetBySharedFolderAndPath(ctx context.Context, folderID uuid.UUID, folderPath string, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error) {
 	var photos []models.Photo
 	var total int64
 
@@ -461,7 +771,37 @@ func (r *PhotoRepositoryImpl) GetBySharedFolderAndPath(ctx context.Context, fold
 	}
 
 	err := query.
-		Order("drive_created_at DESC").
+		Order(photoOrder(sort)).
+		Offset(offset).
+		Limit(limit).
+		Find(&photos).Error
+
+	return photos, total, err
+}
+
+func (r *PhotoRepositoryImpl) GetBySharedFolderAndDateRange(ctx context.Context, folderID uuid.UUID, folderPath string, from, to *time.Time, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error) {
+	var photos []models.Photo
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Photo{}).
+		Where("shared_folder_id = ?", folderID).
+		Where("is_trashed = ?", false)
+	if folderPath != "" {
+		query = query.Where("drive_folder_path = ?", folderPath)
+	}
+	if from != nil {
+		query = query.Where("drive_created_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("drive_created_at <= ?", *to)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order(photoOrder(sort)).
 		Offset(offset).
 		Limit(limit).
 		Find(&photos).Error
@@ -493,7 +833,21 @@ func (r *PhotoRepositoryImpl) GetBySharedFolderAndDriveFolderID(ctx context.Cont
 	return photos, total, err
 }
 
-func (r *PhotoRepositoryImpl) SearchByFolderPathInSharedFolder(ctx context.Context, folderID uuid.UUID, searchQuery string, offset, limit int) ([]models.Photo, int64, error) {
+func (r *PhotoRepositoryImpl) SearchByFolderPathInSharedFolder(ctx context.Context, folderID uuid.UUID, searchQuery string, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error) {
+	return r.searchInSharedFolder(ctx, folderID, []string{"drive_folder_path"}, searchQuery, sort, offset, limit)
+}
+
+func (r *PhotoRepositoryImpl) SearchByFileName(ctx context.Context, folderID uuid.UUID, searchQuery string, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error) {
+	return r.searchInSharedFolder(ctx, folderID, []string{"file_name"}, searchQuery, sort, offset, limit)
+}
+
+func (r *PhotoRepositoryImpl) SearchByFileNameOrFolderPath(ctx context.Context, folderID uuid.UUID, searchQuery string, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error) {
+	return r.searchInSharedFolder(ctx, folderID, []string{"file_name", "drive_folder_path"}, searchQuery, sort, offset, limit)
+}
+
+// searchInSharedFolder pages non-trashed photos of a folder whose columns (any of them)
+// contain searchQuery, case-insensitively
+func (r *PhotoRepositoryImpl) searchInSharedFolder(ctx context.Context, folderID uuid.UUID, columns []string, searchQuery string, sort models.PhotoSort, offset, limit int) ([]models.Photo, int64, error) {
 	var photos []models.Photo
 	var total int64
 
@@ -501,7 +855,12 @@ func (r *PhotoRepositoryImpl) SearchByFolderPathInSharedFolder(ctx context.Conte
 		Where("shared_folder_id = ?", folderID).
 		Where("is_trashed = ?", false)
 	if searchQuery != "" {
-		query = query.Where("LOWER(drive_folder_path) LIKE LOWER(?)", "%"+searchQuery+"%")
+		pattern := "%" + searchQuery + "%"
+		match := r.db.Where("LOWER("+columns[0]+") LIKE LOWER(?)", pattern)
+		for _, column := range columns[1:] {
+			match = match.Or("LOWER("+column+") LIKE LOWER(?)", pattern)
+		}
+		query = query.Where(match)
 	}
 
 	if err := query.Count(&total).Error; err != nil {
@@ -509,7 +868,7 @@ func (r *PhotoRepositoryImpl) SearchByFolderPathInSharedFolder(ctx context.Conte
 	}
 
 	err := query.
-		Order("drive_created_at DESC").
+		Order(photoOrder(sort)).
 		Offset(offset).
 		Limit(limit).
 		Find(&photos).Error
@@ -529,6 +888,120 @@ func (r *PhotoRepositoryImpl) GetFolderPathsInSharedFolder(ctx context.Context,
 	return paths, err
 }
 
+func (r *PhotoRepositoryImpl) CountByFolderPathInSharedFolder(ctx context.Context, folderID uuid.UUID) (map[string]int64, error) {
+	var rows []struct {
+		DriveFolderPath string
+		Count           int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.Photo{}).
+		Select("drive_folder_path, COUNT(*) AS count").
+		Where("shared_folder_id = ?", folderID).
+		Where("is_trashed = ?", false).
+		Group("drive_folder_path").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.DriveFolderPath] += row.Count
+	}
+	return counts, nil
+}
+
+// GetFolderStats computes the folder dashboard totals in a single query; people are counted
+// from faces of non-trashed photos
+func (r *PhotoRepositoryImpl) GetFolderStats(ctx context.Context, folderID uuid.UUID) (*repositories.FolderPhotoStats, error) {
+	var stats repositories.FolderPhotoStats
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			COUNT(*) AS total_photos,
+			COUNT(*) FILTER (WHERE p.face_count > 0) AS photos_with_faces,
+			COALESCE(SUM(p.face_count), 0) AS total_faces,
+			MIN(COALESCE(p.drive_created_at, p.created_at)) AS first_photo_at,
+			MAX(COALESCE(p.drive_created_at, p.created_at)) AS last_photo_at,
+			(
+				SELECT COUNT(DISTINCT f.person_id)
+				FROM faces f
+				JOIN photos fp ON fp.id = f.photo_id
+				WHERE f.shared_folder_id = ?
+					AND f.person_id IS NOT NULL
+					AND f.redacted = false
+					AND fp.is_trashed = false
+			) AS distinct_persons
+		FROM photos p
+		WHERE p.shared_folder_id = ?
+			AND p.is_trashed = false
+	`, folderID, folderID).Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (r *PhotoRepositoryImpl) GetDriveFileIDsBySharedFolder(ctx context.Context, folderID uuid.UUID) ([]string, error) {
+	var ids []string
+	// Uploaded photos only have a placeholder drive_file_id
+	err := r.db.WithContext(ctx).
+		Model(&models.Photo{}).
+		Where("shared_folder_id = ? AND source = ?", folderID, models.PhotoSourceDrive).
+		Where("is_trashed = ?", false).
+		Pluck("drive_file_id", &ids).Error
+	return ids, err
+}
+
+// FindDuplicatesBySharedFolder pages through content hashes shared by more than one non-trashed
+// photo of a folder (most copies first) and loads each group's photos, oldest first
+func (r *PhotoRepositoryImpl) FindDuplicatesBySharedFolder(ctx context.Context, folderID uuid.UUID, offset, limit int) ([]repositories.PhotoDuplicateGroup, int64, error) {
+	duplicated := r.db.WithContext(ctx).Model(&models.Photo{}).
+		Select("content_hash, COUNT(*) AS copies").
+		Where("shared_folder_id = ?", folderID).
+		Where("is_trashed = ?", false).
+		Where("content_hash <> ''").
+		Group("content_hash").
+		Having("COUNT(*) > 1")
+
+	var total int64
+	if err := r.db.WithContext(ctx).Table("(?) AS d", duplicated).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var hashes []string
+	if err := r.db.WithContext(ctx).Table("(?) AS d", duplicated).
+		Order("copies DESC, content_hash").
+		Offset(offset).
+		Limit(limit).
+		Pluck("content_hash", &hashes).Error; err != nil {
+		return nil, 0, err
+	}
+	if len(hashes) == 0 {
+		return []repositories.PhotoDuplicateGroup{}, total, nil
+	}
+
+	var photos []models.Photo
+	if err := r.db.WithContext(ctx).
+		Where("shared_folder_id = ?", folderID).
+		Where("is_trashed = ?", false).
+		Where("content_hash IN ?", hashes).
+		Order("created_at ASC").
+		Find(&photos).Error; err != nil {
+		return nil, 0, err
+	}
+
+	byHash := make(map[string][]models.Photo, len(hashes))
+	for _, photo := range photos {
+		byHash[photo.ContentHash] = append(byHash[photo.ContentHash], photo)
+	}
+
+	groups := make([]repositories.PhotoDuplicateGroup, len(hashes))
+	for i, hash := range hashes {
+		groups[i] = repositories.PhotoDuplicateGroup{ContentHash: hash, Photos: byHash[hash]}
+	}
+	return groups, total, nil
+}
+
 func (r *PhotoRepositoryImpl) CountBySharedFolder(ctx context.Context, folderID uuid.UUID) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&models.Photo{}).
@@ -548,6 +1021,31 @@ func (r *PhotoRepositoryImpl) CountBySharedFolderAndFaceStatus(ctx context.Conte
 	return count, err
 }
 
+// CountFaceQueueBySharedFolders counts non-trashed photos still waiting for or in face processing
+// per folder. Folders with face processing switched off are left out: their photos never get processed.
+func (r *PhotoRepositoryImpl) CountFaceQueueBySharedFolders(ctx context.Context, folderIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64)
+	if len(folderIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []folderPhotoCount
+	err := r.db.WithContext(ctx).
+		Model(&models.Photo{}).
+		Select("shared_folder_id, COUNT(*) AS count").
+		Where("shared_folder_id IN ?", folderIDs).
+		Where("face_status IN ?", []models.FaceProcessingStatus{models.FaceStatusPending, models.FaceStatusProcessing}).
+		Where("is_trashed = ?", false).
+		Not(faceProcessingDisabledFolders).
+		Group("shared_folder_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return folderPhotoCountMap(rows), nil
+}
+
 // Multi-folder queries
 
 func (r *PhotoRepositoryImpl) GetBySharedFolders(ctx context.Context, folderIDs []uuid.UUID, offset, limit int) ([]models.Photo, int64, error) {
@@ -603,6 +1101,62 @@ func (r *PhotoRepositoryImpl) GetBySharedFoldersAndPath(ctx context.Context, fol
 	return photos, total, err
 }
 
+func (r *PhotoRepositoryImpl) GetBySharedFoldersAndDriveFolderID(ctx context.Context, folderIDs []uuid.UUID, driveFolderID string, offset, limit int) ([]models.Photo, int64, error) {
+	var photos []models.Photo
+	var total int64
+
+	if len(folderIDs) == 0 {
+		return photos, 0, nil
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.Photo{}).
+		Where("shared_folder_id IN ?", folderIDs).
+		Where("is_trashed = ?", false)
+	if driveFolderID != "" {
+		query = query.Where("drive_folder_id = ?", driveFolderID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("drive_created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&photos).Error
+
+	return photos, total, err
+}
+
+func (r *PhotoRepositoryImpl) SearchByFolderPathInSharedFolders(ctx context.Context, folderIDs []uuid.UUID, searchQuery string, offset, limit int) ([]models.Photo, int64, error) {
+	var photos []models.Photo
+	var total int64
+
+	if len(folderIDs) == 0 {
+		return photos, 0, nil
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.Photo{}).
+		Where("shared_folder_id IN ?", folderIDs).
+		Where("is_trashed = ?", false)
+	if searchQuery != "" {
+		query = query.Where("LOWER(drive_folder_path) LIKE LOWER(?)", "%"+searchQuery+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("drive_created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&photos).Error
+
+	return photos, total, err
+}
+
 // Delete operations for SharedFolder
 
 func (r *PhotoRepositoryImpl) DeleteByDriveFolderID(ctx context.Context, driveFolderID string) (int64, error) {
@@ -622,18 +1176,38 @@ func (r *PhotoRepositoryImpl) DeleteByDriveFolderID(ctx context.Context, driveFo
 			return err
 		}
 
+		counts, err := activePhotoCounts(tx, "drive_folder_id = ?", driveFolderID)
+		if err != nil {
+			return err
+		}
+
 		result := tx.Where("drive_folder_id = ?", driveFolderID).Delete(&models.Photo{})
 		if result.Error != nil {
 			return result.Error
 		}
 
 		totalDeleted = result.RowsAffected
-		return nil
+		return applyFolderPhotoCounts(tx, counts, -1)
 	})
 
 	return totalDeleted, err
 }
 
+func (r *PhotoRepositoryImpl) CountNotInDriveIDsForFolder(ctx context.Context, folderID uuid.UUID, driveFileIDs []string, includeTrashed bool) (int64, error) {
+	var count int64
+	// Uploaded photos are not in Drive, so they are never orphans
+	query := r.db.WithContext(ctx).Model(&models.Photo{}).
+		Where("shared_folder_id = ? AND source = ?", folderID, models.PhotoSourceDrive)
+	if len(driveFileIDs) > 0 {
+		query = query.Where("drive_file_id NOT IN ?", driveFileIDs)
+	}
+	if !includeTrashed {
+		query = query.Where("is_trashed = ?", false)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
 func (r *PhotoRepositoryImpl) DeleteNotInDriveIDsForFolder(ctx context.Context, folderID uuid.UUID, driveFileIDs []string) (int64, error) {
 	var totalDeleted int64
 
@@ -641,10 +1215,12 @@ func (r *PhotoRepositoryImpl) DeleteNotInDriveIDsForFolder(ctx context.Context,
 		var photoIDs []uuid.UUID
 		var query *gorm.DB
 
+		// Uploaded photos are not in Drive, so they are never orphans
+		base := tx.Model(&models.Photo{}).Where("source = ?", models.PhotoSourceDrive)
 		if len(driveFileIDs) == 0 {
-			query = tx.Model(&models.Photo{}).Where("shared_folder_id = ?", folderID).Pluck("id", &photoIDs)
+			query = base.Where("shared_folder_id = ?", folderID).Pluck("id", &photoIDs)
 		} else {
-			query = tx.Model(&models.Photo{}).Where("shared_folder_id = ? AND drive_file_id NOT IN ?", folderID, driveFileIDs).Pluck("id", &photoIDs)
+			query = base.Where("shared_folder_id = ? AND drive_file_id NOT IN ?", folderID, driveFileIDs).Pluck("id", &photoIDs)
 		}
 
 		if query.Error != nil {
@@ -659,19 +1235,19 @@ func (r *PhotoRepositoryImpl) DeleteNotInDriveIDsForFolder(ctx context.Context,
 			return err
 		}
 
-		var result *gorm.DB
-		if len(driveFileIDs) == 0 {
-			result = tx.Where("shared_folder_id = ?", folderID).Delete(&models.Photo{})
-		} else {
-			result = tx.Where("shared_folder_id = ? AND drive_file_id NOT IN ?", folderID, driveFileIDs).Delete(&models.Photo{})
+		counts, err := activePhotoCounts(tx, "id IN ?", photoIDs)
+		if err != nil {
+			return err
 		}
 
+		result := tx.Where("id IN ?", photoIDs).Delete(&models.Photo{})
+
 		if result.Error != nil {
 			return result.Error
 		}
 
 		totalDeleted = result.RowsAffected
-		return nil
+		return applyFolderPhotoCounts(tx, counts, -1)
 	})
 
 	return totalDeleted, err
@@ -693,6 +1269,65 @@ func (r *PhotoRepositoryImpl) ResetFailedToPending(ctx context.Context, folderID
 	return result.RowsAffected, result.Error
 }
 
+// ResetSkippedToPending queues all skipped photos in a folder for face processing (videos stay skipped)
+func (r *PhotoRepositoryImpl) ResetSkippedToPending(ctx context.Context, folderID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Photo{}).
+		Where("shared_folder_id = ?", folderID).
+		Where("face_status = ?", models.FaceStatusSkipped).
+		Where("media_type <> ?", models.MediaTypeVideo).
+		Updates(map[string]interface{}{
+			"face_status": models.FaceStatusPending,
+			"updated_at":  time.Now(),
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+// ResetAllToPendingForFolder deletes every face of a folder and queues all its photos (videos stay
+// skipped) for face processing again, e.g. after a face model upgrade. Without the delete the face
+// worker would complete the photos with their old faces. Trashed photos are queued too, so they are
+// reprocessed if restored. Face counts and centroids of persons who lose faces are recalculated.
+func (r *PhotoRepositoryImpl) ResetAllToPendingForFolder(ctx context.Context, folderID uuid.UUID) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var personIDs []uuid.UUID
+		if err := tx.Model(&models.Face{}).
+			Where("shared_folder_id = ? AND person_id IS NOT NULL", folderID).
+			Distinct().Pluck("person_id", &personIDs).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("shared_folder_id = ?", folderID).Delete(&models.Face{}).Error; err != nil {
+			return err
+		}
+
+		if len(personIDs) > 0 {
+			if err := refreshPersonStats(tx, personIDs); err != nil {
+				return err
+			}
+		}
+
+		// Forget the last model check too, so faceless photos are extracted again
+		result := tx.Model(&models.Photo{}).
+			Where("shared_folder_id = ?", folderID).
+			Where("media_type <> ?", models.MediaTypeVideo).
+			Updates(map[string]interface{}{
+				"face_status":                   models.FaceStatusPending,
+				"face_count":                    0,
+				"last_face_check_model_version": "",
+				"updated_at":                    time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		affected = result.RowsAffected
+		return nil
+	})
+
+	return affected, err
+}
+
 // ResetStuckProcessingToPending resets photos stuck in "processing" status for longer than threshold
 func (r *PhotoRepositoryImpl) ResetStuckProcessingToPending(ctx context.Context, stuckThresholdMinutes int) (int64, error) {
 	threshold := time.Now().Add(-time.Duration(stuckThresholdMinutes) * time.Minute)
@@ -707,3 +1342,49 @@ func (r *PhotoRepositoryImpl) ResetStuckProcessingToPending(ctx context.Context,
 
 	return result.RowsAffected, result.Error
 }
+
+// Cached shared_folders.photo_count maintenance (counts non-trashed photos)
+
+type folderPhotoCount struct {
+	SharedFolderID uuid.UUID
+	Count          int64
+}
+
+func folderPhotoCountMap(rows []folderPhotoCount) map[uuid.UUID]int64 {
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.SharedFolderID] += row.Count
+	}
+	return counts
+}
+
+// activePhotoCounts counts non-trashed photos matching the condition, by shared folder
+func activePhotoCounts(tx *gorm.DB, query string, args ...interface{}) (map[uuid.UUID]int64, error) {
+	var rows []folderPhotoCount
+	err := tx.Model(&models.Photo{}).
+		Select("shared_folder_id, COUNT(*) AS count").
+		Where(query, args...).
+		Where("is_trashed = ?", false).
+		Group("shared_folder_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return folderPhotoCountMap(rows), nil
+}
+
+// applyFolderPhotoCounts adds sign*count to each folder's cached photo count (never below 0)
+func applyFolderPhotoCounts(tx *gorm.DB, counts map[uuid.UUID]int64, sign int64) error {
+	for folderID, n := range counts {
+		if folderID == uuid.Nil || n == 0 {
+			continue
+		}
+		err := tx.Model(&models.SharedFolder{}).
+			Where("id = ?", folderID).
+			UpdateColumn("photo_count", gorm.Expr("GREATEST(photo_count + ?, 0)", sign*n)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
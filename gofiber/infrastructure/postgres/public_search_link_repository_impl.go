@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+)
+
+type PublicSearchLinkRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPublicSearchLinkRepository(db *gorm.DB) repositories.PublicSearchLinkRepository {
+	return &PublicSearchLinkRepositoryImpl{db: db}
+}
+
+func (r *PublicSearchLinkRepositoryImpl) Create(ctx context.Context, link *models.PublicSearchLink) error {
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+func (r *PublicSearchLinkRepositoryImpl) GetByToken(ctx context.Context, token string) (*models.PublicSearchLink, error) {
+	var link models.PublicSearchLink
+	err := r.db.WithContext(ctx).Where("token = ?", token).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
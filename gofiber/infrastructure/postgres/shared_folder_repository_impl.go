@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -62,11 +63,12 @@ func (r *SharedFolderRepositoryImpl) GetAll(ctx context.Context) ([]models.Share
 }
 
 // GetAllNeedingSync gets all folders that need syncing (have valid tokens)
+// Folders whose token owner lost Drive access are skipped until someone reconnects them
 func (r *SharedFolderRepositoryImpl) GetAllNeedingSync(ctx context.Context) ([]models.SharedFolder, error) {
 	var folders []models.SharedFolder
 	err := r.db.WithContext(ctx).
 		Where("drive_refresh_token != ''").
-		Where("sync_status != ?", models.SyncStatusSyncing).
+		Where("sync_status NOT IN ?", []models.SyncStatus{models.SyncStatusSyncing, models.SyncStatusAccessRevoked}).
 		Find(&folders).Error
 	return folders, err
 }
@@ -91,6 +93,29 @@ func (r *SharedFolderRepositoryImpl) UpdateSyncStatus(ctx context.Context, id uu
 	if status == models.SyncStatusIdle && lastError == "" {
 		updates["last_synced_at"] = time.Now()
 	}
+	if lastError == "" {
+		updates["last_error_code"] = ""
+		updates["last_error_detail"] = gorm.Expr("NULL")
+	}
+	return r.db.WithContext(ctx).Model(&models.SharedFolder{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// UpdateSyncError updates the sync status of a folder together with its classified last error
+func (r *SharedFolderRepositoryImpl) UpdateSyncError(ctx context.Context, id uuid.UUID, status models.SyncStatus, lastError string, code models.SyncErrorCode, detail *models.SyncErrorDetail) error {
+	updates := map[string]interface{}{
+		"sync_status":       status,
+		"last_error":        lastError,
+		"last_error_code":   code,
+		"last_error_detail": gorm.Expr("NULL"),
+		"updated_at":        time.Now(),
+	}
+	if detail != nil {
+		detailJSON, err := json.Marshal(detail)
+		if err != nil {
+			return err
+		}
+		updates["last_error_detail"] = string(detailJSON)
+	}
 	return r.db.WithContext(ctx).Model(&models.SharedFolder{}).Where("id = ?", id).Updates(updates).Error
 }
 
@@ -181,14 +206,43 @@ func (r *SharedFolderRepositoryImpl) CountUsers(ctx context.Context, folderID uu
 	return count, err
 }
 
-// GetFoldersWithExpiringWebhooks gets folders with webhooks expiring before the given threshold
+// ReconcilePhotoCounts recomputes the cached photo_count of folders where it drifted
+func (r *SharedFolderRepositoryImpl) ReconcilePhotoCounts(ctx context.Context) (int64, error) {
+	actualCount := "(SELECT count(*) FROM photos WHERE photos.shared_folder_id = shared_folders.id AND photos.is_trashed = false)"
+
+	result := r.db.WithContext(ctx).Model(&models.SharedFolder{}).
+		Where("photo_count <> "+actualCount).
+		UpdateColumn("photo_count", gorm.Expr(actualCount))
+	return result.RowsAffected, result.Error
+}
+
+// CountFoldersByUser counts the number of folders a user has access to
+func (r *SharedFolderRepositoryImpl) CountFoldersByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.UserFolderAccess{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// GetFoldersWithExpiringWebhooks gets folders with webhooks expiring before the given threshold,
+// plus active channels registered with an unsigned token
 func (r *SharedFolderRepositoryImpl) GetFoldersWithExpiringWebhooks(ctx context.Context, expiryThreshold time.Time) ([]models.SharedFolder, error) {
 	var folders []models.SharedFolder
 	err := r.db.WithContext(ctx).
 		Where("webhook_expiry IS NOT NULL").
-		Where("webhook_expiry < ?", expiryThreshold).
+		Where("webhook_expiry < ? OR webhook_signed = ?", expiryThreshold, false).
 		Where("webhook_channel_id != ''").
 		Where("drive_refresh_token != ''"). // Must have valid tokens to renew
 		Find(&folders).Error
 	return folders, err
 }
+
+// GetFoldersWithSyncSchedule gets folders that have a sync cron schedule
+func (r *SharedFolderRepositoryImpl) GetFoldersWithSyncSchedule(ctx context.Context) ([]models.SharedFolder, error) {
+	var folders []models.SharedFolder
+	err := r.db.WithContext(ctx).
+		Where("sync_schedule_cron != ''").
+		Find(&folders).Error
+	return folders, err
+}
@@ -2,15 +2,21 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"gofiber-template/domain/models"
 	"gofiber-template/domain/repositories"
 )
 
+// Running jobs not updated for this long no longer hold back pending jobs of their folder
+// (a worker that crashed mid-sync leaves its job running forever)
+const runningJobStaleAfter = 30 * time.Minute
+
 type SyncJobRepositoryImpl struct {
 	db *gorm.DB
 }
@@ -62,26 +68,89 @@ func (r *SyncJobRepositoryImpl) GetLatestByUserAndType(ctx context.Context, user
 	return &job, nil
 }
 
-func (r *SyncJobRepositoryImpl) GetPendingJobs(ctx context.Context, jobType models.SyncJobType, limit int) ([]models.SyncJob, error) {
+func (r *SyncJobRepositoryImpl) ClaimPendingJobs(ctx context.Context, jobType models.SyncJobType, limit int) ([]models.SyncJob, error) {
+	return r.claimPendingJobs(ctx, jobType, "", limit)
+}
+
+// incrementalSyncJobCondition matches drive sync jobs the worker runs as incremental syncs: the
+// folder was synced before, has a page token and the job doesn't force a full sync (same rule
+// as SyncWorker.processJob)
+const incrementalSyncJobCondition = `EXISTS (
+		SELECT 1 FROM shared_folders f
+		WHERE f.id = sync_jobs.shared_folder_id AND f.last_synced_at IS NOT NULL AND COALESCE(f.page_token, '') <> ''
+	) AND COALESCE((sync_jobs.metadata->>'force_full')::boolean, false) = false`
+
+func (r *SyncJobRepositoryImpl) ClaimPendingSyncJobs(ctx context.Context, mode models.SyncMode, limit int) ([]models.SyncJob, error) {
+	condition := incrementalSyncJobCondition
+	if mode == models.SyncModeFull {
+		condition = "NOT (" + incrementalSyncJobCondition + ")"
+	}
+	return r.claimPendingJobs(ctx, models.SyncJobTypeDriveSync, condition, limit)
+}
+
+// claimPendingJobs claims pending jobs of a type, optionally restricted by an extra SQL condition
+func (r *SyncJobRepositoryImpl) claimPendingJobs(ctx context.Context, jobType models.SyncJobType, condition string, limit int) ([]models.SyncJob, error) {
 	var jobs []models.SyncJob
-	err := r.db.WithContext(ctx).
-		Where("job_type = ? AND status = ?", jobType, models.SyncJobStatusPending).
-		Order("created_at ASC").
-		Limit(limit).
-		Find(&jobs).Error
+	if limit < 1 {
+		return jobs, nil
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("job_type = ? AND status = ?", jobType, models.SyncJobStatusPending)
+		if condition != "" {
+			query = query.Where(condition)
+		}
+
+		// One job per folder at a time: skip jobs queued behind an older pending or a live running job.
+		// SKIP LOCKED lets concurrent pollers (or other instances) pass over rows another one is claiming
+		if err := query.
+			Where(`NOT EXISTS (
+				SELECT 1 FROM sync_jobs o
+				WHERE o.shared_folder_id = sync_jobs.shared_folder_id AND o.job_type = sync_jobs.job_type AND o.id <> sync_jobs.id
+					AND ((o.status = ? AND (o.created_at, o.id) < (sync_jobs.created_at, sync_jobs.id))
+						OR (o.status = ? AND o.updated_at > ?))
+			)`, models.SyncJobStatusPending, models.SyncJobStatusRunning, time.Now().Add(-runningJobStaleAfter)).
+			Order("priority DESC, created_at ASC, id ASC").
+			Limit(limit).
+			Find(&jobs).Error; err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			return nil
+		}
 
-	return jobs, err
+		// Claim before the locks are released so no other poller picks the same jobs
+		ids := make([]uuid.UUID, len(jobs))
+		for i := range jobs {
+			ids[i] = jobs[i].ID
+		}
+		now := time.Now()
+		if err := tx.Model(&models.SyncJob{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":     models.SyncJobStatusRunning,
+			"started_at": &now,
+			"updated_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		for i := range jobs {
+			jobs[i].Status = models.SyncJobStatusRunning
+			jobs[i].StartedAt = &now
+			jobs[i].UpdatedAt = now
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
 }
 
 func (r *SyncJobRepositoryImpl) HasPendingOrRunningJobForFolder(ctx context.Context, folderID uuid.UUID) (bool, error) {
 	var count int64
-	// Check for pending or running jobs that have this folder ID in metadata
-	// metadata is JSON like: {"shared_folder_id":"uuid-here"}
 	err := r.db.WithContext(ctx).Model(&models.SyncJob{}).
-		Where("status IN (?, ?) AND metadata LIKE ?",
-			models.SyncJobStatusPending,
-			models.SyncJobStatusRunning,
-			"%"+folderID.String()+"%").
+		Where("job_type = ? AND shared_folder_id = ?", models.SyncJobTypeDriveSync, folderID).
+		Where("status IN (?, ?)", models.SyncJobStatusPending, models.SyncJobStatusRunning).
 		Count(&count).Error
 	if err != nil {
 		return false, err
@@ -89,6 +158,130 @@ func (r *SyncJobRepositoryImpl) HasPendingOrRunningJobForFolder(ctx context.Cont
 	return count > 0, nil
 }
 
+func (r *SyncJobRepositoryImpl) CountActive(ctx context.Context, jobType models.SyncJobType) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.SyncJob{}).
+		Where("job_type = ?", jobType).
+		Where("status IN (?, ?)", models.SyncJobStatusPending, models.SyncJobStatusRunning).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *SyncJobRepositoryImpl) GetPendingByFolder(ctx context.Context, folderID uuid.UUID) (*models.SyncJob, error) {
+	var job models.SyncJob
+	err := r.db.WithContext(ctx).
+		Where("job_type = ? AND shared_folder_id = ? AND status = ?", models.SyncJobTypeDriveSync, folderID, models.SyncJobStatusPending).
+		Order("created_at ASC").
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *SyncJobRepositoryImpl) CreatePendingForFolder(ctx context.Context, job *models.SyncJob) (bool, error) {
+	if job.SharedFolderID == nil {
+		return false, errors.New("sync job has no shared folder")
+	}
+
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialize creators per folder until commit so two webhooks can't both see "no pending job"
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "sync_job:"+job.SharedFolderID.String()).Error; err != nil {
+			return err
+		}
+
+		// A pending dry run doesn't sync anything, so it doesn't stand in for a real job
+		var count int64
+		if err := tx.Model(&models.SyncJob{}).
+			Where("job_type = ? AND shared_folder_id = ? AND status = ?", job.JobType, job.SharedFolderID, models.SyncJobStatusPending).
+			Where("COALESCE((metadata->>'dry_run')::boolean, false) = false").
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+func (r *SyncJobRepositoryImpl) GetLatestForFolder(ctx context.Context, folderID uuid.UUID, statuses ...models.SyncJobStatus) (*models.SyncJob, error) {
+	var job models.SyncJob
+	query := r.db.WithContext(ctx).
+		Where("job_type = ? AND shared_folder_id = ?", models.SyncJobTypeDriveSync, folderID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+
+	err := query.Order("created_at DESC").First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *SyncJobRepositoryImpl) GetFailedJobs(ctx context.Context, jobType models.SyncJobType, offset, limit int) ([]models.SyncJob, int64, error) {
+	var jobs []models.SyncJob
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.SyncJob{}).
+		Where("job_type = ? AND status = ?", jobType, models.SyncJobStatusFailed)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("updated_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&jobs).Error
+
+	return jobs, total, err
+}
+
+func (r *SyncJobRepositoryImpl) GetFailedByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.SyncJob, int64, error) {
+	var jobs []models.SyncJob
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.SyncJob{}).
+		Where("job_type = ? AND status = ?", models.SyncJobTypeDriveSync, models.SyncJobStatusFailed).
+		Where("shared_folder_id IN (SELECT shared_folder_id FROM user_folder_access WHERE user_id = ?)", userID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("updated_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&jobs).Error
+
+	return jobs, total, err
+}
+
+func (r *SyncJobRepositoryImpl) Requeue(ctx context.Context, id uuid.UUID) error {
+	updates := map[string]interface{}{
+		"status":           models.SyncJobStatusPending,
+		"last_error":       "",
+		"processed_items":  0,
+		"failed_items":     0,
+		"cancel_requested": false,
+		"started_at":       nil,
+		"completed_at":     nil,
+		"updated_at":       time.Now(),
+	}
+	return r.db.WithContext(ctx).Model(&models.SyncJob{}).Where("id = ?", id).Updates(updates).Error
+}
+
 func (r *SyncJobRepositoryImpl) Update(ctx context.Context, id uuid.UUID, job *models.SyncJob) error {
 	return r.db.WithContext(ctx).Where("id = ?", id).Updates(job).Error
 }
@@ -122,3 +315,60 @@ func (r *SyncJobRepositoryImpl) UpdateProgress(ctx context.Context, id uuid.UUID
 func (r *SyncJobRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.SyncJob{}).Error
 }
+
+func (r *SyncJobRepositoryImpl) DeleteFinishedBefore(ctx context.Context, cutoff time.Time, keepPerFolder int) (int64, error) {
+	finished := []models.SyncJobStatus{models.SyncJobStatusCompleted, models.SyncJobStatusFailed, models.SyncJobStatusCancelled}
+
+	// Rank each folder's finished jobs newest first; only jobs beyond the keep count can go
+	result := r.db.WithContext(ctx).Exec(`
+		DELETE FROM sync_jobs WHERE id IN (
+			SELECT id FROM (
+				SELECT id, updated_at,
+					ROW_NUMBER() OVER (PARTITION BY shared_folder_id ORDER BY created_at DESC) AS rn
+				FROM sync_jobs
+				WHERE job_type = ? AND status IN ?
+			) ranked
+			WHERE rn > ? AND updated_at < ?
+		)`, models.SyncJobTypeDriveSync, finished, keepPerFolder, cutoff)
+
+	return result.RowsAffected, result.Error
+}
+
+func (r *SyncJobRepositoryImpl) RequestCancel(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.SyncJob{}).
+		Where("id = ? AND status = ?", id, models.SyncJobStatusRunning).
+		Updates(map[string]interface{}{
+			"cancel_requested": true,
+			"updated_at":       time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *SyncJobRepositoryImpl) IsCancelRequested(ctx context.Context, id uuid.UUID) (bool, error) {
+	var requested bool
+	err := r.db.WithContext(ctx).Model(&models.SyncJob{}).
+		Select("cancel_requested").Where("id = ?", id).Scan(&requested).Error
+	return requested, err
+}
+
+func (r *SyncJobRepositoryImpl) SaveSnapshot(ctx context.Context, snapshot *models.SyncJobSnapshot) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("shared_folder_id = ?", snapshot.SharedFolderID).Delete(&models.SyncJobSnapshot{}).Error; err != nil {
+			return err
+		}
+		return tx.Save(snapshot).Error
+	})
+}
+
+func (r *SyncJobRepositoryImpl) GetSnapshot(ctx context.Context, folderID uuid.UUID) (*models.SyncJobSnapshot, error) {
+	var snapshot models.SyncJobSnapshot
+	err := r.db.WithContext(ctx).Where("shared_folder_id = ?", folderID).First(&snapshot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (r *SyncJobRepositoryImpl) DeleteSnapshot(ctx context.Context, folderID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("shared_folder_id = ?", folderID).Delete(&models.SyncJobSnapshot{}).Error
+}
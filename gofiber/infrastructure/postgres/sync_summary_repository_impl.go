@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+)
+
+type SyncSummaryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSyncSummaryRepository(db *gorm.DB) repositories.SyncSummaryRepository {
+	return &SyncSummaryRepositoryImpl{db: db}
+}
+
+func (r *SyncSummaryRepositoryImpl) Create(ctx context.Context, summary *models.SyncSummary) error {
+	return r.db.WithContext(ctx).Create(summary).Error
+}
+
+func (r *SyncSummaryRepositoryImpl) AggregateDaily(ctx context.Context, from, to time.Time) ([]repositories.SyncSummaryDaily, error) {
+	var results []repositories.SyncSummaryDaily
+	err := r.db.WithContext(ctx).Model(&models.SyncSummary{}).
+		Select(`date_trunc('day', completed_at) AS date,
+			COUNT(*) AS sync_count,
+			COUNT(*) FILTER (WHERE mode = ?) AS full_count,
+			COUNT(*) FILTER (WHERE mode = ?) AS incremental_count,
+			COALESCE(SUM(new_count), 0) AS new_count,
+			COALESCE(SUM(updated_count), 0) AS updated_count,
+			COALESCE(SUM(deleted_count), 0) AS deleted_count,
+			COALESCE(SUM(failed_count), 0) AS failed_count,
+			COALESCE(SUM(duration_ms), 0) AS total_duration_ms`,
+			models.SyncModeFull, models.SyncModeIncremental).
+		Where("completed_at >= ? AND completed_at < ?", from, to).
+		Group("date").
+		Order("date ASC").
+		Scan(&results).Error
+	return results, err
+}
@@ -2,16 +2,38 @@ package storage
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
+
+	"gofiber-template/pkg/logger"
 )
 
+// ErrBunnyUnavailable is returned while the circuit breaker is open after repeated Bunny failures
+// Callers should fall back (e.g. proxy thumbnails through our server) instead of failing the request
+var ErrBunnyUnavailable = errors.New("bunny storage temporarily unavailable")
+
 type BunnyStorage interface {
 	UploadFile(file io.Reader, path string, contentType string) (string, error)
 	DeleteFile(path string) error
+	DownloadFile(path string) ([]byte, error)
 	GetFileURL(path string) string
+
+	// Available reports whether Bunny is currently accepting calls (circuit breaker closed)
+	Available() bool
+	Health() BunnyHealth
+}
+
+// BunnyHealth describes the circuit breaker state of Bunny storage
+type BunnyHealth struct {
+	Configured          bool
+	Available           bool
+	ConsecutiveFailures int
+	OpenUntil           *time.Time
+	LastError           string
 }
 
 type BunnyStorageImpl struct {
@@ -19,6 +41,11 @@ type BunnyStorageImpl struct {
 	accessKey   string
 	baseURL     string
 	cdnURL      string
+
+	client         *http.Client
+	maxRetries     int
+	retryBaseDelay time.Duration
+	breaker        *circuitBreaker
 }
 
 type BunnyConfig struct {
@@ -26,48 +53,64 @@ type BunnyConfig struct {
 	AccessKey   string
 	BaseURL     string
 	CDNUrl      string
+
+	MaxRetries       int           // Retries after the first attempt on transient failures
+	RetryBaseDelay   time.Duration // Backoff doubles each retry: base, 2x base, 4x base...
+	BreakerThreshold int           // Consecutive failed operations before the breaker opens (0 = disabled)
+	BreakerCooldown  time.Duration // How long Bunny is skipped once the breaker opens
 }
 
+// maxRetryDelay caps the exponential backoff between attempts
+const maxRetryDelay = 5 * time.Second
+
 func NewBunnyStorage(config BunnyConfig) BunnyStorage {
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.RetryBaseDelay <= 0 {
+		config.RetryBaseDelay = 200 * time.Millisecond
+	}
+	if config.BreakerCooldown <= 0 {
+		config.BreakerCooldown = 30 * time.Second
+	}
+
 	return &BunnyStorageImpl{
-		storageZone: config.StorageZone,
-		accessKey:   config.AccessKey,
-		baseURL:     config.BaseURL,
-		cdnURL:      config.CDNUrl,
+		storageZone:    config.StorageZone,
+		accessKey:      config.AccessKey,
+		baseURL:        config.BaseURL,
+		cdnURL:         config.CDNUrl,
+		client:         &http.Client{},
+		maxRetries:     config.MaxRetries,
+		retryBaseDelay: config.RetryBaseDelay,
+		breaker:        newCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown),
 	}
 }
 
 func (b *BunnyStorageImpl) UploadFile(file io.Reader, path string, contentType string) (string, error) {
 	url := fmt.Sprintf("%s/%s/%s", b.baseURL, b.storageZone, path)
 
+	// Buffer once so every retry sends the same body
 	fileBytes, err := io.ReadAll(file)
 	if err != nil {
 		return "", err
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewReader(fileBytes))
-	if err != nil {
-		return "", err
-	}
-
-	// แก้ไข header name
-	req.Header.Set("AccessKey", b.accessKey) // หรือลอง Authorization
-	// req.Header.Set("Authorization", "Bearer " + b.accessKey)  // ทางเลือก
-	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("Content-Length", fmt.Sprintf("%d", len(fileBytes)))
+	err = b.do("upload", path, func() (*http.Request, error) {
+		req, err := http.NewRequest("PUT", url, bytes.NewReader(fileBytes))
+		if err != nil {
+			return nil, err
+		}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+		// แก้ไข header name
+		req.Header.Set("AccessKey", b.accessKey) // หรือลอง Authorization
+		// req.Header.Set("Authorization", "Bearer " + b.accessKey)  // ทางเลือก
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Content-Length", fmt.Sprintf("%d", len(fileBytes)))
+		return req, nil
+	}, http.StatusCreated, http.StatusOK)
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-
-	// เพิ่ม debug
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("upload failed with status: %d, body: %s", resp.StatusCode, string(body))
-	}
 
 	fileURL := b.GetFileURL(path)
 	return fileURL, nil
@@ -76,30 +119,142 @@ func (b *BunnyStorageImpl) UploadFile(file io.Reader, path string, contentType s
 func (b *BunnyStorageImpl) DeleteFile(path string) error {
 	url := fmt.Sprintf("%s/%s/%s", b.baseURL, b.storageZone, path)
 
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return err
+	return b.do("delete", path, func() (*http.Request, error) {
+		req, err := http.NewRequest("DELETE", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("AccessKey", b.accessKey)
+		return req, nil
+	}, http.StatusOK, http.StatusNoContent)
+}
+
+func (b *BunnyStorageImpl) DownloadFile(path string) ([]byte, error) {
+	url := fmt.Sprintf("%s/%s/%s", b.baseURL, b.storageZone, path)
+
+	return b.doWithBody("download", path, func() (*http.Request, error) {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("AccessKey", b.accessKey)
+		return req, nil
+	}, http.StatusOK)
+}
+
+func (b *BunnyStorageImpl) GetFileURL(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return b.cdnURL + path
+}
+
+func (b *BunnyStorageImpl) Available() bool {
+	return b.breaker.allow()
+}
+
+func (b *BunnyStorageImpl) Health() BunnyHealth {
+	open, failures, openUntil, lastError := b.breaker.snapshot()
+
+	health := BunnyHealth{
+		Configured:          b.storageZone != "" && b.accessKey != "",
+		Available:           !open,
+		ConsecutiveFailures: failures,
+		LastError:           lastError,
+	}
+	if open {
+		health.OpenUntil = &openUntil
+	}
+	return health
+}
+
+// transientError marks a failure worth retrying (network error, 429 or 5xx)
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+// do runs a request with retry-with-backoff on transient failures and records the outcome in the circuit breaker
+// newRequest is called for every attempt because a request body can only be read once
+func (b *BunnyStorageImpl) do(op string, path string, newRequest func() (*http.Request, error), okStatus ...int) error {
+	_, err := b.doWithBody(op, path, newRequest, okStatus...)
+	return err
+}
+
+// doWithBody is do that also returns the response body of the successful attempt
+func (b *BunnyStorageImpl) doWithBody(op string, path string, newRequest func() (*http.Request, error), okStatus ...int) ([]byte, error) {
+	if !b.breaker.allow() {
+		return nil, ErrBunnyUnavailable
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= b.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(b.backoff(attempt))
+		}
+
+		var body []byte
+		body, lastErr = b.attempt(op, newRequest, okStatus)
+		if lastErr == nil {
+			b.breaker.recordSuccess()
+			return body, nil
+		}
+
+		var transient *transientError
+		if !errors.As(lastErr, &transient) {
+			// Bunny answered (e.g. 401/404) - not an outage, so no retry and the breaker stays closed
+			b.breaker.recordSuccess()
+			return nil, lastErr
+		}
 	}
 
-	req.Header.Set("AccessKey", b.accessKey)
+	if b.breaker.recordFailure(lastErr) {
+		logger.Error(logger.CategoryAPI, "bunny_circuit_opened", "Bunny storage circuit breaker opened, falling back", lastErr, map[string]interface{}{
+			"operation": op,
+			"path":      path,
+		})
+	}
+	return nil, lastErr
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+func (b *BunnyStorageImpl) attempt(op string, newRequest func() (*http.Request, error), okStatus []int) ([]byte, error) {
+	req, err := newRequest()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, &transientError{err: err}
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("delete failed with status: %d", resp.StatusCode)
+	body, readErr := io.ReadAll(resp.Body)
+	for _, code := range okStatus {
+		if resp.StatusCode == code {
+			if readErr != nil {
+				return nil, &transientError{err: readErr}
+			}
+			return body, nil
+		}
 	}
 
-	return nil
+	err = fmt.Errorf("%s failed with status: %d, body: %s", op, resp.StatusCode, string(body))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, &transientError{err: err}
+	}
+	return nil, err
 }
 
-func (b *BunnyStorageImpl) GetFileURL(path string) string {
-	if !strings.HasPrefix(path, "/") {
-		path = "/" + path
+// backoff returns the delay before the given retry attempt (1-based)
+func (b *BunnyStorageImpl) backoff(attempt int) time.Duration {
+	delay := b.retryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > maxRetryDelay {
+		return maxRetryDelay
 	}
-	return b.cdnURL + path
+	return delay
 }
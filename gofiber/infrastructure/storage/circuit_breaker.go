@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker stops calls to a failing dependency for a cooldown period
+// After the cooldown one trial call is let through; success closes the breaker again
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int           // Consecutive failures before opening (0 = disabled)
+	cooldown  time.Duration // How long the breaker stays open
+	failures  int
+	openUntil time.Time
+	lastError string
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// allow reports whether a call may be attempted
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.threshold <= 0 {
+		return true
+	}
+	return !time.Now().Before(cb.openUntil)
+}
+
+// recordSuccess closes the breaker
+func (cb *circuitBreaker) recordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures = 0
+	cb.openUntil = time.Time{}
+	cb.lastError = ""
+}
+
+// recordFailure counts a failed call and opens the breaker once the threshold is reached
+// Returns true if this failure opened the breaker
+func (cb *circuitBreaker) recordFailure(err error) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures++
+	if err != nil {
+		cb.lastError = err.Error()
+	}
+
+	if cb.threshold <= 0 || cb.failures < cb.threshold {
+		return false
+	}
+
+	// A failed trial call after cooldown re-opens the breaker as well
+	cb.openUntil = time.Now().Add(cb.cooldown)
+	return true
+}
+
+// snapshot returns the current breaker state
+func (cb *circuitBreaker) snapshot() (open bool, failures int, openUntil time.Time, lastError string) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	open = cb.threshold > 0 && time.Now().Before(cb.openUntil)
+	return open, cb.failures, cb.openUntil, cb.lastError
+}
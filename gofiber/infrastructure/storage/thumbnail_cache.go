@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"gofiber-template/infrastructure/redis"
+)
+
+// ThumbnailCache persists generated thumbnails to Bunny CDN keyed by fileID+size
+// The Drive modified time is part of the object path, so a modified photo gets a new
+// cache entry and the previous version is deleted from storage
+type ThumbnailCache struct {
+	storage BunnyStorage
+	redis   *redis.RedisClient
+	ttl     time.Duration // How long the cache index entry is kept in Redis
+}
+
+// NewThumbnailCache creates a new thumbnail cache
+func NewThumbnailCache(storage BunnyStorage, redisClient *redis.RedisClient, ttl time.Duration) *ThumbnailCache {
+	return &ThumbnailCache{
+		storage: storage,
+		redis:   redisClient,
+		ttl:     ttl,
+	}
+}
+
+// Get returns the CDN URL of a cached thumbnail if it exists for the given version
+// Returns false while Bunny is unavailable so the thumbnail is proxied through our server
+func (c *ThumbnailCache) Get(ctx context.Context, fileID string, size int, version int64) (string, bool) {
+	if !c.storage.Available() {
+		return "", false
+	}
+
+	var cachedPath string
+	if err := c.redis.Get(ctx, c.indexKey(fileID, size), &cachedPath); err != nil {
+		return "", false
+	}
+
+	// The extension follows the cached content type, so only the rest of the path is compared
+	if strings.TrimSuffix(cachedPath, path.Ext(cachedPath)) != c.objectBase(fileID, size, version) {
+		return "", false // Photo modified since it was cached
+	}
+
+	return c.storage.GetFileURL(cachedPath), true
+}
+
+// Put uploads a thumbnail to storage and records it in the cache index
+// Any previously cached version for the same fileID+size is deleted
+func (c *ThumbnailCache) Put(ctx context.Context, fileID string, size int, version int64, data []byte, contentType string) (string, error) {
+	if !c.storage.Available() {
+		return "", ErrBunnyUnavailable
+	}
+
+	objectPath := c.objectBase(fileID, size, version) + thumbnailExtension(contentType)
+
+	var previousPath string
+	_ = c.redis.Get(ctx, c.indexKey(fileID, size), &previousPath)
+
+	url, err := c.storage.UploadFile(bytes.NewReader(data), objectPath, contentType)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload thumbnail: %w", err)
+	}
+
+	if err := c.redis.Set(ctx, c.indexKey(fileID, size), objectPath, c.ttl); err != nil {
+		return "", fmt.Errorf("failed to save thumbnail cache index: %w", err)
+	}
+
+	// Invalidate previous version
+	if previousPath != "" && previousPath != objectPath {
+		_ = c.storage.DeleteFile(previousPath)
+	}
+
+	return url, nil
+}
+
+func (c *ThumbnailCache) indexKey(fileID string, size int) string {
+	return fmt.Sprintf("thumbcache:%s:%d", fileID, size)
+}
+
+// objectBase is the object path of a thumbnail without its extension
+func (c *ThumbnailCache) objectBase(fileID string, size int, version int64) string {
+	safeID := strings.NewReplacer("/", "_", "\\", "_").Replace(fileID)
+	return fmt.Sprintf("thumbnails/%s/%d_%d", safeID, size, version)
+}
+
+// thumbnailExtension returns the file extension for a thumbnail content type
+// (Drive serves most thumbnails as JPEG, but PNG/WebP/GIF sources can keep their format)
+func thumbnailExtension(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	extensions := map[string]string{
+		"image/jpeg": ".jpg",
+		"image/png":  ".png",
+		"image/webp": ".webp",
+		"image/gif":  ".gif",
+		"image/avif": ".avif",
+		"image/heic": ".heic",
+	}
+
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if ext, exists := extensions[mediaType]; exists {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
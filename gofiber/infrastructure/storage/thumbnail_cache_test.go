@@ -0,0 +1,33 @@
+package storage
+
+import "testing"
+
+func TestThumbnailExtension(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{contentType: "image/jpeg", want: ".jpg"},
+		{contentType: "image/png", want: ".png"},
+		{contentType: "image/webp", want: ".webp"},
+		{contentType: "image/gif", want: ".gif"},
+		{contentType: "Image/PNG; charset=binary", want: ".png"},
+		{contentType: "image/bmp", want: ".bmp"},
+		{contentType: "", want: ".bin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			if got := thumbnailExtension(tt.contentType); got != tt.want {
+				t.Errorf("thumbnailExtension(%q) = %q, want %q", tt.contentType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThumbnailCacheObjectBase(t *testing.T) {
+	c := &ThumbnailCache{}
+	if got, want := c.objectBase("a/b\\c", 400, 123), "thumbnails/a_b_c/400_123"; got != want {
+		t.Errorf("objectBase = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,186 @@
+package worker
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/api/drive/v3"
+
+	"gofiber-template/infrastructure/googledrive"
+)
+
+// changeScope answers "is this Drive folder inside the synced folder?" and "what is its path?"
+// for the changes of one incremental sync. Drive change lists cover the whole Drive, so most
+// changes of a busy Drive share a few parents outside the folder; caching per parent (and
+// every ancestor seen on the way up) turns one Files.Get walk per change into one per folder.
+// The walk also records each folder's name and parent, so paths of folders already walked
+// are built from the cache instead of another GetFolderPath walk.
+// Lookups reflect Drive's current state, which is what the uncached calls returned as well.
+// A scope lives for one job, so nothing is carried over between syncs.
+type changeScope struct {
+	driveClient  *googledrive.DriveClient
+	srv          *drive.Service
+	rootFolderID string
+
+	within map[string]bool       // Drive folder ID -> inside the root folder
+	paths  map[string]string     // Drive folder ID -> path from the root folder
+	nodes  map[string]folderNode // Drive folder ID -> name and parent, from the parent walks
+
+	// Stats for the incremental sync summary log
+	skippedNonMedia   int // File changes of unsynced MIME types
+	skippedOutside    int // Media changes outside the root folder
+	movedOut          int // Indexed photos whose file moved outside the root folder
+	parentLookups     int // Files.Get calls made walking up parents
+	cachedScopeHits   int // Scope answers served from the cache
+	folderPathLookups int // GetFolderPath calls made (cached afterwards)
+	cachedPathBuilds  int // Paths built from walked ancestors without a Drive call
+}
+
+// folderNode is one Drive folder seen while walking up parents
+type folderNode struct {
+	name     string
+	parentID string // "" at the top of the Drive
+}
+
+func newChangeScope(driveClient *googledrive.DriveClient, srv *drive.Service, rootFolderID string) *changeScope {
+	return &changeScope{
+		driveClient:  driveClient,
+		srv:          srv,
+		rootFolderID: rootFolderID,
+		within:       map[string]bool{rootFolderID: true},
+		paths:        make(map[string]string),
+		nodes:        make(map[string]folderNode),
+	}
+}
+
+// isWithin reports whether folderID is the root folder or one of its descendants.
+// Failed lookups count as outside.
+func (s *changeScope) isWithin(ctx context.Context, folderID string) bool {
+	within, _ := s.lookupWithin(ctx, folderID)
+	return within
+}
+
+// lookupWithin is isWithin plus whether the answer is known: resolved is false when a
+// Drive call failed, so callers can avoid acting on a transient "outside"
+func (s *changeScope) lookupWithin(ctx context.Context, folderID string) (within bool, resolved bool) {
+	if folderID == "" {
+		return false, true
+	}
+	if within, ok := s.within[folderID]; ok {
+		s.cachedScopeHits++
+		return within, true
+	}
+
+	// Walk up until the root, a cached ancestor, or the top of the Drive; every folder
+	// on the way gets the same answer
+	var walked []string
+	result := false
+	currentID := folderID
+	for i := 0; i < 20; i++ {
+		if within, ok := s.within[currentID]; ok {
+			result = within
+			break
+		}
+		walked = append(walked, currentID)
+
+		s.parentLookups++
+		if err := s.driveClient.AccountLimiter().Wait(ctx); err != nil {
+			return false, false
+		}
+		f, err := s.srv.Files.Get(currentID).Fields("name, parents").SupportsAllDrives(true).Context(ctx).Do()
+		if err != nil {
+			// Treated as outside, but not cached: a transient error should not stick for the rest of the job
+			return false, false
+		}
+		node := folderNode{name: f.Name}
+		if len(f.Parents) > 0 {
+			node.parentID = f.Parents[0]
+		}
+		s.nodes[currentID] = node
+		if node.parentID == "" {
+			// Top of the Drive without passing the root folder
+			break
+		}
+		currentID = node.parentID
+	}
+
+	for _, id := range walked {
+		s.within[id] = result
+	}
+	return result, true
+}
+
+// folderPath returns the path of folderID, starting with the root folder's name.
+// Empty means the lookup failed (never a valid path); failures are not cached.
+func (s *changeScope) folderPath(ctx context.Context, folderID string) string {
+	if path, ok := s.paths[folderID]; ok {
+		return path
+	}
+	s.loadRootNode(ctx)
+	if path, ok := s.pathFromNodes(folderID); ok {
+		s.cachedPathBuilds++
+		s.paths[folderID] = path
+		return path
+	}
+
+	s.folderPathLookups++
+	path, err := s.driveClient.GetFolderPath(ctx, s.srv, folderID, s.rootFolderID)
+	if err != nil {
+		return ""
+	}
+	s.paths[folderID] = path
+	return path
+}
+
+// loadRootNode fetches the root folder's name once per job: scope walks stop at the root
+// without reading it, and every path starts with it. A failed fetch is retried on the next call.
+func (s *changeScope) loadRootNode(ctx context.Context) {
+	if _, ok := s.nodes[s.rootFolderID]; ok {
+		return
+	}
+	s.parentLookups++
+	if err := s.driveClient.AccountLimiter().Wait(ctx); err != nil {
+		return
+	}
+	f, err := s.srv.Files.Get(s.rootFolderID).Fields("name").SupportsAllDrives(true).Context(ctx).Do()
+	if err != nil {
+		return
+	}
+	s.nodes[s.rootFolderID] = folderNode{name: f.Name}
+}
+
+// pathFromNodes builds folderID's path from walked ancestors, the same way GetFolderPath
+// joins names up to and including the root folder. ok is false if a folder on the way
+// (the root included) was never walked, or the chain doesn't reach the root.
+func (s *changeScope) pathFromNodes(folderID string) (string, bool) {
+	var names []string
+	currentID := folderID
+	for i := 0; i < 20; i++ {
+		node, ok := s.nodes[currentID]
+		if !ok {
+			return "", false
+		}
+		names = append([]string{node.name}, names...)
+		if currentID == s.rootFolderID {
+			return strings.Join(names, "/"), true
+		}
+		if node.parentID == "" {
+			return "", false
+		}
+		currentID = node.parentID
+	}
+	return "", false
+}
+
+// stats returns the counters for logging
+func (s *changeScope) stats() map[string]interface{} {
+	return map[string]interface{}{
+		"skipped_non_media":   s.skippedNonMedia,
+		"skipped_outside":     s.skippedOutside,
+		"moved_out":           s.movedOut,
+		"parent_lookups":      s.parentLookups,
+		"cached_scope_hits":   s.cachedScopeHits,
+		"folder_path_lookups": s.folderPathLookups,
+		"cached_path_builds":  s.cachedPathBuilds,
+	}
+}
@@ -0,0 +1,197 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+
+	"gofiber-template/infrastructure/googledrive"
+)
+
+// Drive tree used by the tests:
+//
+//	top (My Drive)
+//	├── root (synced folder "Events")
+//	│   └── a ("2024")
+//	│       └── b ("Day 1")
+//	└── other ("Other")
+//	    └── c ("Misc")
+var testDriveFolders = map[string]folderNode{
+	"top":   {name: "My Drive"},
+	"root":  {name: "Events", parentID: "top"},
+	"a":     {name: "2024", parentID: "root"},
+	"b":     {name: "Day 1", parentID: "a"},
+	"other": {name: "Other", parentID: "top"},
+	"c":     {name: "Misc", parentID: "other"},
+}
+
+// newTestDrive returns a Drive service backed by a fake Drive API serving testDriveFolders,
+// with changes as its whole change list. IDs in failing answer with a 500. The returned map
+// counts Files.Get calls per folder ID.
+func newTestDrive(t *testing.T, changes []*drive.Change, failing ...string) (*drive.Service, map[string]int) {
+	t.Helper()
+
+	calls := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/changes") {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(&drive.ChangeList{Changes: changes, NewStartPageToken: "2"})
+			return
+		}
+
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		calls[id]++
+		for _, f := range failing {
+			if f == id {
+				http.Error(w, `{"error":{"code":500,"message":"backend error"}}`, http.StatusInternalServerError)
+				return
+			}
+		}
+		node, ok := testDriveFolders[id]
+		if !ok {
+			http.Error(w, `{"error":{"code":404,"message":"not found"}}`, http.StatusNotFound)
+			return
+		}
+		file := map[string]interface{}{"id": id, "name": node.name}
+		if node.parentID != "" {
+			file["parents"] = []string{node.parentID}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(file)
+	}))
+	t.Cleanup(server.Close)
+
+	srv, err := drive.NewService(context.Background(),
+		option.WithEndpoint(server.URL+"/"),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("drive.NewService: %v", err)
+	}
+	return srv, calls
+}
+
+// newTestScope returns a scope for "root" backed by newTestDrive
+func newTestScope(t *testing.T, failing ...string) (*changeScope, map[string]int) {
+	t.Helper()
+
+	srv, calls := newTestDrive(t, nil, failing...)
+	return newChangeScope(&googledrive.DriveClient{}, srv, "root"), calls
+}
+
+func TestChangeScopeLookupWithin(t *testing.T) {
+	tests := []struct {
+		name         string
+		folderID     string
+		failing      []string
+		wantWithin   bool
+		wantResolved bool
+	}{
+		{name: "root folder", folderID: "root", wantWithin: true, wantResolved: true},
+		{name: "direct child", folderID: "a", wantWithin: true, wantResolved: true},
+		{name: "nested descendant", folderID: "b", wantWithin: true, wantResolved: true},
+		{name: "sibling tree", folderID: "c", wantWithin: false, wantResolved: true},
+		{name: "top of the Drive", folderID: "top", wantWithin: false, wantResolved: true},
+		{name: "empty ID", folderID: "", wantWithin: false, wantResolved: true},
+		{name: "failed lookup", folderID: "b", failing: []string{"a"}, wantWithin: false, wantResolved: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope, _ := newTestScope(t, tt.failing...)
+			within, resolved := scope.lookupWithin(context.Background(), tt.folderID)
+			if within != tt.wantWithin || resolved != tt.wantResolved {
+				t.Errorf("lookupWithin(%q) = (%v, %v), want (%v, %v)",
+					tt.folderID, within, resolved, tt.wantWithin, tt.wantResolved)
+			}
+		})
+	}
+}
+
+func TestChangeScopeCachesWalkedAncestors(t *testing.T) {
+	scope, calls := newTestScope(t)
+	ctx := context.Background()
+
+	if !scope.isWithin(ctx, "b") {
+		t.Fatal("b should be within root")
+	}
+	// b's walk passed a, so a is answered from the cache
+	if !scope.isWithin(ctx, "a") {
+		t.Fatal("a should be within root")
+	}
+	if !scope.isWithin(ctx, "b") {
+		t.Fatal("b should still be within root")
+	}
+
+	if calls["a"] != 1 || calls["b"] != 1 {
+		t.Errorf("Files.Get calls = %v, want one each for a and b", calls)
+	}
+	if scope.parentLookups != 2 || scope.cachedScopeHits != 2 {
+		t.Errorf("parentLookups = %d, cachedScopeHits = %d, want 2 and 2", scope.parentLookups, scope.cachedScopeHits)
+	}
+}
+
+func TestChangeScopeDoesNotCacheFailures(t *testing.T) {
+	scope, calls := newTestScope(t, "c")
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if within, resolved := scope.lookupWithin(ctx, "c"); within || resolved {
+			t.Fatalf("lookup %d = (%v, %v), want (false, false)", i, within, resolved)
+		}
+	}
+	if calls["c"] != 2 {
+		t.Errorf("Files.Get calls for c = %d, want 2 (failure must not be cached)", calls["c"])
+	}
+}
+
+func TestChangeScopePathFromNodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodes    []string // Folder IDs already walked
+		folderID string
+		wantPath string
+		wantOK   bool
+	}{
+		{name: "root itself", nodes: []string{"root"}, folderID: "root", wantPath: "Events", wantOK: true},
+		{name: "nested folder", nodes: []string{"root", "a", "b"}, folderID: "b", wantPath: "Events/2024/Day 1", wantOK: true},
+		{name: "root not walked", nodes: []string{"a", "b"}, folderID: "b", wantOK: false},
+		{name: "gap in the chain", nodes: []string{"root", "b"}, folderID: "b", wantOK: false},
+		{name: "outside the root", nodes: []string{"root", "top", "other", "c"}, folderID: "c", wantOK: false},
+		{name: "unknown folder", nodes: []string{"root"}, folderID: "x", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := newChangeScope(nil, nil, "root")
+			for _, id := range tt.nodes {
+				scope.nodes[id] = testDriveFolders[id]
+			}
+			path, ok := scope.pathFromNodes(tt.folderID)
+			if path != tt.wantPath || ok != tt.wantOK {
+				t.Errorf("pathFromNodes(%q) = (%q, %v), want (%q, %v)", tt.folderID, path, ok, tt.wantPath, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestChangeScopeFolderPathFromWalk(t *testing.T) {
+	scope, calls := newTestScope(t)
+	ctx := context.Background()
+
+	scope.isWithin(ctx, "b")
+	if got := scope.folderPath(ctx, "b"); got != "Events/2024/Day 1" {
+		t.Errorf("folderPath(b) = %q, want %q", got, "Events/2024/Day 1")
+	}
+	// Built from the walk plus one fetch of the root's name, no GetFolderPath walk
+	if scope.folderPathLookups != 0 || scope.cachedPathBuilds != 1 || calls["root"] != 1 {
+		t.Errorf("folderPathLookups = %d, cachedPathBuilds = %d, root fetches = %d, want 0, 1, 1",
+			scope.folderPathLookups, scope.cachedPathBuilds, calls["root"])
+	}
+}
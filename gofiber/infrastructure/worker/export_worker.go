@@ -0,0 +1,419 @@
+package worker
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/api/drive/v3"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+	"gofiber-template/infrastructure/googledrive"
+	"gofiber-template/infrastructure/storage"
+	"gofiber-template/infrastructure/websocket"
+	"gofiber-template/pkg/logger"
+)
+
+// ExportWorker builds folder zip archives in the background and uploads them to storage
+type ExportWorker struct {
+	driveClient      *googledrive.DriveClient
+	storage          storage.BunnyStorage
+	sharedFolderRepo repositories.SharedFolderRepository
+	photoRepo        repositories.PhotoRepository
+	syncJobRepo      repositories.SyncJobRepository
+
+	// Worker control
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	isRunning bool
+	mu        sync.Mutex
+	triggerCh chan struct{}
+
+	// Configuration
+	maxConcurrent  int
+	pollInterval   time.Duration // Safety-net poll for jobs not triggered here (other instances, restarts)
+	pageSize       int           // Photos loaded per DB page
+	broadcastEvery int           // Broadcast progress every N files
+}
+
+// ExportJobMetadata contains metadata for zip export jobs
+type ExportJobMetadata struct {
+	SharedFolderID uuid.UUID `json:"shared_folder_id"`
+	FolderPath     string    `json:"folder_path,omitempty"`  // Optional sub-folder filter
+	StoragePath    string    `json:"storage_path,omitempty"` // Path of the archive in storage
+	DownloadURL    string    `json:"download_url,omitempty"` // CDN URL of the finished archive
+	FileSize       int64     `json:"file_size,omitempty"`
+}
+
+// NewExportWorker creates a new export worker
+func NewExportWorker(
+	driveClient *googledrive.DriveClient,
+	storage storage.BunnyStorage,
+	sharedFolderRepo repositories.SharedFolderRepository,
+	photoRepo repositories.PhotoRepository,
+	syncJobRepo repositories.SyncJobRepository,
+) *ExportWorker {
+	return &ExportWorker{
+		driveClient:      driveClient,
+		storage:          storage,
+		sharedFolderRepo: sharedFolderRepo,
+		photoRepo:        photoRepo,
+		syncJobRepo:      syncJobRepo,
+		triggerCh:        make(chan struct{}, 10),
+		maxConcurrent:    1,
+		pollInterval:     60 * time.Second,
+		pageSize:         200,
+		broadcastEvery:   10,
+	}
+}
+
+// TriggerExport triggers immediate processing of pending export jobs
+func (w *ExportWorker) TriggerExport() {
+	select {
+	case w.triggerCh <- struct{}{}:
+	default:
+		// Channel full, already triggered
+	}
+}
+
+// SetPollInterval sets the safety-net poll interval (must be called before Start)
+func (w *ExportWorker) SetPollInterval(interval time.Duration) {
+	w.pollInterval = clampPollInterval("export_worker", interval)
+}
+
+// Start starts the export worker
+func (w *ExportWorker) Start() {
+	w.mu.Lock()
+	if w.isRunning {
+		w.mu.Unlock()
+		return
+	}
+	w.isRunning = true
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.mu.Unlock()
+
+	w.wg.Add(1)
+	go w.run()
+
+	logger.Sync("export_worker_started", "Export worker started", nil)
+}
+
+// Stop stops the export worker gracefully
+func (w *ExportWorker) Stop() {
+	w.mu.Lock()
+	if !w.isRunning {
+		w.mu.Unlock()
+		return
+	}
+	w.isRunning = false
+	w.mu.Unlock()
+
+	w.cancel()
+	w.wg.Wait()
+	logger.Sync("export_worker_stopped", "Export worker stopped", nil)
+}
+
+// IsRunning returns whether the worker is running
+func (w *ExportWorker) IsRunning() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.isRunning
+}
+
+// run is the main worker loop
+func (w *ExportWorker) run() {
+	defer w.wg.Done()
+
+	// Safety net for jobs queued by another instance or whose trigger was missed
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	// Process any pending jobs on start
+	w.processPendingJobs()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-w.triggerCh:
+			w.processPendingJobs()
+		case <-ticker.C:
+			w.processPendingJobs()
+		}
+	}
+}
+
+// processPendingJobs claims and processes pending export jobs
+func (w *ExportWorker) processPendingJobs() {
+	jobs, err := w.syncJobRepo.ClaimPendingJobs(w.ctx, models.SyncJobTypeZipExport, w.maxConcurrent)
+	if err != nil {
+		logger.SyncError("fetch_pending_exports_failed", "Error fetching pending export jobs", err, nil)
+		return
+	}
+
+	for _, job := range jobs {
+		w.processJob(job)
+	}
+}
+
+// processJob builds the zip archive for a single export job
+func (w *ExportWorker) processJob(job models.SyncJob) {
+	ctx := w.ctx
+	jobID := job.ID
+
+	var metadata ExportJobMetadata
+	if job.Metadata != "" {
+		json.Unmarshal([]byte(job.Metadata), &metadata)
+	}
+
+	if metadata.SharedFolderID == uuid.Nil {
+		w.failJob(job, "Missing shared_folder_id in job metadata")
+		return
+	}
+
+	logger.Sync("export_started", "Export job started", map[string]interface{}{
+		"job_id":    jobID.String(),
+		"folder_id": metadata.SharedFolderID.String(),
+	})
+
+	folder, err := w.sharedFolderRepo.GetByID(ctx, metadata.SharedFolderID)
+	if err != nil {
+		w.failJob(job, fmt.Sprintf("Folder not found: %v", err))
+		return
+	}
+	ctx = googledrive.WithAccount(ctx, folder.TokenOwnerID) // Shared per-account Drive rate limit
+
+	expiry := time.Now()
+	if folder.DriveTokenExpiry != nil {
+		expiry = *folder.DriveTokenExpiry
+	}
+	srv, err := w.driveClient.GetDriveServiceWithResourceKey(ctx, folder.DriveAccessToken, folder.DriveRefreshToken, expiry, folder.DriveFolderID, folder.DriveResourceKey)
+	if err != nil {
+		w.failJob(job, fmt.Sprintf("Failed to get drive service: %v", err))
+		return
+	}
+
+	// Count photos for progress
+	var total int64
+	if metadata.FolderPath != "" {
+		_, total, err = w.photoRepo.GetBySharedFolderAndPath(ctx, folder.ID, metadata.FolderPath, "", 0, 1)
+	} else {
+		total, err = w.photoRepo.CountBySharedFolder(ctx, folder.ID)
+	}
+	if err != nil {
+		w.failJob(job, fmt.Sprintf("Failed to count photos: %v", err))
+		return
+	}
+
+	w.syncJobRepo.Update(ctx, jobID, &models.SyncJob{TotalItems: int(total), UpdatedAt: time.Now()})
+
+	// Build zip on disk (not in memory) - folders can be very large
+	tmpFile, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		w.failJob(job, fmt.Sprintf("Failed to create temp file: %v", err))
+		return
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	zipWriter := zip.NewWriter(tmpFile)
+	filenameCount := make(map[string]int)
+	processed, failed := 0, 0
+
+	for offset := 0; ; offset += w.pageSize {
+		var photos []models.Photo
+		if metadata.FolderPath != "" {
+			photos, _, err = w.photoRepo.GetBySharedFolderAndPath(ctx, folder.ID, metadata.FolderPath, "", offset, w.pageSize)
+		} else {
+			photos, _, err = w.photoRepo.GetBySharedFolder(ctx, folder.ID, "", offset, w.pageSize)
+		}
+		if err != nil {
+			w.failJob(job, fmt.Sprintf("Failed to load photos: %v", err))
+			return
+		}
+
+		for _, photo := range photos {
+			if ctx.Err() != nil {
+				w.requeueJob(job) // Worker stopping - the export starts over on the next claim
+				return
+			}
+
+			if err := w.addPhotoToZip(ctx, srv, zipWriter, photo, filenameCount); err != nil {
+				logger.SyncError("export_add_file_failed", "Failed to add file to export", err, map[string]interface{}{
+					"job_id":        jobID.String(),
+					"drive_file_id": photo.DriveFileID,
+				})
+				failed++
+			}
+			processed++
+
+			if processed%w.broadcastEvery == 0 {
+				w.syncJobRepo.UpdateProgress(ctx, jobID, processed, failed)
+				websocket.Manager.BroadcastToUser(job.UserID, "export:progress", map[string]interface{}{
+					"jobId":    jobID.String(),
+					"folderId": folder.ID.String(),
+					"current":  processed,
+					"total":    total,
+				})
+			}
+		}
+
+		if len(photos) < w.pageSize {
+			break
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		w.failJob(job, fmt.Sprintf("Failed to close zip: %v", err))
+		return
+	}
+
+	info, err := tmpFile.Stat()
+	if err != nil {
+		w.failJob(job, fmt.Sprintf("Failed to stat zip: %v", err))
+		return
+	}
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		w.failJob(job, fmt.Sprintf("Failed to rewind zip: %v", err))
+		return
+	}
+
+	// Upload to object storage (supports resumable range downloads)
+	storagePath := fmt.Sprintf("exports/%s/%s_%s.zip", folder.ID.String(), sanitizeZipName(folder.DriveFolderName), jobID.String()[:8])
+	downloadURL, err := w.storage.UploadFile(tmpFile, storagePath, "application/zip")
+	if err != nil {
+		w.failJob(job, fmt.Sprintf("Failed to upload zip: %v", err))
+		return
+	}
+
+	metadata.StoragePath = storagePath
+	metadata.DownloadURL = downloadURL
+	metadata.FileSize = info.Size()
+	metadataJSON, _ := json.Marshal(metadata)
+
+	now := time.Now()
+	w.syncJobRepo.Update(ctx, jobID, &models.SyncJob{
+		Status:         models.SyncJobStatusCompleted,
+		ProcessedItems: processed,
+		FailedItems:    failed,
+		Metadata:       string(metadataJSON),
+		CompletedAt:    &now,
+		UpdatedAt:      now,
+	})
+
+	websocket.Manager.BroadcastToUser(job.UserID, "export:completed", map[string]interface{}{
+		"jobId":       jobID.String(),
+		"folderId":    folder.ID.String(),
+		"downloadUrl": downloadURL,
+		"fileSize":    info.Size(),
+		"total":       processed,
+		"failed":      failed,
+	})
+
+	logger.Sync("export_completed", "Export job completed", map[string]interface{}{
+		"job_id":    jobID.String(),
+		"folder_id": folder.ID.String(),
+		"processed": processed,
+		"failed":    failed,
+		"file_size": info.Size(),
+	})
+}
+
+// addPhotoToZip downloads a photo from Drive and writes it into the zip
+func (w *ExportWorker) addPhotoToZip(ctx context.Context, srv *drive.Service, zipWriter *zip.Writer, photo models.Photo, filenameCount map[string]int) error {
+	body, err := w.driveClient.DownloadFile(ctx, srv, photo.DriveFileID)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	// Keep sub-folder structure and handle duplicate filenames
+	original := photo.FileName
+	if photo.DriveFolderPath != "" {
+		original = photo.DriveFolderPath + "/" + photo.FileName
+	}
+	filename := original
+	if count, exists := filenameCount[original]; exists {
+		ext := filepath.Ext(original)
+		base := original[:len(original)-len(ext)]
+		filename = fmt.Sprintf("%s_%d%s", base, count+1, ext)
+	}
+	filenameCount[original]++
+
+	entry, err := zipWriter.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(entry, body)
+	return err
+}
+
+// requeueJob puts a job interrupted by shutdown back to pending so it is claimed again. Claims
+// only pick up pending jobs, so one left running would never finish
+func (w *ExportWorker) requeueJob(job models.SyncJob) {
+	// The worker context is already cancelled
+	if err := w.syncJobRepo.UpdateStatus(context.Background(), job.ID, models.SyncJobStatusPending); err != nil {
+		logger.SyncError("export_requeue_failed", "Failed to requeue interrupted export job", err, map[string]interface{}{
+			"job_id": job.ID.String(),
+		})
+		return
+	}
+	logger.Sync("export_requeued", "Export job interrupted by shutdown, requeued", map[string]interface{}{
+		"job_id": job.ID.String(),
+	})
+}
+
+// failJob marks an export job as failed and notifies the requesting user. Errors caused by the
+// worker stopping requeue the job instead
+func (w *ExportWorker) failJob(job models.SyncJob, errMsg string) {
+	if w.ctx.Err() != nil {
+		w.requeueJob(job)
+		return
+	}
+
+	logger.SyncError("export_failed", "Export job failed", nil, map[string]interface{}{
+		"job_id": job.ID.String(),
+		"error":  errMsg,
+	})
+
+	// Detached: the failure must be recorded even if the job's context is done
+	now := time.Now()
+	w.syncJobRepo.Update(context.Background(), job.ID, &models.SyncJob{
+		Status:      models.SyncJobStatusFailed,
+		LastError:   errMsg,
+		CompletedAt: &now,
+		UpdatedAt:   now,
+	})
+
+	websocket.Manager.BroadcastToUser(job.UserID, "export:failed", map[string]interface{}{
+		"jobId":   job.ID.String(),
+		"message": errMsg,
+	})
+}
+
+// sanitizeZipName makes a folder name safe for use in a storage path
+func sanitizeZipName(name string) string {
+	safe := make([]rune, 0, len(name))
+	for _, r := range name {
+		switch r {
+		case '/', '\\', '?', '%', '*', ':', '|', '"', '<', '>', '#', ' ':
+			safe = append(safe, '_')
+		default:
+			safe = append(safe, r)
+		}
+	}
+	if len(safe) == 0 {
+		return "export"
+	}
+	return string(safe)
+}
@@ -2,6 +2,8 @@ package worker
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"sync"
@@ -15,8 +17,10 @@ import (
 	"gofiber-template/domain/repositories"
 	"gofiber-template/infrastructure/faceapi"
 	"gofiber-template/infrastructure/googledrive"
+	"gofiber-template/infrastructure/storage"
 	"gofiber-template/infrastructure/websocket"
 	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/utils"
 )
 
 // FaceWorker processes photos for face detection
@@ -27,6 +31,7 @@ type FaceWorker struct {
 	photoRepo        repositories.PhotoRepository
 	faceRepo         repositories.FaceRepository
 	sharedFolderRepo repositories.SharedFolderRepository
+	uploadStorage    storage.BunnyStorage // Source of uploaded (non-Drive) photos, nil if not set
 
 	// Worker control
 	ctx       context.Context
@@ -36,6 +41,8 @@ type FaceWorker struct {
 	mu        sync.Mutex
 
 	// Configuration
+	// One batch of batchSize photos is fetched per pollInterval tick (ticks are dropped
+	// while a batch is still running), so throughput is at most batchSize per interval.
 	pollInterval  time.Duration
 	maxConcurrent int
 	batchSize     int
@@ -44,6 +51,22 @@ type FaceWorker struct {
 	maxRetries     int
 	baseRetryDelay time.Duration
 
+	// Faceless photos re-queued under an unchanged model are completed without a Face API call
+	skipUnchangedFaceless bool
+	modelVersion          string // Face model reported by the last health check (guarded by mu)
+
+	// Detected faces below these are not stored (see SetFaceQualityFilter)
+	minFaceConfidence float64
+	minFaceArea       float64
+
+	// Pause: batches are skipped while an admin pause is active (it ends on its own at pausedUntil)
+	// or while the drive sync backlog reaches pauseSyncBacklog, so large imports get the database first
+	pausedUntil      time.Time // Zero when not paused by an admin (guarded by mu)
+	pauseReason      string    // guarded by mu
+	syncJobRepo      repositories.SyncJobRepository
+	pauseSyncBacklog int64 // 0 = no automatic pause
+	autoPaused       bool  // The last poll was skipped for the sync backlog (guarded by mu)
+
 	// Circuit breaker
 	circuitBreaker *CircuitBreaker
 }
@@ -99,6 +122,13 @@ func (cb *CircuitBreaker) GetFailures() int32 {
 	return atomic.LoadInt32(&cb.failures)
 }
 
+// FaceAPIRetry controls how the worker retries Face API failures and when it stops calling the API
+type FaceAPIRetry struct {
+	MaxRetries       int           // Retries per photo after the first attempt (backoff doubles from 2s)
+	BreakerThreshold int           // Consecutive failed photos that open the circuit breaker (<= 0 uses 10)
+	BreakerReset     time.Duration // How long the breaker stays open before letting a photo through (<= 0 uses 60s)
+}
+
 // NewFaceWorker creates a new face processing worker
 func NewFaceWorker(
 	faceClient *faceapi.FaceClient,
@@ -107,7 +137,18 @@ func NewFaceWorker(
 	photoRepo repositories.PhotoRepository,
 	faceRepo repositories.FaceRepository,
 	sharedFolderRepo repositories.SharedFolderRepository,
+	retry FaceAPIRetry,
 ) *FaceWorker {
+	if retry.MaxRetries < 0 {
+		retry.MaxRetries = 0
+	}
+	if retry.BreakerThreshold <= 0 {
+		retry.BreakerThreshold = 10
+	}
+	if retry.BreakerReset <= 0 {
+		retry.BreakerReset = 60 * time.Second
+	}
+
 	return &FaceWorker{
 		faceClient:       faceClient,
 		driveClient:      driveClient,
@@ -118,10 +159,138 @@ func NewFaceWorker(
 		pollInterval:     10 * time.Second,  // Reduced from 15s for faster processing
 		maxConcurrent:    3,                 // Reduced for CPU-based Face API (prevent overload)
 		batchSize:        20,                // Reduced batch size for stability
-		maxRetries:       3,                 // Retry failed operations
+		maxRetries:       retry.MaxRetries,
 		baseRetryDelay:   2 * time.Second,   // Base delay for exponential backoff
-		circuitBreaker:   NewCircuitBreaker(10, 60*time.Second), // Open after 10 failures, reset after 60s
+		circuitBreaker:   NewCircuitBreaker(int32(retry.BreakerThreshold), retry.BreakerReset),
+	}
+}
+
+// SetSkipUnchangedFaceless enables skipping faceless photos already checked by the current model (must be called before Start)
+func (w *FaceWorker) SetSkipUnchangedFaceless(skip bool) {
+	w.skipUnchangedFaceless = skip
+}
+
+// SetFaceQualityFilter drops detected faces below minConfidence (0-1) or whose bounding box covers
+// less than minArea of the image (0-1, 0 = no size limit) before they are stored (must be called before Start)
+func (w *FaceWorker) SetFaceQualityFilter(minConfidence, minArea float64) {
+	w.minFaceConfidence = minConfidence
+	w.minFaceArea = minArea
+}
+
+// passesQualityFilter reports whether a detected face is worth storing (bounding boxes are normalized 0-1)
+func (w *FaceWorker) passesQualityFilter(face faceapi.DetectedFace) bool {
+	if face.Confidence < w.minFaceConfidence {
+		return false
+	}
+	return w.minFaceArea <= 0 || face.BboxWidth*face.BboxHeight >= w.minFaceArea
+}
+
+// currentModelVersion returns the face model of the last health check
+func (w *FaceWorker) currentModelVersion() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.modelVersion
+}
+
+// SetSyncBacklogPause pauses face processing while at least threshold drive sync jobs are
+// pending or running (0 = off; must be called before Start)
+func (w *FaceWorker) SetSyncBacklogPause(syncJobRepo repositories.SyncJobRepository, threshold int) {
+	w.syncJobRepo = syncJobRepo
+	w.pauseSyncBacklog = int64(max(threshold, 0))
+}
+
+// Pause stops picking up new batches for d; a batch already running is finished.
+// Pausing again replaces the previous expiry. Returns when processing resumes.
+func (w *FaceWorker) Pause(d time.Duration, reason string) time.Time {
+	w.mu.Lock()
+	w.pausedUntil = time.Now().Add(d)
+	w.pauseReason = reason
+	until := w.pausedUntil
+	w.mu.Unlock()
+
+	logger.Face("worker_paused", "Face worker paused", map[string]interface{}{
+		"paused_until": until.Format(time.RFC3339),
+		"reason":       reason,
+	})
+	return until
+}
+
+// Resume ends an admin pause early (the sync backlog pause still applies)
+func (w *FaceWorker) Resume() {
+	w.mu.Lock()
+	wasPaused := !w.pausedUntil.IsZero()
+	w.pausedUntil = time.Time{}
+	w.pauseReason = ""
+	w.mu.Unlock()
+
+	if wasPaused {
+		logger.Face("worker_resumed", "Face worker resumed", map[string]interface{}{
+			"reason": "admin",
+		})
+	}
+}
+
+// isPaused reports whether the next batch should be skipped, clearing an expired admin pause
+func (w *FaceWorker) isPaused() bool {
+	w.mu.Lock()
+	if !w.pausedUntil.IsZero() {
+		if time.Now().Before(w.pausedUntil) {
+			w.mu.Unlock()
+			return true
+		}
+		w.pausedUntil = time.Time{}
+		w.pauseReason = ""
+		w.mu.Unlock()
+		logger.Face("worker_resumed", "Face worker resumed", map[string]interface{}{
+			"reason": "expired",
+		})
+	} else {
+		w.mu.Unlock()
+	}
+
+	if w.syncJobRepo == nil || w.pauseSyncBacklog <= 0 {
+		return false
+	}
+	backlog, err := w.syncJobRepo.CountActive(w.ctx, models.SyncJobTypeDriveSync)
+	if err != nil {
+		// Never block face processing on a failed count
+		logger.FaceError("sync_backlog_check_failed", "Failed to count active sync jobs", err, nil)
+		return false
+	}
+
+	paused := backlog >= w.pauseSyncBacklog
+	w.mu.Lock()
+	changed := paused != w.autoPaused
+	w.autoPaused = paused
+	w.mu.Unlock()
+	if changed && paused {
+		logger.Face("worker_paused", "Face worker paused for sync backlog", map[string]interface{}{
+			"reason":       "sync_backlog",
+			"sync_backlog": backlog,
+			"threshold":    w.pauseSyncBacklog,
+		})
+	} else if changed {
+		logger.Face("worker_resumed", "Face worker resumed", map[string]interface{}{
+			"reason":       "sync_backlog_cleared",
+			"sync_backlog": backlog,
+		})
+	}
+	return paused
+}
+
+// PauseState returns the admin pause (zero until when not paused) and whether the sync backlog pause is active
+func (w *FaceWorker) PauseState() (until time.Time, reason string, autoPaused bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.pausedUntil.IsZero() && !time.Now().Before(w.pausedUntil) {
+		return time.Time{}, "", w.autoPaused
 	}
+	return w.pausedUntil, w.pauseReason, w.autoPaused
+}
+
+// SetPollInterval sets how often pending photos are polled (must be called before Start)
+func (w *FaceWorker) SetPollInterval(interval time.Duration) {
+	w.pollInterval = clampPollInterval("face_worker", interval)
 }
 
 // Start starts the face worker
@@ -190,6 +359,10 @@ func (w *FaceWorker) run() {
 
 // processPendingPhotos fetches and processes photos with pending face status
 func (w *FaceWorker) processPendingPhotos() {
+	if w.isPaused() {
+		return
+	}
+
 	// Check circuit breaker
 	if w.circuitBreaker.IsOpen() {
 		logger.Face("circuit_breaker_open", "Circuit breaker open, skipping face processing", map[string]interface{}{
@@ -198,15 +371,24 @@ func (w *FaceWorker) processPendingPhotos() {
 		return
 	}
 
-	// Check if face API is available
-	if !w.faceClient.IsAvailable(w.ctx) {
+	// Check if face API is available (the health response also names the model in use)
+	health, err := w.faceClient.Health(w.ctx)
+	if err != nil || health.Status != "ok" {
 		w.circuitBreaker.RecordFailure()
 		logger.Face("face_api_unavailable_trigger", "Face API not available, circuit breaker triggered", nil)
 		return
 	}
+	if modelVersion := health.ModelVersion(); modelVersion != w.currentModelVersion() {
+		w.mu.Lock()
+		w.modelVersion = modelVersion
+		w.mu.Unlock()
+		logger.Face("face_model_version", "Face API model version", map[string]interface{}{
+			"model_version": modelVersion,
+		})
+	}
 
 	// Get photos with pending face status
-	photos, err := w.photoRepo.GetByFaceStatus(w.ctx, models.FaceStatusPending, w.batchSize)
+	photos, err := w.photoRepo.GetPendingForFaceWorker(w.ctx, w.batchSize)
 	if err != nil {
 		logger.FaceError("fetch_pending_photos_failed", "Error fetching pending photos", err, nil)
 		return
@@ -335,6 +517,12 @@ func (w *FaceWorker) processPhoto(photo models.Photo) error {
 	ctx := w.ctx
 	photoID := photo.ID
 
+	// Videos are never face processed (e.g. queued by a retry or reprocess)
+	if photo.MediaType == models.MediaTypeVideo {
+		w.photoRepo.UpdateFaceStatus(ctx, photoID, models.FaceStatusSkipped, 0)
+		return nil
+	}
+
 	// Check for existing faces (prevent duplicates on restart)
 	existingFaces, _ := w.faceRepo.GetByPhoto(ctx, photoID)
 	if len(existingFaces) > 0 {
@@ -343,27 +531,45 @@ func (w *FaceWorker) processPhoto(photo models.Photo) error {
 		return nil
 	}
 
+	// Already found faceless by this exact model: another extraction would give the same answer
+	modelVersion := w.currentModelVersion()
+	if w.skipUnchangedFaceless && modelVersion != "" && photo.LastFaceCheckModelVersion == modelVersion {
+		w.photoRepo.UpdateFaceStatus(ctx, photoID, models.FaceStatusCompleted, 0)
+		w.broadcastToFolderUsers(ctx, photo.SharedFolderID, "photo:updated", map[string]interface{}{
+			"photoId":    photoID.String(),
+			"faceStatus": models.FaceStatusCompleted,
+			"faceCount":  0,
+		})
+		logger.Face("faceless_photo_skipped", "Skipped faceless photo already checked by the current model", map[string]interface{}{
+			"photo_id":      photoID.String(),
+			"model_version": modelVersion,
+		})
+		return nil
+	}
+
 	// Update status to processing
 	if err := w.photoRepo.UpdateFaceStatus(ctx, photoID, models.FaceStatusProcessing, 0); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
 	}
 
-	// Get SharedFolder for Drive credentials
-	folder, err := w.sharedFolderRepo.GetByID(ctx, photo.SharedFolderID)
+	// Download image from wherever the photo lives (Drive or upload storage)
+	imageData, mimeType, err := w.downloadImage(ctx, photo)
 	if err != nil {
-		return fmt.Errorf("failed to get shared folder: %w", err)
+		return fmt.Errorf("failed to download image: %w", err)
 	}
 
-	// Get token owner for Drive access
-	user, err := w.userRepo.GetByID(ctx, folder.TokenOwnerID)
-	if err != nil {
-		return fmt.Errorf("failed to get token owner: %w", err)
+	// Uploaded photos are downloaded in full, so their EXIF is still there (Drive photos get
+	// capture metadata from Drive during sync instead: the worker only fetches their thumbnail)
+	if photo.Source == models.PhotoSourceUpload && photo.TakenAt == nil && photo.CameraModel == "" {
+		if exif, ok := utils.ParseExif(imageData); ok {
+			w.photoRepo.UpdateCaptureMetadata(ctx, photoID, exif.TakenAt, exif.CameraMake, exif.CameraModel, exif.Width, exif.Height)
+		}
 	}
 
-	// Download image from Google Drive (with authentication)
-	imageData, mimeType, err := w.downloadImageWithRetry(ctx, user, photo)
-	if err != nil {
-		return fmt.Errorf("failed to download image: %w", err)
+	// Hash the bytes we already have for duplicate detection; rows without a hash fill in as they are processed
+	sum := sha256.Sum256(imageData)
+	if contentHash := hex.EncodeToString(sum[:]); contentHash != photo.ContentHash {
+		w.photoRepo.UpdateMetadata(ctx, photoID, map[string]interface{}{"content_hash": contentHash})
 	}
 
 	// Call face API with image bytes (not URL)
@@ -372,22 +578,37 @@ func (w *FaceWorker) processPhoto(photo models.Photo) error {
 		return fmt.Errorf("face extraction failed: %w", err)
 	}
 
+	// Remember which model checked the photo (only successful extractions count)
+	if modelVersion != "" {
+		w.photoRepo.UpdateMetadata(ctx, photoID, map[string]interface{}{"last_face_check_model_version": modelVersion})
+	}
+
+	// Tiny or uncertain detections (blurry background faces) only pollute search
+	kept := make([]faceapi.DetectedFace, 0, len(result.Faces))
+	for _, detectedFace := range result.Faces {
+		if w.passesQualityFilter(detectedFace) {
+			kept = append(kept, detectedFace)
+		}
+	}
+	dropped := len(result.Faces) - len(kept)
+
 	// Process detected faces
-	if len(result.Faces) == 0 {
-		// No faces detected - mark as completed
+	if len(kept) == 0 {
+		// No faces detected (or none good enough) - mark as completed
 		w.photoRepo.UpdateFaceStatus(ctx, photoID, models.FaceStatusCompleted, 0)
 		// Broadcast to all users with folder access
 		w.broadcastToFolderUsers(ctx, photo.SharedFolderID, "photo:updated", map[string]interface{}{
-			"photoId":    photoID.String(),
-			"faceStatus": models.FaceStatusCompleted,
-			"faceCount":  0,
+			"photoId":      photoID.String(),
+			"faceStatus":   models.FaceStatusCompleted,
+			"faceCount":    0,
+			"facesDropped": dropped,
 		})
 		return nil
 	}
 
 	// Save detected faces
-	faces := make([]*models.Face, 0, len(result.Faces))
-	for _, detectedFace := range result.Faces {
+	faces := make([]*models.Face, 0, len(kept))
+	for _, detectedFace := range kept {
 		// Convert float32 embedding to pgvector
 		embedding := make([]float32, len(detectedFace.Embedding))
 		copy(embedding, detectedFace.Embedding)
@@ -418,20 +639,63 @@ func (w *FaceWorker) processPhoto(photo models.Photo) error {
 
 	// Broadcast to all users with folder access
 	w.broadcastToFolderUsers(ctx, photo.SharedFolderID, "photo:updated", map[string]interface{}{
-		"photoId":    photoID.String(),
-		"faceStatus": models.FaceStatusCompleted,
-		"faceCount":  len(faces),
+		"photoId":      photoID.String(),
+		"faceStatus":   models.FaceStatusCompleted,
+		"faceCount":    len(faces),
+		"facesDropped": dropped,
 	})
 
 	logger.Face("photo_processed", "Photo processed successfully", map[string]interface{}{
-		"photo_id":   photoID.String(),
-		"face_count": len(faces),
+		"photo_id":      photoID.String(),
+		"face_count":    len(faces),
+		"faces_dropped": dropped,
 	})
 	return nil
 }
 
-// downloadImageWithRetry downloads image with retry logic
-func (w *FaceWorker) downloadImageWithRetry(ctx context.Context, user *models.User, photo models.Photo) ([]byte, string, error) {
+// SetUploadStorage sets the storage uploaded photos are read from (must be called before Start)
+func (w *FaceWorker) SetUploadStorage(uploadStorage storage.BunnyStorage) {
+	w.uploadStorage = uploadStorage
+}
+
+// downloadImage downloads a photo's image based on its source
+func (w *FaceWorker) downloadImage(ctx context.Context, photo models.Photo) ([]byte, string, error) {
+	switch photo.Source {
+	case models.PhotoSourceUpload:
+		return w.downloadUploadedImage(photo)
+	case models.PhotoSourceDrive, "":
+		// Get SharedFolder for Drive credentials
+		folder, err := w.sharedFolderRepo.GetByID(ctx, photo.SharedFolderID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get shared folder: %w", err)
+		}
+
+		// Get token owner for Drive access
+		user, err := w.userRepo.GetByID(ctx, folder.TokenOwnerID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get token owner: %w", err)
+		}
+
+		return w.downloadDriveImageWithRetry(googledrive.WithAccount(ctx, folder.TokenOwnerID), user, photo)
+	default:
+		return nil, "", fmt.Errorf("unsupported photo source: %s", photo.Source)
+	}
+}
+
+// downloadUploadedImage reads an uploaded photo from Bunny storage (the storage client retries on its own)
+func (w *FaceWorker) downloadUploadedImage(photo models.Photo) ([]byte, string, error) {
+	if w.uploadStorage == nil {
+		return nil, "", fmt.Errorf("upload storage not configured")
+	}
+	data, err := w.uploadStorage.DownloadFile(photo.StoragePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download uploaded file: %w", err)
+	}
+	return data, photo.MimeType, nil
+}
+
+// downloadDriveImageWithRetry downloads a Drive image with retry logic
+func (w *FaceWorker) downloadDriveImageWithRetry(ctx context.Context, user *models.User, photo models.Photo) ([]byte, string, error) {
 	var lastErr error
 
 	for attempt := 0; attempt <= 2; attempt++ {
@@ -439,7 +703,7 @@ func (w *FaceWorker) downloadImageWithRetry(ctx context.Context, user *models.Us
 			time.Sleep(time.Duration(attempt) * time.Second)
 		}
 
-		data, mimeType, err := w.downloadImage(ctx, user, photo)
+		data, mimeType, err := w.downloadDriveImage(ctx, user, photo)
 		if err == nil {
 			return data, mimeType, nil
 		}
@@ -449,8 +713,8 @@ func (w *FaceWorker) downloadImageWithRetry(ctx context.Context, user *models.Us
 	return nil, "", lastErr
 }
 
-// downloadImage downloads the image from Google Drive with authentication
-func (w *FaceWorker) downloadImage(ctx context.Context, user *models.User, photo models.Photo) ([]byte, string, error) {
+// downloadDriveImage downloads the image from Google Drive with authentication
+func (w *FaceWorker) downloadDriveImage(ctx context.Context, user *models.User, photo models.Photo) ([]byte, string, error) {
 	if user.DriveRefreshToken == "" {
 		return nil, "", fmt.Errorf("user has no Drive credentials")
 	}
@@ -527,11 +791,29 @@ func (w *FaceWorker) broadcastToFolderUsers(ctx context.Context, folderID uuid.U
 
 // GetStats returns worker statistics
 func (w *FaceWorker) GetStats() map[string]interface{} {
-	return map[string]interface{}{
+	stats := map[string]interface{}{
 		"isRunning":        w.IsRunning(),
 		"maxConcurrent":    w.maxConcurrent,
 		"batchSize":        w.batchSize,
 		"circuitBreaker":   !w.circuitBreaker.IsOpen(),
 		"circuitFailures":  w.circuitBreaker.GetFailures(),
+
+		"maxRetries":            w.maxRetries,
+		"faceApiTimeoutSeconds": int(w.faceClient.Timeout().Seconds()),
+		"breakerThreshold":      w.circuitBreaker.threshold,
+		"breakerResetSeconds":   int(w.circuitBreaker.resetTimeout.Seconds()),
+
+		"modelVersion":          w.currentModelVersion(),
+		"skipUnchangedFaceless": w.skipUnchangedFaceless,
+	}
+
+	until, reason, autoPaused := w.PauseState()
+	stats["paused"] = !until.IsZero() || autoPaused
+	stats["autoPaused"] = autoPaused
+	stats["pauseSyncBacklog"] = w.pauseSyncBacklog
+	if !until.IsZero() {
+		stats["pausedUntil"] = until
+		stats["pauseReason"] = reason
 	}
+	return stats
 }
@@ -0,0 +1,105 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+)
+
+// fakeSyncJobRepo keeps the state of the job being synced and the listing snapshot in memory,
+// and records the jobs created through CreatePendingForFolder
+type fakeSyncJobRepo struct {
+	repositories.SyncJobRepository
+	created  []*models.SyncJob
+	job      models.SyncJob // Non-zero fields of every update, merged like GORM's Updates
+	snapshot *models.SyncJobSnapshot
+}
+
+func (r *fakeSyncJobRepo) Update(ctx context.Context, id uuid.UUID, job *models.SyncJob) error {
+	if job.Status != "" {
+		r.job.Status = job.Status
+	}
+	if job.TotalItems != 0 {
+		r.job.TotalItems = job.TotalItems
+	}
+	if job.ProcessedItems != 0 {
+		r.job.ProcessedItems = job.ProcessedItems
+	}
+	if job.FailedItems != 0 {
+		r.job.FailedItems = job.FailedItems
+	}
+	if job.Metadata != "" {
+		r.job.Metadata = job.Metadata
+	}
+	return nil
+}
+
+func (r *fakeSyncJobRepo) UpdateProgress(ctx context.Context, id uuid.UUID, processed, failed int) error {
+	r.job.ProcessedItems = processed
+	r.job.FailedItems = failed
+	return nil
+}
+
+func (r *fakeSyncJobRepo) SaveSnapshot(ctx context.Context, snapshot *models.SyncJobSnapshot) error {
+	r.snapshot = snapshot
+	return nil
+}
+
+func (r *fakeSyncJobRepo) GetSnapshot(ctx context.Context, folderID uuid.UUID) (*models.SyncJobSnapshot, error) {
+	if r.snapshot == nil || r.snapshot.SharedFolderID != folderID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.snapshot, nil
+}
+
+func (r *fakeSyncJobRepo) DeleteSnapshot(ctx context.Context, folderID uuid.UUID) error {
+	r.snapshot = nil
+	return nil
+}
+
+func (r *fakeSyncJobRepo) CreatePendingForFolder(ctx context.Context, job *models.SyncJob) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	r.created = append(r.created, job)
+	return true, nil
+}
+
+func TestQueueFollowUpSync(t *testing.T) {
+	tests := []struct {
+		name        string
+		cancel      bool
+		wantCreated int
+	}{
+		{name: "job still running", cancel: false, wantCreated: 1},
+		{name: "job cancelled", cancel: true, wantCreated: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := &fakeSyncJobRepo{}
+			w := &SyncWorker{syncJobRepo: jobs, triggerCh: make(chan struct{}, 10)}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.cancel {
+				cancel()
+			}
+			defer cancel()
+
+			folder := &models.SharedFolder{ID: uuid.New()}
+			w.queueFollowUpSync(ctx, models.SyncJob{ID: uuid.New(), UserID: uuid.New()}, folder)
+
+			if len(jobs.created) != tt.wantCreated {
+				t.Fatalf("created %d follow-up jobs, want %d", len(jobs.created), tt.wantCreated)
+			}
+			if tt.wantCreated > 0 && *jobs.created[0].SharedFolderID != folder.ID {
+				t.Errorf("follow-up folder = %v, want %v", *jobs.created[0].SharedFolderID, folder.ID)
+			}
+		})
+	}
+}
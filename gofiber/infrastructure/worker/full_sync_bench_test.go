@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/infrastructure/googledrive"
+)
+
+// fullSyncBenchLatency is the simulated round trip of each photo query in the full sync benchmarks
+const fullSyncBenchLatency = time.Millisecond
+
+// benchPhotos returns the stored photos of a benchmark run: none if the files are new, else
+// one per file that is older than its Drive file, so the sync updates each of them
+func benchPhotos(folder *models.SharedFolder, files []googledrive.DriveFile, stored bool) map[uuid.UUID]*models.Photo {
+	photos := make(map[uuid.UUID]*models.Photo)
+	if !stored {
+		return photos
+	}
+	for _, f := range files {
+		id := uuid.New()
+		photos[id] = &models.Photo{
+			ID:               id,
+			SharedFolderID:   folder.ID,
+			DriveFileID:      f.ID,
+			DriveFolderID:    f.ParentID,
+			FileName:         f.Name,
+			ThumbnailPending: true,
+			UpdatedAt:        f.ModifiedTime.Add(-time.Hour),
+		}
+	}
+	return photos
+}
+
+// BenchmarkFullSyncFiles compares SYNC_FILE_CONCURRENCY settings on one chunk of a full sync,
+// listed from a fake Drive, with photo queries taking fullSyncBenchLatency:
+//
+//	go test ./infrastructure/worker -run '^$' -bench FullSyncFiles
+//
+// New files cost the chunk's one preload query; changed files are each written by their own
+// query, which is the work the goroutines overlap.
+func BenchmarkFullSyncFiles(b *testing.B) {
+	const chunkSize = 100 // Default SYNC_BATCH_SIZE
+
+	ctx := context.Background()
+	srv := newListingDrive(b, testDriveImages(chunkSize))
+	folder := &models.SharedFolder{ID: uuid.New(), DriveFolderID: "root", DriveFolderName: "Events"}
+	lister := &SyncWorker{driveClient: &googledrive.DriveClient{}, syncJobRepo: &fakeSyncJobRepo{}}
+	folderPathMap, files, err := lister.loadFullSyncListing(ctx, models.SyncJob{ID: uuid.New()}, folder, srv, false)
+	if err != nil {
+		b.Fatalf("list files: %v", err)
+	}
+
+	for _, kind := range []string{"new", "changed"} {
+		for _, concurrency := range []int{1, 4, 8, 16, MaxSyncFileConcurrency} {
+			b.Run(fmt.Sprintf("%s/concurrency=%d", kind, concurrency), func(b *testing.B) {
+				photos := &fakePhotoRepo{latency: fullSyncBenchLatency}
+				w := &SyncWorker{driveClient: lister.driveClient, photoRepo: photos, fileConcurrency: concurrency}
+
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					photos.photos = benchPhotos(folder, files, kind == "changed")
+					b.StartTimer()
+
+					w.processFullSyncFiles(ctx, folder, srv, folderPathMap, files)
+				}
+				b.ReportMetric(float64(len(files)*b.N)/b.Elapsed().Seconds(), "files/s")
+			})
+		}
+	}
+}
+
+// BenchmarkFullSync10k runs whole full syncs of a 10,000-file folder sequentially and with
+// parallel file checks, with the worker's default batch and checkpoint sizes and photo queries
+// taking fullSyncBenchLatency:
+//
+//	go test ./infrastructure/worker -run '^$' -bench FullSync10k -benchtime 3x
+//
+// "new" is a first sync of the folder, "changed" a resync where every file was modified.
+func BenchmarkFullSync10k(b *testing.B) {
+	const fileCount = 10000
+
+	files := testDriveImages(fileCount)
+	srv := newListingDrive(b, files)
+	folder := &models.SharedFolder{ID: uuid.New(), DriveFolderID: "root", DriveFolderName: "Events"}
+
+	for _, kind := range []string{"new", "changed"} {
+		for _, concurrency := range []int{1, 4, 16} {
+			mode := "sequential"
+			if concurrency > 1 {
+				mode = fmt.Sprintf("parallel=%d", concurrency)
+			}
+			b.Run(kind+"/"+mode, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					photos := &fakePhotoRepo{photos: benchPhotos(folder, files, kind == "changed"), latency: fullSyncBenchLatency}
+					jobs := &fakeSyncJobRepo{}
+					w := NewSyncWorker(&googledrive.DriveClient{}, &fakeSharedFolderRepo{}, photos, jobs, &fakeActivityLogRepo{}, nil)
+					w.fileConcurrency = concurrency
+					b.StartTimer()
+
+					w.processFullSync(context.Background(), models.SyncJob{ID: uuid.New()}, folder, srv)
+
+					b.StopTimer()
+					if jobs.job.Status != models.SyncJobStatusCompleted || len(photos.photos) != fileCount {
+						b.Fatalf("sync ended %q with %d photos, want completed with %d", jobs.job.Status, len(photos.photos), fileCount)
+					}
+					b.StartTimer()
+				}
+				b.ReportMetric(float64(fileCount*b.N)/b.Elapsed().Seconds(), "files/s")
+			})
+		}
+	}
+}
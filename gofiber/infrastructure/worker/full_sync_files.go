@@ -0,0 +1,173 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/api/drive/v3"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/infrastructure/googledrive"
+	"gofiber-template/pkg/logger"
+)
+
+// fullSyncFileResult is the outcome of checking one listed file during a full sync
+type fullSyncFileResult struct {
+	done     bool          // false if ctx was done before the file was handled
+	newPhoto *models.Photo // Not in the database yet: queued for the next batch insert
+	updated  int           // Existing photo updates (restore from trash and/or metadata change)
+}
+
+// processFullSyncFiles handles a chunk of listed files with up to fileConcurrency goroutines.
+// Results are returned in listing order; new photos are only built here, the caller inserts them.
+// Existing photos of the chunk are loaded with one query instead of one per file.
+func (w *SyncWorker) processFullSyncFiles(ctx context.Context, folder *models.SharedFolder, srv *drive.Service, folderPathMap map[string]string, files []googledrive.DriveFile) []fullSyncFileResult {
+	results := make([]fullSyncFileResult, len(files))
+
+	driveFileIDs := make([]string, len(files))
+	for i, file := range files {
+		driveFileIDs[i] = file.ID
+	}
+	existing, err := w.photoRepo.GetByDriveFileIDs(ctx, folder.ID, driveFileIDs)
+	if err != nil {
+		// Fall back to per-file lookups for this chunk
+		logger.SyncWarn("existing_photos_preload_failed", "Failed to load existing photos of chunk, looking up per file", map[string]interface{}{
+			"folder_id":  folder.ID.String(),
+			"file_count": len(files),
+			"error":      err.Error(),
+		})
+		existing = nil
+	}
+
+	workers := min(max(w.fileConcurrency, 1), len(files))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				// Each goroutine writes only its own slots, so results needs no lock
+				if ctx.Err() != nil {
+					continue
+				}
+				results[i] = w.processFullSyncFile(ctx, folder, srv, folderPathMap, existing, files[i])
+			}
+		}()
+	}
+
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// processFullSyncFile compares one listed file with its photo: restores and updates existing
+// photos in place, or builds the photo to insert for a new file. Files under exclude paths are skipped.
+// existing holds the chunk's preloaded photos; nil means look the photo up directly.
+func (w *SyncWorker) processFullSyncFile(ctx context.Context, folder *models.SharedFolder, srv *drive.Service, folderPathMap map[string]string, existing map[string]*models.Photo, file googledrive.DriveFile) fullSyncFileResult {
+	result := fullSyncFileResult{done: true}
+
+	// Get folder path from map (O(1)) or fallback to API
+	var folderPath string
+	if folderPathMap != nil {
+		folderPath = folderPathMap[file.ParentID]
+	} else {
+		folderPath, _ = w.driveClient.GetFolderPath(ctx, srv, file.ParentID, folder.DriveFolderID)
+	}
+
+	// Excluded paths are neither indexed nor updated (existing photos are left as they are)
+	if folder.IsPathExcluded(folderPath) {
+		return result
+	}
+
+	var existingPhoto *models.Photo
+	if existing != nil {
+		if preloaded, ok := existing[file.ID]; ok {
+			// A copy: a file listed under two parents may be handled by two goroutines at once
+			photo := *preloaded
+			existingPhoto = &photo
+		}
+	} else {
+		existingPhoto, _ = w.photoRepo.GetByDriveFileID(ctx, folder.ID, file.ID)
+	}
+	if existingPhoto == nil {
+		result.newPhoto = &models.Photo{
+			ID:              uuid.New(),
+			SharedFolderID:  folder.ID,
+			DriveFileID:     file.ID,
+			DriveFolderID:   file.ParentID,
+			DriveFolderPath: folderPath,
+			FileName:        file.Name,
+			MimeType:        file.MimeType,
+			MediaType:       models.MediaTypeFromMime(file.MimeType),
+			FileSize:        file.Size,
+			Width:           file.Width,
+			Height:          file.Height,
+			TakenAt:         file.TakenAt,
+			CameraMake:      file.CameraMake,
+			CameraModel:     file.CameraModel,
+			ThumbnailURL:    file.ThumbnailURL,
+			WebViewURL:      file.WebViewURL,
+			DriveCreatedAt:  &file.CreatedTime,
+			DriveModifiedAt: &file.ModifiedTime,
+			FaceStatus:      initialFaceStatus(folder, models.MediaTypeFromMime(file.MimeType)),
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+
+			ThumbnailPending: file.ThumbnailURL == "",
+		}
+		return result
+	}
+
+	// Unresolved path (lookup failed): keep the photo's known path instead of blanking it
+	if folderPath == "" {
+		folderPath = existingPhoto.DriveFolderPath
+	}
+
+	// Back in the listing: restore a photo trashed by orphan cleanup
+	if existingPhoto.IsTrashed {
+		if restored, err := w.photoRepo.RestoreByID(ctx, existingPhoto.ID); err == nil && restored {
+			result.updated++
+		}
+		// The struct update below would write the trash flag back
+		existingPhoto.IsTrashed = false
+		existingPhoto.TrashedAt = nil
+	}
+
+	// Photos synced before capture metadata was requested pick it up on the next full sync
+	missingCapture := existingPhoto.TakenAt == nil && file.TakenAt != nil
+
+	needsUpdate := file.ModifiedTime.After(existingPhoto.UpdatedAt) ||
+		existingPhoto.DriveFolderID != file.ParentID ||
+		existingPhoto.DriveFolderPath != folderPath ||
+		missingCapture
+
+	if needsUpdate {
+		if pending := file.ThumbnailURL == ""; pending != existingPhoto.ThumbnailPending {
+			// Struct updates skip false, so write the flag explicitly
+			w.photoRepo.UpdateMetadata(ctx, existingPhoto.ID, map[string]interface{}{"thumbnail_pending": pending})
+		}
+		existingPhoto.FileName = file.Name
+		existingPhoto.ThumbnailURL = file.ThumbnailURL
+		existingPhoto.WebViewURL = file.WebViewURL
+		existingPhoto.DriveFolderID = file.ParentID
+		existingPhoto.DriveFolderPath = folderPath
+		existingPhoto.DriveModifiedAt = &file.ModifiedTime
+		if file.TakenAt != nil {
+			existingPhoto.TakenAt = file.TakenAt
+			existingPhoto.CameraMake = file.CameraMake
+			existingPhoto.CameraModel = file.CameraModel
+		}
+		existingPhoto.UpdatedAt = time.Now()
+		w.photoRepo.Update(ctx, existingPhoto.ID, existingPhoto)
+		result.updated++
+	}
+
+	return result
+}
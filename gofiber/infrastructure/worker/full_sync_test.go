@@ -0,0 +1,211 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+	"gofiber-template/infrastructure/googledrive"
+)
+
+// fakeSharedFolderRepo accepts the status writes of a sync; the folder has no users to notify
+type fakeSharedFolderRepo struct {
+	repositories.SharedFolderRepository
+}
+
+func (r *fakeSharedFolderRepo) Update(ctx context.Context, id uuid.UUID, folder *models.SharedFolder) error {
+	return nil
+}
+
+func (r *fakeSharedFolderRepo) UpdateSyncStatus(ctx context.Context, id uuid.UUID, status models.SyncStatus, lastError string) error {
+	return nil
+}
+
+func (r *fakeSharedFolderRepo) GetUsersByFolder(ctx context.Context, folderID uuid.UUID) ([]models.User, error) {
+	return nil, nil
+}
+
+// testDriveImages returns n images directly in the synced folder "root"
+func testDriveImages(n int) []googledrive.DriveFile {
+	created := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	files := make([]googledrive.DriveFile, n)
+	for i := range files {
+		files[i] = googledrive.DriveFile{
+			ID:           fmt.Sprintf("img-%05d", i),
+			Name:         fmt.Sprintf("IMG_%05d.jpg", i),
+			MimeType:     "image/jpeg",
+			ParentID:     "root",
+			CreatedTime:  created,
+			ModifiedTime: created,
+		}
+	}
+	return files
+}
+
+// newListingDrive returns a Drive service backed by a fake Drive API whose synced folder "root"
+// ("Events") holds files and no subfolders, in a single listing page
+func newListingDrive(tb testing.TB, files []googledrive.DriveFile) *drive.Service {
+	tb.Helper()
+
+	listing := make([]map[string]interface{}, len(files))
+	for i, f := range files {
+		listing[i] = map[string]interface{}{
+			"id":           f.ID,
+			"name":         f.Name,
+			"mimeType":     f.MimeType,
+			"parents":      []string{f.ParentID},
+			"createdTime":  f.CreatedTime.Format(time.RFC3339),
+			"modifiedTime": f.ModifiedTime.Format(time.RFC3339),
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/changes/startPageToken"):
+			body = map[string]string{"startPageToken": "1"}
+		case strings.HasSuffix(r.URL.Path, "/files/root"):
+			body = map[string]string{"id": "root", "name": "Events"}
+		case strings.HasSuffix(r.URL.Path, "/files") && strings.Contains(r.URL.Query().Get("q"), "vnd.google-apps.folder"):
+			body = map[string]interface{}{"files": []interface{}{}}
+		case strings.HasSuffix(r.URL.Path, "/files"):
+			body = map[string]interface{}{"files": listing}
+		default:
+			http.Error(w, `{"error":{"code":404,"message":"not found"}}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}))
+	tb.Cleanup(server.Close)
+
+	srv, err := drive.NewService(context.Background(),
+		option.WithEndpoint(server.URL+"/"),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(server.Client()))
+	if err != nil {
+		tb.Fatalf("drive.NewService: %v", err)
+	}
+	return srv
+}
+
+// runFullSync runs processFullSync and reports whether it panicked, which the tests use to
+// stand in for the process dying mid-sync
+func runFullSync(ctx context.Context, w *SyncWorker, job models.SyncJob, folder *models.SharedFolder, srv *drive.Service) (crashed bool) {
+	defer func() {
+		if recover() != nil {
+			crashed = true
+		}
+	}()
+	w.processFullSync(ctx, job, folder, srv)
+	return false
+}
+
+func TestFullSyncResumeCreatesNoDuplicates(t *testing.T) {
+	const fileCount = 23
+
+	tests := []struct {
+		name      string
+		interrupt func(photos *fakePhotoRepo, cancel context.CancelFunc)
+	}{
+		{
+			// Shutdown while the third chunk is preloaded: the run saves its progress and stops
+			name: "context done halfway",
+			interrupt: func(photos *fakePhotoRepo, cancel context.CancelFunc) {
+				photos.onPreload = func(n int) {
+					if n == 3 {
+						cancel()
+					}
+				}
+			},
+		},
+		{
+			// The process dies inserting the second batch: only the last checkpoint is saved
+			name: "crash halfway",
+			interrupt: func(photos *fakePhotoRepo, cancel context.CancelFunc) {
+				photos.onBatch = func(n int) {
+					if n == 2 {
+						panic("crash")
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := testDriveImages(fileCount)
+			srv := newListingDrive(t, files)
+			folder := &models.SharedFolder{ID: uuid.New(), DriveFolderID: "root", DriveFolderName: "Events"}
+			photos := &fakePhotoRepo{photos: make(map[uuid.UUID]*models.Photo)}
+			jobs := &fakeSyncJobRepo{}
+			w := &SyncWorker{
+				driveClient:      &googledrive.DriveClient{},
+				sharedFolderRepo: &fakeSharedFolderRepo{},
+				photoRepo:        photos,
+				syncJobRepo:      jobs,
+				activityLogRepo:  &fakeActivityLogRepo{},
+				// Checkpoints fall between batch inserts, so photos are queued at some of them
+				batchSize:       5,
+				checkpointEvery: 3,
+				fileConcurrency: 4,
+				orphanPolicy:    models.OrphanPolicyTrash,
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			tt.interrupt(photos, cancel)
+			job := models.SyncJob{ID: uuid.New(), SharedFolderID: &folder.ID, Status: models.SyncJobStatusRunning}
+			runFullSync(ctx, w, job, folder, srv)
+			if len(photos.photos) == fileCount {
+				t.Fatal("first run was not interrupted")
+			}
+
+			var metadata SyncJobMetadata
+			if err := json.Unmarshal([]byte(jobs.job.Metadata), &metadata); err != nil || metadata.LastProcessedID == "" {
+				t.Fatalf("interrupted run saved no resume point (metadata %q)", jobs.job.Metadata)
+			}
+
+			// The job is picked up again with what the interrupted run saved
+			photos.onPreload, photos.onBatch = nil, nil
+			job.Metadata = jobs.job.Metadata
+			job.FailedItems = jobs.job.FailedItems
+			if runFullSync(context.Background(), w, job, folder, srv) {
+				t.Fatal("resumed run crashed")
+			}
+
+			if jobs.job.Status != models.SyncJobStatusCompleted {
+				t.Fatalf("resumed job status = %q, want completed", jobs.job.Status)
+			}
+			if jobs.job.ProcessedItems != fileCount {
+				t.Errorf("processed items = %d, want %d", jobs.job.ProcessedItems, fileCount)
+			}
+			if photos.rejected > 0 {
+				t.Errorf("%d photos rejected as duplicates", photos.rejected)
+			}
+			count := make(map[string]int)
+			for _, p := range photos.photos {
+				count[p.DriveFileID]++
+				if p.IsTrashed {
+					t.Errorf("photo of %s was trashed", p.DriveFileID)
+				}
+			}
+			for _, f := range files {
+				if count[f.ID] != 1 {
+					t.Errorf("file %s has %d photos, want 1", f.ID, count[f.ID])
+				}
+			}
+		})
+	}
+}
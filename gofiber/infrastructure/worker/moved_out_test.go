@@ -0,0 +1,294 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/api/drive/v3"
+	"gorm.io/gorm"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+	"gofiber-template/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	// Keep worker logs out of the package directory
+	logDir, err := os.MkdirTemp("", "worker-test-logs")
+	if err == nil {
+		logger.Init(logDir, false)
+		logger.SetOutput(logger.OutputStdout)
+	}
+	code := m.Run()
+	os.RemoveAll(logDir)
+	os.Exit(code)
+}
+
+// fakePhotoRepo keeps photos in memory; only the methods the worker tests use are implemented.
+// Like the unique index on drive_file_id, CreateBatch rejects a batch holding a known file.
+type fakePhotoRepo struct {
+	repositories.PhotoRepository
+	mu      sync.Mutex
+	photos  map[uuid.UUID]*models.Photo
+	latency time.Duration // Simulated database round trip of the sync's reads and writes
+
+	preloads  int
+	onPreload func(n int) // Called on the nth GetByDriveFileIDs (from 1)
+	batches   int
+	onBatch   func(n int) // Called before the nth CreateBatch (from 1), e.g. to crash the sync
+	rejected  int         // Photos of batches rejected for a duplicate Drive file
+}
+
+func (r *fakePhotoRepo) roundTrip() {
+	if r.latency > 0 {
+		time.Sleep(r.latency)
+	}
+}
+
+func (r *fakePhotoRepo) GetByDriveFileID(ctx context.Context, folderID uuid.UUID, driveFileID string) (*models.Photo, error) {
+	r.roundTrip()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.photos {
+		if p.SharedFolderID == folderID && p.DriveFileID == driveFileID {
+			photo := *p
+			return &photo, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakePhotoRepo) GetByDriveFileIDs(ctx context.Context, folderID uuid.UUID, driveFileIDs []string) (map[string]*models.Photo, error) {
+	r.mu.Lock()
+	r.preloads++
+	n := r.preloads
+	r.mu.Unlock()
+	if r.onPreload != nil {
+		r.onPreload(n)
+	}
+
+	r.roundTrip()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wanted := make(map[string]bool, len(driveFileIDs))
+	for _, id := range driveFileIDs {
+		wanted[id] = true
+	}
+	found := make(map[string]*models.Photo)
+	for _, p := range r.photos {
+		if p.SharedFolderID == folderID && wanted[p.DriveFileID] {
+			photo := *p
+			found[p.DriveFileID] = &photo
+		}
+	}
+	return found, nil
+}
+
+func (r *fakePhotoRepo) CreateBatch(ctx context.Context, photos []*models.Photo) error {
+	r.mu.Lock()
+	r.batches++
+	n := r.batches
+	r.mu.Unlock()
+	if r.onBatch != nil {
+		r.onBatch(n)
+	}
+
+	r.roundTrip()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := make(map[string]bool, len(r.photos))
+	for _, p := range r.photos {
+		stored[p.DriveFileID] = true
+	}
+	for _, photo := range photos {
+		if stored[photo.DriveFileID] {
+			r.rejected += len(photos)
+			return errors.New("duplicate key value violates unique constraint")
+		}
+	}
+	for _, photo := range photos {
+		stored := *photo
+		r.photos[photo.ID] = &stored
+	}
+	return nil
+}
+
+func (r *fakePhotoRepo) Update(ctx context.Context, id uuid.UUID, photo *models.Photo) error {
+	r.roundTrip()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.photos[id]; ok {
+		updated := *photo
+		// Struct updates skip zero values: a false trash flag leaves a trashed photo trashed
+		if stored.IsTrashed && !updated.IsTrashed {
+			updated.IsTrashed, updated.TrashedAt = true, stored.TrashedAt
+		}
+		r.photos[id] = &updated
+	}
+	return nil
+}
+
+func (r *fakePhotoRepo) RestoreByID(ctx context.Context, id uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.photos[id]
+	if !ok || !p.IsTrashed {
+		return false, nil
+	}
+	p.IsTrashed, p.TrashedAt = false, nil
+	return true, nil
+}
+
+func (r *fakePhotoRepo) UpdateMetadata(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	r.roundTrip()
+	return nil
+}
+
+// orphans returns the folder's photos whose files are not in driveFileIDs; r.mu must be held
+func (r *fakePhotoRepo) orphans(folderID uuid.UUID, driveFileIDs []string) []*models.Photo {
+	listed := make(map[string]bool, len(driveFileIDs))
+	for _, id := range driveFileIDs {
+		listed[id] = true
+	}
+	var orphans []*models.Photo
+	for _, p := range r.photos {
+		if p.SharedFolderID == folderID && !listed[p.DriveFileID] {
+			orphans = append(orphans, p)
+		}
+	}
+	return orphans
+}
+
+func (r *fakePhotoRepo) TrashNotInDriveIDsForFolder(ctx context.Context, folderID uuid.UUID, driveFileIDs []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var trashed int64
+	for _, p := range r.orphans(folderID, driveFileIDs) {
+		if !p.IsTrashed {
+			p.IsTrashed = true
+			trashed++
+		}
+	}
+	return trashed, nil
+}
+
+func (r *fakePhotoRepo) DeleteNotInDriveIDsForFolder(ctx context.Context, folderID uuid.UUID, driveFileIDs []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	orphans := r.orphans(folderID, driveFileIDs)
+	for _, p := range orphans {
+		delete(r.photos, p.ID)
+	}
+	return int64(len(orphans)), nil
+}
+
+func (r *fakePhotoRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.photos, id)
+	return nil
+}
+
+func (r *fakePhotoRepo) TrashByID(ctx context.Context, id uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.photos[id]
+	if !ok || p.IsTrashed {
+		return false, nil
+	}
+	now := time.Now()
+	p.IsTrashed, p.TrashedAt = true, &now
+	return true, nil
+}
+
+// fakeActivityLogRepo records created activity logs
+type fakeActivityLogRepo struct {
+	repositories.ActivityLogRepository
+	logs []*models.ActivityLog
+}
+
+func (r *fakeActivityLogRepo) Create(ctx context.Context, log *models.ActivityLog) error {
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func TestRemoveMovedOutPhoto(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      models.OrphanPolicy
+		wantRemoved bool
+		wantRow     bool // Row still in the DB
+		wantTrashed bool
+	}{
+		{name: "delete policy removes the row", policy: models.OrphanPolicyDelete, wantRemoved: true, wantRow: false},
+		{name: "trash policy trashes the row", policy: models.OrphanPolicyTrash, wantRemoved: true, wantRow: true, wantTrashed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folder := &models.SharedFolder{ID: uuid.New(), OrphanPolicy: tt.policy}
+			photo := &models.Photo{
+				ID:              uuid.New(),
+				SharedFolderID:  folder.ID,
+				DriveFileID:     "file-1",
+				FileName:        "a.jpg",
+				DriveFolderPath: "Events/2024",
+			}
+			photos := &fakePhotoRepo{photos: map[uuid.UUID]*models.Photo{photo.ID: photo}}
+			activity := &fakeActivityLogRepo{}
+			w := &SyncWorker{photoRepo: photos, activityLogRepo: activity, orphanPolicy: models.OrphanPolicyDelete}
+
+			// The file now sits under a parent outside the synced folder
+			file := &drive.File{Id: "file-1", Name: "a.jpg", Parents: []string{"outside"}}
+			removed := w.removeMovedOutPhoto(context.Background(), uuid.New(), folder, file, &drive.Change{FileId: "file-1", File: file})
+
+			if removed != tt.wantRemoved {
+				t.Fatalf("removeMovedOutPhoto = %v, want %v", removed, tt.wantRemoved)
+			}
+			row, ok := photos.photos[photo.ID]
+			if ok != tt.wantRow {
+				t.Fatalf("row present = %v, want %v", ok, tt.wantRow)
+			}
+			if ok && row.IsTrashed != tt.wantTrashed {
+				t.Errorf("IsTrashed = %v, want %v", row.IsTrashed, tt.wantTrashed)
+			}
+
+			if len(activity.logs) != 1 || activity.logs[0].ActivityType != models.ActivityPhotoMoved {
+				t.Fatalf("activity logs = %+v, want one %s", activity.logs, models.ActivityPhotoMoved)
+			}
+			var details models.ActivityDetails
+			if err := json.Unmarshal([]byte(activity.logs[0].Details), &details); err != nil {
+				t.Fatalf("activity details: %v", err)
+			}
+			if !details.OutOfScope || details.DriveFileID != "file-1" {
+				t.Errorf("details = %+v, want out-of-scope marker for file-1", details)
+			}
+		})
+	}
+}
+
+func TestRemoveMovedOutPhotoIgnoresUnknownFiles(t *testing.T) {
+	folder := &models.SharedFolder{ID: uuid.New()}
+	other := &models.Photo{ID: uuid.New(), SharedFolderID: uuid.New(), DriveFileID: "file-1"}
+	photos := &fakePhotoRepo{photos: map[uuid.UUID]*models.Photo{other.ID: other}}
+	activity := &fakeActivityLogRepo{}
+	w := &SyncWorker{photoRepo: photos, activityLogRepo: activity, orphanPolicy: models.OrphanPolicyDelete}
+
+	// The file only has a photo in another folder: nothing of this folder to remove
+	file := &drive.File{Id: "file-1", Name: "a.jpg", Parents: []string{"outside"}}
+	if w.removeMovedOutPhoto(context.Background(), uuid.New(), folder, file, &drive.Change{FileId: "file-1", File: file}) {
+		t.Fatal("removeMovedOutPhoto = true for a file without a photo in the folder")
+	}
+	if _, ok := photos.photos[other.ID]; !ok {
+		t.Error("another folder's photo of the same file was removed")
+	}
+	if len(activity.logs) != 0 {
+		t.Errorf("activity logs = %+v, want none", activity.logs)
+	}
+}
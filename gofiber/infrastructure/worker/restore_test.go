@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/api/drive/v3"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/infrastructure/googledrive"
+	"gofiber-template/pkg/config"
+)
+
+// trashedTestPhoto returns a photo of Drive file "file" that was in folder "a" (Events/2024)
+// when orphan cleanup trashed it
+func trashedTestPhoto(folder *models.SharedFolder) *models.Photo {
+	trashedAt := time.Now().Add(-time.Hour)
+	return &models.Photo{
+		ID:              uuid.New(),
+		SharedFolderID:  folder.ID,
+		DriveFileID:     "file",
+		DriveFolderID:   "a",
+		DriveFolderPath: "Events/2024",
+		FileName:        "IMG_0001.jpg",
+		MimeType:        "image/jpeg",
+		IsTrashed:       true,
+		TrashedAt:       &trashedAt,
+		UpdatedAt:       trashedAt,
+	}
+}
+
+// checkRestored fails the test unless the photo is out of the trash, in folder "b" with wantPath
+func checkRestored(t *testing.T, photos *fakePhotoRepo, id uuid.UUID, wantPath string) {
+	t.Helper()
+
+	photo := photos.photos[id]
+	if photo.IsTrashed || photo.TrashedAt != nil {
+		t.Errorf("photo is still trashed (trashed_at %v)", photo.TrashedAt)
+	}
+	if photo.DriveFolderID != "b" {
+		t.Errorf("drive_folder_id = %q, want %q", photo.DriveFolderID, "b")
+	}
+	if photo.DriveFolderPath != wantPath {
+		t.Errorf("drive_folder_path = %q, want %q", photo.DriveFolderPath, wantPath)
+	}
+}
+
+func TestIncrementalSyncRestoresNestedPhoto(t *testing.T) {
+	tests := []struct {
+		name     string
+		failing  []string
+		wantPath string
+	}{
+		{name: "path resolved", wantPath: "Events/2024/Day 1"},
+		// Without the root folder's name no path can be built; the known one is kept
+		{name: "path lookup failed", failing: []string{"root"}, wantPath: "Events/2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folder := &models.SharedFolder{ID: uuid.New(), DriveFolderID: "root", DriveFolderName: "Events", PageToken: "1"}
+			photo := trashedTestPhoto(folder)
+			photos := &fakePhotoRepo{photos: map[uuid.UUID]*models.Photo{photo.ID: photo}}
+
+			// The file is back from the trash, in the nested folder "Day 1"
+			changes := []*drive.Change{{
+				FileId: "file",
+				File: &drive.File{
+					Id:           "file",
+					Name:         "IMG_0001.jpg",
+					MimeType:     "image/jpeg",
+					Parents:      []string{"b"},
+					ModifiedTime: time.Now().Format(time.RFC3339),
+				},
+			}}
+			srv, _ := newTestDrive(t, changes, tt.failing...)
+			jobs := &fakeSyncJobRepo{}
+			w := NewSyncWorker(googledrive.NewDriveClient(config.GoogleDriveConfig{}), &fakeSharedFolderRepo{}, photos, jobs, &fakeActivityLogRepo{}, nil)
+
+			w.processIncrementalSync(context.Background(), models.SyncJob{ID: uuid.New()}, folder, srv)
+
+			if jobs.job.Status != models.SyncJobStatusCompleted {
+				t.Fatalf("job status = %q, want completed", jobs.job.Status)
+			}
+			checkRestored(t, photos, photo.ID, tt.wantPath)
+		})
+	}
+}
+
+func TestFullSyncRestoresNestedPhoto(t *testing.T) {
+	folder := &models.SharedFolder{ID: uuid.New(), DriveFolderID: "root", DriveFolderName: "Events"}
+	photo := trashedTestPhoto(folder)
+	photos := &fakePhotoRepo{photos: map[uuid.UUID]*models.Photo{photo.ID: photo}}
+	srv, _ := newTestDrive(t, nil)
+	w := &SyncWorker{driveClient: &googledrive.DriveClient{}, photoRepo: photos}
+
+	// Listed again in "Day 1"; without a path map the path is looked up on Drive
+	file := googledrive.DriveFile{
+		ID:           "file",
+		Name:         "IMG_0001.jpg",
+		MimeType:     "image/jpeg",
+		ParentID:     "b",
+		ModifiedTime: time.Now(),
+	}
+	result := w.processFullSyncFile(context.Background(), folder, srv, nil, nil, file)
+
+	if result.newPhoto != nil {
+		t.Fatal("trashed photo was inserted again")
+	}
+	checkRestored(t, photos, photo.ID, "Events/2024/Day 1")
+}
@@ -0,0 +1,272 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/pkg/logger"
+)
+
+// dryRunCounts is what a sync would change
+type dryRunCounts struct {
+	processed int
+	newFiles  int
+	updated   int
+	deleted   int
+	trashed   int // Orphans the trash policy would move to trash (full sync only)
+}
+
+// processDryRun previews a sync: it reads Drive and the photos table like a real sync of the
+// given mode, but never creates, updates or deletes photos and keeps the folder's page token.
+// The counts are broadcast with sync:completed (dryRun: true) and logged as ActivitySyncDryRun.
+func (w *SyncWorker) processDryRun(ctx context.Context, job models.SyncJob, folder *models.SharedFolder, srv *drive.Service, mode models.SyncMode) {
+	jobID := job.ID
+	startTime := time.Now()
+
+	logger.Sync("dry_run_start", "Starting dry-run sync", map[string]interface{}{
+		"job_id":    jobID.String(),
+		"folder_id": folder.ID.String(),
+		"mode":      string(mode),
+	})
+
+	var counts dryRunCounts
+	var err error
+	if mode == models.SyncModeIncremental {
+		counts, err = w.dryRunIncremental(ctx, folder, srv)
+	} else {
+		counts, err = w.dryRunFull(ctx, folder, srv)
+	}
+
+	if ctx.Err() != nil {
+		if isCancelled(ctx) {
+			w.markCancelled(jobID, folder.ID, counts.processed, 0, nil)
+			return
+		}
+		// Worker stopping - a dry run has no checkpoint, so it simply runs again on the next start
+		w.syncJobRepo.UpdateStatus(context.Background(), jobID, models.SyncJobStatusPending)
+		return
+	}
+
+	if err != nil {
+		// The folder's own status is left alone: nothing was synced
+		w.failJob(ctx, jobID, nil, fmt.Sprintf("Dry run failed: %v", err))
+		w.broadcastToFolderUsers(ctx, folder.ID, "sync:failed", map[string]interface{}{
+			"jobId":    jobID.String(),
+			"folderId": folder.ID.String(),
+			"status":   "failed",
+			"message":  err.Error(),
+			"dryRun":   true,
+		})
+		return
+	}
+
+	now := time.Now()
+	duration := now.Sub(startTime)
+	w.syncJobRepo.Update(ctx, jobID, &models.SyncJob{
+		Status:         models.SyncJobStatusCompleted,
+		TotalItems:     counts.processed,
+		ProcessedItems: counts.processed,
+		CompletedAt:    &now,
+		UpdatedAt:      now,
+	})
+
+	w.broadcastToFolderUsers(ctx, folder.ID, "sync:completed", map[string]interface{}{
+		"jobId":          jobID.String(),
+		"folderId":       folder.ID.String(),
+		"status":         "completed",
+		"processedFiles": counts.processed,
+		"newFiles":       counts.newFiles,
+		"updatedFiles":   counts.updated,
+		"deletedFiles":   counts.deleted,
+		"trashedFiles":   counts.trashed,
+		"duration":       duration.String(),
+		"isIncremental":  mode == models.SyncModeIncremental,
+		"dryRun":         true,
+	})
+
+	w.logActivity(ctx, folder.ID, models.ActivitySyncDryRun,
+		fmt.Sprintf("ทดลองซิงค์ (ไม่บันทึก) - จะเพิ่ม %d, อัพเดท %d, ลบ %d, ย้ายไปถังขยะ %d รายการ",
+			counts.newFiles, counts.updated, counts.deleted, counts.trashed),
+		&models.ActivityDetails{
+			JobID:         jobID.String(),
+			FolderName:    folder.DriveFolderName,
+			IsIncremental: mode == models.SyncModeIncremental,
+			DurationMs:    duration.Milliseconds(),
+			TotalNew:      counts.newFiles,
+			TotalUpdated:  counts.updated,
+			TotalDeleted:  counts.deleted,
+			TotalTrashed:  counts.trashed,
+		}, nil)
+
+	logger.Sync("dry_run_completed", "Dry-run sync completed", map[string]interface{}{
+		"job_id":        jobID.String(),
+		"folder_id":     folder.ID.String(),
+		"mode":          string(mode),
+		"duration_ms":   duration.Milliseconds(),
+		"processed":     counts.processed,
+		"new_files":     counts.newFiles,
+		"updated_files": counts.updated,
+		"deleted_files": counts.deleted,
+		"trashed_files": counts.trashed,
+	})
+}
+
+// dryRunFull counts what processFullSync would change, including orphan cleanup
+func (w *SyncWorker) dryRunFull(ctx context.Context, folder *models.SharedFolder, srv *drive.Service) (dryRunCounts, error) {
+	var counts dryRunCounts
+
+	var folderPathMap map[string]string
+	if allFolders, err := w.driveClient.ListAllFoldersRecursive(ctx, srv, folder.DriveFolderID); err == nil {
+		folderPathMap = w.driveClient.BuildFolderPathMap(allFolders, folder.DriveFolderID)
+	}
+
+	files, err := w.driveClient.ListAllImagesRecursive(ctx, srv, folder.DriveFolderID)
+	if err != nil {
+		return counts, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	driveFileIDs := make([]string, 0, len(files))
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return counts, ctx.Err()
+		}
+		driveFileIDs = append(driveFileIDs, file.ID)
+
+		var folderPath string
+		if folderPathMap != nil {
+			folderPath = folderPathMap[file.ParentID]
+		} else {
+			folderPath, _ = w.driveClient.GetFolderPath(ctx, srv, file.ParentID, folder.DriveFolderID)
+		}
+		if folder.IsPathExcluded(folderPath) {
+			counts.processed++
+			continue
+		}
+
+		// Same decisions as processFullSync
+		existingPhoto, _ := w.photoRepo.GetByDriveFileID(ctx, folder.ID, file.ID)
+		if existingPhoto == nil {
+			counts.newFiles++
+		} else if existingPhoto.IsTrashed ||
+			file.ModifiedTime.After(existingPhoto.UpdatedAt) ||
+			existingPhoto.DriveFolderID != file.ParentID ||
+			existingPhoto.DriveFolderPath != folderPath ||
+			(existingPhoto.TakenAt == nil && file.TakenAt != nil) {
+			counts.updated++
+		}
+		counts.processed++
+	}
+
+	if w.orphanPolicyFor(folder) == models.OrphanPolicyTrash {
+		trashed, err := w.photoRepo.CountNotInDriveIDsForFolder(ctx, folder.ID, driveFileIDs, false)
+		if err != nil {
+			return counts, fmt.Errorf("failed to count orphaned photos: %w", err)
+		}
+		counts.trashed = int(trashed)
+	} else {
+		deleted, err := w.photoRepo.CountNotInDriveIDsForFolder(ctx, folder.ID, driveFileIDs, true)
+		if err != nil {
+			return counts, fmt.Errorf("failed to count orphaned photos: %w", err)
+		}
+		counts.deleted = int(deleted)
+	}
+
+	return counts, nil
+}
+
+// dryRunIncremental counts what processIncrementalSync would change for the pending Drive changes
+func (w *SyncWorker) dryRunIncremental(ctx context.Context, folder *models.SharedFolder, srv *drive.Service) (dryRunCounts, error) {
+	var counts dryRunCounts
+
+	// The page token is not saved, so the real sync still sees these changes
+	changes, _, _, err := w.driveClient.GetChanges(ctx, srv, folder.PageToken, 0)
+	if err != nil {
+		return counts, fmt.Errorf("failed to get changes: %w", err)
+	}
+
+	scope := newChangeScope(w.driveClient, srv, folder.DriveFolderID)
+	for _, change := range changes {
+		if ctx.Err() != nil {
+			return counts, ctx.Err()
+		}
+		counts.processed++
+
+		if change.Removed || change.File == nil {
+			if change.FileId == "" {
+				continue
+			}
+			if existingPhoto, _ := w.photoRepo.GetByDriveFileID(ctx, folder.ID, change.FileId); existingPhoto != nil {
+				counts.deleted++
+			}
+			if _, inFolder, err := w.photoRepo.GetBySharedFolderAndDriveFolderID(ctx, folder.ID, change.FileId, 0, 1); err == nil {
+				counts.deleted += int(inFolder)
+			}
+			continue
+		}
+
+		file := change.File
+
+		if file.MimeType == "application/vnd.google-apps.folder" {
+			photos, inFolder, err := w.photoRepo.GetBySharedFolderAndDriveFolderID(ctx, folder.ID, file.Id, 0, 1)
+			if err != nil || inFolder == 0 {
+				continue
+			}
+			if file.Trashed {
+				counts.updated += int(inFolder)
+				continue
+			}
+			// Renamed or moved: every photo of the folder gets the new path
+			if scope.isWithin(ctx, file.Id) {
+				newFolderPath := scope.folderPath(ctx, file.Id)
+				if newFolderPath != "" && photos[0].DriveFolderPath != newFolderPath {
+					counts.updated += int(inFolder)
+				}
+			}
+			continue
+		}
+
+		if file.MimeType == "" || !w.driveClient.IsSupportedMimeType(file.MimeType) {
+			continue
+		}
+
+		existingPhoto, _ := w.photoRepo.GetByDriveFileID(ctx, folder.ID, file.Id)
+
+		if file.Trashed {
+			if existingPhoto != nil && !existingPhoto.IsTrashed {
+				counts.updated++
+			}
+			continue
+		}
+
+		parentID := ""
+		if len(file.Parents) > 0 {
+			parentID = file.Parents[0]
+		}
+		if within, resolved := scope.lookupWithin(ctx, parentID); !within {
+			// Moved out of the folder: the photo would be trashed or deleted
+			if resolved && existingPhoto != nil && existingPhoto.SharedFolderID == folder.ID {
+				if w.orphanPolicyFor(folder) != models.OrphanPolicyTrash {
+					counts.deleted++
+				} else if !existingPhoto.IsTrashed {
+					counts.updated++
+				}
+			}
+			continue
+		}
+		if folder.IsPathExcluded(scope.folderPath(ctx, parentID)) {
+			continue
+		}
+
+		if existingPhoto != nil {
+			counts.updated++
+		} else {
+			counts.newFiles++
+		}
+	}
+
+	return counts, nil
+}
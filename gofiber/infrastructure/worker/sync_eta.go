@@ -0,0 +1,48 @@
+package worker
+
+import "time"
+
+// etaWindow is how many progress samples the rate is averaged over
+const etaWindow = 5
+
+type etaSample struct {
+	at        time.Time
+	processed int
+}
+
+// syncETA estimates the remaining time of a full sync from its recent processing rate.
+// The rate is measured across the last etaWindow samples (a simple moving average),
+// so one slow batch early on doesn't swing the estimate.
+type syncETA struct {
+	samples []etaSample
+}
+
+// newSyncETA starts an estimate at the given processed count (non-zero when a job resumes,
+// so files done by an earlier run don't inflate the rate)
+func newSyncETA(start time.Time, processed int) *syncETA {
+	return &syncETA{samples: []etaSample{{at: start, processed: processed}}}
+}
+
+// add records the processed count at a progress broadcast
+func (e *syncETA) add(at time.Time, processed int) {
+	e.samples = append(e.samples, etaSample{at: at, processed: processed})
+	if len(e.samples) > etaWindow+1 {
+		e.samples = e.samples[len(e.samples)-(etaWindow+1):]
+	}
+}
+
+// seconds returns the estimated seconds left for total files, or false while there is no rate yet
+func (e *syncETA) seconds(total int) (int, bool) {
+	first, last := e.samples[0], e.samples[len(e.samples)-1]
+	done := last.processed - first.processed
+	elapsed := last.at.Sub(first.at).Seconds()
+	if total <= 0 || done <= 0 || elapsed <= 0 {
+		return 0, false
+	}
+
+	remaining := total - last.processed
+	if remaining <= 0 {
+		return 0, true
+	}
+	return int(float64(remaining)/(float64(done)/elapsed) + 0.5), true
+}
@@ -3,6 +3,7 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -25,6 +26,7 @@ type SyncWorker struct {
 	photoRepo        repositories.PhotoRepository
 	syncJobRepo      repositories.SyncJobRepository
 	activityLogRepo  repositories.ActivityLogRepository
+	syncSummaryRepo  repositories.SyncSummaryRepository
 
 	// Worker control
 	ctx        context.Context
@@ -34,12 +36,21 @@ type SyncWorker struct {
 	mu         sync.Mutex
 	triggerCh  chan struct{} // Channel to trigger immediate processing
 
+	// Per-job cancellation of running syncs (keyed by job ID)
+	jobCancels map[uuid.UUID]context.CancelCauseFunc
+	cancelMu   sync.Mutex
+
 	// Configuration
-	pollInterval    time.Duration
-	maxConcurrent   int
-	batchSize       int // Batch size for photo creation
-	checkpointEvery int // Save checkpoint every N files
-	broadcastEvery  int // Broadcast progress every N files
+	pollInterval     time.Duration       // Safety-net poll for pending jobs not picked up via TriggerSync
+	fullSlots        chan struct{}       // Limits concurrent full syncs (heavy: listing + batch inserts)
+	incrementalSlots chan struct{}       // Limits concurrent incremental syncs (light)
+	batchSize        int                 // Batch size for photo creation
+	checkpointEvery  int                 // Save checkpoint every N files
+	broadcastEvery   int                 // Broadcast progress every N files
+	addedBatchSize   int                 // New photo IDs per photos:added message in incremental sync
+	maxChangesPerJob int                 // Drive changes read and applied per incremental job
+	fileConcurrency  int                 // Files of one full sync processed concurrently
+	orphanPolicy     models.OrphanPolicy // Default orphan cleanup policy for folders without their own
 }
 
 // SyncJobMetadata contains metadata for sync jobs
@@ -50,6 +61,8 @@ type SyncJobMetadata struct {
 	LastProcessedID string    `json:"last_processed_id,omitempty"`
 	IsIncremental   bool      `json:"is_incremental,omitempty"`
 	SharedFolderID  uuid.UUID `json:"shared_folder_id,omitempty"`
+	DryRun          bool      `json:"dry_run,omitempty"`    // Only count what the sync would change, write nothing
+	ForceFull       bool      `json:"force_full,omitempty"` // Dry run: preview a full sync even if the folder has a page token
 }
 
 // NewSyncWorker creates a new sync worker
@@ -59,6 +72,7 @@ func NewSyncWorker(
 	photoRepo repositories.PhotoRepository,
 	syncJobRepo repositories.SyncJobRepository,
 	activityLogRepo repositories.ActivityLogRepository,
+	syncSummaryRepo repositories.SyncSummaryRepository,
 ) *SyncWorker {
 	return &SyncWorker{
 		driveClient:      driveClient,
@@ -66,12 +80,312 @@ func NewSyncWorker(
 		photoRepo:        photoRepo,
 		syncJobRepo:      syncJobRepo,
 		activityLogRepo:  activityLogRepo,
+		syncSummaryRepo:  syncSummaryRepo,
 		triggerCh:        make(chan struct{}, 10), // Buffered channel for triggers
-		maxConcurrent:    2,
+		jobCancels:       make(map[uuid.UUID]context.CancelCauseFunc),
+		pollInterval:     60 * time.Second,
+		fullSlots:        make(chan struct{}, 1),
+		incrementalSlots: make(chan struct{}, 4),
 		batchSize:        100,
 		checkpointEvery:  100,
 		broadcastEvery:   50,
+		addedBatchSize:   50,
+		maxChangesPerJob: 1000,
+		fileConcurrency:  4,
+		orphanPolicy:     models.OrphanPolicyDelete,
+	}
+}
+
+// SyncWorkerConfig tunes the sync worker (zero values keep the defaults)
+type SyncWorkerConfig struct {
+	PollInterval             time.Duration
+	MaxConcurrentFull        int
+	MaxConcurrentIncremental int
+	BatchSize                int                 // Photos per batch insert (1-500)
+	CheckpointEvery          int                 // Save a resumable checkpoint every N files
+	BroadcastEvery           int                 // Broadcast progress every N files
+	AddedBatchSize           int                 // New photo IDs per photos:added message in incremental sync
+	FileConcurrency          int                 // Files of one full sync processed concurrently (1-32)
+	MaxChangesPerJob         int                 // Drive changes read and applied per incremental job
+	OrphanPolicy             models.OrphanPolicy // Default orphan cleanup policy ("delete" or "trash")
+}
+
+// MaxSyncBatchSize is the largest allowed photo batch insert
+const MaxSyncBatchSize = 500
+
+// MaxSyncFileConcurrency is the largest allowed per-sync file concurrency
+const MaxSyncFileConcurrency = 32
+
+// Configure applies the worker configuration (must be called before Start)
+// Out-of-range values are clamped and logged rather than rejected
+func (w *SyncWorker) Configure(cfg SyncWorkerConfig) {
+	if cfg.PollInterval > 0 {
+		w.SetPollInterval(cfg.PollInterval)
+	}
+	if cfg.MaxConcurrentFull > 0 || cfg.MaxConcurrentIncremental > 0 {
+		maxFull, maxIncremental := cfg.MaxConcurrentFull, cfg.MaxConcurrentIncremental
+		if maxFull <= 0 {
+			maxFull = cap(w.fullSlots)
+		}
+		if maxIncremental <= 0 {
+			maxIncremental = cap(w.incrementalSlots)
+		}
+		w.SetConcurrency(maxFull, maxIncremental)
 	}
+
+	if cfg.BatchSize > 0 {
+		if cfg.BatchSize > MaxSyncBatchSize {
+			logger.StartupWarn("sync_batch_size_too_high", "Sync batch size above maximum, using maximum", map[string]interface{}{
+				"batch_size": cfg.BatchSize,
+				"maximum":    MaxSyncBatchSize,
+			})
+			cfg.BatchSize = MaxSyncBatchSize
+		}
+		w.batchSize = cfg.BatchSize
+	}
+	if cfg.CheckpointEvery > 0 {
+		w.checkpointEvery = cfg.CheckpointEvery
+	}
+	if cfg.BroadcastEvery > 0 {
+		w.broadcastEvery = cfg.BroadcastEvery
+	}
+	if cfg.AddedBatchSize > 0 {
+		w.addedBatchSize = cfg.AddedBatchSize
+	}
+	if cfg.MaxChangesPerJob > 0 {
+		w.maxChangesPerJob = cfg.MaxChangesPerJob
+	}
+	if cfg.FileConcurrency > 0 {
+		if cfg.FileConcurrency > MaxSyncFileConcurrency {
+			logger.StartupWarn("sync_file_concurrency_too_high", "Sync file concurrency above maximum, using maximum", map[string]interface{}{
+				"file_concurrency": cfg.FileConcurrency,
+				"maximum":          MaxSyncFileConcurrency,
+			})
+			cfg.FileConcurrency = MaxSyncFileConcurrency
+		}
+		w.fileConcurrency = cfg.FileConcurrency
+	}
+	if cfg.OrphanPolicy != "" {
+		if cfg.OrphanPolicy.IsValid() {
+			w.orphanPolicy = cfg.OrphanPolicy
+		} else {
+			logger.StartupWarn("orphan_policy_invalid", "Unknown orphan policy, using default", map[string]interface{}{
+				"orphan_policy": string(cfg.OrphanPolicy),
+				"default":       string(w.orphanPolicy),
+			})
+		}
+	}
+}
+
+// flushAddedPhotos broadcasts the collected new photo IDs as one photos:added message and empties the list
+func (w *SyncWorker) flushAddedPhotos(ctx context.Context, folderID uuid.UUID, photoIDs *[]string) {
+	if len(*photoIDs) == 0 {
+		return
+	}
+	w.broadcastToFolderUsers(ctx, folderID, "photos:added", map[string]interface{}{
+		"count":    len(*photoIDs),
+		"photoIds": *photoIDs,
+	})
+	*photoIDs = make([]string, 0, w.addedBatchSize)
+}
+
+// orphanPolicyFor returns the folder's orphan policy, falling back to the worker default
+func (w *SyncWorker) orphanPolicyFor(folder *models.SharedFolder) models.OrphanPolicy {
+	if folder.OrphanPolicy.IsValid() {
+		return folder.OrphanPolicy
+	}
+	return w.orphanPolicy
+}
+
+// removeMovedOutPhoto handles a media file that moved outside the folder: its photo is trashed
+// or deleted following the orphan policy. Returns false if the file has no photo in the folder.
+func (w *SyncWorker) removeMovedOutPhoto(ctx context.Context, jobID uuid.UUID, folder *models.SharedFolder, file *drive.File, change *drive.Change) bool {
+	existingPhoto, _ := w.photoRepo.GetByDriveFileID(ctx, folder.ID, file.Id)
+	if existingPhoto == nil || existingPhoto.SharedFolderID != folder.ID {
+		return false
+	}
+
+	trash := w.orphanPolicyFor(folder) == models.OrphanPolicyTrash
+	var outcome string
+	if trash {
+		if existingPhoto.IsTrashed {
+			return false
+		}
+		if wasUpdated, err := w.photoRepo.TrashByID(ctx, existingPhoto.ID); err != nil || !wasUpdated {
+			return false
+		}
+		outcome = "ย้ายไปถังขยะ"
+	} else {
+		if err := w.photoRepo.Delete(ctx, existingPhoto.ID); err != nil {
+			return false
+		}
+		outcome = "ลบแล้ว"
+	}
+
+	logger.SyncSampled("photo_moved_out", "Photo moved outside the synced folder", map[string]interface{}{
+		"job_id":        jobID.String(),
+		"drive_file_id": file.Id,
+		"file_name":     file.Name,
+		"old_path":      existingPhoto.DriveFolderPath,
+		"trashed":       trash,
+	})
+
+	w.logActivity(ctx, folder.ID, models.ActivityPhotoMoved,
+		fmt.Sprintf("รูปภาพ %s ถูกย้ายออกจากโฟลเดอร์ที่ซิงค์ (%s)", file.Name, outcome),
+		&models.ActivityDetails{
+			JobID:       jobID.String(),
+			FileNames:   []string{file.Name},
+			DriveFileID: file.Id,
+			FolderPath:  existingPhoto.DriveFolderPath,
+			OutOfScope:  true,
+			Count:       1,
+		}, change)
+	return true
+}
+
+// GetStats returns worker statistics and the active configuration
+func (w *SyncWorker) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"isRunning":                w.IsRunning(),
+		"pollIntervalSeconds":      w.pollInterval.Seconds(),
+		"maxConcurrentFull":        cap(w.fullSlots),
+		"maxConcurrentIncremental": cap(w.incrementalSlots),
+		"runningFull":              len(w.fullSlots),
+		"runningIncremental":       len(w.incrementalSlots),
+		"batchSize":                w.batchSize,
+		"checkpointEvery":          w.checkpointEvery,
+		"broadcastEvery":           w.broadcastEvery,
+		"addedBatchSize":           w.addedBatchSize,
+		"fileConcurrency":          w.fileConcurrency,
+		"maxChangesPerJob":         w.maxChangesPerJob,
+		"orphanPolicy":             string(w.orphanPolicy),
+	}
+}
+
+// MinPollInterval is the lowest allowed worker poll interval (prevents accidental tight loops)
+const MinPollInterval = time.Second
+
+// clampPollInterval raises intervals below MinPollInterval to the minimum
+func clampPollInterval(workerName string, interval time.Duration) time.Duration {
+	if interval < MinPollInterval {
+		logger.StartupWarn("poll_interval_too_low", "Poll interval below minimum, using minimum", map[string]interface{}{
+			"worker":      workerName,
+			"interval_ms": interval.Milliseconds(),
+			"minimum_ms":  MinPollInterval.Milliseconds(),
+		})
+		return MinPollInterval
+	}
+	return interval
+}
+
+// SetPollInterval sets the safety-net poll interval (must be called before Start)
+func (w *SyncWorker) SetPollInterval(interval time.Duration) {
+	w.pollInterval = clampPollInterval("sync_worker", interval)
+}
+
+// SetConcurrency sets the per-mode concurrency caps (must be called before Start)
+func (w *SyncWorker) SetConcurrency(maxFull, maxIncremental int) {
+	if maxFull < 1 {
+		maxFull = 1
+	}
+	if maxIncremental < 1 {
+		maxIncremental = 1
+	}
+	w.fullSlots = make(chan struct{}, maxFull)
+	w.incrementalSlots = make(chan struct{}, maxIncremental)
+}
+
+// slotsFor returns the slot channel of a sync mode
+func (w *SyncWorker) slotsFor(mode models.SyncMode) chan struct{} {
+	if mode == models.SyncModeFull {
+		return w.fullSlots
+	}
+	return w.incrementalSlots
+}
+
+// acquireSlot blocks until a slot for the given sync mode is free.
+// Returns a release func, or nil if the worker is stopping.
+func (w *SyncWorker) acquireSlot(jobID uuid.UUID, mode models.SyncMode) func() {
+	slots := w.slotsFor(mode)
+
+	select {
+	case slots <- struct{}{}:
+	default:
+		logger.Sync("sync_waiting_for_slot", "Waiting for a free sync slot", map[string]interface{}{
+			"job_id":   jobID.String(),
+			"mode":     string(mode),
+			"capacity": cap(slots),
+		})
+		select {
+		case slots <- struct{}{}:
+		case <-w.ctx.Done():
+			return nil
+		}
+	}
+
+	return func() { <-slots }
+}
+
+// ErrSyncCancelled is the cancellation cause of a sync job stopped by a user
+var ErrSyncCancelled = errors.New("sync cancelled by user")
+
+// CancelJob stops a sync job running in this worker.
+// Returns false if the job is not running here (pending, finished or unknown).
+func (w *SyncWorker) CancelJob(jobID uuid.UUID) bool {
+	w.cancelMu.Lock()
+	defer w.cancelMu.Unlock()
+
+	cancel, ok := w.jobCancels[jobID]
+	if !ok {
+		return false
+	}
+	cancel(ErrSyncCancelled)
+	return true
+}
+
+// cancelPollInterval is how often a running job checks its cancel_requested flag
+const cancelPollInterval = 5 * time.Second
+
+// jobContext derives a cancellable context for a job, registers it for CancelJob and watches
+// the job's cancel flag, so a cancel requested on another instance stops it too
+func (w *SyncWorker) jobContext(jobID uuid.UUID) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(w.ctx)
+
+	w.cancelMu.Lock()
+	w.jobCancels[jobID] = cancel
+	w.cancelMu.Unlock()
+
+	go w.watchCancelRequest(ctx, jobID, cancel)
+
+	return ctx, func() {
+		w.cancelMu.Lock()
+		delete(w.jobCancels, jobID)
+		w.cancelMu.Unlock()
+		cancel(nil)
+	}
+}
+
+// watchCancelRequest polls the job's cancel flag until ctx is done and cancels the job once set
+func (w *SyncWorker) watchCancelRequest(ctx context.Context, jobID uuid.UUID, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(cancelPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if requested, err := w.syncJobRepo.IsCancelRequested(ctx, jobID); err == nil && requested {
+				cancel(ErrSyncCancelled)
+				return
+			}
+		}
+	}
+}
+
+// isCancelled reports whether the job context was cancelled by a user (not worker shutdown)
+func isCancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrSyncCancelled)
 }
 
 // TriggerSync triggers immediate processing of pending jobs
@@ -127,6 +441,10 @@ func (w *SyncWorker) IsRunning() bool {
 func (w *SyncWorker) run() {
 	defer w.wg.Done()
 
+	// Safety net for jobs whose trigger was missed (e.g. created while the channel was full)
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
 	// Process any pending jobs on start
 	w.processPendingJobs()
 
@@ -137,15 +455,47 @@ func (w *SyncWorker) run() {
 		case <-w.triggerCh:
 			// Triggered - process immediately
 			w.processPendingJobs()
+		case <-ticker.C:
+			w.processPendingJobs()
 		}
 	}
 }
 
-// processPendingJobs fetches and processes pending sync jobs
+// processPendingJobs claims as many pending jobs per sync mode as that mode has free slots and
+// starts them without waiting for them, so incremental syncs keep being picked up while a long
+// full sync runs. Slots are taken before claiming, so a claimed job never waits for one
 func (w *SyncWorker) processPendingJobs() {
-	jobs, err := w.syncJobRepo.GetPendingJobs(w.ctx, models.SyncJobTypeDriveSync, w.maxConcurrent)
+	for _, mode := range []models.SyncMode{models.SyncModeFull, models.SyncModeIncremental} {
+		w.claimAndStart(mode)
+	}
+}
+
+// claimAndStart fills the free slots of one sync mode with claimed jobs
+func (w *SyncWorker) claimAndStart(mode models.SyncMode) {
+	slots := w.slotsFor(mode)
+	free := 0
+reserve:
+	for {
+		select {
+		case slots <- struct{}{}:
+			free++
+		default:
+			break reserve
+		}
+	}
+	if free == 0 {
+		return
+	}
+
+	jobs, err := w.syncJobRepo.ClaimPendingSyncJobs(w.ctx, mode, free)
+	// Give back the slots no job was claimed for
+	for i := len(jobs); i < free; i++ {
+		<-slots
+	}
 	if err != nil {
-		logger.SyncError("fetch_pending_jobs_failed", "Error fetching pending jobs", err, nil)
+		logger.SyncError("fetch_pending_jobs_failed", "Error fetching pending jobs", err, map[string]interface{}{
+			"mode": string(mode),
+		})
 		return
 	}
 
@@ -155,30 +505,26 @@ func (w *SyncWorker) processPendingJobs() {
 
 	logger.Sync("processing_jobs", "Processing sync jobs", map[string]interface{}{
 		"job_count": len(jobs),
+		"mode":      string(mode),
 	})
 
-	var jobWg sync.WaitGroup
-	sem := make(chan struct{}, w.maxConcurrent)
-
 	for _, job := range jobs {
-		sem <- struct{}{}
-		jobWg.Add(1)
-
+		w.wg.Add(1) // Stop waits for running jobs
 		go func(j models.SyncJob) {
-			defer jobWg.Done()
-			defer func() { <-sem }()
-
-			w.processJob(j)
+			defer w.wg.Done()
+			w.processJob(j, mode, func() { <-slots })
 		}(job)
 	}
-
-	jobWg.Wait()
 }
 
-// processJob processes a single sync job
-func (w *SyncWorker) processJob(job models.SyncJob) {
-	ctx := w.ctx
+// processJob processes a single sync job claimed for claimedMode, holding that mode's slot
+// (released through release)
+func (w *SyncWorker) processJob(job models.SyncJob, claimedMode models.SyncMode, release func()) {
+	defer func() { release() }()
+
 	jobID := job.ID
+	ctx, done := w.jobContext(jobID)
+	defer done()
 
 	logger.Sync("job_started", "Sync job started", map[string]interface{}{
 		"job_id": jobID.String(),
@@ -198,18 +544,22 @@ func (w *SyncWorker) processJob(job models.SyncJob) {
 		return
 	}
 
+	// A cancel that lands after the last ctx check (e.g. during completion writes) still ends as cancelled
+	defer func() {
+		if !isCancelled(ctx) {
+			return
+		}
+		if current, err := w.syncJobRepo.GetByID(context.Background(), jobID); err == nil && current.Status == models.SyncJobStatusRunning {
+			w.markCancelled(jobID, metadata.SharedFolderID, current.ProcessedItems, current.FailedItems, nil)
+		}
+	}()
+
 	logger.Sync("job_metadata_parsed", "Job metadata parsed", map[string]interface{}{
 		"job_id":           jobID.String(),
 		"shared_folder_id": metadata.SharedFolderID.String(),
 	})
 
-	// Update job status to running
-	if err := w.syncJobRepo.UpdateStatus(ctx, jobID, models.SyncJobStatusRunning); err != nil {
-		logger.SyncError("update_status_failed", "Failed to update job status", err, map[string]interface{}{
-			"job_id": jobID.String(),
-		})
-		return
-	}
+	// The job was already marked running when it was claimed
 
 	// Get shared folder
 	folder, err := w.sharedFolderRepo.GetByID(ctx, metadata.SharedFolderID)
@@ -222,6 +572,9 @@ func (w *SyncWorker) processJob(job models.SyncJob) {
 		return
 	}
 
+	// Drive calls of this job count against the token owner's shared rate limit
+	ctx = googledrive.WithAccount(ctx, folder.TokenOwnerID)
+
 	logger.Sync("folder_loaded", "Shared folder loaded", map[string]interface{}{
 		"job_id":            jobID.String(),
 		"folder_id":         folder.ID.String(),
@@ -236,21 +589,25 @@ func (w *SyncWorker) processJob(job models.SyncJob) {
 		"jobId":    jobID.String(),
 		"folderId": folder.ID.String(),
 		"status":   "running",
+		"dryRun":   metadata.DryRun,
 	})
 
-	// Log activity: sync started
+	// A dry run leaves the folder's status and activity log alone until it reports its result
 	isFirstSync := folder.LastSyncedAt == nil
-	w.logActivity(ctx, folder.ID, models.ActivitySyncStarted,
-		fmt.Sprintf("เริ่มซิงค์โฟลเดอร์ %s", folder.DriveFolderName),
-		&models.ActivityDetails{
-			JobID:         jobID.String(),
-			FolderName:    folder.DriveFolderName,
-			DriveFolderID: folder.DriveFolderID,
-			IsIncremental: !isFirstSync && folder.PageToken != "",
-		}, nil)
+	if !metadata.DryRun {
+		// Log activity: sync started
+		w.logActivity(ctx, folder.ID, models.ActivitySyncStarted,
+			fmt.Sprintf("เริ่มซิงค์โฟลเดอร์ %s", folder.DriveFolderName),
+			&models.ActivityDetails{
+				JobID:         jobID.String(),
+				FolderName:    folder.DriveFolderName,
+				DriveFolderID: folder.DriveFolderID,
+				IsIncremental: !isFirstSync && folder.PageToken != "",
+			}, nil)
 
-	// Update folder sync status
-	w.sharedFolderRepo.UpdateSyncStatus(ctx, folder.ID, models.SyncStatusSyncing, "")
+		// Update folder sync status
+		w.sharedFolderRepo.UpdateSyncStatus(ctx, folder.ID, models.SyncStatusSyncing, "")
+	}
 
 	// Check if folder has valid tokens
 	if folder.DriveRefreshToken == "" {
@@ -308,7 +665,7 @@ func (w *SyncWorker) processJob(job models.SyncJob) {
 			w.sharedFolderRepo.UpdateSyncStatus(ctx, folder.ID, models.SyncStatusError, "Google token expired - please reconnect")
 		}
 
-		w.failJob(ctx, jobID, &folder.ID, fmt.Sprintf("Failed to get drive service: %v", err))
+		w.failJobWithError(ctx, jobID, &folder.ID, fmt.Sprintf("Failed to get drive service: %v", err), err)
 		return
 	}
 
@@ -317,12 +674,43 @@ func (w *SyncWorker) processJob(job models.SyncJob) {
 		"folder_id": folder.ID.String(),
 	})
 
+	// A folder un-shared from the token owner fails every call with 403/404 - no point syncing
+	if _, err := w.driveClient.GetFile(ctx, srv, folder.DriveFolderID); err != nil && googledrive.IsAccessDenied(err) {
+		w.markAccessRevoked(ctx, jobID, folder, err)
+		return
+	}
+
 	// Fetch and update folder metadata (name, description)
-	w.updateFolderMetadata(ctx, folder, srv)
+	if !metadata.DryRun {
+		w.updateFolderMetadata(ctx, folder, srv)
+	}
 
 	// Decide: Incremental sync or Full sync
 	// Use LastSyncedAt to determine if this is first sync (not PageToken, which may be set by webhook registration)
-	if !isFirstSync && folder.PageToken != "" {
+	mode := models.SyncModeFull
+	if !isFirstSync && folder.PageToken != "" && !metadata.ForceFull {
+		mode = models.SyncModeIncremental
+	}
+
+	if mode != claimedMode {
+		// The folder's sync state changed since the claim (e.g. its first sync just finished):
+		// trade the slot for one of the mode actually run
+		release()
+		if release = w.acquireSlot(jobID, mode); release == nil {
+			release = func() {}
+			// Worker is stopping - leave the job for the next start
+			w.syncJobRepo.UpdateStatus(context.Background(), jobID, models.SyncJobStatusPending)
+			w.sharedFolderRepo.UpdateSyncStatus(context.Background(), folder.ID, models.SyncStatusIdle, "")
+			return
+		}
+	}
+
+	if metadata.DryRun {
+		w.processDryRun(ctx, job, folder, srv, mode)
+		return
+	}
+
+	if mode == models.SyncModeIncremental {
 		logger.Sync("sync_mode", "Starting incremental sync", map[string]interface{}{
 			"job_id":      jobID.String(),
 			"folder_id":   folder.ID.String(),
@@ -433,7 +821,7 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 		"folder_name": folder.DriveFolderName,
 	})
 
-	changes, newPageToken, err := w.driveClient.GetChanges(ctx, srv, folder.PageToken)
+	changes, newPageToken, hasMore, err := w.driveClient.GetChanges(ctx, srv, folder.PageToken, w.maxChangesPerJob)
 	if err != nil {
 		logger.SyncError("get_changes_failed", "Failed to get changes", err, map[string]interface{}{
 			"job_id":    jobID.String(),
@@ -467,7 +855,7 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 
 			// Update folder status with error and fail the job
 			w.sharedFolderRepo.UpdateSyncStatus(ctx, folder.ID, models.SyncStatusError, "Google token expired - please reconnect")
-			w.failJob(ctx, jobID, &folder.ID, fmt.Sprintf("Failed to get changes: %v", err))
+			w.failJobWithError(ctx, jobID, &folder.ID, fmt.Sprintf("Failed to get changes: %v", err), err)
 			return
 		}
 
@@ -480,8 +868,14 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 	logger.Sync("changes_found", "Found changes", map[string]interface{}{
 		"job_id":       jobID.String(),
 		"change_count": len(changes),
+		"has_more":     hasMore,
 	})
 
+	// More changes remain beyond the per-job cap - queue a follow-up job once this one finishes
+	if hasMore {
+		defer w.queueFollowUpSync(ctx, job, folder)
+	}
+
 	if len(changes) == 0 {
 		w.sharedFolderRepo.Update(ctx, folder.ID, &models.SharedFolder{PageToken: newPageToken})
 
@@ -496,6 +890,8 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 			UpdatedAt:      now,
 		})
 
+		w.recordSummary(ctx, jobID, folder.ID, models.SyncModeIncremental, duration, 0, 0, 0, 0)
+
 		// Update folder status
 		w.sharedFolderRepo.UpdateSyncStatus(ctx, folder.ID, models.SyncStatusIdle, "")
 
@@ -529,6 +925,12 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 
 	var totalProcessed, totalNew, totalUpdated, totalDeleted, totalFailed int
 
+	// Scope checks and folder paths are cached per Drive folder for the whole change list
+	scope := newChangeScope(w.driveClient, srv, folder.DriveFolderID)
+
+	// New photo IDs are coalesced into photos:added messages of addedBatchSize
+	newPhotoIDs := make([]string, 0, w.addedBatchSize)
+
 	w.syncJobRepo.Update(ctx, jobID, &models.SyncJob{
 		TotalItems: len(changes),
 		UpdatedAt:  time.Now(),
@@ -537,14 +939,22 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 	for i, change := range changes {
 		select {
 		case <-ctx.Done():
-			w.saveIncrementalProgress(ctx, jobID, folder.ID, newPageToken, totalProcessed)
+			// Photos created so far stay, so clients still hear about them
+			w.flushAddedPhotos(context.Background(), folder.ID, &newPhotoIDs)
+			if isCancelled(ctx) {
+				// Keep the folder's page token: the next sync re-reads these changes
+				w.markCancelled(jobID, folder.ID, totalProcessed, totalFailed, nil)
+				return
+			}
+			// Not every change was applied, so the resumed job re-reads them from the old token
+			w.saveIncrementalProgress(context.Background(), jobID, totalProcessed)
 			return
 		default:
 		}
 
 		if change.Removed || change.File == nil {
 			if change.FileId != "" {
-				existingPhoto, _ := w.photoRepo.GetByDriveFileID(ctx, change.FileId)
+				existingPhoto, _ := w.photoRepo.GetByDriveFileID(ctx, folder.ID, change.FileId)
 				if existingPhoto != nil {
 					w.photoRepo.Delete(ctx, existingPhoto.ID)
 					totalDeleted++
@@ -609,7 +1019,7 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 			}
 
 			// Check if this folder is within our root folder (for rename or restore handling)
-			if w.isWithinRootFolder(ctx, srv, file.Id, folder.DriveFolderID) || file.Id == folder.DriveFolderID {
+			if scope.isWithin(ctx, file.Id) {
 				// Track if this was a restore operation
 				wasRestored := false
 
@@ -644,8 +1054,7 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 				}
 
 				// Get the new folder path
-				newFolderPath, err := w.driveClient.GetFolderPath(ctx, srv, file.Id, folder.DriveFolderID)
-				if err == nil && newFolderPath != "" {
+				if newFolderPath := scope.folderPath(ctx, file.Id); newFolderPath != "" {
 					// Update all photos with this folder ID to have the new path
 					updatedCount, err := w.photoRepo.UpdateFolderPath(ctx, file.Id, newFolderPath)
 					if err == nil && updatedCount > 0 {
@@ -712,7 +1121,9 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 			continue
 		}
 
-		if file.MimeType == "" || !isImageMimeType(file.MimeType) {
+		// Non-media changes (documents, spreadsheets...) are dropped before any lookup
+		if file.MimeType == "" || !w.driveClient.IsSupportedMimeType(file.MimeType) {
+			scope.skippedNonMedia++
 			totalProcessed++
 			continue
 		}
@@ -723,7 +1134,7 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 			wasUpdated, err := w.photoRepo.SetTrashedByDriveFileID(ctx, file.Id, true)
 			if err == nil && wasUpdated {
 				totalUpdated++
-				logger.Sync("photo_soft_deleted", "Marked photo as trashed", map[string]interface{}{
+				logger.SyncSampled("photo_soft_deleted", "Marked photo as trashed", map[string]interface{}{
 					"job_id":        jobID.String(),
 					"drive_file_id": file.Id,
 					"file_name":     file.Name,
@@ -748,16 +1159,46 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 			parentID = file.Parents[0]
 		}
 
-		if !w.isWithinRootFolder(ctx, srv, parentID, folder.DriveFolderID) {
+		if within, resolved := scope.lookupWithin(ctx, parentID); !within {
+			// An indexed photo whose file moved out of the folder would otherwise stay behind
+			// with its old path. Only a resolved answer counts: a failed lookup is not a move.
+			if resolved && w.removeMovedOutPhoto(ctx, jobID, folder, file, change) {
+				scope.movedOut++
+				if w.orphanPolicyFor(folder) == models.OrphanPolicyTrash {
+					totalUpdated++ // Counted as an update, like trashing above
+				} else {
+					totalDeleted++
+				}
+			} else {
+				scope.skippedOutside++
+			}
 			totalProcessed++
 			continue
 		}
 
-		existingPhoto, _ := w.photoRepo.GetByDriveFileID(ctx, file.Id)
+		// Files under the folder's exclude paths are neither indexed nor updated
+		folderPath := scope.folderPath(ctx, parentID)
+		if folder.IsPathExcluded(folderPath) {
+			totalProcessed++
+			continue
+		}
+
+		existingPhoto, _ := w.photoRepo.GetByDriveFileID(ctx, folder.ID, file.Id)
 		if existingPhoto != nil {
-			folderPath, _ := w.driveClient.GetFolderPath(ctx, srv, parentID, folder.DriveFolderID)
 			modifiedTime, _ := time.Parse(time.RFC3339, file.ModifiedTime)
 
+			// Path lookup failed (already retried by the Drive client): keep the known path rather
+			// than blanking it - the next change or full sync of the folder corrects a stale one
+			if folderPath == "" {
+				folderPath = existingPhoto.DriveFolderPath
+				logger.SyncWarn("folder_path_unresolved", "Could not resolve folder path, keeping previous path", map[string]interface{}{
+					"job_id":          jobID.String(),
+					"drive_file_id":   file.Id,
+					"drive_folder_id": parentID,
+					"kept_path":       folderPath,
+				})
+			}
+
 			// Track what changed for logging
 			wasRestored := false
 			wasRenamed := existingPhoto.FileName != file.Name
@@ -768,9 +1209,11 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 			// Restore from trash if was trashed
 			if existingPhoto.IsTrashed {
 				wasRestored = true
+				// Struct updates skip false, so the trash flag is cleared explicitly
+				w.photoRepo.RestoreByID(ctx, existingPhoto.ID)
 				existingPhoto.IsTrashed = false
 				existingPhoto.TrashedAt = nil
-				logger.Sync("photo_restored", "Restored photo from trash", map[string]interface{}{
+				logger.SyncSampled("photo_restored", "Restored photo from trash", map[string]interface{}{
 					"job_id":        jobID.String(),
 					"drive_file_id": file.Id,
 					"file_name":     file.Name,
@@ -788,6 +1231,10 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 			}
 
 			// Update photo data
+			if pending := file.ThumbnailLink == ""; pending != existingPhoto.ThumbnailPending {
+				// Struct updates skip false, so write the flag explicitly
+				w.photoRepo.UpdateMetadata(ctx, existingPhoto.ID, map[string]interface{}{"thumbnail_pending": pending})
+			}
 			existingPhoto.FileName = file.Name
 			existingPhoto.ThumbnailURL = file.ThumbnailLink
 			existingPhoto.WebViewURL = file.WebViewLink
@@ -834,7 +1281,6 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 				// Only significant changes (rename, move) are logged
 			}
 		} else {
-			folderPath, _ := w.driveClient.GetFolderPath(ctx, srv, parentID, folder.DriveFolderID)
 			createdTime, _ := time.Parse(time.RFC3339, file.CreatedTime)
 			modifiedTime, _ := time.Parse(time.RFC3339, file.ModifiedTime)
 
@@ -846,14 +1292,24 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 				DriveFolderPath: folderPath,
 				FileName:        file.Name,
 				MimeType:        file.MimeType,
+				MediaType:       models.MediaTypeFromMime(file.MimeType),
 				FileSize:        file.Size,
 				ThumbnailURL:    file.ThumbnailLink,
 				WebViewURL:      file.WebViewLink,
 				DriveCreatedAt:  &createdTime,
 				DriveModifiedAt: &modifiedTime,
-				FaceStatus:      models.FaceStatusPending,
+				FaceStatus:      initialFaceStatus(folder, models.MediaTypeFromMime(file.MimeType)),
 				CreatedAt:       time.Now(),
 				UpdatedAt:       time.Now(),
+
+				ThumbnailPending: file.ThumbnailLink == "",
+			}
+			if file.ImageMediaMetadata != nil {
+				photo.Width = int(file.ImageMediaMetadata.Width)
+				photo.Height = int(file.ImageMediaMetadata.Height)
+				photo.TakenAt = googledrive.ImageTakenAt(file.ImageMediaMetadata)
+				photo.CameraMake = file.ImageMediaMetadata.CameraMake
+				photo.CameraModel = file.ImageMediaMetadata.CameraModel
 			}
 
 			if err := w.photoRepo.Create(ctx, photo); err != nil {
@@ -864,10 +1320,10 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 				totalFailed++
 			} else {
 				totalNew++
-				w.broadcastToFolderUsers(ctx, folder.ID, "photos:added", map[string]interface{}{
-					"count":    1,
-					"photoIds": []string{photo.ID.String()},
-				})
+				newPhotoIDs = append(newPhotoIDs, photo.ID.String())
+				if len(newPhotoIDs) >= w.addedBatchSize {
+					w.flushAddedPhotos(ctx, folder.ID, &newPhotoIDs)
+				}
 
 				// Log activity: photo added
 				w.logActivity(ctx, folder.ID, models.ActivityPhotosAdded,
@@ -894,9 +1350,16 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 				"failedFiles":    totalFailed,
 				"isIncremental":  true,
 			})
+			logger.SyncSampled("sync_progress", "Incremental sync progress", map[string]interface{}{
+				"job_id":    jobID.String(),
+				"processed": totalProcessed,
+				"total":     len(changes),
+			})
 		}
 	}
 
+	w.flushAddedPhotos(ctx, folder.ID, &newPhotoIDs)
+
 	// Save new page token
 	w.sharedFolderRepo.Update(ctx, folder.ID, &models.SharedFolder{PageToken: newPageToken})
 
@@ -940,6 +1403,11 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 		"failed_files":  totalFailed,
 	})
 
+	classified := scope.stats()
+	classified["job_id"] = jobID.String()
+	classified["total_changes"] = len(changes)
+	logger.Sync("incremental_changes_classified", "Incremental sync change classification", classified)
+
 	// Log activity: sync completed
 	w.logActivity(ctx, folder.ID, models.ActivitySyncCompleted,
 		fmt.Sprintf("ซิงค์สำเร็จ - เพิ่ม %d, อัพเดท %d, ลบ %d รายการ", totalNew, totalUpdated, totalDeleted),
@@ -953,6 +1421,8 @@ func (w *SyncWorker) processIncrementalSync(ctx context.Context, job models.Sync
 			TotalDeleted:  totalDeleted,
 			TotalFailed:   totalFailed,
 		}, nil)
+
+	w.recordSummary(ctx, jobID, folder.ID, models.SyncModeIncremental, duration, totalNew, totalUpdated, totalDeleted, totalFailed)
 }
 
 // processFullSync does a full sync of all images
@@ -977,35 +1447,28 @@ func (w *SyncWorker) processFullSync(ctx context.Context, job models.SyncJob, fo
 	totalUpdated := 0
 	totalDeleted := 0
 
-	// Step 1: List ALL folders first for path mapping (optimization)
-	allFolders, err := w.driveClient.ListAllFoldersRecursive(ctx, srv, folder.DriveFolderID)
-	if err != nil {
-		logger.SyncError("list_folders_failed", "Failed to list folders (will use API per photo)", err, map[string]interface{}{
-			"job_id":    jobID.String(),
-			"folder_id": folder.ID.String(),
-		})
-		allFolders = nil
-	} else {
-		logger.Sync("folders_listed", "Listed folders from Drive", map[string]interface{}{
-			"job_id":       jobID.String(),
-			"folder_count": len(allFolders),
-		})
-	}
-
-	// Step 2: Build folder path map (O(1) lookup)
-	var folderPathMap map[string]string
-	if allFolders != nil {
-		folderPathMap = w.driveClient.BuildFolderPathMap(allFolders, folder.DriveFolderID)
-	}
+	// The listing snapshot is only kept while the folder's sync waits to resume (after a restart
+	// or a cancel)
+	keepSnapshot := false
+	defer func() {
+		if !keepSnapshot {
+			w.syncJobRepo.DeleteSnapshot(context.Background(), folder.ID)
+		}
+	}()
 
-	// Step 3: List all images
-	files, err := w.driveClient.ListAllImagesRecursive(ctx, srv, folder.DriveFolderID)
+	// Steps 1-3: folder path map and file list (reused from the snapshot when resuming)
+	folderPathMap, files, err := w.loadFullSyncListing(ctx, job, folder, srv, metadata.LastProcessedID != "")
 	if err != nil {
 		logger.SyncError("list_images_failed", "Failed to list images", err, map[string]interface{}{
 			"job_id":    jobID.String(),
 			"folder_id": folder.ID.String(),
 		})
 
+		if googledrive.IsAccessDenied(err) {
+			w.markAccessRevoked(ctx, jobID, folder, err)
+			return
+		}
+
 		// Check if it's a token error and notify users
 		errStr := err.Error()
 		isTokenError := strings.Contains(errStr, "401") ||
@@ -1035,7 +1498,7 @@ func (w *SyncWorker) processFullSync(ctx context.Context, job models.SyncJob, fo
 			w.sharedFolderRepo.UpdateSyncStatus(ctx, folder.ID, models.SyncStatusError, "Google token expired - please reconnect")
 		}
 
-		w.failJob(ctx, jobID, &folder.ID, fmt.Sprintf("Failed to list files: %v", err))
+		w.failJobWithError(ctx, jobID, &folder.ID, fmt.Sprintf("Failed to list files: %v", err), err)
 		return
 	}
 	logger.Sync("images_listed", "Listed images from Drive", map[string]interface{}{
@@ -1043,6 +1506,7 @@ func (w *SyncWorker) processFullSync(ctx context.Context, job models.SyncJob, fo
 		"image_count": len(files),
 	})
 
+	// Excluded files are skipped below but still listed here, so orphan cleanup keeps their existing photos
 	driveFileIDs := make([]string, 0, len(files))
 	for _, file := range files {
 		driveFileIDs = append(driveFileIDs, file.ID)
@@ -1056,138 +1520,176 @@ func (w *SyncWorker) processFullSync(ctx context.Context, job models.SyncJob, fo
 
 	startIndex := 0
 	if metadata.LastProcessedID != "" {
+		found := false
 		for i, file := range files {
 			if file.ID == metadata.LastProcessedID {
 				startIndex = i + 1
+				found = true
 				break
 			}
 		}
+		// The checkpoint file is not in this listing (re-listed after the snapshot expired or the
+		// file was removed): start over, so progress counts only files of this listing
+		if !found {
+			logger.SyncWarn("checkpoint_not_found", "Checkpoint file not in listing, restarting full sync from the first file", map[string]interface{}{
+				"job_id":            jobID.String(),
+				"last_processed_id": metadata.LastProcessedID,
+				"processed_files":   totalProcessed,
+			})
+			metadata.LastProcessedID = ""
+			totalProcessed = 0
+			totalFailed = 0
+		}
 	}
 
 	photoBatch := make([]*models.Photo, 0, w.batchSize)
 	newPhotoIDs := make([]string, 0, w.batchSize)
 	lastBroadcastPercent := 0
+	eta := newSyncETA(time.Now(), totalProcessed)
 
-	for i := startIndex; i < len(files); i++ {
-		file := files[i]
+	// Time spent checking files vs inserting new photos, logged on completion to compare
+	// SYNC_FILE_CONCURRENCY settings on real folders
+	var checkDuration, writeDuration time.Duration
 
-		select {
-		case <-ctx.Done():
-			if len(photoBatch) > 0 {
-				w.flushPhotoBatch(ctx, photoBatch, &totalNew, &totalFailed)
-			}
-			metadata.LastProcessedID = file.ID
-			w.saveProgress(ctx, jobID, totalProcessed, totalFailed, metadata)
-			return
-		default:
-		}
+	// flushBatch inserts the queued new photos and announces them
+	flushBatch := func() {
+		writeStart := time.Now()
+		w.flushPhotoBatch(ctx, photoBatch, &totalNew, &totalFailed)
+		writeDuration += time.Since(writeStart)
 
-		// Get folder path from map (O(1)) or fallback to API
-		var folderPath string
-		if folderPathMap != nil {
-			folderPath = folderPathMap[file.ParentID]
-		} else {
-			folderPath, _ = w.driveClient.GetFolderPath(ctx, srv, file.ParentID, folder.DriveFolderID)
+		if len(newPhotoIDs) > 0 {
+			w.broadcastToFolderUsers(ctx, folder.ID, "photos:added", map[string]interface{}{
+				"count":    len(newPhotoIDs),
+				"photoIds": newPhotoIDs,
+			})
 		}
 
-		existingPhoto, _ := w.photoRepo.GetByDriveFileID(ctx, file.ID)
-		if existingPhoto != nil {
-			needsUpdate := file.ModifiedTime.After(existingPhoto.UpdatedAt) ||
-				existingPhoto.DriveFolderID != file.ParentID ||
-				existingPhoto.DriveFolderPath != folderPath
-
-			if needsUpdate {
-				existingPhoto.FileName = file.Name
-				existingPhoto.ThumbnailURL = file.ThumbnailURL
-				existingPhoto.WebViewURL = file.WebViewURL
-				existingPhoto.DriveFolderID = file.ParentID
-				existingPhoto.DriveFolderPath = folderPath
-				existingPhoto.DriveModifiedAt = &file.ModifiedTime
-				existingPhoto.UpdatedAt = time.Now()
-				w.photoRepo.Update(ctx, existingPhoto.ID, existingPhoto)
-				totalUpdated++
-			}
-			totalProcessed++
-		} else {
-			photo := &models.Photo{
-				ID:              uuid.New(),
-				SharedFolderID:  folder.ID,
-				DriveFileID:     file.ID,
-				DriveFolderID:   file.ParentID,
-				DriveFolderPath: folderPath,
-				FileName:        file.Name,
-				MimeType:        file.MimeType,
-				FileSize:        file.Size,
-				ThumbnailURL:    file.ThumbnailURL,
-				WebViewURL:      file.WebViewURL,
-				DriveCreatedAt:  &file.CreatedTime,
-				DriveModifiedAt: &file.ModifiedTime,
-				FaceStatus:      models.FaceStatusPending,
-				CreatedAt:       time.Now(),
-				UpdatedAt:       time.Now(),
+		photoBatch = make([]*models.Photo, 0, w.batchSize)
+		newPhotoIDs = make([]string, 0, w.batchSize)
+	}
+
+	// Files are checked in chunks of batchSize by up to fileConcurrency goroutines, then merged
+	// in listing order so batches, progress and checkpoints advance exactly as in a sequential run
+	for chunkStart := startIndex; chunkStart < len(files); chunkStart += w.batchSize {
+		chunkEnd := min(chunkStart+w.batchSize, len(files))
+		checkStart := time.Now()
+		results := w.processFullSyncFiles(ctx, folder, srv, folderPathMap, files[chunkStart:chunkEnd])
+		checkDuration += time.Since(checkStart)
+
+		for j, result := range results {
+			i := chunkStart + j
+			file := files[i]
+
+			if !result.done {
+				// ctx is done, so checkpoint writes use a fresh context
+				// Later files of the chunk are dropped and redone on resume (updates are idempotent)
+				if len(photoBatch) > 0 {
+					w.flushPhotoBatch(context.Background(), photoBatch, &totalNew, &totalFailed)
+				}
+				if i > 0 {
+					metadata.LastProcessedID = files[i-1].ID // Resume after the last handled file
+				}
+				keepSnapshot = true
+				if isCancelled(ctx) {
+					w.markCancelled(jobID, folder.ID, totalProcessed, totalFailed, &metadata)
+					return
+				}
+				w.saveProgress(context.Background(), jobID, totalProcessed, totalFailed, metadata)
+				return
 			}
 
-			photoBatch = append(photoBatch, photo)
-			newPhotoIDs = append(newPhotoIDs, photo.ID.String())
+			totalUpdated += result.updated
+			if result.newPhoto != nil {
+				photoBatch = append(photoBatch, result.newPhoto)
+				newPhotoIDs = append(newPhotoIDs, result.newPhoto.ID.String())
+			}
 			totalProcessed++
-		}
 
-		if len(photoBatch) >= w.batchSize {
-			w.flushPhotoBatch(ctx, photoBatch, &totalNew, &totalFailed)
+			if len(photoBatch) >= w.batchSize {
+				flushBatch()
+			}
 
-			if len(newPhotoIDs) > 0 {
-				w.broadcastToFolderUsers(ctx, folder.ID, "photos:added", map[string]interface{}{
-					"count":    len(newPhotoIDs),
-					"photoIds": newPhotoIDs,
+			// Send progress every 5%
+			currentPercent := 0
+			if totalItems > 0 {
+				currentPercent = (totalProcessed * 100) / totalItems
+			}
+			if currentPercent >= lastBroadcastPercent+5 || i == totalItems-1 {
+				lastBroadcastPercent = currentPercent
+				w.syncJobRepo.UpdateProgress(ctx, jobID, totalProcessed, totalFailed)
+
+				now := time.Now()
+				eta.add(now, totalProcessed)
+				progress := map[string]interface{}{
+					"jobId":          jobID.String(),
+					"folderId":       folder.ID.String(),
+					"processedFiles": totalProcessed,
+					"totalFiles":     totalItems,
+					"percent":        currentPercent,
+					"newFiles":       totalNew,
+					"updatedFiles":   totalUpdated,
+					"failedFiles":    totalFailed,
+					"elapsedSeconds": int(now.Sub(startTime).Seconds()),
+				}
+				// Left out until this run has a processing rate
+				if etaSeconds, ok := eta.seconds(totalItems); ok {
+					progress["etaSeconds"] = etaSeconds
+				}
+				w.broadcastToFolderUsers(ctx, folder.ID, "sync:progress", progress)
+				logger.SyncSampled("sync_progress", "Full sync progress", map[string]interface{}{
+					"job_id":    jobID.String(),
+					"processed": totalProcessed,
+					"total":     totalItems,
+					"percent":   currentPercent,
 				})
 			}
 
-			photoBatch = make([]*models.Photo, 0, w.batchSize)
-			newPhotoIDs = make([]string, 0, w.batchSize)
-		}
-
-		// Send progress every 5%
-		currentPercent := 0
-		if totalItems > 0 {
-			currentPercent = (totalProcessed * 100) / totalItems
-		}
-		if currentPercent >= lastBroadcastPercent+5 || i == totalItems-1 {
-			lastBroadcastPercent = currentPercent
-			w.syncJobRepo.UpdateProgress(ctx, jobID, totalProcessed, totalFailed)
-
-			w.broadcastToFolderUsers(ctx, folder.ID, "sync:progress", map[string]interface{}{
-				"jobId":          jobID.String(),
-				"folderId":       folder.ID.String(),
-				"processedFiles": totalProcessed,
-				"totalFiles":     totalItems,
-				"percent":        currentPercent,
-				"newFiles":       totalNew,
-				"updatedFiles":   totalUpdated,
-				"failedFiles":    totalFailed,
-			})
-		}
-
-		if (i+1)%w.checkpointEvery == 0 {
-			metadata.LastProcessedID = file.ID
-			metadata.ProcessedFiles = totalProcessed
-			w.saveCheckpoint(ctx, jobID, totalProcessed, totalFailed, metadata)
+			if (i+1)%w.checkpointEvery == 0 {
+				// New photos of the files up to the checkpoint are stored first: a resume after a
+				// crash starts after the checkpoint and would never insert them
+				if len(photoBatch) > 0 {
+					flushBatch()
+				}
+				metadata.LastProcessedID = file.ID
+				metadata.ProcessedFiles = totalProcessed
+				w.saveCheckpoint(ctx, jobID, totalProcessed, totalFailed, metadata)
+			}
 		}
 	}
 
 	if len(photoBatch) > 0 {
-		w.flushPhotoBatch(ctx, photoBatch, &totalNew, &totalFailed)
+		flushBatch()
+	}
 
-		if len(newPhotoIDs) > 0 {
-			w.broadcastToFolderUsers(ctx, folder.ID, "photos:added", map[string]interface{}{
-				"count":    len(newPhotoIDs),
-				"photoIds": newPhotoIDs,
+	// Cleanup orphaned photos (trash policy keeps them restorable until purged)
+	if w.orphanPolicyFor(folder) == models.OrphanPolicyTrash {
+		trashedCount, err := w.photoRepo.TrashNotInDriveIDsForFolder(ctx, folder.ID, driveFileIDs)
+		if err != nil {
+			logger.SyncError("cleanup_orphaned_failed", "Failed to trash orphaned photos", err, map[string]interface{}{
+				"job_id":    jobID.String(),
+				"folder_id": folder.ID.String(),
+			})
+		} else if trashedCount > 0 {
+			totalUpdated += int(trashedCount) // Counted as updates, like trashing in incremental sync
+			logger.Sync("orphaned_photos_trashed", "Moved orphaned photos to trash", map[string]interface{}{
+				"job_id":        jobID.String(),
+				"folder_id":     folder.ID.String(),
+				"trashed_count": trashedCount,
 			})
-		}
-	}
 
-	// Cleanup orphaned photos
-	deletedCount, err := w.photoRepo.DeleteNotInDriveIDsForFolder(ctx, folder.ID, driveFileIDs)
-	if err != nil {
+			w.broadcastToFolderUsers(ctx, folder.ID, "photos:trashed", map[string]interface{}{
+				"count":  trashedCount,
+				"reason": "cleanup_orphaned",
+			})
+
+			w.logActivity(ctx, folder.ID, models.ActivityPhotosTrashed,
+				fmt.Sprintf("รูปภาพ %d รายการไม่พบใน Google Drive ถูกย้ายไปถังขยะ", trashedCount),
+				&models.ActivityDetails{
+					JobID: jobID.String(),
+					Count: int(trashedCount),
+				}, nil)
+		}
+	} else if deletedCount, err := w.photoRepo.DeleteNotInDriveIDsForFolder(ctx, folder.ID, driveFileIDs); err != nil {
 		logger.SyncError("cleanup_orphaned_failed", "Failed to cleanup orphaned photos", err, map[string]interface{}{
 			"job_id":    jobID.String(),
 			"folder_id": folder.ID.String(),
@@ -1254,6 +1756,10 @@ func (w *SyncWorker) processFullSync(ctx context.Context, job models.SyncJob, fo
 		"updated_files":   totalUpdated,
 		"deleted_files":   totalDeleted,
 		"failed_files":    totalFailed,
+
+		"file_concurrency": w.fileConcurrency,
+		"check_ms":         checkDuration.Milliseconds(),
+		"write_ms":         writeDuration.Milliseconds(),
 	})
 
 	// Log activity: sync completed
@@ -1269,6 +1775,35 @@ func (w *SyncWorker) processFullSync(ctx context.Context, job models.SyncJob, fo
 			TotalDeleted:  totalDeleted,
 			TotalFailed:   totalFailed,
 		}, nil)
+
+	w.recordSummary(ctx, jobID, folder.ID, models.SyncModeFull, duration, totalNew, totalUpdated, totalDeleted, totalFailed)
+}
+
+// recordSummary persists a sync summary row for analytics (failures are logged, not fatal)
+func (w *SyncWorker) recordSummary(ctx context.Context, jobID, folderID uuid.UUID, mode models.SyncMode, duration time.Duration, newCount, updated, deleted, failed int) {
+	if w.syncSummaryRepo == nil {
+		return
+	}
+
+	summary := &models.SyncSummary{
+		ID:             uuid.New(),
+		JobID:          jobID,
+		SharedFolderID: folderID,
+		Mode:           mode,
+		DurationMs:     duration.Milliseconds(),
+		NewCount:       newCount,
+		UpdatedCount:   updated,
+		DeletedCount:   deleted,
+		FailedCount:    failed,
+		CompletedAt:    time.Now(),
+		CreatedAt:      time.Now(),
+	}
+	if err := w.syncSummaryRepo.Create(ctx, summary); err != nil {
+		logger.SyncError("sync_summary_create_failed", "Failed to save sync summary", err, map[string]interface{}{
+			"job_id":    jobID.String(),
+			"folder_id": folderID.String(),
+		})
+	}
 }
 
 // broadcastToFolderUsers broadcasts a message to all users with access to a folder
@@ -1301,6 +1836,18 @@ func (w *SyncWorker) flushPhotoBatch(ctx context.Context, photos []*models.Photo
 
 // failJob marks a job as failed
 func (w *SyncWorker) failJob(ctx context.Context, jobID uuid.UUID, folderID *uuid.UUID, errMsg string) {
+	w.failJobWithError(ctx, jobID, folderID, errMsg, nil)
+}
+
+// failJobWithError marks a job as failed and stores the classified cause (a Drive API error, if
+// any) on the folder so the UI can tell a reconnect from a quota or Google outage
+func (w *SyncWorker) failJobWithError(ctx context.Context, jobID uuid.UUID, folderID *uuid.UUID, errMsg string, cause error) {
+	// Drive/DB calls fail once a job is cancelled - that is a cancellation, not a failure
+	if isCancelled(ctx) && folderID != nil {
+		w.markCancelled(jobID, *folderID, 0, 0, nil)
+		return
+	}
+
 	logData := map[string]interface{}{
 		"job_id": jobID.String(),
 		"error":  errMsg,
@@ -1319,13 +1866,19 @@ func (w *SyncWorker) failJob(ctx context.Context, jobID uuid.UUID, folderID *uui
 	})
 
 	if folderID != nil {
-		w.sharedFolderRepo.UpdateSyncStatus(ctx, *folderID, models.SyncStatusError, errMsg)
+		code := models.SyncErrorUnknown
+		var detail *models.SyncErrorDetail
+		if cause != nil {
+			code, detail = googledrive.ClassifyError(cause)
+		}
+		w.sharedFolderRepo.UpdateSyncError(ctx, *folderID, models.SyncStatusError, errMsg, code, detail)
 
 		w.broadcastToFolderUsers(ctx, *folderID, "sync:failed", map[string]interface{}{
-			"jobId":    jobID.String(),
-			"folderId": folderID.String(),
-			"status":   "failed",
-			"message":  errMsg,
+			"jobId":     jobID.String(),
+			"folderId":  folderID.String(),
+			"status":    "failed",
+			"message":   errMsg,
+			"errorCode": string(code),
 		})
 
 		// Log activity: sync failed
@@ -1338,6 +1891,91 @@ func (w *SyncWorker) failJob(ctx context.Context, jobID uuid.UUID, folderID *uui
 	}
 }
 
+// markAccessRevoked fails a job whose folder the token owner can no longer read in Drive.
+// The folder gets SyncStatusAccessRevoked (not error) so users know a reconnect by someone who
+// still has access is needed; retrying with the same token would fail the same way.
+func (w *SyncWorker) markAccessRevoked(ctx context.Context, jobID uuid.UUID, folder *models.SharedFolder, err error) {
+	errMsg := "Token owner lost access to the Drive folder - please reconnect with an account that can access it"
+
+	logger.SyncError("folder_access_revoked", "Token owner lost access to Drive folder", err, map[string]interface{}{
+		"job_id":          jobID.String(),
+		"folder_id":       folder.ID.String(),
+		"drive_folder_id": folder.DriveFolderID,
+		"token_owner_id":  folder.TokenOwnerID.String(),
+	})
+
+	// nil folder: keep the access_revoked status instead of failJob's generic error status
+	w.failJob(ctx, jobID, nil, errMsg)
+	_, detail := googledrive.ClassifyError(err)
+	w.sharedFolderRepo.UpdateSyncError(ctx, folder.ID, models.SyncStatusAccessRevoked, errMsg, models.SyncErrorPermissionDenied, detail)
+
+	w.broadcastToFolderUsers(ctx, folder.ID, "folder:access_revoked", map[string]interface{}{
+		"folderId":     folder.ID.String(),
+		"folderName":   folder.DriveFolderName,
+		"tokenOwnerId": folder.TokenOwnerID.String(),
+		"message":      "เจ้าของ Token ไม่มีสิทธิ์เข้าถึงโฟลเดอร์นี้ใน Google Drive แล้ว กรุณา Reconnect ด้วยบัญชีที่ยังเข้าถึงได้",
+	})
+	w.broadcastToFolderUsers(ctx, folder.ID, "sync:failed", map[string]interface{}{
+		"jobId":    jobID.String(),
+		"folderId": folder.ID.String(),
+		"status":   "failed",
+		"message":  errMsg,
+	})
+
+	w.logActivity(ctx, folder.ID, models.ActivityAccessRevoked,
+		fmt.Sprintf("ไม่มีสิทธิ์เข้าถึงโฟลเดอร์ %s ใน Google Drive - กรุณา Reconnect", folder.DriveFolderName),
+		&models.ActivityDetails{
+			JobID:        jobID.String(),
+			FolderName:   folder.DriveFolderName,
+			ErrorMessage: err.Error(),
+		}, nil)
+}
+
+// markCancelled finishes a job stopped by a user: the checkpoint (if any) is saved so the next
+// sync of the folder can resume, the job becomes cancelled and the folder idle again.
+// Uses a fresh context because the job context is already cancelled.
+func (w *SyncWorker) markCancelled(jobID uuid.UUID, folderID uuid.UUID, processed, failed int, metadata *SyncJobMetadata) {
+	ctx := context.Background()
+	now := time.Now()
+
+	update := &models.SyncJob{
+		Status:      models.SyncJobStatusCancelled,
+		LastError:   ErrSyncCancelled.Error(),
+		CompletedAt: &now,
+		UpdatedAt:   now,
+	}
+	if metadata != nil {
+		metadata.ProcessedFiles = processed
+		metadataJSON, _ := json.Marshal(metadata)
+		update.Metadata = string(metadataJSON)
+		update.ProcessedItems = processed
+		update.FailedItems = failed
+	}
+	w.syncJobRepo.Update(ctx, jobID, update)
+
+	w.sharedFolderRepo.UpdateSyncStatus(ctx, folderID, models.SyncStatusIdle, "")
+
+	logger.Sync("job_cancelled", "Sync job cancelled", map[string]interface{}{
+		"job_id":    jobID.String(),
+		"folder_id": folderID.String(),
+		"processed": processed,
+	})
+
+	w.broadcastToFolderUsers(ctx, folderID, "sync:cancelled", map[string]interface{}{
+		"jobId":     jobID.String(),
+		"folderId":  folderID.String(),
+		"status":    string(models.SyncJobStatusCancelled),
+		"processed": processed,
+	})
+
+	w.logActivity(ctx, folderID, models.ActivitySyncCancelled,
+		"ยกเลิกการซิงค์",
+		&models.ActivityDetails{
+			JobID: jobID.String(),
+			Count: processed,
+		}, nil)
+}
+
 // saveProgress saves progress for resuming
 func (w *SyncWorker) saveProgress(ctx context.Context, jobID uuid.UUID, processed, failed int, metadata SyncJobMetadata) {
 	metadata.ProcessedFiles = processed
@@ -1364,10 +2002,59 @@ func (w *SyncWorker) saveCheckpoint(ctx context.Context, jobID uuid.UUID, proces
 	})
 }
 
-// saveIncrementalProgress saves progress for incremental sync
-func (w *SyncWorker) saveIncrementalProgress(ctx context.Context, jobID uuid.UUID, folderID uuid.UUID, pageToken string, processed int) {
-	w.sharedFolderRepo.Update(ctx, folderID, &models.SharedFolder{PageToken: pageToken})
+// queueFollowUpSync creates another incremental sync job for a folder whose
+// change stream was truncated, continuing from the checkpointed page token.
+// Skipped once ctx is done: a cancelled job wants no follow-up and a job stopped by
+// shutdown is requeued, so it reads the remaining changes itself.
+func (w *SyncWorker) queueFollowUpSync(ctx context.Context, job models.SyncJob, folder *models.SharedFolder) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	metadata := SyncJobMetadata{
+		SharedFolderID: folder.ID,
+		IsIncremental:  true,
+	}
+	metadataJSON, _ := json.Marshal(metadata)
+
+	now := time.Now()
+	followUp := &models.SyncJob{
+		ID:             uuid.New(),
+		UserID:         job.UserID,
+		JobType:        models.SyncJobTypeDriveSync,
+		Status:         models.SyncJobStatusPending,
+		SharedFolderID: &folder.ID,
+		Metadata:       string(metadataJSON),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	created, err := w.syncJobRepo.CreatePendingForFolder(ctx, followUp)
+	if err != nil {
+		logger.SyncError("follow_up_sync_failed", "Failed to queue follow-up sync job", err, map[string]interface{}{
+			"job_id":    job.ID.String(),
+			"folder_id": folder.ID.String(),
+		})
+		return
+	}
+	if !created {
+		// A webhook already queued the next sync, which reads the remaining changes too
+		w.TriggerSync()
+		return
+	}
+
+	logger.Sync("follow_up_sync_queued", "Queued follow-up sync for remaining changes", map[string]interface{}{
+		"job_id":       job.ID.String(),
+		"follow_up_id": followUp.ID.String(),
+		"folder_id":    folder.ID.String(),
+	})
 
+	w.TriggerSync()
+}
+
+// saveIncrementalProgress puts an interrupted incremental sync back to pending.
+// The folder's page token is left alone: it only advances once every change read with it is applied.
+func (w *SyncWorker) saveIncrementalProgress(ctx context.Context, jobID uuid.UUID, processed int) {
 	w.syncJobRepo.Update(ctx, jobID, &models.SyncJob{
 		Status:         models.SyncJobStatusPending,
 		ProcessedItems: processed,
@@ -1375,9 +2062,89 @@ func (w *SyncWorker) saveIncrementalProgress(ctx context.Context, jobID uuid.UUI
 	})
 }
 
-// isImageMimeType checks if the mime type is an image
-func isImageMimeType(mimeType string) bool {
-	return strings.HasPrefix(mimeType, "image/")
+
+// fullSyncSnapshotMaxAge is how long a listing snapshot may be reused; older ones are re-listed
+// so a job that sat pending for long doesn't sync (and clean up orphans against) a stale listing
+const fullSyncSnapshotMaxAge = 24 * time.Hour
+
+// loadFullSyncListing returns the folder path map and ordered file list of a full sync.
+// A resumed job reuses the folder's snapshot saved by the run it resumes (its own earlier run or
+// a cancelled job), so the tree isn't walked again and LastProcessedID and orphan cleanup refer
+// to the same listing.
+func (w *SyncWorker) loadFullSyncListing(ctx context.Context, job models.SyncJob, folder *models.SharedFolder, srv *drive.Service, resuming bool) (map[string]string, []googledrive.DriveFile, error) {
+	jobID := job.ID
+
+	if resuming {
+		if snapshot, err := w.syncJobRepo.GetSnapshot(ctx, folder.ID); err == nil && time.Since(snapshot.CreatedAt) < fullSyncSnapshotMaxAge {
+			var folderPathMap map[string]string
+			var files []googledrive.DriveFile
+			if json.Unmarshal([]byte(snapshot.FolderPaths), &folderPathMap) == nil && json.Unmarshal([]byte(snapshot.Files), &files) == nil {
+				logger.Sync("listing_snapshot_reused", "Resuming full sync from saved listing", map[string]interface{}{
+					"job_id":       jobID.String(),
+					"folder_count": len(folderPathMap),
+					"image_count":  len(files),
+				})
+				return folderPathMap, files, nil
+			}
+		}
+	}
+
+	// Step 1: List ALL folders first for path mapping (optimization)
+	allFolders, err := w.driveClient.ListAllFoldersRecursive(ctx, srv, folder.DriveFolderID)
+	if err != nil {
+		logger.SyncError("list_folders_failed", "Failed to list folders (will use API per photo)", err, map[string]interface{}{
+			"job_id":    jobID.String(),
+			"folder_id": folder.ID.String(),
+		})
+		allFolders = nil
+	} else {
+		logger.Sync("folders_listed", "Listed folders from Drive", map[string]interface{}{
+			"job_id":       jobID.String(),
+			"folder_count": len(allFolders),
+		})
+	}
+
+	// Step 2: Build folder path map (O(1) lookup)
+	var folderPathMap map[string]string
+	if allFolders != nil {
+		folderPathMap = w.driveClient.BuildFolderPathMap(allFolders, folder.DriveFolderID)
+	}
+
+	// Step 3: List all images
+	files, err := w.driveClient.ListAllImagesRecursive(ctx, srv, folder.DriveFolderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Save the listing so a restart mid-folder can resume on it
+	folderPathsJSON, _ := json.Marshal(folderPathMap)
+	filesJSON, err := json.Marshal(files)
+	if err == nil {
+		err = w.syncJobRepo.SaveSnapshot(ctx, &models.SyncJobSnapshot{
+			JobID:          jobID,
+			SharedFolderID: folder.ID,
+			FolderPaths:    string(folderPathsJSON),
+			Files:          string(filesJSON),
+			CreatedAt:      time.Now(),
+		})
+	}
+	if err != nil {
+		logger.SyncError("listing_snapshot_failed", "Failed to save listing snapshot (a resumed job will re-list)", err, map[string]interface{}{
+			"job_id": jobID.String(),
+		})
+	}
+
+	return folderPathMap, files, nil
+}
+
+// initialFaceStatus returns the face status for newly synced photos.
+// Folders added with deferred face processing keep photos skipped until enabled,
+// folders with face processing switched off keep them skipped until it is switched on.
+func initialFaceStatus(folder *models.SharedFolder, mediaType models.MediaType) models.FaceProcessingStatus {
+	if folder.FaceProcessingDeferred || !folder.FaceProcessingEnabled || mediaType == models.MediaTypeVideo {
+		return models.FaceStatusSkipped
+	}
+	return models.FaceStatusPending
 }
 
 // logActivity creates an activity log entry
@@ -1412,43 +2179,6 @@ func (w *SyncWorker) logActivity(ctx context.Context, folderID uuid.UUID, activi
 	}
 }
 
-// isWithinRootFolder checks if a folder is within the root folder
-func (w *SyncWorker) isWithinRootFolder(ctx context.Context, srv *drive.Service, folderID, rootFolderID string) bool {
-	if folderID == "" {
-		return false
-	}
-	if folderID == rootFolderID {
-		return true
-	}
-
-	currentID := folderID
-	visited := make(map[string]bool)
-
-	for i := 0; i < 20; i++ {
-		if visited[currentID] {
-			return false
-		}
-		visited[currentID] = true
-
-		if currentID == rootFolderID {
-			return true
-		}
-
-		folder, err := srv.Files.Get(currentID).Fields("parents").SupportsAllDrives(true).Do()
-		if err != nil {
-			return false
-		}
-
-		if len(folder.Parents) == 0 {
-			return false
-		}
-
-		currentID = folder.Parents[0]
-	}
-
-	return false
-}
-
 // getParentPath extracts the parent path from a full folder path
 // e.g., "KU TEST/Subfolder/MyFolder" → "KU TEST/Subfolder"
 func getParentPath(path string) string {
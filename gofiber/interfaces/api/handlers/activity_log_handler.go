@@ -162,6 +162,8 @@ func (h *ActivityLogHandler) GetActivityTypes(c *fiber.Ctx) error {
 		{"value": "sync_started", "label": "เริ่มซิงค์", "category": "sync"},
 		{"value": "sync_completed", "label": "ซิงค์สำเร็จ", "category": "sync"},
 		{"value": "sync_failed", "label": "ซิงค์ล้มเหลว", "category": "sync"},
+		{"value": "sync_cancelled", "label": "ยกเลิกการซิงค์", "category": "sync"},
+		{"value": "sync_dry_run", "label": "ทดลองซิงค์ (ไม่บันทึก)", "category": "sync"},
 		{"value": "photos_added", "label": "เพิ่มรูปภาพ", "category": "photo"},
 		{"value": "photos_trashed", "label": "ย้ายรูปไปถังขยะ", "category": "photo"},
 		{"value": "photos_restored", "label": "กู้คืนรูปภาพ", "category": "photo"},
@@ -174,6 +176,7 @@ func (h *ActivityLogHandler) GetActivityTypes(c *fiber.Ctx) error {
 		{"value": "webhook_renewed", "label": "ต่ออายุ Webhook", "category": "webhook"},
 		{"value": "webhook_expired", "label": "Webhook หมดอายุ", "category": "webhook"},
 		{"value": "token_expired", "label": "Token หมดอายุ", "category": "error"},
+		{"value": "access_revoked", "label": "ไม่มีสิทธิ์เข้าถึงโฟลเดอร์", "category": "error"},
 		{"value": "sync_error", "label": "เกิดข้อผิดพลาด", "category": "error"},
 	}
 
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/utils"
+)
+
+// maxBulkFaces caps how many faces a single bulk action can touch
+const maxBulkFaces = 500
+
+type CurationHandler struct {
+	curationService services.CurationService
+	undoWindow      time.Duration
+}
+
+func NewCurationHandler(curationService services.CurationService, undoWindow time.Duration) *CurationHandler {
+	return &CurationHandler{
+		curationService: curationService,
+		undoWindow:      undoWindow,
+	}
+}
+
+// BulkAssignFaces assigns many faces to a person in one undoable action
+// @Summary Bulk assign faces to a person
+// @Description Accessible faces are assigned in one transaction; inaccessible and missing face IDs are reported
+// @Tags Faces
+// @Accept json
+// @Produce json
+// @Param request body dto.BulkAssignFacesRequest true "Face IDs and person ID"
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/faces/assign [post]
+func (h *CurationHandler) BulkAssignFaces(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	var req dto.BulkAssignFacesRequest
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
+	}
+
+	if len(req.FaceIDs) > maxBulkFaces {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Too many faces in one action", nil)
+	}
+
+	result, err := h.curationService.BulkAssignFacesToPerson(c.Context(), userCtx.ID, req.FaceIDs, req.PersonID)
+	if err != nil {
+		if errors.Is(err, services.ErrPersonNotFound) {
+			return utils.NotFoundResponse(c, err.Error())
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to assign faces", err)
+	}
+
+	resp := &dto.BulkAssignFacesResponse{
+		Action:        dto.CurationActionToResponse(result.Action, h.undoWindow),
+		AssignedCount: len(result.AssignedIDs),
+		Inaccessible:  result.Inaccessible,
+		NotFound:      result.NotFound,
+	}
+	if resp.Inaccessible == nil {
+		resp.Inaccessible = []uuid.UUID{}
+	}
+	if resp.NotFound == nil {
+		resp.NotFound = []uuid.UUID{}
+	}
+
+	// Nothing assignable - report what was skipped
+	if result.Action == nil {
+		return c.Status(fiber.StatusNotFound).JSON(utils.Response{
+			Success: false,
+			Message: "No faces could be assigned",
+			Data:    resp,
+			Error:   "Not found",
+		})
+	}
+
+	return utils.SuccessResponse(c, "Faces assigned", resp)
+}
+
+// UndoAction reverses the user's most recent bulk curation action
+// @Summary Undo curation action
+// @Tags Actions
+// @Produce json
+// @Param id path string true "Action ID"
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/actions/{id}/undo [post]
+func (h *CurationHandler) UndoAction(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	actionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid action ID", err)
+	}
+
+	action, err := h.curationService.UndoAction(c.Context(), userCtx.ID, actionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrActionNotFound):
+			return utils.NotFoundResponse(c, err.Error())
+		case errors.Is(err, services.ErrActionAlreadyUndone), errors.Is(err, services.ErrActionNotLatest):
+			return utils.ErrorResponse(c, fiber.StatusConflict, err.Error(), nil)
+		case errors.Is(err, services.ErrUndoWindowExpired):
+			return utils.ErrorResponse(c, fiber.StatusGone, err.Error(), nil)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to undo action", err)
+	}
+
+	return utils.SuccessResponse(c, "Action undone", dto.CurationActionToResponse(action, h.undoWindow))
+}
@@ -6,7 +6,9 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -35,6 +37,11 @@ func getFrontendURL() string {
 type DriveHandler struct {
 	driveService        services.DriveService
 	sharedFolderService services.SharedFolderService
+	thumbnailSigner     *utils.ThumbnailSigner
+	webhookSigner       *utils.WebhookTokenSigner // Verifies webhook channel tokens (nil = not checked)
+	requireSigned       bool                      // Reject unsigned (legacy) channel tokens
+	unsignedUserUntil   time.Time                 // Until then unsigned tokens are still matched against users
+	adminToken          string                    // Guards admin remediation endpoints (empty = disabled)
 }
 
 func NewDriveHandler(driveService services.DriveService) *DriveHandler {
@@ -48,6 +55,57 @@ func (h *DriveHandler) SetSharedFolderService(svc services.SharedFolderService)
 	h.sharedFolderService = svc
 }
 
+// SetThumbnailSigner enables signed thumbnail URLs
+func (h *DriveHandler) SetThumbnailSigner(signer *utils.ThumbnailSigner) {
+	h.thumbnailSigner = signer
+}
+
+// SetWebhookSigner enables verification of signed webhook channel tokens. With requireSigned,
+// tokens without a signature are rejected instead of being accepted as legacy channels, except
+// for user root folder channels until unsignedUserUntil
+func (h *DriveHandler) SetWebhookSigner(signer *utils.WebhookTokenSigner, requireSigned bool, unsignedUserUntil time.Time) {
+	h.webhookSigner = signer
+	h.requireSigned = requireSigned
+	h.unsignedUserUntil = unsignedUserUntil
+}
+
+// SetAdminToken sets the token required by admin remediation endpoints
+func (h *DriveHandler) SetAdminToken(token string) {
+	h.adminToken = token
+}
+
+// defaultThumbnailSize is the thumbnail size used for signed URLs in photo responses
+const defaultThumbnailSize = 400
+
+// signPhotoResponses adds short-lived signed thumbnail URLs to photo responses
+// Caller must have verified the user's access to these photos before minting
+func signPhotoResponses(signer *utils.ThumbnailSigner, responses []dto.PhotoResponse, userID uuid.UUID) {
+	if signer == nil {
+		return
+	}
+	for i := range responses {
+		responses[i].SignedThumbnailURL = signer.SignURL(responses[i].DriveFileID, defaultThumbnailSize, userID.String())
+	}
+}
+
+// thumbnailRetryAfterSeconds is how long clients should wait before polling a pending thumbnail again
+const thumbnailRetryAfterSeconds = 5
+
+// thumbnailNotReadyResponse answers 202 + Retry-After while Drive is still generating a thumbnail
+// (a transient state for fresh uploads, not an error)
+func thumbnailNotReadyResponse(c *fiber.Ctx) error {
+	c.Set("Cache-Control", "no-store")
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(thumbnailRetryAfterSeconds))
+	return c.Status(fiber.StatusAccepted).JSON(utils.Response{
+		Success: true,
+		Message: "Thumbnail not ready yet",
+		Data: fiber.Map{
+			"status":      "pending",
+			"retry_after": thumbnailRetryAfterSeconds,
+		},
+	})
+}
+
 // getJWTSecret returns the JWT secret for HMAC signing
 func getJWTSecret() string {
 	if secret := os.Getenv("JWT_SECRET"); secret != "" {
@@ -272,11 +330,11 @@ func (h *DriveHandler) SetRootFolder(c *fiber.Ctx) error {
 	}
 
 	var req struct {
-		FolderID string `json:"folderId"`
+		FolderID string `json:"folderId" validate:"required"`
 	}
 
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request", err)
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	if err := h.driveService.SetRootFolder(c.Context(), userCtx.ID, req.FolderID); err != nil {
@@ -346,6 +404,7 @@ func (h *DriveHandler) GetPhotos(c *fiber.Ctx) error {
 
 	// Convert to DTO
 	photoResponses := dto.PhotosToPhotoResponses(photos)
+	signPhotoResponses(h.thumbnailSigner, photoResponses, userCtx.ID)
 
 	return utils.SuccessResponse(c, "Photos retrieved", dto.PhotoListResponse{
 		Photos: photoResponses,
@@ -356,6 +415,7 @@ func (h *DriveHandler) GetPhotos(c *fiber.Ctx) error {
 }
 
 // GetThumbnail proxies thumbnail requests to Google Drive with authentication
+// Answers 202 with Retry-After while Drive has not generated the thumbnail yet
 func (h *DriveHandler) GetThumbnail(c *fiber.Ctx) error {
 	userCtx, err := utils.GetUserFromContext(c)
 	if err != nil {
@@ -369,8 +429,16 @@ func (h *DriveHandler) GetThumbnail(c *fiber.Ctx) error {
 
 	size := c.QueryInt("size", 400) // Default thumbnail size
 
+	// Redirect to CDN if thumbnail is already cached
+	if cdnURL, _ := h.driveService.GetCachedThumbnailURL(c.Context(), userCtx.ID, driveFileID, size); cdnURL != "" {
+		return c.Redirect(cdnURL, fiber.StatusFound)
+	}
+
 	data, contentType, err := h.driveService.GetPhotoThumbnail(c.Context(), userCtx.ID, driveFileID, size)
 	if err != nil {
+		if errors.Is(err, services.ErrThumbnailNotReady) {
+			return thumbnailNotReadyResponse(c)
+		}
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get thumbnail", err)
 	}
 
@@ -381,6 +449,108 @@ func (h *DriveHandler) GetThumbnail(c *fiber.Ctx) error {
 	return c.Send(data)
 }
 
+// GetOriginal streams the original image from Google Drive
+// Forwards Content-Length and Range support so browsers show progress and can resume
+// @Summary Get original photo
+// @Tags Drive
+// @Produce octet-stream
+// @Param driveFileId path string true "Drive file ID"
+// @Param download query bool false "Send as attachment instead of inline"
+// @Success 200
+// @Success 206
+// @Security BearerAuth
+// @Router /api/v1/drive/original/{driveFileId} [get]
+func (h *DriveHandler) GetOriginal(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	driveFileID := c.Params("driveFileId")
+	if driveFileID == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Drive file ID is required", nil)
+	}
+
+	stream, err := h.driveService.OpenOriginalPhoto(c.Context(), userCtx.ID, driveFileID, c.Get(fiber.HeaderRange))
+	if err != nil {
+		if errors.Is(err, services.ErrPhotoNotFound) {
+			return utils.NotFoundResponse(c, "Photo not found")
+		}
+		return utils.ErrorResponse(c, fiber.StatusBadGateway, "Failed to get original photo", err)
+	}
+
+	disposition := "inline"
+	if c.QueryBool("download") {
+		disposition = "attachment"
+	}
+
+	c.Set(fiber.HeaderContentType, stream.ContentType)
+	c.Set(fiber.HeaderAcceptRanges, stream.AcceptRanges)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("%s; filename*=UTF-8''%s", disposition, url.PathEscape(stream.FileName)))
+	c.Set("Cache-Control", "private, max-age=3600")
+	if stream.Partial {
+		c.Status(fiber.StatusPartialContent)
+		c.Set(fiber.HeaderContentRange, stream.ContentRange)
+	}
+
+	// Known length sets Content-Length; -1 falls back to chunked transfer
+	// (the body is closed by fasthttp once fully sent)
+	c.Context().SetBodyStream(stream.Body, int(stream.ContentLength))
+	return nil
+}
+
+// GetSignedThumbnail serves a thumbnail using a signed, time-limited URL (no auth header required)
+func (h *DriveHandler) GetSignedThumbnail(c *fiber.Ctx) error {
+	if h.thumbnailSigner == nil {
+		return utils.NotFoundResponse(c, "Signed thumbnails are disabled")
+	}
+
+	driveFileID := c.Params("driveFileId")
+	if driveFileID == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Drive file ID is required", nil)
+	}
+
+	size := c.QueryInt("size", defaultThumbnailSize)
+	signerID := c.Query("uid")
+	sig := c.Query("sig")
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil || signerID == "" || sig == "" {
+		return utils.UnauthorizedResponse(c, "Invalid signed URL")
+	}
+
+	if err := h.thumbnailSigner.Verify(driveFileID, size, signerID, expires, sig); err != nil {
+		return utils.UnauthorizedResponse(c, err.Error())
+	}
+
+	userID, err := uuid.Parse(signerID)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Invalid signed URL")
+	}
+
+	// Redirect to CDN if thumbnail is already cached
+	if cdnURL, _ := h.driveService.GetCachedThumbnailURL(c.Context(), userID, driveFileID, size); cdnURL != "" {
+		return c.Redirect(cdnURL, fiber.StatusFound)
+	}
+
+	data, contentType, err := h.driveService.GetPhotoThumbnail(c.Context(), userID, driveFileID, size)
+	if err != nil {
+		if errors.Is(err, services.ErrThumbnailNotReady) {
+			return thumbnailNotReadyResponse(c)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get thumbnail", err)
+	}
+
+	// Cache until the signature expires
+	maxAge := expires - time.Now().Unix()
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	c.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	c.Set("Content-Type", contentType)
+
+	return c.Send(data)
+}
+
 // DownloadPhotos downloads multiple photos as a zip file
 func (h *DriveHandler) DownloadPhotos(c *fiber.Ctx) error {
 	userCtx, err := utils.GetUserFromContext(c)
@@ -389,15 +559,11 @@ func (h *DriveHandler) DownloadPhotos(c *fiber.Ctx) error {
 	}
 
 	var req struct {
-		DriveFileIDs []string `json:"driveFileIds"`
+		DriveFileIDs []string `json:"driveFileIds" validate:"required,min=1,dive,required"`
 	}
 
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request", err)
-	}
-
-	if len(req.DriveFileIDs) == 0 {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "No files specified", nil)
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	// Progress callback - sends WebSocket message for each file
@@ -411,6 +577,9 @@ func (h *DriveHandler) DownloadPhotos(c *fiber.Ctx) error {
 
 	zipData, err := h.driveService.DownloadPhotosAsZip(c.Context(), userCtx.ID, req.DriveFileIDs, onProgress)
 	if err != nil {
+		if errors.Is(err, services.ErrZipTooManyFiles) || errors.Is(err, services.ErrZipTooLarge) {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Download exceeds the zip limits", err)
+		}
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create zip", err)
 	}
 
@@ -427,18 +596,76 @@ func (h *DriveHandler) DownloadPhotos(c *fiber.Ctx) error {
 	return c.Send(zipData)
 }
 
+// StopWatch stops a Drive watch channel by channel + resource ID
+// @Summary Stop a Drive watch channel
+// @Description Manual webhook cleanup for channels the automated renewal does not stop. The folder's tokens are used for the call
+// @Tags Admin
+// @Security AdminToken
+// @Accept json
+// @Produce json
+// @Param body body object true "folderId, channelId, resourceId"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} utils.Response
+// @Router /admin/drive/stop-watch [post]
+func (h *DriveHandler) StopWatch(c *fiber.Ctx) error {
+	token := c.Get("X-Admin-Token")
+	if token == "" {
+		token = c.Query("token")
+	}
+	if h.adminToken == "" || token != h.adminToken {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid admin token",
+		})
+	}
+
+	if h.sharedFolderService == nil {
+		return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, "Shared folders are not available", nil)
+	}
+
+	var req struct {
+		FolderID   string `json:"folderId" validate:"required,uuid"`
+		ChannelID  string `json:"channelId" validate:"required"`
+		ResourceID string `json:"resourceId" validate:"required"`
+	}
+
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
+	}
+
+	folderID, err := uuid.Parse(req.FolderID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid folder ID", err)
+	}
+
+	if err := h.sharedFolderService.StopWebhookChannel(c.Context(), folderID, req.ChannelID, req.ResourceID); err != nil {
+		if errors.Is(err, services.ErrFolderNotFound) {
+			return utils.NotFoundResponse(c, "Folder not found")
+		}
+		return utils.ErrorResponse(c, fiber.StatusBadGateway, "Failed to stop watch channel", err)
+	}
+
+	return utils.SuccessResponse(c, "Watch channel stopped", fiber.Map{
+		"folderId":   folderID,
+		"channelId":  req.ChannelID,
+		"resourceId": req.ResourceID,
+	})
+}
+
 // Webhook handles Google Drive push notifications
+// Always answers 200 once the channel ID is present: Google retries non-2xx responses, and a
+// rejected or unknown notification should simply be dropped
 func (h *DriveHandler) Webhook(c *fiber.Ctx) error {
 	payload := googledrive.ParseWebhookHeaders(c.GetReqHeaders())
 
-	// Log webhook received
+	// The channel token authenticates the call, so it is never logged
 	logger.Webhook("WEBHOOK_RECEIVED", "Google Drive webhook received", map[string]interface{}{
 		"channel_id":     payload.ChannelID,
 		"resource_id":    payload.ResourceID,
 		"resource_state": payload.ResourceState,
-		"channel_token":  payload.ChannelToken,
+		"token_length":   len(payload.ChannelToken),
 		"resource_uri":   payload.ResourceURI,
-		"headers":        c.GetReqHeaders(),
 	})
 
 	if payload.ChannelID == "" {
@@ -446,59 +673,72 @@ func (h *DriveHandler) Webhook(c *fiber.Ctx) error {
 		return c.SendStatus(fiber.StatusBadRequest)
 	}
 
+	if payload.ChannelToken == "" {
+		logger.WebhookWarn("WEBHOOK_REJECTED", "Missing channel token", map[string]interface{}{
+			"channel_id": payload.ChannelID,
+		})
+		return c.SendStatus(fiber.StatusOK)
+	}
+
 	// Copy values before goroutine (fasthttp context becomes invalid after handler returns)
 	channelID := payload.ChannelID
 	resourceID := payload.ResourceID
 	resourceState := payload.ResourceState
 	channelToken := payload.ChannelToken
 
+	// Signed tokens must match their channel. Unsigned ones come from channels registered before
+	// signing; they are rejected unless signing is not required yet (the renewal job re-registers
+	// those channels with signed tokens), otherwise omitting the signature would bypass the check.
+	// User root folder channels are never renewed, so theirs are accepted until they have expired.
+	userOnly := false
+	if h.webhookSigner != nil {
+		token, signed, err := h.webhookSigner.Verify(channelToken, channelID)
+		if err != nil {
+			logger.WebhookWarn("WEBHOOK_REJECTED", "Invalid channel token signature", map[string]interface{}{
+				"channel_id": channelID,
+			})
+			return c.SendStatus(fiber.StatusOK)
+		}
+		if !signed && h.requireSigned {
+			if !time.Now().Before(h.unsignedUserUntil) {
+				logger.WebhookWarn("WEBHOOK_REJECTED", "Unsigned channel token", map[string]interface{}{
+					"channel_id": channelID,
+				})
+				return c.SendStatus(fiber.StatusOK)
+			}
+			userOnly = true
+		}
+		if !signed {
+			logger.Webhook("WEBHOOK_UNSIGNED_TOKEN", "Accepted unsigned channel token (legacy channel)", map[string]interface{}{
+				"channel_id": channelID,
+			})
+		}
+		channelToken = token
+	}
+
 	// Process webhook asynchronously with background context
 	// IMPORTANT: Cannot use c.Context() in goroutine - it becomes nil after handler returns
 	go func() {
 		ctx := context.Background()
 
-		logger.Webhook("WEBHOOK_PROCESSING", "Processing webhook asynchronously", map[string]interface{}{
-			"resource_state": resourceState,
-			"channel_token":  channelToken,
-		})
-
-		// Try user-based webhook first
-		err := h.driveService.HandleWebhook(
-			ctx,
-			channelID,
-			resourceID,
-			resourceState,
-			channelToken,
-		)
+		// Try user-based webhook first, then shared folders; a token unknown to both is dropped
+		err := h.driveService.HandleWebhook(ctx, channelID, resourceID, resourceState, channelToken)
+		if errors.Is(err, services.ErrUnknownWebhookToken) && h.sharedFolderService != nil && !userOnly {
+			err = h.sharedFolderService.HandleWebhook(ctx, channelID, resourceID, resourceState, channelToken)
+		}
 
-		if err != nil {
-			logger.Webhook("WEBHOOK_USER_FAILED", "User webhook failed, trying shared folder", map[string]interface{}{
-				"error": err.Error(),
+		switch {
+		case errors.Is(err, services.ErrUnknownWebhookToken):
+			logger.WebhookWarn("WEBHOOK_UNKNOWN_TOKEN", "Webhook token matches no user or shared folder, ignored", map[string]interface{}{
+				"channel_id":  channelID,
+				"resource_id": resourceID,
 			})
-
-			// Try shared folder webhook
-			if h.sharedFolderService != nil {
-				err = h.sharedFolderService.HandleWebhook(
-					ctx,
-					channelID,
-					resourceID,
-					resourceState,
-					channelToken,
-				)
-				if err != nil {
-					logger.WebhookError("WEBHOOK_FAILED", "Both user and shared folder webhook failed", err, map[string]interface{}{
-						"channel_id": channelID,
-					})
-				} else {
-					logger.Webhook("WEBHOOK_SUCCESS", "SharedFolder webhook processed successfully", map[string]interface{}{
-						"channel_id": channelID,
-					})
-				}
-			} else {
-				logger.WebhookError("WEBHOOK_ERROR", "SharedFolderService not available", nil, nil)
-			}
-		} else {
-			logger.Webhook("WEBHOOK_SUCCESS", "User webhook processed successfully", map[string]interface{}{
+		case err != nil:
+			logger.WebhookError("WEBHOOK_FAILED", "Webhook processing failed", err, map[string]interface{}{
+				"channel_id": channelID,
+			})
+		default:
+			logger.Webhook("WEBHOOK_SUCCESS", "Webhook processed successfully", map[string]interface{}{
 				"channel_id": channelID,
 			})
 		}
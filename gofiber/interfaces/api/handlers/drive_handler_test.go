@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/utils"
+)
+
+// userWebhooks reports user webhooks on calls; no token matches a user
+type userWebhooks struct {
+	services.DriveService
+	calls chan string
+}
+
+func (s *userWebhooks) HandleWebhook(ctx context.Context, channelID, resourceID, resourceState, token string) error {
+	s.calls <- "user"
+	return services.ErrUnknownWebhookToken
+}
+
+// sharedFolderWebhooks reports shared folder webhooks on calls
+type sharedFolderWebhooks struct {
+	services.SharedFolderService
+	calls chan string
+}
+
+func (s *sharedFolderWebhooks) HandleWebhook(ctx context.Context, channelID, resourceID, resourceState, token string) error {
+	s.calls <- "shared"
+	return nil
+}
+
+func TestWebhookUnsignedTokens(t *testing.T) {
+	signer := utils.NewWebhookTokenSigner("test-secret")
+
+	tests := []struct {
+		name              string
+		token             string
+		unsignedUserUntil time.Time
+		want              []string
+	}{
+		{name: "signed", token: signer.Sign("token", "channel"), want: []string{"user", "shared"}},
+		// Legacy user root channels expire unrenewed, so only users are matched meanwhile
+		{name: "unsigned in migration window", token: "token", unsignedUserUntil: time.Now().Add(time.Hour), want: []string{"user"}},
+		{name: "unsigned after migration window", token: "token", unsignedUserUntil: time.Now().Add(-time.Hour)},
+		{name: "unsigned without migration window", token: "token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := make(chan string, 2)
+			h := NewDriveHandler(&userWebhooks{calls: calls})
+			h.SetSharedFolderService(&sharedFolderWebhooks{calls: calls})
+			h.SetWebhookSigner(signer, true, tt.unsignedUserUntil)
+			app := fiber.New()
+			app.Post("/webhook", h.Webhook)
+
+			req := httptest.NewRequest("POST", "/webhook", nil)
+			req.Header.Set("X-Goog-Channel-Id", "channel")
+			req.Header.Set("X-Goog-Channel-Token", tt.token)
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+
+			// Webhooks are processed in the background; wait for the expected calls, then
+			// briefly for any unexpected one
+			for _, want := range tt.want {
+				select {
+				case got := <-calls:
+					if got != want {
+						t.Fatalf("webhook reached %s service, want %s", got, want)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("webhook never reached %s service", want)
+				}
+			}
+			select {
+			case got := <-calls:
+				t.Errorf("webhook also reached %s service", got)
+			case <-time.After(50 * time.Millisecond):
+			}
+		})
+	}
+}
@@ -1,24 +1,161 @@
 package handlers
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
 	"gofiber-template/domain/models"
 	"gofiber-template/domain/services"
+	"gofiber-template/infrastructure/worker"
+	"gofiber-template/pkg/config"
+	"gofiber-template/pkg/logger"
 	"gofiber-template/pkg/utils"
 )
 
+// validImageTypes are the accepted upload content types for face detection/search
+var validImageTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+	"image/gif",
+}
+
+// faceSearchMetric is the similarity metric used by face search (pgvector cosine distance)
+const faceSearchMetric = "cosine"
+
 type FaceHandler struct {
 	faceService services.FaceService
+	cfg         config.FaceAPIConfig
+
+	// In-flight face searches per user (fairness guard on top of the global rate limit)
+	searchesMu       sync.Mutex
+	searchesInFlight map[uuid.UUID]int
+
+	faceWorker *worker.FaceWorker // nil when face processing is disabled
+}
+
+// SetFaceWorker enables the admin face worker controls (pause/resume/status)
+func (h *FaceHandler) SetFaceWorker(faceWorker *worker.FaceWorker) {
+	h.faceWorker = faceWorker
 }
 
-func NewFaceHandler(faceService services.FaceService) *FaceHandler {
+func NewFaceHandler(faceService services.FaceService, cfg config.FaceAPIConfig) *FaceHandler {
 	return &FaceHandler{
-		faceService: faceService,
+		faceService:      faceService,
+		cfg:              cfg,
+		searchesInFlight: make(map[uuid.UUID]int),
+	}
+}
+
+// acquireSearch reserves one of the user's concurrent face search slots.
+// Returns false if the user already has SearchMaxConcurrentPerUser searches running;
+// otherwise the returned func must be called when the search finishes.
+func (h *FaceHandler) acquireSearch(userID uuid.UUID) (func(), bool) {
+	if h.cfg.SearchMaxConcurrentPerUser <= 0 {
+		return func() {}, true
+	}
+
+	h.searchesMu.Lock()
+	defer h.searchesMu.Unlock()
+
+	if h.searchesInFlight[userID] >= h.cfg.SearchMaxConcurrentPerUser {
+		return nil, false
+	}
+	h.searchesInFlight[userID]++
+
+	return func() {
+		h.searchesMu.Lock()
+		defer h.searchesMu.Unlock()
+
+		if h.searchesInFlight[userID] <= 1 {
+			delete(h.searchesInFlight, userID)
+		} else {
+			h.searchesInFlight[userID]--
+		}
+	}, true
+}
+
+// searchBusyResponse rejects a search while the user is at their concurrent search limit
+func (h *FaceHandler) searchBusyResponse(c *fiber.Ctx) error {
+	return utils.ErrorResponse(c, fiber.StatusTooManyRequests,
+		fmt.Sprintf("Too many face searches in progress (max %d at a time). Please wait for the current search to finish.", h.cfg.SearchMaxConcurrentPerUser), nil)
+}
+
+// withIndexStatus adds the face index readiness of the user's folders to a search response:
+// index_complete false with pending photos means matches in those photos can't be found yet.
+// The fields are left out if the status can't be read (the search itself succeeded).
+func (h *FaceHandler) withIndexStatus(ctx context.Context, userID uuid.UUID, data fiber.Map) fiber.Map {
+	status, err := h.faceService.GetFaceIndexStatus(ctx, userID)
+	if err != nil {
+		logger.FaceError("face_index_status_failed", "Failed to get face index status", err, map[string]interface{}{
+			"user_id": userID.String(),
+		})
+		return data
+	}
+	data["index_complete"] = status.Complete
+	data["pending"] = status.Pending
+	data["pending_folders"] = status.Folders
+	return data
+}
+
+// maxUploadBytes returns the max accepted image size for detect/search uploads
+func (h *FaceHandler) maxUploadBytes() int64 {
+	return int64(h.cfg.MaxUploadMB) * 1024 * 1024
+}
+
+// searchParams applies the server's defaults to out-of-range limit/threshold values and raises
+// thresholds below the configured floor to it
+func (h *FaceHandler) searchParams(limit int, threshold float64) (int, float64) {
+	if limit < 1 || limit > h.cfg.SearchMaxLimit {
+		limit = h.cfg.SearchDefaultLimit
+	}
+	if threshold < 0 || threshold > 1 {
+		threshold = h.cfg.SearchDefaultThreshold
+	}
+	if threshold < h.cfg.SearchMinThreshold {
+		threshold = h.cfg.SearchMinThreshold
 	}
+	return limit, threshold
+}
+
+// GetConfig returns face search defaults and limits so clients match server-side validation
+// @Summary Get face search configuration
+// @Description Default/min/max limit and threshold, similarity metric and upload limits used by the server
+// @Tags Faces
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /api/v1/faces/config [get]
+func (h *FaceHandler) GetConfig(c *fiber.Ctx) error {
+	return utils.SuccessResponse(c, "Face config retrieved", fiber.Map{
+		"search": fiber.Map{
+			"default_limit":     h.cfg.SearchDefaultLimit,
+			"min_limit":         1,
+			"max_limit":         h.cfg.SearchMaxLimit,
+			"default_threshold": h.cfg.SearchDefaultThreshold,
+			"min_threshold":     h.cfg.SearchMinThreshold, // Lower requested values are raised to this
+			"max_threshold":     1,
+			"metric":            faceSearchMetric,
+			"supported_metrics": []string{faceSearchMetric},
+
+			"max_concurrent_per_user": h.cfg.SearchMaxConcurrentPerUser,
+		},
+		"upload": fiber.Map{
+			"max_size_bytes": h.maxUploadBytes(),
+			"max_size_mb":    h.cfg.MaxUploadMB,
+			"allowed_types":  validImageTypes,
+		},
+	})
 }
 
 // SearchByImageRequest is the request for searching by image upload
@@ -30,8 +167,86 @@ type SearchByImageRequest struct {
 // SearchByFaceIDRequest is the request for searching by existing face
 type SearchByFaceIDRequest struct {
 	FaceID    string  `json:"face_id" validate:"required,uuid"`
-	Limit     int     `json:"limit"`
-	Threshold float64 `json:"threshold"`
+	Limit     int     `json:"limit" validate:"gte=0"`
+	Threshold float64 `json:"threshold" validate:"gte=0,lte=1"`
+}
+
+// SearchByPersonRequest is the request for searching with a person's tagged faces
+type SearchByPersonRequest struct {
+	PersonID  string  `json:"person_id" validate:"required,uuid"`
+	Limit     int     `json:"limit" validate:"gte=0"`
+	Threshold float64 `json:"threshold" validate:"gte=0,lte=1"`
+}
+
+// DeleteFacesRequest is the request for bulk deleting faces (e.g. false positives)
+type DeleteFacesRequest struct {
+	FaceIDs []uuid.UUID `json:"face_ids" validate:"required,min=1"`
+}
+
+// RedactFacesRequest is the request for redacting specific faces
+type RedactFacesRequest struct {
+	FaceIDs []uuid.UUID `json:"face_ids" validate:"required,min=1"`
+}
+
+// LowConfidenceFaceResponse is a face to review, with the photo needed to render its crop
+type LowConfidenceFaceResponse struct {
+	FaceID       string  `json:"face_id"`
+	PhotoID      string  `json:"photo_id"`
+	DriveFileID  string  `json:"drive_file_id"`
+	FileName     string  `json:"file_name"`
+	ThumbnailURL string  `json:"thumbnail_url"`
+	BboxX        float64 `json:"bbox_x"`
+	BboxY        float64 `json:"bbox_y"`
+	BboxWidth    float64 `json:"bbox_width"`
+	BboxHeight   float64 `json:"bbox_height"`
+	Confidence   float64 `json:"confidence"`
+	PersonID     *string `json:"person_id"`
+}
+
+// MergePersonsRequest is the request for merging another person into the one in the path
+type MergePersonsRequest struct {
+	MergeID uuid.UUID `json:"merge_id" validate:"required"`
+}
+
+// SplitPersonRequest is the request for moving some of a person's faces to a new person
+type SplitPersonRequest struct {
+	FaceIDs []uuid.UUID `json:"face_ids" validate:"required,min=1"`
+	Name    string      `json:"name" validate:"required,max=255"`
+}
+
+// PersonResponse is a person after a merge or split
+type PersonResponse struct {
+	PersonID  string `json:"person_id"`
+	Name      string `json:"name"`
+	FaceCount int    `json:"face_count"`
+	Redacted  bool   `json:"redacted"`
+}
+
+// ClusterFacesRequest is the request for clustering a folder's unassigned faces
+type ClusterFacesRequest struct {
+	Threshold float64 `json:"threshold"` // Similarity linking two faces (0-1), default: search default threshold
+}
+
+// ClusterFaceResponse is a sample face of a suggested cluster, cropped from its photo thumbnail
+type ClusterFaceResponse struct {
+	FaceID       string  `json:"face_id"`
+	PhotoID      string  `json:"photo_id"`
+	DriveFileID  string  `json:"drive_file_id"`
+	FileName     string  `json:"file_name"`
+	ThumbnailURL string  `json:"thumbnail_url"`
+	BboxX        float64 `json:"bbox_x"`
+	BboxY        float64 `json:"bbox_y"`
+	BboxWidth    float64 `json:"bbox_width"`
+	BboxHeight   float64 `json:"bbox_height"`
+	Confidence   float64 `json:"confidence"`
+}
+
+// FaceClusterResponse is a suggested person: unassigned faces that look alike
+type FaceClusterResponse struct {
+	Size           int                   `json:"size"`
+	FaceIDs        []string              `json:"face_ids"`
+	Representative *ClusterFaceResponse  `json:"representative"`
+	Samples        []ClusterFaceResponse `json:"samples"`
 }
 
 // FaceSearchResultResponse is the response for face search (updated)
@@ -52,6 +267,34 @@ type FaceSearchResultResponse struct {
 	Similarity     float64 `json:"similarity"`
 }
 
+// toFaceSearchResultResponse converts a service search result to its response format
+func toFaceSearchResultResponse(r services.FaceSearchResult) FaceSearchResultResponse {
+	return FaceSearchResultResponse{
+		FaceID:         r.Face.ID.String(),
+		PhotoID:        r.Photo.ID.String(),
+		SharedFolderID: r.Photo.SharedFolderID.String(),
+		DriveFileID:    r.Photo.DriveFileID,
+		DriveFolderID:  r.Photo.DriveFolderID,
+		FileName:       r.Photo.FileName,
+		ThumbnailURL:   r.Photo.ThumbnailURL,
+		WebViewURL:     r.Photo.WebViewURL,
+		FolderPath:     r.Photo.DriveFolderPath,
+		BboxX:          r.Face.BboxX,
+		BboxY:          r.Face.BboxY,
+		BboxWidth:      r.Face.BboxWidth,
+		BboxHeight:     r.Face.BboxHeight,
+		Similarity:     r.Similarity,
+	}
+}
+
+// FolderFaceMatchesResponse is the matches of a "search my face" request in one folder
+type FolderFaceMatchesResponse struct {
+	FolderID   string                     `json:"folder_id"`
+	FolderName string                     `json:"folder_name"`
+	Count      int                        `json:"count"`
+	Matches    []FaceSearchResultResponse `json:"matches"`
+}
+
 // DetectedFaceResponse is the response for detected faces
 type DetectedFaceResponse struct {
 	Index      int     `json:"index"`
@@ -77,9 +320,9 @@ func (h *FaceHandler) DetectFaces(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Image file is required", err)
 	}
 
-	// Validate file size (max 10MB)
-	if file.Size > 10*1024*1024 {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "File size exceeds 10MB limit", nil)
+	// Validate file size
+	if file.Size > h.maxUploadBytes() {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("File size exceeds %dMB limit", h.cfg.MaxUploadMB), nil)
 	}
 
 	// Validate content type
@@ -95,9 +338,8 @@ func (h *FaceHandler) DetectFaces(c *fiber.Ctx) error {
 	}
 	defer f.Close()
 
-	// Read file content
-	imageData := make([]byte, file.Size)
-	_, err = f.Read(imageData)
+	// Read file content (a single Read may return only part of the file)
+	imageData, err := io.ReadAll(f)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to read file", err)
 	}
@@ -111,7 +353,15 @@ func (h *FaceHandler) DetectFaces(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Face detection failed", err)
 	}
 
-	// Convert to response format
+	response := toDetectedFaceResponses(faces)
+
+	return utils.SuccessResponse(c, "Faces detected", fiber.Map{
+		"faces": response,
+		"count": len(response),
+	})
+}
+
+func toDetectedFaceResponses(faces []services.DetectedFace) []DetectedFaceResponse {
 	response := make([]DetectedFaceResponse, len(faces))
 	for i, f := range faces {
 		response[i] = DetectedFaceResponse{
@@ -123,11 +373,102 @@ func (h *FaceHandler) DetectFaces(c *fiber.Ctx) error {
 			Confidence: f.Confidence,
 		}
 	}
+	return response
+}
 
-	return utils.SuccessResponse(c, "Faces detected", fiber.Map{
-		"faces": response,
-		"count": len(response),
-	})
+// DetectAndSearch detects all faces in an uploaded image and searches with one of them
+// @Summary Detect faces and search in one request
+// @Description Returns the detected faces and the matches of the face at auto_index, extracting faces once (replaces /faces/detect followed by /faces/search/image)
+// @Tags Faces
+// @Accept multipart/form-data
+// @Produce json
+// @Param image formData file true "Image file"
+// @Param auto_index query string false "Index of the face to search, or best (default) for the most prominent face"
+// @Param limit query int false "Max results" default(20)
+// @Param threshold query number false "Similarity threshold (0-1)" default(0.6)
+// @Success 200 {object} utils.Response
+// @Router /api/v1/faces/search/detect [post]
+func (h *FaceHandler) DetectAndSearch(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	// Get the uploaded file
+	file, err := c.FormFile("image")
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Image file is required", err)
+	}
+
+	// Validate file size
+	if file.Size > h.maxUploadBytes() {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("File size exceeds %dMB limit", h.cfg.MaxUploadMB), nil)
+	}
+
+	// Validate content type
+	contentType := file.Header.Get("Content-Type")
+	if !isValidImageType(contentType) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid image type. Allowed: jpeg, png, webp, gif", nil)
+	}
+
+	faceIndex := services.BestFaceIndex
+	if raw := c.Query("auto_index"); raw != "" && raw != "best" {
+		index, err := strconv.Atoi(raw)
+		if err != nil || index < 0 {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "ตำแหน่งใบหน้าไม่ถูกต้อง", err)
+		}
+		faceIndex = index
+	}
+	limit, threshold := h.searchParams(
+		c.QueryInt("limit", h.cfg.SearchDefaultLimit),
+		c.QueryFloat("threshold", h.cfg.SearchDefaultThreshold),
+	)
+
+	release, ok := h.acquireSearch(userCtx.ID)
+	if !ok {
+		return h.searchBusyResponse(c)
+	}
+	defer release()
+
+	// Open the file
+	f, err := file.Open()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to read file", err)
+	}
+	defer f.Close()
+
+	// Read file content (a single Read may return only part of the file)
+	imageData, err := io.ReadAll(f)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to read file", err)
+	}
+
+	result, err := h.faceService.DetectAndSearch(c.Context(), userCtx.ID, imageData, contentType, faceIndex, limit, threshold)
+	if err != nil {
+		if errors.Is(err, services.ErrNoFacesDetected) {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่พบใบหน้าในรูปภาพที่อัปโหลด กรุณาใช้รูปที่เห็นใบหน้าชัดเจน", err)
+		}
+		if errors.Is(err, services.ErrInvalidFaceIndex) {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "ตำแหน่งใบหน้าไม่ถูกต้อง", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Face search failed", err)
+	}
+
+	faces := toDetectedFaceResponses(result.Faces)
+	response := make([]FaceSearchResultResponse, len(result.Results))
+	for i, r := range result.Results {
+		response[i] = toFaceSearchResultResponse(r)
+	}
+
+	return utils.SuccessResponse(c, "Face search completed", h.withIndexStatus(c.Context(), userCtx.ID, fiber.Map{
+		"faces":      faces,
+		"face_count": len(faces),
+		"face_index": result.FaceIndex,
+		"results":    response,
+		"count":      len(response),
+		"limit":      limit,
+		"threshold":  threshold,
+	}))
 }
 
 // SearchByImage handles face search by uploading an image
@@ -136,9 +477,10 @@ func (h *FaceHandler) DetectFaces(c *fiber.Ctx) error {
 // @Accept multipart/form-data
 // @Produce json
 // @Param image formData file true "Image file"
-// @Param face_index query int false "Face index to search (default: 0)" default(0)
+// @Param face_index query int false "Face index to search (from /faces/detect). Omit to use the most prominent face"
 // @Param limit query int false "Max results" default(20)
 // @Param threshold query number false "Similarity threshold (0-1)" default(0.6)
+// @Param stream query bool false "Stream matches per folder as NDJSON lines (results, then done or error) instead of one JSON response"
 // @Success 200 {object} utils.Response
 // @Router /api/v1/faces/search/image [post]
 func (h *FaceHandler) SearchByImage(c *fiber.Ctx) error {
@@ -153,9 +495,9 @@ func (h *FaceHandler) SearchByImage(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Image file is required", err)
 	}
 
-	// Validate file size (max 10MB)
-	if file.Size > 10*1024*1024 {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "File size exceeds 10MB limit", nil)
+	// Validate file size
+	if file.Size > h.maxUploadBytes() {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("File size exceeds %dMB limit", h.cfg.MaxUploadMB), nil)
 	}
 
 	// Validate content type
@@ -164,6 +506,17 @@ func (h *FaceHandler) SearchByImage(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid image type. Allowed: jpeg, png, webp, gif", nil)
 	}
 
+	release, ok := h.acquireSearch(userCtx.ID)
+	if !ok {
+		return h.searchBusyResponse(c)
+	}
+	streaming := false
+	defer func() {
+		if !streaming {
+			release()
+		}
+	}()
+
 	// Open the file
 	f, err := file.Open()
 	if err != nil {
@@ -171,24 +524,29 @@ func (h *FaceHandler) SearchByImage(c *fiber.Ctx) error {
 	}
 	defer f.Close()
 
-	// Read file content
-	imageData := make([]byte, file.Size)
-	_, err = f.Read(imageData)
+	// Read file content (a single Read may return only part of the file)
+	imageData, err := io.ReadAll(f)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to read file", err)
 	}
 
-	// Get query parameters
-	faceIndex := c.QueryInt("face_index", 0)
-	limit := c.QueryInt("limit", 20)
-	threshold := c.QueryFloat("threshold", 0.6)
-
-	// Validate parameters
-	if limit < 1 || limit > 100 {
-		limit = 20
+	// Get query parameters (no face_index: pick the most prominent face, e.g. the selfie taker in a group selfie)
+	faceIndex := services.BestFaceIndex
+	if raw := c.Query("face_index"); raw != "" {
+		index, err := strconv.Atoi(raw)
+		if err != nil || index < 0 {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "ตำแหน่งใบหน้าไม่ถูกต้อง", err)
+		}
+		faceIndex = index
 	}
-	if threshold < 0 || threshold > 1 {
-		threshold = 0.6
+	limit, threshold := h.searchParams(
+		c.QueryInt("limit", h.cfg.SearchDefaultLimit),
+		c.QueryFloat("threshold", h.cfg.SearchDefaultThreshold),
+	)
+
+	if c.QueryBool("stream", false) {
+		streaming = true // The stream releases the search slot when it ends
+		return h.streamSearchByImage(c, userCtx.ID, imageData, contentType, faceIndex, limit, threshold, release)
 	}
 
 	// Search for similar faces with selected face index
@@ -225,12 +583,261 @@ func (h *FaceHandler) SearchByImage(c *fiber.Ctx) error {
 		}
 	}
 
-	return utils.SuccessResponse(c, "Face search completed", fiber.Map{
+	return utils.SuccessResponse(c, "Face search completed", h.withIndexStatus(c.Context(), userCtx.ID, fiber.Map{
 		"results":   response,
 		"count":     len(response),
 		"limit":     limit,
 		"threshold": threshold,
+	}))
+}
+
+// faceSearchStreamEvent is one NDJSON line of a streamed face search
+type faceSearchStreamEvent struct {
+	Type         string                     `json:"type"` // results, done or error
+	FolderID     string                     `json:"folder_id,omitempty"`
+	Results      []FaceSearchResultResponse `json:"results,omitempty"` // done: overall top matches, most similar first
+	FoldersDone  int                        `json:"folders_done"`
+	FoldersTotal int                        `json:"folders_total"`
+	Limit        int                        `json:"limit,omitempty"`
+	Threshold    float64                    `json:"threshold,omitempty"`
+	Message      string                     `json:"message,omitempty"`
+
+	// done: face index readiness, see withIndexStatus
+	IndexComplete  *bool                        `json:"index_complete,omitempty"`
+	Pending        int64                        `json:"pending,omitempty"`
+	PendingFolders []services.FolderIndexStatus `json:"pending_folders,omitempty"`
+}
+
+// streamSearchByImage answers an image search with chunked NDJSON: a results line per folder as its
+// search finishes, then a done line with the overall top matches (or an error line). Extraction
+// errors still get a normal JSON error response. It takes over release of the user's search slot.
+func (h *FaceHandler) streamSearchByImage(c *fiber.Ctx, userID uuid.UUID, imageData []byte, contentType string, faceIndex, limit int, threshold float64, release func()) error {
+	// The stream outlives the handler (and its request context), so it gets its own
+	ctx, cancel := context.WithCancel(context.Background())
+
+	batches, folderCount, err := h.faceService.StreamSearchByImage(ctx, userID, imageData, contentType, faceIndex, limit, threshold)
+	if err != nil {
+		cancel()
+		release()
+		if errors.Is(err, services.ErrNoFacesDetected) {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่พบใบหน้าในรูปภาพที่อัปโหลด กรุณาใช้รูปที่เห็นใบหน้าชัดเจน", err)
+		}
+		if errors.Is(err, services.ErrInvalidFaceIndex) {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "ตำแหน่งใบหน้าไม่ถูกต้อง", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Face search failed", err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer release()
+		defer cancel()
+
+		// A client that went away makes writes fail: stop searching, keep draining until closed
+		write := func(event faceSearchStreamEvent) {
+			if ctx.Err() != nil {
+				return
+			}
+			line, _ := json.Marshal(event)
+			if _, err := w.Write(append(line, '\n')); err != nil {
+				cancel()
+				return
+			}
+			if err := w.Flush(); err != nil {
+				cancel()
+			}
+		}
+
+		var top []services.FaceSearchResult
+		done := 0
+		for batch := range batches {
+			if batch.Err != nil {
+				logger.FaceError("face_search_stream_failed", "Streamed face search failed", batch.Err, map[string]interface{}{
+					"user_id":   userID.String(),
+					"folder_id": batch.FolderID.String(),
+				})
+				write(faceSearchStreamEvent{Type: "error", Message: "Face search failed", FoldersDone: done, FoldersTotal: folderCount})
+				cancel()
+				continue
+			}
+			done++
+			if len(batch.Results) == 0 {
+				continue
+			}
+
+			response := make([]FaceSearchResultResponse, len(batch.Results))
+			for i, r := range batch.Results {
+				response[i] = toFaceSearchResultResponse(r)
+			}
+			write(faceSearchStreamEvent{Type: "results", FolderID: batch.FolderID.String(), Results: response, FoldersDone: done, FoldersTotal: folderCount})
+
+			top = append(top, batch.Results...)
+			sort.SliceStable(top, func(i, j int) bool { return top[i].Similarity > top[j].Similarity })
+			if len(top) > limit {
+				top = top[:limit]
+			}
+		}
+
+		response := make([]FaceSearchResultResponse, len(top))
+		for i, r := range top {
+			response[i] = toFaceSearchResultResponse(r)
+		}
+		doneEvent := faceSearchStreamEvent{Type: "done", Results: response, FoldersDone: done, FoldersTotal: folderCount, Limit: limit, Threshold: threshold}
+		if ctx.Err() == nil {
+			if status, err := h.faceService.GetFaceIndexStatus(ctx, userID); err == nil {
+				doneEvent.IndexComplete = &status.Complete
+				doneEvent.Pending = status.Pending
+				doneEvent.PendingFolders = status.Folders
+			}
+		}
+		write(doneEvent)
 	})
+	return nil
+}
+
+// SearchMyFace finds the uploader in every folder they can access from a selfie
+// @Summary Search my face across all my events
+// @Description Upload a selfie: the most confidently detected face is searched in all folders the user can access, and matches are returned grouped by folder (folders with the most matches first). limit caps the total matches across folders.
+// @Tags Faces
+// @Accept multipart/form-data
+// @Produce json
+// @Param image formData file true "Selfie image file"
+// @Param limit query int false "Max matches across all folders" default(20)
+// @Param threshold query number false "Similarity threshold (0-1)" default(0.6)
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/faces/search/me [post]
+func (h *FaceHandler) SearchMyFace(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Image file is required", err)
+	}
+
+	if file.Size > h.maxUploadBytes() {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("File size exceeds %dMB limit", h.cfg.MaxUploadMB), nil)
+	}
+
+	contentType := file.Header.Get("Content-Type")
+	if !isValidImageType(contentType) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid image type. Allowed: jpeg, png, webp, gif", nil)
+	}
+
+	release, ok := h.acquireSearch(userCtx.ID)
+	if !ok {
+		return h.searchBusyResponse(c)
+	}
+	defer release()
+
+	f, err := file.Open()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to read file", err)
+	}
+	defer f.Close()
+
+	imageData, err := io.ReadAll(f)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to read file", err)
+	}
+
+	limit, threshold := h.searchParams(
+		c.QueryInt("limit", h.cfg.SearchDefaultLimit),
+		c.QueryFloat("threshold", h.cfg.SearchDefaultThreshold),
+	)
+
+	groups, err := h.faceService.SearchMyFace(c.Context(), userCtx.ID, imageData, contentType, limit, threshold)
+	if err != nil {
+		if errors.Is(err, services.ErrNoFacesDetected) {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่พบใบหน้าในรูปภาพที่อัปโหลด กรุณาใช้รูปที่เห็นใบหน้าชัดเจน", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Face search failed", err)
+	}
+
+	total := 0
+	response := make([]FolderFaceMatchesResponse, len(groups))
+	for i, g := range groups {
+		matches := make([]FaceSearchResultResponse, len(g.Matches))
+		for j, r := range g.Matches {
+			matches[j] = toFaceSearchResultResponse(r)
+		}
+		response[i] = FolderFaceMatchesResponse{
+			FolderID:   g.FolderID.String(),
+			FolderName: g.FolderName,
+			Count:      len(matches),
+			Matches:    matches,
+		}
+		total += len(matches)
+	}
+
+	return utils.SuccessResponse(c, "Face search completed", h.withIndexStatus(c.Context(), userCtx.ID, fiber.Map{
+		"folders":      response,
+		"folder_count": len(response),
+		"count":        total,
+		"limit":        limit,
+		"threshold":    threshold,
+	}))
+}
+
+// SearchByPerson searches with the averaged embedding (centroid) of a person's tagged faces
+// @Summary Search similar faces using a person's tagged faces
+// @Description Uses the average of all faces tagged as the person, which also finds them at angles no single tagged face shows. Faces far from the average (likely mis-tagged) are left out of it.
+// @Tags Faces
+// @Accept json
+// @Produce json
+// @Param request body SearchByPersonRequest true "Search request"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/faces/search/person [post]
+func (h *FaceHandler) SearchByPerson(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	var req SearchByPersonRequest
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
+	}
+
+	personID, err := uuid.Parse(req.PersonID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid person ID", err)
+	}
+
+	release, ok := h.acquireSearch(userCtx.ID)
+	if !ok {
+		return h.searchBusyResponse(c)
+	}
+	defer release()
+
+	limit, threshold := h.searchParams(req.Limit, req.Threshold)
+
+	results, err := h.faceService.SearchByPerson(c.Context(), userCtx.ID, personID, limit, threshold)
+	if err != nil {
+		if errors.Is(err, services.ErrPersonNotFound) {
+			return utils.NotFoundResponse(c, "Person not found")
+		}
+		if errors.Is(err, services.ErrPersonHasNoFaces) {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "บุคคลนี้ยังไม่มีใบหน้าที่ถูกระบุ", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Face search failed", err)
+	}
+
+	response := make([]FaceSearchResultResponse, len(results))
+	for i, r := range results {
+		response[i] = toFaceSearchResultResponse(r)
+	}
+
+	return utils.SuccessResponse(c, "Face search completed", h.withIndexStatus(c.Context(), userCtx.ID, fiber.Map{
+		"results":   response,
+		"count":     len(response),
+		"limit":     limit,
+		"threshold": threshold,
+	}))
 }
 
 // SearchByFaceID handles face search using an existing face's embedding
@@ -248,8 +855,8 @@ func (h *FaceHandler) SearchByFaceID(c *fiber.Ctx) error {
 	}
 
 	var req SearchByFaceIDRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	// Parse face ID
@@ -258,16 +865,14 @@ func (h *FaceHandler) SearchByFaceID(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid face ID", err)
 	}
 
-	// Validate parameters
-	limit := req.Limit
-	if limit < 1 || limit > 100 {
-		limit = 20
+	release, ok := h.acquireSearch(userCtx.ID)
+	if !ok {
+		return h.searchBusyResponse(c)
 	}
+	defer release()
 
-	threshold := req.Threshold
-	if threshold < 0 || threshold > 1 {
-		threshold = 0.6
-	}
+	// Validate parameters
+	limit, threshold := h.searchParams(req.Limit, req.Threshold)
 
 	// Search for similar faces
 	results, err := h.faceService.SearchByFaceID(c.Context(), userCtx.ID, faceID, limit, threshold)
@@ -296,12 +901,12 @@ func (h *FaceHandler) SearchByFaceID(c *fiber.Ctx) error {
 		}
 	}
 
-	return utils.SuccessResponse(c, "Face search completed", fiber.Map{
+	return utils.SuccessResponse(c, "Face search completed", h.withIndexStatus(c.Context(), userCtx.ID, fiber.Map{
 		"results":   response,
 		"count":     len(response),
 		"limit":     limit,
 		"threshold": threshold,
-	})
+	}))
 }
 
 // GetFacesByPhoto returns all faces detected in a photo
@@ -404,12 +1009,131 @@ func (h *FaceHandler) RetryFailed(c *fiber.Ctx) error {
 	})
 }
 
+// ClusterFaces suggests persons by grouping a folder's unassigned faces by similarity
+// @Summary Cluster unassigned faces into suggested persons
+// @Description Links each unassigned face to its most similar faces at or above threshold and returns the linked groups, largest first, with sample faces for "Is this the same person?" prompts. Only the most confident faces are compared when a folder has very many.
+// @Tags Faces
+// @Accept json
+// @Produce json
+// @Param id path string true "Folder ID"
+// @Param request body ClusterFacesRequest false "Clustering options"
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/folders/{id}/faces/cluster [post]
+func (h *FaceHandler) ClusterFaces(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid folder ID", err)
+	}
+
+	var req ClusterFacesRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+		}
+	}
+	threshold := req.Threshold
+	if threshold == 0 {
+		threshold = h.cfg.SearchDefaultThreshold
+	}
+	_, threshold = h.searchParams(1, threshold)
+
+	result, err := h.faceService.ClusterFaces(c.Context(), userCtx.ID, folderID, threshold)
+	if err != nil {
+		if errors.Is(err, services.ErrFolderNotFound) {
+			return utils.NotFoundResponse(c, "Folder not found")
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to cluster faces", err)
+	}
+
+	response := make([]FaceClusterResponse, len(result.Clusters))
+	for i, cluster := range result.Clusters {
+		faceIDs := make([]string, len(cluster.FaceIDs))
+		for j, id := range cluster.FaceIDs {
+			faceIDs[j] = id.String()
+		}
+		samples := make([]ClusterFaceResponse, len(cluster.Samples))
+		for j, f := range cluster.Samples {
+			samples[j] = ClusterFaceResponse{
+				FaceID:       f.ID.String(),
+				PhotoID:      f.PhotoID.String(),
+				DriveFileID:  f.Photo.DriveFileID,
+				FileName:     f.Photo.FileName,
+				ThumbnailURL: f.Photo.ThumbnailURL,
+				BboxX:        f.BboxX,
+				BboxY:        f.BboxY,
+				BboxWidth:    f.BboxWidth,
+				BboxHeight:   f.BboxHeight,
+				Confidence:   f.Confidence,
+			}
+		}
+		response[i] = FaceClusterResponse{
+			Size:    len(cluster.FaceIDs),
+			FaceIDs: faceIDs,
+			Samples: samples,
+		}
+		if len(samples) > 0 {
+			response[i].Representative = &samples[0]
+		}
+	}
+
+	return utils.SuccessResponse(c, "Faces clustered", fiber.Map{
+		"clusters":         response,
+		"count":            len(response),
+		"threshold":        threshold,
+		"faces_considered": result.FacesConsidered,
+		"max_faces":        result.MaxFaces,
+	})
+}
+
+// ReprocessFolder queues every photo of a folder for face processing again
+// @Summary Reprocess all faces of a folder
+// @Description Deletes the folder's faces (person tags included) and resets all its photos to pending, e.g. after a face model upgrade
+// @Tags Faces
+// @Produce json
+// @Param id path string true "Folder ID"
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/folders/{id}/faces/reprocess [post]
+func (h *FaceHandler) ReprocessFolder(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid folder ID", err)
+	}
+
+	count, err := h.faceService.ReprocessFolder(c.Context(), userCtx.ID, folderID)
+	if err != nil {
+		if errors.Is(err, services.ErrFolderNotFound) {
+			return utils.NotFoundResponse(c, "Folder not found")
+		}
+		if errors.Is(err, services.ErrFaceProcessingDisabled) {
+			return utils.ErrorResponse(c, fiber.StatusConflict, "โฟลเดอร์นี้ปิดการประมวลผลใบหน้าอยู่", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to reprocess folder", err)
+	}
+
+	return utils.SuccessResponse(c, "Reprocess initiated", map[string]interface{}{
+		"reset_count": count,
+	})
+}
+
 // GetFaces returns paginated faces for a user
 // @Summary Get all faces with pagination
 // @Tags Faces
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Page size" default(50)
+// @Param assigned query bool false "Filter by person assignment (false = faces still needing a name)"
 // @Success 200 {object} utils.Response
 // @Router /api/v1/faces [get]
 func (h *FaceHandler) GetFaces(c *fiber.Ctx) error {
@@ -428,7 +1152,21 @@ func (h *FaceHandler) GetFaces(c *fiber.Ctx) error {
 		limit = 50
 	}
 
-	faces, total, err := h.faceService.GetFaces(c.Context(), userCtx.ID, page, limit)
+	// Optional person assignment filter
+	var assigned *bool
+	switch c.Query("assigned") {
+	case "":
+	case "true":
+		v := true
+		assigned = &v
+	case "false":
+		v := false
+		assigned = &v
+	default:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "assigned must be true or false", nil)
+	}
+
+	faces, total, err := h.faceService.GetFaces(c.Context(), userCtx.ID, page, limit, assigned)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get faces", err)
 	}
@@ -460,6 +1198,320 @@ func (h *FaceHandler) GetFaces(c *fiber.Ctx) error {
 	})
 }
 
+// GetLowConfidenceFaces returns faces in a folder detected below a confidence threshold
+// @Summary Get low-confidence faces for review
+// @Description Faces with confidence below max (least confident first), with bounding box and photo thumbnail for cropping
+// @Tags Faces
+// @Produce json
+// @Param id path string true "Folder ID"
+// @Param max query number false "Confidence threshold (exclusive)" default(0.8)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size" default(50)
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/folders/{id}/faces/low-confidence [get]
+func (h *FaceHandler) GetLowConfidenceFaces(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid folder ID", err)
+	}
+
+	maxConfidence := c.QueryFloat("max", 0.8)
+	if maxConfidence <= 0 || maxConfidence > 1 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "max must be between 0 and 1", nil)
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 50)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	faces, total, err := h.faceService.GetLowConfidenceFaces(c.Context(), userCtx.ID, folderID, maxConfidence, page, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrFolderNotFound) {
+			return utils.NotFoundResponse(c, "Folder not found")
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get faces", err)
+	}
+
+	response := make([]LowConfidenceFaceResponse, len(faces))
+	for i, f := range faces {
+		response[i] = LowConfidenceFaceResponse{
+			FaceID:       f.ID.String(),
+			PhotoID:      f.PhotoID.String(),
+			DriveFileID:  f.Photo.DriveFileID,
+			FileName:     f.Photo.FileName,
+			ThumbnailURL: f.Photo.ThumbnailURL,
+			BboxX:        f.BboxX,
+			BboxY:        f.BboxY,
+			BboxWidth:    f.BboxWidth,
+			BboxHeight:   f.BboxHeight,
+			Confidence:   f.Confidence,
+		}
+		if f.PersonID != nil {
+			personID := f.PersonID.String()
+			response[i].PersonID = &personID
+		}
+	}
+
+	return utils.SuccessResponse(c, "Faces retrieved", fiber.Map{
+		"faces": response,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+		"max":   maxConfidence,
+	})
+}
+
+// DeleteFaces deletes faces in bulk (e.g. false positive detections)
+// @Summary Bulk delete faces
+// @Description Deletes faces the user can access and decrements the owning photos' face_count
+// @Tags Faces
+// @Accept json
+// @Produce json
+// @Param request body DeleteFacesRequest true "Face IDs"
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/faces/delete [post]
+func (h *FaceHandler) DeleteFaces(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	var req DeleteFacesRequest
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
+	}
+
+	if len(req.FaceIDs) > maxBulkFaces {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Too many faces in one request", nil)
+	}
+
+	result, err := h.faceService.DeleteFaces(c.Context(), userCtx.ID, req.FaceIDs)
+	if err != nil {
+		if errors.Is(err, services.ErrFaceNotFound) {
+			return utils.NotFoundResponse(c, err.Error())
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to delete faces", err)
+	}
+
+	return utils.SuccessResponse(c, "Faces deleted", fiber.Map{
+		"requested_count": len(req.FaceIDs),
+		"deleted_count":   result.DeletedCount,
+		"photos_updated":  result.PhotosUpdated,
+		"persons_updated": result.PersonsUpdated,
+	})
+}
+
+// RedactPerson excludes all faces of a person from search (privacy opt-out)
+// @Summary Redact a person
+// @Description Marks the person as redacted; their current and future faces are excluded from all face searches. Photos stay viewable.
+// @Tags Faces
+// @Produce json
+// @Param id path string true "Person ID"
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/persons/{id}/redact [post]
+func (h *FaceHandler) RedactPerson(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	personID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid person ID", err)
+	}
+
+	redacted, err := h.faceService.RedactPerson(c.Context(), userCtx.ID, personID)
+	if err != nil {
+		if errors.Is(err, services.ErrPersonNotFound) {
+			return utils.NotFoundResponse(c, err.Error())
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to redact person", err)
+	}
+
+	return utils.SuccessResponse(c, "Person redacted", fiber.Map{
+		"person_id":      personID.String(),
+		"faces_redacted": redacted,
+	})
+}
+
+// MergePersons merges another person (the same individual) into the person in the path
+// @Summary Merge two persons
+// @Description Moves all faces of merge_id to the person in the path, recomputes its face count and search centroid, and deletes merge_id. Both persons must be yours.
+// @Tags Faces
+// @Accept json
+// @Produce json
+// @Param id path string true "Person ID to keep"
+// @Param request body MergePersonsRequest true "Person to merge"
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/persons/{id}/merge [post]
+func (h *FaceHandler) MergePersons(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	keepID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid person ID", err)
+	}
+
+	var req MergePersonsRequest
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
+	}
+
+	person, err := h.faceService.MergePersons(c.Context(), userCtx.ID, keepID, req.MergeID)
+	if err != nil {
+		if errors.Is(err, services.ErrPersonNotFound) {
+			return utils.NotFoundResponse(c, err.Error())
+		}
+		if errors.Is(err, services.ErrSamePerson) {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่สามารถรวมบุคคลเข้ากับตัวเองได้", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to merge persons", err)
+	}
+
+	return utils.SuccessResponse(c, "Persons merged", toPersonResponse(person))
+}
+
+// SplitPerson moves some faces of a person to a new person
+// @Summary Split a person
+// @Description Creates a new person with the given name and moves the selected faces to it. Every face must currently be tagged as the person in the path.
+// @Tags Faces
+// @Accept json
+// @Produce json
+// @Param id path string true "Person ID to split"
+// @Param request body SplitPersonRequest true "Faces to move and the new person's name"
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/persons/{id}/split [post]
+func (h *FaceHandler) SplitPerson(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	personID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid person ID", err)
+	}
+
+	var req SplitPersonRequest
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
+	}
+
+	person, err := h.faceService.SplitPerson(c.Context(), userCtx.ID, personID, req.FaceIDs, req.Name)
+	if err != nil {
+		if errors.Is(err, services.ErrPersonNotFound) {
+			return utils.NotFoundResponse(c, err.Error())
+		}
+		if errors.Is(err, services.ErrFaceNotFound) {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "บางใบหน้าไม่ได้ถูกระบุเป็นบุคคลนี้", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to split person", err)
+	}
+
+	return utils.SuccessResponse(c, "Person split", toPersonResponse(person))
+}
+
+// toPersonResponse converts a person to its API response
+func toPersonResponse(p *models.Person) PersonResponse {
+	return PersonResponse{
+		PersonID:  p.ID.String(),
+		Name:      p.Name,
+		FaceCount: p.FaceCount,
+		Redacted:  p.Redacted,
+	}
+}
+
+// GetPersonFolders lists the shared folders a person appears in
+// @Summary Get folders of a person
+// @Description Distinct shared folders (events) containing the person's faces, with photo and face counts, most photos first. Only folders the user can access are included; trashed photos are not counted.
+// @Tags Faces
+// @Produce json
+// @Param id path string true "Person ID"
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/persons/{id}/folders [get]
+func (h *FaceHandler) GetPersonFolders(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	personID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid person ID", err)
+	}
+
+	folders, err := h.faceService.GetPersonFolders(c.Context(), userCtx.ID, personID)
+	if err != nil {
+		if errors.Is(err, services.ErrPersonNotFound) {
+			return utils.NotFoundResponse(c, err.Error())
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get person folders", err)
+	}
+
+	return utils.SuccessResponse(c, "Person folders retrieved", fiber.Map{
+		"person_id": personID.String(),
+		"folders":   folders,
+	})
+}
+
+// RedactFaces excludes specific faces from search (privacy opt-out)
+// @Summary Redact faces
+// @Tags Faces
+// @Accept json
+// @Produce json
+// @Param request body RedactFacesRequest true "Face IDs"
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/faces/redact [post]
+func (h *FaceHandler) RedactFaces(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	var req RedactFacesRequest
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
+	}
+
+	if len(req.FaceIDs) > maxBulkFaces {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Too many faces in one request", nil)
+	}
+
+	redacted, err := h.faceService.RedactFaces(c.Context(), userCtx.ID, req.FaceIDs)
+	if err != nil {
+		if errors.Is(err, services.ErrFaceNotFound) {
+			return utils.NotFoundResponse(c, err.Error())
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to redact faces", err)
+	}
+
+	return utils.SuccessResponse(c, "Faces redacted", fiber.Map{
+		"requested_count": len(req.FaceIDs),
+		"redacted_count":  redacted,
+	})
+}
+
 // GetPendingPhotos returns photos waiting for face processing
 // @Summary Get pending photos for face processing
 // @Tags Faces
@@ -524,6 +1576,7 @@ func (h *FaceHandler) GetPendingPhotos(c *fiber.Ctx) error {
 // ResetPhotosRequest is the request for resetting photos to pending
 type ResetPhotosRequest struct {
 	PhotoIDs []string `json:"photo_ids" validate:"required,min=1"`
+	Force    bool     `json:"force"` // Re-extract even photos the current face model already found faceless
 }
 
 // ResetStuckProcessing resets photos stuck in "processing" status back to "pending"
@@ -554,8 +1607,74 @@ func (h *FaceHandler) ResetStuckProcessing(c *fiber.Ctx) error {
 	})
 }
 
+// PauseFaceWorkerRequest is the body of POST /admin/face-worker/pause
+type PauseFaceWorkerRequest struct {
+	// Resumes on its own afterwards; capped at a day so a forgotten pause can't stop face processing for long
+	Minutes int    `json:"minutes" validate:"required,min=1,max=1440"`
+	Reason  string `json:"reason" validate:"omitempty,max=200"`
+}
+
+// GetFaceWorkerStatus returns the face worker's stats, including its pause state
+// @Summary Get face worker status
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/admin/face-worker [get]
+func (h *FaceHandler) GetFaceWorkerStatus(c *fiber.Ctx) error {
+	if h.faceWorker == nil {
+		return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, "Face processing is disabled", nil)
+	}
+	return utils.SuccessResponse(c, "Face worker status retrieved", h.faceWorker.GetStats())
+}
+
+// PauseFaceWorker stops face processing for a while (e.g. during a large import) and resumes it automatically
+// @Summary Pause face worker
+// @Description New batches are skipped until the pause expires or is resumed; a batch already running is finished
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body PauseFaceWorkerRequest true "Pause duration (1-1440 minutes)"
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/admin/face-worker/pause [post]
+func (h *FaceHandler) PauseFaceWorker(c *fiber.Ctx) error {
+	if h.faceWorker == nil {
+		return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, "Face processing is disabled", nil)
+	}
+
+	var req PauseFaceWorkerRequest
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
+	}
+
+	until := h.faceWorker.Pause(time.Duration(req.Minutes)*time.Minute, req.Reason)
+
+	return utils.SuccessResponse(c, "Face worker paused", fiber.Map{
+		"paused_until": until,
+	})
+}
+
+// ResumeFaceWorker ends an admin pause of the face worker
+// @Summary Resume face worker
+// @Description Ends an admin pause early; the automatic sync backlog pause still applies
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/admin/face-worker/resume [post]
+func (h *FaceHandler) ResumeFaceWorker(c *fiber.Ctx) error {
+	if h.faceWorker == nil {
+		return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, "Face processing is disabled", nil)
+	}
+
+	h.faceWorker.Resume()
+	return utils.SuccessResponse(c, "Face worker resumed", h.faceWorker.GetStats())
+}
+
 // ResetPhotosToPending resets specific photos to pending for reprocessing
 // @Summary Reset photos to pending status for reprocessing
+// @Description Photos the current face model already found faceless are completed without another extraction unless force is true
 // @Tags Faces
 // @Accept json
 // @Produce json
@@ -570,8 +1689,8 @@ func (h *FaceHandler) ResetPhotosToPending(c *fiber.Ctx) error {
 	}
 
 	var req ResetPhotosRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	// Convert string IDs to UUIDs
@@ -588,7 +1707,7 @@ func (h *FaceHandler) ResetPhotosToPending(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "No valid photo IDs provided", nil)
 	}
 
-	count, err := h.faceService.ResetPhotosToPending(c.Context(), userCtx.ID, photoIDs)
+	count, err := h.faceService.ResetPhotosToPending(c.Context(), userCtx.ID, photoIDs, req.Force)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to reset photos", err)
 	}
@@ -600,13 +1719,7 @@ func (h *FaceHandler) ResetPhotosToPending(c *fiber.Ctx) error {
 
 // isValidImageType checks if the content type is a valid image
 func isValidImageType(contentType string) bool {
-	validTypes := []string{
-		"image/jpeg",
-		"image/png",
-		"image/webp",
-		"image/gif",
-	}
-	for _, t := range validTypes {
+	for _, t := range validImageTypes {
 		if contentType == t {
 			return true
 		}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/config"
+	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/utils"
+)
+
+func TestMain(m *testing.M) {
+	// Keep handler logs out of the package directory
+	logDir, err := os.MkdirTemp("", "handler-test-logs")
+	if err == nil {
+		logger.Init(logDir, false)
+		logger.SetOutput(logger.OutputStdout)
+	}
+	code := m.Run()
+	os.RemoveAll(logDir)
+	os.Exit(code)
+}
+
+// fakeFaceService records the images handed to face detection and search
+type fakeFaceService struct {
+	services.FaceService
+	images [][]byte
+}
+
+func (s *fakeFaceService) DetectFaces(ctx context.Context, imageData []byte, mimeType string) ([]services.DetectedFace, error) {
+	s.images = append(s.images, imageData)
+	return []services.DetectedFace{{Index: 0}}, nil
+}
+
+func (s *fakeFaceService) SearchByImageWithIndex(ctx context.Context, userID uuid.UUID, imageData []byte, mimeType string, faceIndex int, limit int, threshold float64) ([]services.FaceSearchResult, error) {
+	s.images = append(s.images, imageData)
+	return nil, nil
+}
+
+func (s *fakeFaceService) DetectAndSearch(ctx context.Context, userID uuid.UUID, imageData []byte, mimeType string, faceIndex int, limit int, threshold float64) (*services.DetectSearchResult, error) {
+	s.images = append(s.images, imageData)
+	return &services.DetectSearchResult{}, nil
+}
+
+func (s *fakeFaceService) GetFaceIndexStatus(ctx context.Context, userID uuid.UUID) (*services.FaceIndexStatus, error) {
+	return &services.FaceIndexStatus{Complete: true}, nil
+}
+
+// imageUpload returns a multipart body with data as its "image" JPEG file
+func imageUpload(t *testing.T, data []byte) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="image"; filename="selfie.jpg"`)
+	header.Set("Content-Type", "image/jpeg")
+	part, err := form.CreatePart(header)
+	if err != nil {
+		t.Fatalf("create part: %v", err)
+	}
+	part.Write(data)
+	form.Close()
+	return body, form.FormDataContentType()
+}
+
+func TestFaceUploadsReachServiceWhole(t *testing.T) {
+	// Several MB, so the upload is read in many chunks; the pattern makes a short or
+	// reordered read show up as a mismatch
+	image := make([]byte, 6*1024*1024+123)
+	for i := range image {
+		image[i] = byte(i % 251)
+	}
+
+	h := NewFaceHandler(nil, config.FaceAPIConfig{
+		MaxUploadMB:            10,
+		SearchDefaultLimit:     20,
+		SearchMaxLimit:         100,
+		SearchDefaultThreshold: 0.6,
+	})
+	app := fiber.New(fiber.Config{BodyLimit: 100 * 1024 * 1024}) // APP_BODY_LIMIT_MB default
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &utils.UserContext{ID: uuid.New()})
+		return c.Next()
+	})
+	app.Post("/detect", h.DetectFaces)
+	app.Post("/search/image", h.SearchByImage)
+	app.Post("/search/detect", h.DetectAndSearch)
+
+	for _, route := range []string{"detect", "search/image", "search/detect"} {
+		t.Run(route, func(t *testing.T) {
+			faceService := &fakeFaceService{}
+			h.faceService = faceService
+
+			body, contentType := imageUpload(t, image)
+			req := httptest.NewRequest("POST", "/"+route, body)
+			req.Header.Set("Content-Type", contentType)
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			if len(faceService.images) != 1 {
+				t.Fatalf("face service got %d images, want 1", len(faceService.images))
+			}
+			if got := faceService.images[0]; !bytes.Equal(got, image) {
+				t.Errorf("face service got %d bytes, want the %d uploaded bytes unchanged", len(got), len(image))
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"time"
+
 	"gofiber-template/domain/repositories"
 	"gofiber-template/domain/services"
 	"gofiber-template/pkg/config"
+	"gofiber-template/pkg/utils"
 )
 
 // Services contains all the services needed for handlers
@@ -18,6 +21,8 @@ type Services struct {
 	NewsService         services.NewsService
 	SharedFolderService services.SharedFolderService
 	ActivityLogService  services.ActivityLogService
+	CurationService     services.CurationService
+	PublicSearchService services.PublicSearchService
 }
 
 // Repositories contains repositories needed for some handlers
@@ -25,6 +30,7 @@ type Repositories struct {
 	PhotoRepository        repositories.PhotoRepository
 	SharedFolderRepository repositories.SharedFolderRepository
 	UserRepository         repositories.UserRepository
+	SyncSummaryRepository  repositories.SyncSummaryRepository
 }
 
 // Handlers contains all HTTP handlers
@@ -40,6 +46,9 @@ type Handlers struct {
 	SharedFolderHandler *SharedFolderHandler
 	LogHandler          *LogHandler
 	ActivityLogHandler  *ActivityLogHandler
+	CurationHandler     *CurationHandler
+	SyncSummaryHandler  *SyncSummaryHandler
+	PublicSearchHandler *PublicSearchHandler
 
 	// Short accessors for routes
 	User         *UserHandler
@@ -53,6 +62,9 @@ type Handlers struct {
 	SharedFolder *SharedFolderHandler
 	Log          *LogHandler
 	ActivityLog  *ActivityLogHandler
+	Curation     *CurationHandler
+	SyncSummary  *SyncSummaryHandler
+	PublicSearch *PublicSearchHandler
 }
 
 // NewHandlers creates a new instance of Handlers with all dependencies
@@ -63,7 +75,7 @@ func NewHandlers(services *Services, repos *Repositories, cfg *config.Config) *H
 	jobHandler := NewJobHandler(services.JobService)
 	authHandler := NewAuthHandler(services.AuthService)
 	driveHandler := NewDriveHandler(services.DriveService)
-	faceHandler := NewFaceHandler(services.FaceService)
+	faceHandler := NewFaceHandler(services.FaceService, cfg.FaceAPI)
 	newsHandler := NewNewsHandler(services.NewsService)
 	logHandler := NewLogHandler(cfg)
 
@@ -75,11 +87,41 @@ func NewHandlers(services *Services, repos *Repositories, cfg *config.Config) *H
 			repos.PhotoRepository,
 			repos.SharedFolderRepository,
 			repos.UserRepository,
+			cfg.Folder.MaxPhotoUploadMB,
 		)
 		// Wire shared folder service to drive handler for webhook support
 		driveHandler.SetSharedFolderService(services.SharedFolderService)
 	}
 
+	// Webhook channel tokens are signed by the Drive client with the same secret
+	driveHandler.SetWebhookSigner(utils.NewWebhookTokenSigner(cfg.GoogleDrive.WebhookSecret), cfg.GoogleDrive.WebhookRequireSigned, cfg.GoogleDrive.WebhookUnsignedUserUntil)
+
+	// Admin remediation endpoints use the same token as the log endpoints
+	adminToken := cfg.Admin.Token
+	if adminToken == "" {
+		adminToken = cfg.JWT.Secret
+	}
+	driveHandler.SetAdminToken(adminToken)
+
+	// Signed thumbnail URLs (optional)
+	var publicSearchHandler *PublicSearchHandler
+	if cfg.Thumbnail.SignedURLEnabled {
+		secret := cfg.Thumbnail.SigningSecret
+		if secret == "" {
+			secret = cfg.JWT.Secret
+		}
+		signer := utils.NewThumbnailSigner(secret, time.Duration(cfg.Thumbnail.SignedURLTTLMinutes)*time.Minute, "/api/v1/drive/thumbnail/signed")
+		driveHandler.SetThumbnailSigner(signer)
+		if sharedFolderHandler != nil {
+			sharedFolderHandler.SetThumbnailSigner(signer)
+		}
+		// Anonymous results must never expose raw Drive thumbnail URLs, so public
+		// search is only available with signing
+		if services.PublicSearchService != nil {
+			publicSearchHandler = NewPublicSearchHandler(services.PublicSearchService, cfg.PublicSearch, signer)
+		}
+	}
+
 	if services.ActivityLogService != nil && repos != nil {
 		activityLogHandler = NewActivityLogHandler(
 			services.ActivityLogService,
@@ -87,6 +129,16 @@ func NewHandlers(services *Services, repos *Repositories, cfg *config.Config) *H
 		)
 	}
 
+	var curationHandler *CurationHandler
+	if services.CurationService != nil {
+		curationHandler = NewCurationHandler(services.CurationService, time.Duration(cfg.Curation.UndoWindowMinutes)*time.Minute)
+	}
+
+	var syncSummaryHandler *SyncSummaryHandler
+	if repos != nil && repos.SyncSummaryRepository != nil {
+		syncSummaryHandler = NewSyncSummaryHandler(repos.SyncSummaryRepository)
+	}
+
 	return &Handlers{
 		UserHandler:         userHandler,
 		TaskHandler:         taskHandler,
@@ -99,6 +151,9 @@ func NewHandlers(services *Services, repos *Repositories, cfg *config.Config) *H
 		SharedFolderHandler: sharedFolderHandler,
 		LogHandler:          logHandler,
 		ActivityLogHandler:  activityLogHandler,
+		CurationHandler:     curationHandler,
+		SyncSummaryHandler:  syncSummaryHandler,
+		PublicSearchHandler: publicSearchHandler,
 
 		// Short accessors
 		User:         userHandler,
@@ -112,5 +167,8 @@ func NewHandlers(services *Services, repos *Repositories, cfg *config.Config) *H
 		SharedFolder: sharedFolderHandler,
 		Log:          logHandler,
 		ActivityLog:  activityLogHandler,
+		Curation:     curationHandler,
+		SyncSummary:  syncSummaryHandler,
+		PublicSearch: publicSearchHandler,
 	}
 }
\ No newline at end of file
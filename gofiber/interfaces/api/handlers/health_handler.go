@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -10,7 +11,9 @@ import (
 	"gofiber-template/domain/models"
 	"gofiber-template/domain/repositories"
 	"gofiber-template/infrastructure/faceapi"
+	"gofiber-template/infrastructure/googledrive"
 	"gofiber-template/infrastructure/redis"
+	"gofiber-template/infrastructure/storage"
 )
 
 // HealthHandler handles health check endpoints
@@ -19,6 +22,8 @@ type HealthHandler struct {
 	redisClient     *redis.RedisClient
 	faceClient      *faceapi.FaceClient
 	photoRepository repositories.PhotoRepository
+	bunnyStorage    storage.BunnyStorage
+	driveLimiter    *googledrive.AccountLimiter
 }
 
 // NewHealthHandler creates a new health handler
@@ -27,12 +32,16 @@ func NewHealthHandler(
 	redisClient *redis.RedisClient,
 	faceClient *faceapi.FaceClient,
 	photoRepository repositories.PhotoRepository,
+	bunnyStorage storage.BunnyStorage,
+	driveLimiter *googledrive.AccountLimiter,
 ) *HealthHandler {
 	return &HealthHandler{
 		db:              db,
 		redisClient:     redisClient,
 		faceClient:      faceClient,
 		photoRepository: photoRepository,
+		bunnyStorage:    bunnyStorage,
+		driveLimiter:    driveLimiter,
 	}
 }
 
@@ -49,6 +58,9 @@ type DetailedHealthResponse struct {
 	Timestamp  time.Time                  `json:"timestamp"`
 	Components map[string]ComponentHealth `json:"components"`
 	Metrics    *HealthMetrics             `json:"metrics,omitempty"`
+
+	// Per Google account Drive call rate limiter utilization
+	DriveRateLimit *googledrive.AccountLimiterStats `json:"drive_rate_limit,omitempty"`
 }
 
 // HealthMetrics contains various system metrics
@@ -101,6 +113,20 @@ func (h *HealthHandler) DetailedHealth(c *fiber.Ctx) error {
 		allHealthy = false
 	}
 
+	// Check Bunny storage (failures fall back to proxying, so only degraded)
+	bunnyHealth := h.checkBunnyStorage()
+	response.Components["bunny_storage"] = bunnyHealth
+	if bunnyHealth.Status == "error" {
+		allHealthy = false
+	}
+
+	// Drive rate limiting (waiting calls are expected under load, so never a failure)
+	if h.driveLimiter != nil {
+		stats := h.driveLimiter.Stats()
+		response.DriveRateLimit = &stats
+		response.Components["drive_rate_limit"] = h.checkDriveRateLimit(stats)
+	}
+
 	// Get metrics (only if DB is ok)
 	if dbHealth.Status == "ok" {
 		metrics := h.getMetrics(ctx)
@@ -211,6 +237,64 @@ func (h *HealthHandler) checkFaceAPI(ctx context.Context) ComponentHealth {
 	}
 }
 
+func (h *HealthHandler) checkBunnyStorage() ComponentHealth {
+	if h.bunnyStorage == nil {
+		return ComponentHealth{
+			Status:  "unavailable",
+			Message: "Bunny storage not configured",
+		}
+	}
+
+	health := h.bunnyStorage.Health()
+	if !health.Configured {
+		return ComponentHealth{
+			Status:  "unavailable",
+			Message: "Bunny storage not configured",
+		}
+	}
+
+	if !health.Available {
+		message := "Circuit open, falling back to proxy"
+		if health.OpenUntil != nil {
+			message += " until " + health.OpenUntil.Format(time.RFC3339)
+		}
+		if health.LastError != "" {
+			message += ": " + health.LastError
+		}
+		return ComponentHealth{
+			Status:  "error",
+			Message: message,
+		}
+	}
+
+	message := "Available"
+	if health.ConsecutiveFailures > 0 {
+		message = fmt.Sprintf("Available (%d recent failures)", health.ConsecutiveFailures)
+	}
+	return ComponentHealth{
+		Status:  "ok",
+		Message: message,
+	}
+}
+
+func (h *HealthHandler) checkDriveRateLimit(stats googledrive.AccountLimiterStats) ComponentHealth {
+	if !stats.Enabled {
+		return ComponentHealth{
+			Status:  "unavailable",
+			Message: "Drive rate limiting disabled",
+		}
+	}
+
+	waiting := 0
+	for _, account := range stats.Accounts {
+		waiting += account.Waiting
+	}
+	return ComponentHealth{
+		Status:  "ok",
+		Message: fmt.Sprintf("%d accounts active, %d calls waiting", len(stats.Accounts), waiting),
+	}
+}
+
 func (h *HealthHandler) getMetrics(ctx context.Context) *HealthMetrics {
 	if h.photoRepository == nil {
 		return nil
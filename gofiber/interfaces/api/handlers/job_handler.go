@@ -21,17 +21,8 @@ func NewJobHandler(jobService services.JobService) *JobHandler {
 
 func (h *JobHandler) CreateJob(c *fiber.Ctx) error {
 	var req dto.CreateJobRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ValidationErrorResponse(c, "Invalid request body")
-	}
-
-	if err := utils.ValidateStruct(&req); err != nil {
-		errors := utils.GetValidationErrors(err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Validation failed",
-			"errors":  errors,
-		})
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	job, err := h.jobService.CreateJob(c.Context(), &req)
@@ -67,8 +58,8 @@ func (h *JobHandler) UpdateJob(c *fiber.Ctx) error {
 	}
 
 	var req dto.UpdateJobRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ValidationErrorResponse(c, "Invalid request body")
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	job, err := h.jobService.UpdateJob(c.Context(), jobID, &req)
@@ -20,10 +20,10 @@ func NewNewsHandler(newsService services.NewsService) *NewsHandler {
 
 // GenerateNewsRequest is the request body for news generation
 type GenerateNewsRequest struct {
-	PhotoIDs []string `json:"photo_ids"` // Optional: photos for context
-	Headings []string `json:"headings"`  // Optional: 4 custom headings
-	Tone     string   `json:"tone"`      // formal, friendly, news
-	Length   string   `json:"length"`    // short, medium, long
+	PhotoIDs []string `json:"photo_ids" validate:"omitempty,dive,uuid"`             // Optional: photos for context
+	Headings []string `json:"headings"`                                             // Optional: 4 custom headings
+	Tone     string   `json:"tone" validate:"omitempty,oneof=formal friendly news"` // formal, friendly, news
+	Length   string   `json:"length" validate:"omitempty,oneof=short medium long"`  // short, medium, long
 }
 
 // GenerateNews handles news generation from photos
@@ -41,8 +41,8 @@ func (h *NewsHandler) GenerateNews(c *fiber.Ctx) error {
 	}
 
 	var req GenerateNewsRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	// Parse photo IDs to UUIDs (optional)
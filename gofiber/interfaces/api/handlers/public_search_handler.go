@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/config"
+	"gofiber-template/pkg/utils"
+)
+
+const defaultPublicSearchExpiryHours = 72
+
+type PublicSearchHandler struct {
+	publicSearchService services.PublicSearchService
+	cfg                 config.PublicSearchConfig
+	thumbnailSigner     *utils.ThumbnailSigner
+}
+
+// NewPublicSearchHandler creates the public search handler. Results only carry thumbnails
+// signed by thumbnailSigner, never the photo's Drive thumbnail URL.
+func NewPublicSearchHandler(publicSearchService services.PublicSearchService, cfg config.PublicSearchConfig, thumbnailSigner *utils.ThumbnailSigner) *PublicSearchHandler {
+	return &PublicSearchHandler{
+		publicSearchService: publicSearchService,
+		cfg:                 cfg,
+		thumbnailSigner:     thumbnailSigner,
+	}
+}
+
+// CreateLink mints a public event search link for a folder
+// @Summary Create public event search link
+// @Description Folder owner creates a token link that lets anyone search this folder by selfie without logging in
+// @Tags Folders
+// @Accept json
+// @Produce json
+// @Param id path string true "Folder ID"
+// @Param request body dto.CreatePublicSearchLinkRequest false "Expiry and result cap"
+// @Success 200 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/v1/folders/{id}/public-search [post]
+func (h *PublicSearchHandler) CreateLink(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "Not authenticated")
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid folder ID", err)
+	}
+
+	var req dto.CreatePublicSearchLinkRequest
+	if len(c.Body()) > 0 {
+		if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+			return utils.FieldErrorsResponse(c, fieldErrors)
+		}
+	}
+
+	if req.ExpiresInHours == 0 {
+		req.ExpiresInHours = defaultPublicSearchExpiryHours
+	}
+	if req.ExpiresInHours < 1 || req.ExpiresInHours > h.cfg.MaxExpiryHours {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "expires_in_hours is out of range", nil)
+	}
+	if req.MaxResults == 0 {
+		req.MaxResults = services.DefaultPublicSearchResults
+	}
+	if req.MaxResults < 1 || req.MaxResults > h.cfg.MaxResults {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "max_results is out of range", nil)
+	}
+
+	link, err := h.publicSearchService.CreateLink(c.Context(), userCtx.ID, folderID, time.Duration(req.ExpiresInHours)*time.Hour, req.MaxResults)
+	if err != nil {
+		if errors.Is(err, services.ErrFolderNotFound) {
+			return utils.NotFoundResponse(c, "Folder not found")
+		}
+		if errors.Is(err, services.ErrNotFolderOwner) {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error(), nil)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create link", err)
+	}
+
+	return utils.SuccessResponse(c, "Public search link created", dto.PublicSearchLinkToResponse(link))
+}
+
+// Search runs an anonymous face search on a public event folder
+// @Summary Public event face search
+// @Description Upload a selfie to find your photos in a public event folder (no login, rate limited)
+// @Tags Public
+// @Accept multipart/form-data
+// @Produce json
+// @Param token path string true "Public search token"
+// @Param image formData file true "Image file"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 410 {object} utils.Response
+// @Router /api/v1/public/events/{token}/search [post]
+func (h *PublicSearchHandler) Search(c *fiber.Ctx) error {
+	token := c.Params("token")
+	if token == "" {
+		return utils.NotFoundResponse(c, "Link not found")
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Image file is required", err)
+	}
+
+	// Validate file size (max 10MB)
+	if file.Size > 10*1024*1024 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "File size exceeds 10MB limit", nil)
+	}
+
+	contentType := file.Header.Get("Content-Type")
+	if !isValidImageType(contentType) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid image type. Allowed: jpeg, png, webp, gif", nil)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to read file", err)
+	}
+	defer f.Close()
+
+	imageData, err := io.ReadAll(f)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to read file", err)
+	}
+
+	link, results, err := h.publicSearchService.Search(c.Context(), token, imageData, contentType, h.cfg.Threshold)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPublicLinkNotFound):
+			return utils.NotFoundResponse(c, "Link not found")
+		case errors.Is(err, services.ErrPublicLinkExpired):
+			return utils.ErrorResponse(c, fiber.StatusGone, "ลิงก์นี้หมดอายุแล้ว", nil)
+		case errors.Is(err, services.ErrNoFacesDetected):
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่พบใบหน้าในรูปภาพที่อัปโหลด กรุณาใช้รูปที่เห็นใบหน้าชัดเจน", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Face search failed", nil)
+	}
+
+	response := make([]dto.PublicSearchMatchResponse, len(results))
+	for i, r := range results {
+		response[i] = dto.PublicSearchMatchResponse{
+			PhotoID:      r.Photo.ID.String(),
+			FileName:     r.Photo.FileName,
+			ThumbnailURL: h.thumbnailSigner.SignURL(r.Photo.DriveFileID, defaultThumbnailSize, link.CreatedBy.String()),
+			BboxX:        r.Face.BboxX,
+			BboxY:        r.Face.BboxY,
+			BboxWidth:    r.Face.BboxWidth,
+			BboxHeight:   r.Face.BboxHeight,
+			Similarity:   r.Similarity,
+		}
+	}
+
+	return utils.SuccessResponse(c, "Search completed", fiber.Map{
+		"results":    response,
+		"count":      len(response),
+		"expires_at": link.ExpiresAt,
+	})
+}
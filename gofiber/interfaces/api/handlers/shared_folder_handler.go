@@ -2,6 +2,10 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -20,6 +24,8 @@ type SharedFolderHandler struct {
 	photoRepo           repositories.PhotoRepository
 	sharedFolderRepo    repositories.SharedFolderRepository
 	userRepo            repositories.UserRepository
+	thumbnailSigner     *utils.ThumbnailSigner
+	maxUploadMB         int // Max size of a directly uploaded photo
 }
 
 func NewSharedFolderHandler(
@@ -27,15 +33,22 @@ func NewSharedFolderHandler(
 	photoRepo repositories.PhotoRepository,
 	sharedFolderRepo repositories.SharedFolderRepository,
 	userRepo repositories.UserRepository,
+	maxUploadMB int,
 ) *SharedFolderHandler {
 	return &SharedFolderHandler{
 		sharedFolderService: sharedFolderService,
 		photoRepo:           photoRepo,
 		sharedFolderRepo:    sharedFolderRepo,
 		userRepo:            userRepo,
+		maxUploadMB:         maxUploadMB,
 	}
 }
 
+// SetThumbnailSigner enables signed thumbnail URLs in photo responses
+func (h *SharedFolderHandler) SetThumbnailSigner(signer *utils.ThumbnailSigner) {
+	h.thumbnailSigner = signer
+}
+
 // ListFolders returns all folders the user has access to (with sub-folders as children)
 // @Summary List user's folders
 // @Tags Folders
@@ -62,16 +75,15 @@ func (h *SharedFolderHandler) ListFolders(c *fiber.Ctx) error {
 	// Build response with counts and children (sub-folders)
 	responses := make([]dto.SharedFolderResponse, 0, len(folders))
 	for _, folder := range folders {
-		photoCount, _ := h.photoRepo.CountBySharedFolder(c.Context(), folder.ID)
 		userCount, _ := h.sharedFolderRepo.CountUsers(c.Context(), folder.ID)
-		response := dto.SharedFolderToResponse(&folder, photoCount, userCount)
+		response := dto.SharedFolderToResponse(&folder, folder.PhotoCount, userCount)
 
 		// Get sub-folders (children) for this folder
 		paths, _ := h.photoRepo.GetFolderPathsInSharedFolder(c.Context(), folder.ID)
 		children := make([]dto.SubFolderInfo, 0, len(paths))
 		for _, path := range paths {
 			// Get photo count for this path
-			_, count, _ := h.photoRepo.GetBySharedFolderAndPath(c.Context(), folder.ID, path, 0, 0)
+			_, count, _ := h.photoRepo.GetBySharedFolderAndPath(c.Context(), folder.ID, path, "", 0, 0)
 
 			// Extract folder name from path (last segment)
 			name := path
@@ -133,12 +145,81 @@ func (h *SharedFolderHandler) GetFolder(c *fiber.Ctx) error {
 		})
 	}
 
-	photoCount, _ := h.photoRepo.CountBySharedFolder(c.Context(), folder.ID)
 	userCount, _ := h.sharedFolderRepo.CountUsers(c.Context(), folder.ID)
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data":    dto.SharedFolderToResponse(folder, photoCount, userCount),
+		"data":    dto.SharedFolderToResponse(folder, folder.PhotoCount, userCount),
+	})
+}
+
+// UpdateFolder updates folder settings
+// @Summary Update folder settings
+// @Description Only the fields present in the body are changed. Turning face_processing_enabled on queues the folder's skipped photos for face processing
+// @Tags Folders
+// @Security BearerAuth
+// @Accept json
+// @Param id path string true "Folder ID"
+// @Param body body dto.UpdateFolderRequest true "Folder settings"
+// @Success 200 {object} dto.SharedFolderResponse
+// @Router /folders/{id} [put]
+func (h *SharedFolderHandler) UpdateFolder(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	var req dto.UpdateFolderRequest
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"errors":  fieldErrors,
+		})
+	}
+
+	var requeued int64
+	if req.FaceProcessingEnabled != nil {
+		requeued, err = h.sharedFolderService.SetFaceProcessingEnabled(c.Context(), userCtx.ID, folderID, *req.FaceProcessingEnabled)
+		if err != nil {
+			if errors.Is(err, services.ErrFolderNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"success": false,
+					"error":   "Folder not found",
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	folder, err := h.sharedFolderService.GetFolderByID(c.Context(), userCtx.ID, folderID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"success": false,
+			"error":   "Folder not found",
+		})
+	}
+
+	userCount, _ := h.sharedFolderRepo.CountUsers(c.Context(), folder.ID)
+
+	return c.JSON(fiber.Map{
+		"success":      true,
+		"data":         dto.SharedFolderToResponse(folder, folder.PhotoCount, userCount),
+		"queued_count": requeued,
 	})
 }
 
@@ -169,15 +250,17 @@ func (h *SharedFolderHandler) AddFolder(c *fiber.Ctx) error {
 
 	// Step 2: Parse request body
 	var req dto.AddFolderRequest
-	if err := c.BodyParser(&req); err != nil {
-		logger.DriveError("add_folder_parse_failed", "Failed to parse request body", err, map[string]interface{}{
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		logger.DriveError("add_folder_parse_failed", "Invalid request body", nil, map[string]interface{}{
 			"user_id":    userCtx.ID.String(),
 			"user_email": userCtx.Email,
 			"raw_body":   string(c.Body()),
+			"errors":     fieldErrors,
 		})
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"success": false,
-			"error":   "Invalid request body",
+			"error":   "Validation failed",
+			"errors":  fieldErrors,
 		})
 	}
 
@@ -188,17 +271,6 @@ func (h *SharedFolderHandler) AddFolder(c *fiber.Ctx) error {
 		"has_resource_key": req.DriveResourceKey != "",
 	})
 
-	if req.DriveFolderID == "" {
-		logger.DriveError("add_folder_missing_id", "drive_folder_id is empty", nil, map[string]interface{}{
-			"user_id":    userCtx.ID.String(),
-			"user_email": userCtx.Email,
-		})
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "drive_folder_id is required",
-		})
-	}
-
 	// Step 3: Get user's OAuth tokens from database
 	user, err := h.userRepo.GetByID(c.Context(), userCtx.ID)
 	if err != nil {
@@ -239,7 +311,7 @@ func (h *SharedFolderHandler) AddFolder(c *fiber.Ctx) error {
 		"drive_folder_id": req.DriveFolderID,
 	})
 
-	folder, err := h.sharedFolderService.AddFolder(c.Context(), userCtx.ID, req.DriveFolderID, req.DriveResourceKey, user.DriveAccessToken, user.DriveRefreshToken)
+	folder, err := h.sharedFolderService.AddFolder(c.Context(), userCtx.ID, req.DriveFolderID, req.DriveResourceKey, user.DriveAccessToken, user.DriveRefreshToken, req.DeferFaceProcessing)
 	if err != nil {
 		// Check if it's a Google token error
 		var tokenErr *serviceimpl.GoogleTokenError
@@ -256,6 +328,17 @@ func (h *SharedFolderHandler) AddFolder(c *fiber.Ctx) error {
 			})
 		}
 
+		// Check if user reached the folder limit
+		var limitErr *serviceimpl.FolderLimitError
+		if errors.As(err, &limitErr) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"success":    false,
+				"error":      limitErr.Message,
+				"error_code": limitErr.Code,
+				"limit":      limitErr.Limit,
+			})
+		}
+
 		logger.DriveError("add_folder_service_failed", "AddFolder service failed", err, map[string]interface{}{
 			"user_id":         userCtx.ID.String(),
 			"user_email":      userCtx.Email,
@@ -268,7 +351,7 @@ func (h *SharedFolderHandler) AddFolder(c *fiber.Ctx) error {
 		})
 	}
 
-	photoCount, _ := h.photoRepo.CountBySharedFolder(c.Context(), folder.ID)
+	photoCount := folder.PhotoCount
 	userCount, _ := h.sharedFolderRepo.CountUsers(c.Context(), folder.ID)
 
 	logger.Drive("add_folder_success", "Folder added successfully", map[string]interface{}{
@@ -329,7 +412,9 @@ func (h *SharedFolderHandler) RemoveFolder(c *fiber.Ctx) error {
 // @Tags Folders
 // @Security BearerAuth
 // @Param id path string true "Folder ID"
+// @Description With dry_run=true the sync only counts what it would add/update/delete (result in the sync:completed event with dryRun: true)
 // @Param force query bool false "Force full sync (re-sync all photos)"
+// @Param dry_run query bool false "Preview changes without writing anything"
 // @Success 200
 // @Router /folders/{id}/sync [post]
 func (h *SharedFolderHandler) TriggerSync(c *fiber.Ctx) error {
@@ -352,6 +437,31 @@ func (h *SharedFolderHandler) TriggerSync(c *fiber.Ctx) error {
 	// Check for force full sync parameter
 	forceFullSync := c.QueryBool("force", false)
 
+	if c.QueryBool("dry_run", false) {
+		job, err := h.sharedFolderService.TriggerDryRunSync(c.Context(), userCtx.ID, folderID, forceFullSync)
+		if err != nil {
+			if errors.Is(err, services.ErrFolderNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"success": false,
+					"error":   "Folder not found",
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Dry-run sync triggered",
+			"data": fiber.Map{
+				"job_id":  job.ID,
+				"dry_run": true,
+			},
+		})
+	}
+
 	if err := h.sharedFolderService.TriggerSync(c.Context(), userCtx.ID, folderID, forceFullSync); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
@@ -370,17 +480,17 @@ func (h *SharedFolderHandler) TriggerSync(c *fiber.Ctx) error {
 	})
 }
 
-// GetPhotos returns photos from a folder
-// @Summary Get photos from folder
+// SetExcludePaths sets the sub-folder paths that sync skips
+// @Summary Set sync exclude paths
+// @Description Paths are relative to the folder (e.g. "_raw", "2567/archive") and match sub-folders too. Already synced photos under them are kept. Empty list clears
 // @Tags Folders
 // @Security BearerAuth
+// @Accept json
 // @Param id path string true "Folder ID"
-// @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(50)
-// @Param folder_path query string false "Filter by sub-folder path"
-// @Success 200 {object} dto.PhotoListResponse
-// @Router /folders/{id}/photos [get]
-func (h *SharedFolderHandler) GetPhotos(c *fiber.Ctx) error {
+// @Param body body dto.SetExcludePathsRequest true "Exclude paths"
+// @Success 200
+// @Router /folders/{id}/exclude-paths [put]
+func (h *SharedFolderHandler) SetExcludePaths(c *fiber.Ctx) error {
 	userCtx, err := utils.GetUserFromContext(c)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -397,32 +507,23 @@ func (h *SharedFolderHandler) GetPhotos(c *fiber.Ctx) error {
 		})
 	}
 
-	// Verify access
-	hasAccess, err := h.sharedFolderRepo.HasUserAccess(c.Context(), userCtx.ID, folderID)
-	if err != nil || !hasAccess {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+	var req dto.SetExcludePathsRequest
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"success": false,
-			"error":   "Folder not found",
+			"error":   "Validation failed",
+			"errors":  fieldErrors,
 		})
 	}
 
-	page := c.QueryInt("page", 1)
-	limit := c.QueryInt("limit", 50)
-	offset := (page - 1) * limit
-	folderPath := c.Query("folder_path", "")
-
-	var photos []models.Photo
-	var total int64
-
-	if folderPath != "" {
-		// Filter by specific sub-folder path
-		photos, total, err = h.photoRepo.GetBySharedFolderAndPath(c.Context(), folderID, folderPath, offset, limit)
-	} else {
-		// Get all photos in shared folder
-		photos, total, err = h.photoRepo.GetBySharedFolder(c.Context(), folderID, offset, limit)
-	}
-
+	paths, err := h.sharedFolderService.SetExcludePaths(c.Context(), userCtx.ID, folderID, req.Paths)
 	if err != nil {
+		if errors.Is(err, services.ErrFolderNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Folder not found",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error":   err.Error(),
@@ -431,23 +532,21 @@ func (h *SharedFolderHandler) GetPhotos(c *fiber.Ctx) error {
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data": dto.PhotoListResponse{
-			Photos: dto.PhotosToPhotoResponses(photos),
-			Total:  total,
-			Page:   page,
-			Limit:  limit,
+		"data": fiber.Map{
+			"exclude_paths": paths,
 		},
 	})
 }
 
-// RegisterWebhook registers a webhook for an existing folder
-// @Summary Register webhook for folder
+// RefreshPhotoMetadata refreshes thumbnail/webView links and names of existing photos
+// @Summary Refresh photo metadata
+// @Description Re-fetch thumbnailLink/webViewLink/name from Google Drive without full re-sync (folder owner or admin only)
 // @Tags Folders
 // @Security BearerAuth
 // @Param id path string true "Folder ID"
 // @Success 200
-// @Router /folders/{id}/webhook [post]
-func (h *SharedFolderHandler) RegisterWebhook(c *fiber.Ctx) error {
+// @Router /folders/{id}/refresh-metadata [post]
+func (h *SharedFolderHandler) RefreshPhotoMetadata(c *fiber.Ctx) error {
 	userCtx, err := utils.GetUserFromContext(c)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -464,7 +563,32 @@ func (h *SharedFolderHandler) RegisterWebhook(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.sharedFolderService.RegisterWebhook(c.Context(), userCtx.ID, folderID); err != nil {
+	folder, err := h.sharedFolderRepo.GetByID(c.Context(), folderID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"success": false,
+			"error":   "Folder not found",
+		})
+	}
+
+	// Only folder owner or admin can refresh
+	if userCtx.Role != "admin" && folder.TokenOwnerID != userCtx.ID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"error":   "Only folder owner or admin can refresh metadata",
+		})
+	}
+
+	updated, err := h.sharedFolderService.RefreshPhotoMetadata(c.Context(), folderID)
+	if err != nil {
+		var tokenErr *serviceimpl.GoogleTokenError
+		if errors.As(err, &tokenErr) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success":    false,
+				"error":      tokenErr.Message,
+				"error_code": tokenErr.Code,
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error":   err.Error(),
@@ -473,18 +597,21 @@ func (h *SharedFolderHandler) RegisterWebhook(c *fiber.Ctx) error {
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "Webhook registered successfully",
+		"data": fiber.Map{
+			"updated_count": updated,
+		},
 	})
 }
 
-// ReconnectFolder updates a folder's Google tokens with the user's current tokens
-// @Summary Reconnect Google Drive to folder
+// EnableFaceProcessing queues skipped photos of a deferred folder for face processing
+// @Summary Enable face processing
+// @Description Turn off deferred face processing and move all skipped photos to pending
 // @Tags Folders
 // @Security BearerAuth
 // @Param id path string true "Folder ID"
 // @Success 200
-// @Router /folders/{id}/reconnect [post]
-func (h *SharedFolderHandler) ReconnectFolder(c *fiber.Ctx) error {
+// @Router /folders/{id}/faces/enable [post]
+func (h *SharedFolderHandler) EnableFaceProcessing(c *fiber.Ctx) error {
 	userCtx, err := utils.GetUserFromContext(c)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -501,54 +628,42 @@ func (h *SharedFolderHandler) ReconnectFolder(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get user's current Google tokens
-	user, err := h.userRepo.GetByID(c.Context(), userCtx.ID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "Failed to get user",
-		})
-	}
-
-	if user.DriveRefreshToken == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+	// Verify access
+	hasAccess, err := h.sharedFolderRepo.HasUserAccess(c.Context(), userCtx.ID, folderID)
+	if err != nil || !hasAccess {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"success": false,
-			"error":   "Google Drive not connected. Please reconnect your Google account first.",
+			"error":   "Folder not found",
 		})
 	}
 
-	// Update folder tokens
-	expiry := user.DriveTokenExpiry
-	if err := h.sharedFolderRepo.UpdateTokens(c.Context(), folderID, user.DriveAccessToken, user.DriveRefreshToken, expiry, userCtx.ID); err != nil {
+	enabled, err := h.sharedFolderService.EnableFaceProcessing(c.Context(), userCtx.ID, folderID)
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
-			"error":   "Failed to update folder tokens: " + err.Error(),
+			"error":   err.Error(),
 		})
 	}
 
-	// Clear error status after reconnect
-	h.sharedFolderRepo.UpdateSyncStatus(c.Context(), folderID, models.SyncStatusIdle, "")
-
-	// Try to register webhook with new tokens
-	_ = h.sharedFolderService.RegisterWebhook(c.Context(), userCtx.ID, folderID)
-
-	// Trigger sync to verify tokens work and refresh data
-	_ = h.sharedFolderService.TriggerSync(c.Context(), userCtx.ID, folderID, false)
-
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "Folder reconnected and sync triggered",
+		"data": fiber.Map{
+			"queued_count": enabled,
+		},
 	})
 }
 
-// GetSubFolders returns distinct sub-folder paths within a shared folder
-// @Summary Get sub-folders in a shared folder
+// UploadPhotos uploads images directly into a folder (stored on Bunny, bypassing Drive)
+// @Summary Upload photos to folder
+// @Description Upload one or more images (multipart field "photos"). Photos are stored on Bunny storage and queued for face processing
 // @Tags Folders
 // @Security BearerAuth
+// @Accept multipart/form-data
 // @Param id path string true "Folder ID"
-// @Success 200 {object} map[string]interface{}
-// @Router /folders/{id}/subfolders [get]
-func (h *SharedFolderHandler) GetSubFolders(c *fiber.Ctx) error {
+// @Param photos formData file true "Image files (jpeg, png, webp, gif)"
+// @Success 201
+// @Router /folders/{id}/photos/upload [post]
+func (h *SharedFolderHandler) UploadPhotos(c *fiber.Ctx) error {
 	userCtx, err := utils.GetUserFromContext(c)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -574,51 +689,1305 @@ func (h *SharedFolderHandler) GetSubFolders(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get distinct folder paths
-	paths, err := h.photoRepo.GetFolderPathsInSharedFolder(c.Context(), folderID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["photos"]) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"success": false,
-			"error":   err.Error(),
+			"error":   "No photos provided",
 		})
 	}
 
-	// Build response with photo counts for each sub-folder
-	type SubFolderInfo struct {
-		Path       string `json:"path"`
-		Name       string `json:"name"`
-		PhotoCount int64  `json:"photo_count"`
-	}
+	maxBytes := int64(h.maxUploadMB) * 1024 * 1024
+	uploaded := make([]dto.PhotoResponse, 0, len(form.File["photos"]))
+	failed := make([]fiber.Map, 0)
 
-	subFolders := make([]SubFolderInfo, 0, len(paths))
-	for _, path := range paths {
-		// Get photo count for this path
-		photos, count, _ := h.photoRepo.GetBySharedFolderAndPath(c.Context(), folderID, path, 0, 0)
-		_ = photos // We only need the count
+	for _, file := range form.File["photos"] {
+		contentType := file.Header.Get("Content-Type")
+		if !isValidImageType(contentType) {
+			failed = append(failed, fiber.Map{"file_name": file.Filename, "error": "Invalid file type. Allowed: jpeg, png, webp, gif"})
+			continue
+		}
+		if file.Size > maxBytes {
+			failed = append(failed, fiber.Map{"file_name": file.Filename, "error": fmt.Sprintf("File size exceeds %dMB limit", h.maxUploadMB)})
+			continue
+		}
 
-		// Extract the folder name from path (last segment)
-		name := path
-		if idx := len(path) - 1; idx >= 0 {
-			for i := len(path) - 1; i >= 0; i-- {
-				if path[i] == '/' {
-					name = path[i+1:]
-					break
-				}
+		f, err := file.Open()
+		if err != nil {
+			failed = append(failed, fiber.Map{"file_name": file.Filename, "error": "Failed to read file"})
+			continue
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			failed = append(failed, fiber.Map{"file_name": file.Filename, "error": "Failed to read file"})
+			continue
+		}
+
+		photo, err := h.sharedFolderService.UploadPhoto(c.Context(), userCtx.ID, folderID, file.Filename, contentType, data)
+		if err != nil {
+			if errors.Is(err, serviceimpl.ErrUploadStorageUnavailable) {
+				return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+					"success": false,
+					"error":   "Photo upload is not available",
+				})
 			}
+			failed = append(failed, fiber.Map{"file_name": file.Filename, "error": err.Error()})
+			continue
 		}
+		uploaded = append(uploaded, *dto.PhotoToPhotoResponse(photo))
+	}
 
-		subFolders = append(subFolders, SubFolderInfo{
-			Path:       path,
-			Name:       name,
-			PhotoCount: count,
+	status := fiber.StatusCreated
+	if len(uploaded) == 0 {
+		status = fiber.StatusBadRequest
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"success": len(uploaded) > 0,
+		"data": fiber.Map{
+			"uploaded": uploaded,
+			"failed":   failed,
+		},
+	})
+}
+
+// SetSyncSchedule sets or clears a folder's periodic sync schedule
+// @Summary Set folder sync schedule
+// @Description Periodically sync the folder on a cron schedule (UTC) in addition to webhooks. Empty cron clears the schedule
+// @Tags Folders
+// @Security BearerAuth
+// @Accept json
+// @Param id path string true "Folder ID"
+// @Param body body dto.SetSyncScheduleRequest true "Sync schedule"
+// @Success 200 {object} dto.SharedFolderResponse
+// @Router /folders/{id}/schedule [put]
+func (h *SharedFolderHandler) SetSyncSchedule(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	var req dto.SetSyncScheduleRequest
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"errors":  fieldErrors,
+		})
+	}
+
+	folder, err := h.sharedFolderService.SetSyncSchedule(c.Context(), userCtx.ID, folderID, req.Cron)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceimpl.ErrInvalidSyncSchedule):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		case errors.Is(err, services.ErrFolderNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Folder not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
 		})
 	}
 
+	userCount, _ := h.sharedFolderRepo.CountUsers(c.Context(), folder.ID)
+
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data": fiber.Map{
-			"subfolders": subFolders,
-			"total":      len(subFolders),
+		"data":    dto.SharedFolderToResponse(folder, folder.PhotoCount, userCount),
+	})
+}
+
+// SetOrphanPolicy sets how full sync handles photos that are no longer in Drive
+// @Summary Set orphan cleanup policy
+// @Description "delete" hard deletes missing photos, "trash" marks them trashed so they can be restored until purged. Empty uses the global default
+// @Tags Folders
+// @Security BearerAuth
+// @Accept json
+// @Param id path string true "Folder ID"
+// @Param body body dto.SetOrphanPolicyRequest true "Orphan policy"
+// @Success 200
+// @Router /folders/{id}/orphan-policy [put]
+func (h *SharedFolderHandler) SetOrphanPolicy(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	var req dto.SetOrphanPolicyRequest
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Validation failed",
+			"errors":  fieldErrors,
+		})
+	}
+
+	policy := models.OrphanPolicy(req.Policy)
+	if err := h.sharedFolderService.SetOrphanPolicy(c.Context(), userCtx.ID, folderID, policy); err != nil {
+		switch {
+		case errors.Is(err, serviceimpl.ErrInvalidOrphanPolicy):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Policy must be \"delete\", \"trash\" or empty",
+			})
+		case errors.Is(err, services.ErrFolderNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Folder not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"orphan_policy": req.Policy,
+		},
+	})
+}
+
+// CreateExport starts an async zip export of a folder
+// @Summary Export folder as zip
+// @Description Builds the zip server-side and uploads it to storage. Poll GET /exports/{id} or listen for export:* WebSocket events
+// @Tags Folders
+// @Security BearerAuth
+// @Accept json
+// @Param id path string true "Folder ID"
+// @Param body body dto.CreateExportRequest false "Export options"
+// @Success 202 {object} dto.ExportJobResponse
+// @Router /folders/{id}/export [post]
+func (h *SharedFolderHandler) CreateExport(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	var req dto.CreateExportRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid request body",
+			})
+		}
+	}
+
+	job, err := h.sharedFolderService.CreateExport(c.Context(), userCtx.ID, folderID, req.FolderPath)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"success": true,
+		"data":    dto.ExportJobToResponse(job),
+	})
+}
+
+// GetExport returns the status of a zip export job
+// @Summary Get export status
+// @Tags Exports
+// @Security BearerAuth
+// @Param id path string true "Export ID"
+// @Success 200 {object} dto.ExportJobResponse
+// @Router /exports/{id} [get]
+func (h *SharedFolderHandler) GetExport(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	exportID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid export ID",
+		})
+	}
+
+	job, err := h.sharedFolderService.GetExport(c.Context(), userCtx.ID, exportID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"success": false,
+			"error":   "Export not found",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    dto.ExportJobToResponse(job),
+	})
+}
+
+// GetPhotos returns photos from a folder
+// @Summary Get photos from folder
+// @Tags Folders
+// @Security BearerAuth
+// @Param id path string true "Folder ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(50)
+// @Param folder_path query string false "Filter by sub-folder path (ignored when search is set)"
+// @Param search query string false "Case-insensitive text to find in the whole folder"
+// @Param search_field query string false "Where to look for search: filename, path or both" default(both)
+// @Param from query string false "Only photos created in Drive at or after this time (RFC3339, ignored when search is set)"
+// @Param to query string false "Only photos created in Drive at or before this time (RFC3339, ignored when search is set)"
+// @Param sort query string false "created_desc, created_asc, name_asc, name_desc, modified_desc, taken_desc or taken_asc (capture time)" default(created_desc)
+// @Success 200 {object} dto.PhotoListResponse
+// @Router /folders/{id}/photos [get]
+func (h *SharedFolderHandler) GetPhotos(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	// Verify access
+	hasAccess, err := h.sharedFolderRepo.HasUserAccess(c.Context(), userCtx.ID, folderID)
+	if err != nil || !hasAccess {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"success": false,
+			"error":   "Folder not found",
+		})
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 50)
+	offset := (page - 1) * limit
+	folderPath := c.Query("folder_path", "")
+	search := strings.TrimSpace(c.Query("search", ""))
+	searchField := c.Query("search_field", "both")
+
+	sort := models.PhotoSort(c.Query("sort", string(models.PhotoSortCreatedDesc)))
+	if !sort.IsValid() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "sort must be created_desc, created_asc, name_asc, name_desc, modified_desc, taken_desc or taken_asc",
+		})
+	}
+
+	// Optional date range on drive_created_at; either end may be left open
+	var from, to *time.Time
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid from, expected RFC3339 (e.g. 2024-01-31T00:00:00Z)",
+			})
+		}
+		from = &parsed
+	}
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid to, expected RFC3339 (e.g. 2024-01-31T23:59:59Z)",
+			})
+		}
+		to = &parsed
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "to must not be before from",
+		})
+	}
+
+	var photos []models.Photo
+	var total int64
+
+	if search != "" {
+		switch searchField {
+		case "filename":
+			photos, total, err = h.photoRepo.SearchByFileName(c.Context(), folderID, search, sort, offset, limit)
+		case "path":
+			photos, total, err = h.photoRepo.SearchByFolderPathInSharedFolder(c.Context(), folderID, search, sort, offset, limit)
+		case "both":
+			photos, total, err = h.photoRepo.SearchByFileNameOrFolderPath(c.Context(), folderID, search, sort, offset, limit)
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "search_field must be filename, path or both",
+			})
+		}
+	} else if from != nil || to != nil {
+		// Date range, optionally within one sub-folder path
+		photos, total, err = h.photoRepo.GetBySharedFolderAndDateRange(c.Context(), folderID, folderPath, from, to, sort, offset, limit)
+	} else if folderPath != "" {
+		// Filter by specific sub-folder path
+		photos, total, err = h.photoRepo.GetBySharedFolderAndPath(c.Context(), folderID, folderPath, sort, offset, limit)
+	} else {
+		// Get all photos in shared folder
+		photos, total, err = h.photoRepo.GetBySharedFolder(c.Context(), folderID, sort, offset, limit)
+	}
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	photoResponses := dto.PhotosToPhotoResponses(photos)
+	signPhotoResponses(h.thumbnailSigner, photoResponses, userCtx.ID)
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": dto.PhotoListResponse{
+			Photos: photoResponses,
+			Total:  total,
+			Page:   page,
+			Limit:  limit,
+		},
+	})
+}
+
+// GetTrashedPhotos lists a shared folder's trashed photos
+// @Summary Get trashed photos in a shared folder
+// @Description Photos trashed by sync (trashed in Drive or missing from a full sync with the trash policy), most recently trashed first
+// @Tags Folders
+// @Security BearerAuth
+// @Param id path string true "Folder ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(50)
+// @Success 200 {object} dto.PhotoListResponse
+// @Router /folders/{id}/photos/trashed [get]
+func (h *SharedFolderHandler) GetTrashedPhotos(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	// Verify access
+	hasAccess, err := h.sharedFolderRepo.HasUserAccess(c.Context(), userCtx.ID, folderID)
+	if err != nil || !hasAccess {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"success": false,
+			"error":   "Folder not found",
+		})
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 50)
+	offset := (page - 1) * limit
+
+	photos, total, err := h.photoRepo.GetTrashedBySharedFolder(c.Context(), folderID, offset, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	photoResponses := dto.PhotosToPhotoResponses(photos)
+	signPhotoResponses(h.thumbnailSigner, photoResponses, userCtx.ID)
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": dto.PhotoListResponse{
+			Photos: photoResponses,
+			Total:  total,
+			Page:   page,
+			Limit:  limit,
+		},
+	})
+}
+
+// GetDuplicatePhotos lists groups of identical photos in a shared folder
+// @Summary Get duplicate photos in a shared folder
+// @Description Groups non-trashed photos by content hash, most copies first. Hashes are computed by face processing, so photos not processed yet (and videos) are not compared
+// @Tags Folders
+// @Security BearerAuth
+// @Param id path string true "Folder ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Groups per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Router /folders/{id}/duplicates [get]
+func (h *SharedFolderHandler) GetDuplicatePhotos(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	// Verify access
+	hasAccess, err := h.sharedFolderRepo.HasUserAccess(c.Context(), userCtx.ID, folderID)
+	if err != nil || !hasAccess {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"success": false,
+			"error":   "Folder not found",
+		})
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+	offset := (page - 1) * limit
+
+	groups, total, err := h.photoRepo.FindDuplicatesBySharedFolder(c.Context(), folderID, offset, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	groupResponses := make([]fiber.Map, len(groups))
+	for i, group := range groups {
+		photoResponses := dto.PhotosToPhotoResponses(group.Photos)
+		signPhotoResponses(h.thumbnailSigner, photoResponses, userCtx.ID)
+		groupResponses[i] = fiber.Map{
+			"content_hash": group.ContentHash,
+			"count":        len(photoResponses),
+			"photos":       photoResponses,
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"groups": groupResponses,
+			"total":  total,
+			"page":   page,
+			"limit":  limit,
+		},
+	})
+}
+
+// RestorePhoto takes a trashed photo out of the trash
+// @Summary Restore trashed photo
+// @Description Clears the photo's trashed state in this app's database only; the file in Google Drive is not changed, so a photo still trashed or missing in Drive is trashed again by the next sync
+// @Tags Folders
+// @Security BearerAuth
+// @Param id path string true "Photo ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /photos/{id}/restore [post]
+func (h *SharedFolderHandler) RestorePhoto(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	photoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid photo ID",
+		})
+	}
+
+	photo, err := h.sharedFolderService.RestorePhoto(c.Context(), userCtx.ID, photoID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrPhotoNotFound):
+			status = fiber.StatusNotFound
+		case errors.Is(err, serviceimpl.ErrPhotoNotTrashed):
+			status = fiber.StatusConflict
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	photoResponses := dto.PhotosToPhotoResponses([]models.Photo{*photo})
+	signPhotoResponses(h.thumbnailSigner, photoResponses, userCtx.ID)
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Photo restored",
+		"data":    photoResponses[0],
+	})
+}
+
+// CancelSync stops the running sync job of a folder
+// @Summary Cancel running sync
+// @Description Stops an in-flight sync; the next sync resumes from the saved checkpoint
+// @Tags Folders
+// @Security BearerAuth
+// @Param id path string true "Folder ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /folders/{id}/sync/cancel [post]
+func (h *SharedFolderHandler) CancelSync(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	job, err := h.sharedFolderService.CancelSync(c.Context(), userCtx.ID, folderID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrFolderNotFound):
+			status = fiber.StatusNotFound
+		case errors.Is(err, serviceimpl.ErrSyncNotRunning):
+			status = fiber.StatusConflict
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Sync cancellation requested",
+		"data": fiber.Map{
+			"id":     job.ID,
+			"status": models.SyncJobStatusCancelled,
+		},
+	})
+}
+
+// RegisterWebhook registers a webhook for an existing folder
+// @Summary Register webhook for folder
+// @Tags Folders
+// @Security BearerAuth
+// @Param id path string true "Folder ID"
+// @Success 200
+// @Router /folders/{id}/webhook [post]
+func (h *SharedFolderHandler) RegisterWebhook(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	if err := h.sharedFolderService.RegisterWebhook(c.Context(), userCtx.ID, folderID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Webhook registered successfully",
+	})
+}
+
+// ReconnectFolder updates a folder's Google tokens with the user's current tokens
+// @Summary Reconnect Google Drive to folder
+// @Tags Folders
+// @Security BearerAuth
+// @Param id path string true "Folder ID"
+// @Success 200
+// @Router /folders/{id}/reconnect [post]
+func (h *SharedFolderHandler) ReconnectFolder(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	// Get user's current Google tokens
+	user, err := h.userRepo.GetByID(c.Context(), userCtx.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to get user",
+		})
+	}
+
+	if user.DriveRefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Google Drive not connected. Please reconnect your Google account first.",
+		})
+	}
+
+	// Update folder tokens
+	expiry := user.DriveTokenExpiry
+	if err := h.sharedFolderRepo.UpdateTokens(c.Context(), folderID, user.DriveAccessToken, user.DriveRefreshToken, expiry, userCtx.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to update folder tokens: " + err.Error(),
+		})
+	}
+
+	// Clear error status after reconnect
+	h.sharedFolderRepo.UpdateSyncStatus(c.Context(), folderID, models.SyncStatusIdle, "")
+
+	// Try to register webhook with new tokens
+	_ = h.sharedFolderService.RegisterWebhook(c.Context(), userCtx.ID, folderID)
+
+	// Trigger sync to verify tokens work and refresh data
+	_ = h.sharedFolderService.TriggerSync(c.Context(), userCtx.ID, folderID, false)
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Folder reconnected and sync triggered",
+	})
+}
+
+// GetFolderTree returns the sub-folders of a shared folder as a nested tree
+// @Summary Get folder tree of a shared folder
+// @Description Sub-folders nested by path, with photo counts per node (total_photo_count includes descendants)
+// @Tags Folders
+// @Security BearerAuth
+// @Param id path string true "Folder ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /folders/{id}/tree [get]
+func (h *SharedFolderHandler) GetFolderTree(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	tree, err := h.sharedFolderService.GetFolderTree(c.Context(), userCtx.ID, folderID)
+	if err != nil {
+		if errors.Is(err, services.ErrFolderNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Folder not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    tree,
+	})
+}
+
+// GetFolderDashboard returns aggregate statistics of a shared folder
+// @Summary Get folder dashboard
+// @Description Total photos, photos with faces, total faces, distinct people, date range covered and top sub-folders by photo count (trashed photos excluded)
+// @Tags Folders
+// @Security BearerAuth
+// @Param id path string true "Folder ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /folders/{id}/dashboard [get]
+func (h *SharedFolderHandler) GetFolderDashboard(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	dashboard, err := h.sharedFolderService.GetFolderDashboard(c.Context(), userCtx.ID, folderID)
+	if err != nil {
+		if errors.Is(err, services.ErrFolderNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Folder not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    dashboard,
+	})
+}
+
+// GetPhotoRevisions returns a photo's revision history from Google Drive
+// @Summary Get photo revisions
+// @Description Lists the Drive revisions of a photo (read live from Drive). supported is false for uploaded photos and files whose revisions can't be read with the folder's token
+// @Tags Folders
+// @Security BearerAuth
+// @Param id path string true "Folder ID"
+// @Param photoId path string true "Photo ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /folders/{id}/photos/{photoId}/revisions [get]
+func (h *SharedFolderHandler) GetPhotoRevisions(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	photoID, err := uuid.Parse(c.Params("photoId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid photo ID",
+		})
+	}
+
+	revisions, supported, err := h.sharedFolderService.GetPhotoRevisions(c.Context(), userCtx.ID, folderID, photoID)
+	if err != nil {
+		var tokenErr *serviceimpl.GoogleTokenError
+		if errors.As(err, &tokenErr) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success":    false,
+				"error":      tokenErr.Message,
+				"error_code": tokenErr.Code,
+			})
+		}
+		if errors.Is(err, services.ErrFolderNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Folder not found",
+			})
+		}
+		if errors.Is(err, services.ErrPhotoNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Photo not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"photo_id":  photoID,
+			"supported": supported,
+			"revisions": revisions,
+		},
+	})
+}
+
+// GetSubFolders returns distinct sub-folder paths within a shared folder
+// @Summary Get sub-folders in a shared folder
+// @Tags Folders
+// @Security BearerAuth
+// @Param id path string true "Folder ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /folders/{id}/subfolders [get]
+func (h *SharedFolderHandler) GetSubFolders(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	// Verify access
+	hasAccess, err := h.sharedFolderRepo.HasUserAccess(c.Context(), userCtx.ID, folderID)
+	if err != nil || !hasAccess {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"success": false,
+			"error":   "Folder not found",
+		})
+	}
+
+	// Get distinct folder paths
+	paths, err := h.photoRepo.GetFolderPathsInSharedFolder(c.Context(), folderID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	// Build response with photo counts for each sub-folder
+	type SubFolderInfo struct {
+		Path       string `json:"path"`
+		Name       string `json:"name"`
+		PhotoCount int64  `json:"photo_count"`
+	}
+
+	subFolders := make([]SubFolderInfo, 0, len(paths))
+	for _, path := range paths {
+		// Get photo count for this path
+		photos, count, _ := h.photoRepo.GetBySharedFolderAndPath(c.Context(), folderID, path, "", 0, 0)
+		_ = photos // We only need the count
+
+		// Extract the folder name from path (last segment)
+		name := path
+		if idx := len(path) - 1; idx >= 0 {
+			for i := len(path) - 1; i >= 0; i-- {
+				if path[i] == '/' {
+					name = path[i+1:]
+					break
+				}
+			}
+		}
+
+		subFolders = append(subFolders, SubFolderInfo{
+			Path:       path,
+			Name:       name,
+			PhotoCount: count,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"subfolders": subFolders,
+			"total":      len(subFolders),
+		},
+	})
+}
+
+// ListFailedSyncJobs lists failed drive sync jobs (dead-letter view)
+// @Summary List failed sync jobs
+// @Description Recently failed sync jobs with folder, error and timestamps (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/sync-jobs/failed [get]
+func (h *SharedFolderHandler) ListFailedSyncJobs(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	jobs, total, err := h.sharedFolderService.ListFailedSyncJobs(c.Context(), page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	// Resolve folder names (cached per request - many jobs usually share a folder)
+	folders := make(map[uuid.UUID]*models.SharedFolder)
+	responses := make([]dto.FailedSyncJobResponse, 0, len(jobs))
+	for i := range jobs {
+		folderID := dto.SyncJobFolderID(&jobs[i])
+		folder, ok := folders[folderID]
+		if !ok && folderID != uuid.Nil {
+			folder, _ = h.sharedFolderRepo.GetByID(c.Context(), folderID)
+			folders[folderID] = folder
+		}
+		responses = append(responses, dto.FailedSyncJobToResponse(&jobs[i], folder))
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    responses,
+		"meta": fiber.Map{
+			"total":      total,
+			"page":       page,
+			"limit":      limit,
+			"totalPages": totalPages,
+			"hasNext":    page < totalPages,
+			"hasPrev":    page > 1,
+		},
+	})
+}
+
+// ReconcileFaceCounts recomputes face_count of a folder's photos from the faces table
+// @Summary Reconcile photo face counts
+// @Description Fixes photos whose face_count does not match their detected faces (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path string true "Folder ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/folders/{id}/reconcile-face-counts [post]
+func (h *SharedFolderHandler) ReconcileFaceCounts(c *fiber.Ctx) error {
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	fixed, err := h.sharedFolderService.ReconcileFaceCounts(c.Context(), folderID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, services.ErrFolderNotFound) {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"folder_id": folderID,
+			"fixed":     fixed,
+		},
+	})
+}
+
+// Reconcile compares a folder's Google Drive listing with its indexed photos
+// @Summary Reconcile folder with Google Drive
+// @Description Counts files in Drive missing from the database and photos whose Drive file is gone, without changing anything (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path string true "Folder ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /folders/{id}/reconcile [get]
+func (h *SharedFolderHandler) Reconcile(c *fiber.Ctx) error {
+	folderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid folder ID",
+		})
+	}
+
+	report, err := h.sharedFolderService.Reconcile(c.Context(), folderID)
+	if err != nil {
+		var tokenErr *serviceimpl.GoogleTokenError
+		if errors.As(err, &tokenErr) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success":    false,
+				"error":      tokenErr.Message,
+				"error_code": tokenErr.Code,
+			})
+		}
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, services.ErrFolderNotFound) {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// RetrySyncJob requeues a failed sync job
+// @Summary Retry failed sync job
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path string true "Sync job ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/sync-jobs/{id}/retry [post]
+func (h *SharedFolderHandler) RetrySyncJob(c *fiber.Ctx) error {
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid job ID",
+		})
+	}
+
+	job, err := h.sharedFolderService.RetrySyncJob(c.Context(), jobID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		switch {
+		case errors.Is(err, serviceimpl.ErrSyncJobNotFound):
+			status = fiber.StatusNotFound
+		case errors.Is(err, serviceimpl.ErrSyncJobNotFailed), errors.Is(err, serviceimpl.ErrSyncJobInProgress):
+			status = fiber.StatusConflict
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"id":     job.ID,
+			"status": job.Status,
+		},
+	})
+}
+
+// ListUserSyncJobs lists the failed sync jobs of the user's folders
+// @Summary List my failed sync jobs
+// @Description Failed sync jobs of folders the user can access, most recent first
+// @Tags Folders
+// @Security BearerAuth
+// @Param status query string false "Job status (only failed is supported)" default(failed)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Router /folders/sync-jobs [get]
+func (h *SharedFolderHandler) ListUserSyncJobs(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	if status := c.Query("status", string(models.SyncJobStatusFailed)); status != string(models.SyncJobStatusFailed) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Only status=failed is supported",
+		})
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	jobs, total, err := h.sharedFolderService.ListUserFailedSyncJobs(c.Context(), userCtx.ID, page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	// Resolve folder names (cached per request - many jobs usually share a folder)
+	folders := make(map[uuid.UUID]*models.SharedFolder)
+	responses := make([]dto.FailedSyncJobResponse, 0, len(jobs))
+	for i := range jobs {
+		folderID := dto.SyncJobFolderID(&jobs[i])
+		folder, ok := folders[folderID]
+		if !ok && folderID != uuid.Nil {
+			folder, _ = h.sharedFolderRepo.GetByID(c.Context(), folderID)
+			folders[folderID] = folder
+		}
+		responses = append(responses, dto.FailedSyncJobToResponse(&jobs[i], folder))
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    responses,
+		"meta": fiber.Map{
+			"total":      total,
+			"page":       page,
+			"limit":      limit,
+			"totalPages": totalPages,
+			"hasNext":    page < totalPages,
+			"hasPrev":    page > 1,
+		},
+	})
+}
+
+// RetryUserSyncJob requeues a failed sync job of one of the user's folders
+// @Summary Retry my failed sync job
+// @Description Resets the job to pending (keeping its checkpoint) and wakes the sync worker
+// @Tags Folders
+// @Security BearerAuth
+// @Param id path string true "Sync job ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /folders/sync-jobs/{id}/retry [post]
+func (h *SharedFolderHandler) RetryUserSyncJob(c *fiber.Ctx) error {
+	userCtx, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+	}
+
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid job ID",
+		})
+	}
+
+	job, err := h.sharedFolderService.RetryUserSyncJob(c.Context(), userCtx.ID, jobID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		switch {
+		case errors.Is(err, serviceimpl.ErrSyncJobNotFound):
+			status = fiber.StatusNotFound
+		case errors.Is(err, serviceimpl.ErrSyncJobNotFailed), errors.Is(err, serviceimpl.ErrSyncJobInProgress):
+			status = fiber.StatusConflict
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"id":     job.ID,
+			"status": job.Status,
 		},
 	})
 }
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-template/domain/repositories"
+)
+
+const syncSummaryDateLayout = "2006-01-02"
+
+type SyncSummaryHandler struct {
+	syncSummaryRepo repositories.SyncSummaryRepository
+}
+
+func NewSyncSummaryHandler(syncSummaryRepo repositories.SyncSummaryRepository) *SyncSummaryHandler {
+	return &SyncSummaryHandler{
+		syncSummaryRepo: syncSummaryRepo,
+	}
+}
+
+// GetSyncSummaries returns daily aggregates of completed sync jobs
+// @Summary Get sync summaries
+// @Description Daily totals of completed sync jobs (new/updated/deleted/failed, duration) for trend analysis (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Param from query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param to query string false "End date inclusive (YYYY-MM-DD), default today"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /admin/sync-summaries [get]
+func (h *SyncSummaryHandler) GetSyncSummaries(c *fiber.Ctx) error {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -29)
+	to := today
+
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.Parse(syncSummaryDateLayout, v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid from date, expected YYYY-MM-DD",
+			})
+		}
+		from = parsed
+	}
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.Parse(syncSummaryDateLayout, v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid to date, expected YYYY-MM-DD",
+			})
+		}
+		to = parsed
+	}
+	if to.Before(from) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "to must not be before from",
+		})
+	}
+
+	// "to" is inclusive - query up to the start of the following day
+	days, err := h.syncSummaryRepo.AggregateDaily(c.Context(), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"from": from.Format(syncSummaryDateLayout),
+			"to":   to.Format(syncSummaryDateLayout),
+			"days": days,
+		},
+	})
+}
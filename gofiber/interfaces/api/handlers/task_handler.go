@@ -26,17 +26,8 @@ func (h *TaskHandler) CreateTask(c *fiber.Ctx) error {
 	}
 
 	var req dto.CreateTaskRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ValidationErrorResponse(c, "Invalid request body")
-	}
-
-	if err := utils.ValidateStruct(&req); err != nil {
-		errors := utils.GetValidationErrors(err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Validation failed",
-			"errors":  errors,
-		})
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	task, err := h.taskService.CreateTask(c.Context(), user.ID, &req)
@@ -72,8 +63,8 @@ func (h *TaskHandler) UpdateTask(c *fiber.Ctx) error {
 	}
 
 	var req dto.UpdateTaskRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ValidationErrorResponse(c, "Invalid request body")
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	task, err := h.taskService.UpdateTask(c.Context(), taskID, &req)
@@ -20,17 +20,8 @@ func NewUserHandler(userService services.UserService) *UserHandler {
 
 func (h *UserHandler) Register(c *fiber.Ctx) error {
 	var req dto.CreateUserRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ValidationErrorResponse(c, "Invalid request body")
-	}
-
-	if err := utils.ValidateStruct(&req); err != nil {
-		errors := utils.GetValidationErrors(err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Validation failed",
-			"errors":  errors,
-		})
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	user, err := h.userService.Register(c.Context(), &req)
@@ -44,17 +35,8 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 
 func (h *UserHandler) Login(c *fiber.Ctx) error {
 	var req dto.LoginRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ValidationErrorResponse(c, "Invalid request body")
-	}
-
-	if err := utils.ValidateStruct(&req); err != nil {
-		errors := utils.GetValidationErrors(err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Validation failed",
-			"errors":  errors,
-		})
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	token, user, err := h.userService.Login(c.Context(), &req)
@@ -91,8 +73,8 @@ func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
 	}
 
 	var req dto.UpdateUserRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ValidationErrorResponse(c, "Invalid request body")
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	updatedUser, err := h.userService.UpdateProfile(c.Context(), user.ID, &req)
@@ -125,8 +107,8 @@ func (h *UserHandler) UpdateGeminiSettings(c *fiber.Ctx) error {
 	}
 
 	var req dto.GeminiSettingsRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ValidationErrorResponse(c, "Invalid request body")
+	if fieldErrors := utils.ParseBody(c, &req); fieldErrors != nil {
+		return utils.FieldErrorsResponse(c, fieldErrors)
 	}
 
 	updatedUser, err := h.userService.UpdateGeminiSettings(c.Context(), user.ID, &req)
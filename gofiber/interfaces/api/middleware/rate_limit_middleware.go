@@ -63,3 +63,31 @@ func AuthRateLimiter(cfg *config.RateLimitConfig) fiber.Handler {
 		SkipSuccessfulRequests: false,
 	})
 }
+
+// PublicSearchRateLimiter returns an aggressive rate limiting middleware for anonymous face search
+func PublicSearchRateLimiter(cfg *config.RateLimitConfig) fiber.Handler {
+	if !cfg.Enabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return limiter.New(limiter.Config{
+		Max:        cfg.PublicSearchMaxRequests,
+		Expiration: time.Duration(cfg.PublicSearchWindowSeconds) * time.Second,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"success": false,
+				"error": fiber.Map{
+					"code":    "PUBLIC_SEARCH_RATE_LIMIT_EXCEEDED",
+					"message": "Too many searches. Please try again later.",
+				},
+			})
+		},
+		SkipFailedRequests:     false,
+		SkipSuccessfulRequests: false,
+	})
+}
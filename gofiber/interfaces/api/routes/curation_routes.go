@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gofiber-template/interfaces/api/handlers"
+	"gofiber-template/interfaces/api/middleware"
+)
+
+func SetupCurationRoutes(api fiber.Router, h *handlers.Handlers) {
+	// Skip if handler not initialized
+	if h.Curation == nil {
+		return
+	}
+
+	// Bulk curation (recorded for undo)
+	api.Post("/faces/assign", middleware.Protected(), h.Curation.BulkAssignFaces)
+
+	// Undo
+	actions := api.Group("/actions", middleware.Protected())
+	actions.Post("/:id/undo", h.Curation.UndoAction)
+}
@@ -12,10 +12,16 @@ func SetupDriveRoutes(router fiber.Router, h *handlers.Handlers) {
 	// Public webhook endpoint (no auth required)
 	drive.Post("/webhook", h.Drive.Webhook)
 
+	// Public signed thumbnail endpoint (signature + expiry in query, no auth header)
+	drive.Get("/thumbnail/signed/:driveFileId", h.Drive.GetSignedThumbnail)
+
 	// Thumbnail endpoint with query token support (for browser img src)
 	// Must be registered BEFORE protected group to avoid middleware conflicts
 	drive.Get("/thumbnail/:driveFileId", middleware.ProtectedWithQueryToken(), h.Drive.GetThumbnail)
 
+	// Original image proxy (streams with Content-Length/Range support, query token for <img src>)
+	drive.Get("/original/:driveFileId", middleware.ProtectedWithQueryToken(), h.Drive.GetOriginal)
+
 	// OAuth endpoints (partial auth - user must be logged in)
 	drive.Get("/connect", middleware.Protected(), h.Drive.Connect)
 	drive.Get("/callback", h.Drive.Callback) // No auth - handles OAuth redirect
@@ -29,4 +35,7 @@ func SetupDriveRoutes(router fiber.Router, h *handlers.Handlers) {
 	drive.Get("/sync/status", middleware.Protected(), h.Drive.GetSyncStatus)
 	drive.Get("/photos", middleware.Protected(), h.Drive.GetPhotos)
 	drive.Post("/download", middleware.Protected(), h.Drive.DownloadPhotos)
+
+	// Admin webhook cleanup (admin token, not JWT)
+	router.Post("/admin/drive/stop-watch", h.Drive.StopWatch)
 }
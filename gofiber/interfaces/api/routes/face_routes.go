@@ -13,16 +13,26 @@ func SetupFaceRoutes(router fiber.Router, h *handlers.Handlers) {
 	// Detect faces (no auth required - just detection, no DB access)
 	faces.Post("/detect", h.Face.DetectFaces)
 
+	// Search defaults and limits (no auth required - lets clients match server validation)
+	faces.Get("/config", h.Face.GetConfig)
+
 	// All routes below require authentication
 	faces.Use(middleware.Protected())
 
 	// Face search endpoints
 	faces.Post("/search/image", h.Face.SearchByImage)   // Search by uploading image
 	faces.Post("/search/face", h.Face.SearchByFaceID)   // Search by existing face ID
+	faces.Post("/search/me", h.Face.SearchMyFace)       // Find my photos in all my folders (selfie, best face)
+	faces.Post("/search/person", h.Face.SearchByPerson) // Search by a person's tagged faces (centroid)
+
+	// Detect faces and search with one of them in one request (faces are extracted once)
+	faces.Post("/search/detect", h.Face.DetectAndSearch)
 
 	// Get faces
 	faces.Get("/", h.Face.GetFaces)                     // Get all faces (paginated)
 	faces.Get("/photo/:photo_id", h.Face.GetFacesByPhoto) // Get faces in a photo
+	faces.Post("/delete", h.Face.DeleteFaces)            // Bulk delete faces (false positives)
+	faces.Post("/redact", h.Face.RedactFaces)            // Exclude faces from search (privacy opt-out)
 
 	// Stats
 	faces.Get("/stats", h.Face.GetProcessingStats)      // Get processing stats
@@ -32,4 +42,29 @@ func SetupFaceRoutes(router fiber.Router, h *handlers.Handlers) {
 	faces.Get("/pending", h.Face.GetPendingPhotos)          // Get pending photos
 	faces.Post("/process", h.Face.ResetPhotosToPending)     // Reset photos to pending for reprocessing
 	faces.Post("/reset-stuck", h.Face.ResetStuckProcessing) // Reset stuck "processing" photos (admin)
+
+	// Low-confidence review queue for a folder
+	router.Get("/folders/:id/faces/low-confidence", middleware.Protected(), h.Face.GetLowConfidenceFaces)
+
+	// Suggested persons from similar unassigned faces of a folder
+	router.Post("/folders/:id/faces/cluster", middleware.Protected(), h.Face.ClusterFaces)
+
+	// Re-extract all faces of a folder (e.g. after a face model upgrade)
+	router.Post("/folders/:id/faces/reprocess", middleware.Protected(), h.Face.ReprocessFolder)
+
+	// Privacy opt-out for a person
+	router.Post("/persons/:id/redact", middleware.Protected(), h.Face.RedactPerson)
+
+	// Merge two records of the same person, or split faces off into a new person
+	router.Post("/persons/:id/merge", middleware.Protected(), h.Face.MergePersons)
+	router.Post("/persons/:id/split", middleware.Protected(), h.Face.SplitPerson)
+
+	// Folders (events) a person appears in
+	router.Get("/persons/:id/folders", middleware.Protected(), h.Face.GetPersonFolders)
+
+	// Face worker controls (pause during heavy imports)
+	faceWorker := router.Group("/admin/face-worker", middleware.Protected(), middleware.AdminOnly())
+	faceWorker.Get("/", h.Face.GetFaceWorkerStatus)
+	faceWorker.Post("/pause", h.Face.PauseFaceWorker)
+	faceWorker.Post("/resume", h.Face.ResumeFaceWorker)
 }
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gofiber-template/interfaces/api/handlers"
+	"gofiber-template/interfaces/api/middleware"
+	"gofiber-template/pkg/config"
+)
+
+func SetupPublicSearchRoutes(api fiber.Router, h *handlers.Handlers, rateLimitCfg *config.RateLimitConfig) {
+	// Skip if handler not initialized (face API or signed thumbnail URLs disabled)
+	if h.PublicSearch == nil {
+		return
+	}
+
+	// Owner mints a public link for a folder
+	api.Post("/folders/:id/public-search", middleware.Protected(), h.PublicSearch.CreateLink)
+
+	// Anonymous search (token-gated, aggressively rate limited)
+	api.Post("/public/events/:token/search", middleware.PublicSearchRateLimiter(rateLimitCfg), h.PublicSearch.Search)
+}
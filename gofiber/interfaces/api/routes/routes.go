@@ -25,6 +25,8 @@ func SetupRoutes(app *fiber.App, h *handlers.Handlers, healthHandler *handlers.H
 	SetupSharedFolderRoutes(api, h)
 	SetupLogRoutes(api, h)
 	SetupActivityLogRoutes(api, h)
+	SetupCurationRoutes(api, h)
+	SetupPublicSearchRoutes(api, h, &cfg.RateLimit)
 
 	// Setup WebSocket routes (needs app, not api group)
 	SetupWebSocketRoutes(app)
@@ -15,16 +15,58 @@ func SetupSharedFolderRoutes(api fiber.Router, h *handlers.Handlers) {
 	// Protected routes for shared folders
 	folders := api.Group("/folders", middleware.Protected())
 
+	// Failed sync jobs of the user's folders (registered before /:id so "sync-jobs" isn't taken as an ID)
+	folders.Get("/sync-jobs", h.SharedFolder.ListUserSyncJobs)
+	folders.Post("/sync-jobs/:id/retry", h.SharedFolder.RetryUserSyncJob)
+
 	// Folder management
 	folders.Get("/", h.SharedFolder.ListFolders)
 	folders.Get("/:id", h.SharedFolder.GetFolder)
 	folders.Post("/", h.SharedFolder.AddFolder)
+	folders.Put("/:id", h.SharedFolder.UpdateFolder)
 	folders.Delete("/:id", h.SharedFolder.RemoveFolder)
 
 	// Folder operations
 	folders.Post("/:id/sync", h.SharedFolder.TriggerSync)
+	folders.Post("/:id/sync/cancel", h.SharedFolder.CancelSync)
 	folders.Post("/:id/webhook", h.SharedFolder.RegisterWebhook)
 	folders.Post("/:id/reconnect", h.SharedFolder.ReconnectFolder)
+	folders.Post("/:id/refresh-metadata", h.SharedFolder.RefreshPhotoMetadata)
+	folders.Post("/:id/faces/enable", h.SharedFolder.EnableFaceProcessing)
+	folders.Put("/:id/schedule", h.SharedFolder.SetSyncSchedule)
+	folders.Put("/:id/orphan-policy", h.SharedFolder.SetOrphanPolicy)
+	folders.Put("/:id/exclude-paths", h.SharedFolder.SetExcludePaths)
 	folders.Get("/:id/photos", h.SharedFolder.GetPhotos)
+	folders.Get("/:id/photos/trashed", h.SharedFolder.GetTrashedPhotos)
+	folders.Get("/:id/duplicates", h.SharedFolder.GetDuplicatePhotos)
+	folders.Post("/:id/photos/upload", h.SharedFolder.UploadPhotos)
+	folders.Get("/:id/photos/:photoId/revisions", h.SharedFolder.GetPhotoRevisions)
 	folders.Get("/:id/subfolders", h.SharedFolder.GetSubFolders)
+	folders.Get("/:id/tree", h.SharedFolder.GetFolderTree)
+	folders.Get("/:id/dashboard", h.SharedFolder.GetFolderDashboard)
+	folders.Post("/:id/export", h.SharedFolder.CreateExport)
+
+	// Drive/database drift diagnostics (admin only, read-only)
+	folders.Get("/:id/reconcile", middleware.AdminOnly(), h.SharedFolder.Reconcile)
+
+	// Restore a trashed photo (database only, Drive is not changed)
+	photos := api.Group("/photos", middleware.Protected())
+	photos.Post("/:id/restore", h.SharedFolder.RestorePhoto)
+
+	// Zip export status
+	exports := api.Group("/exports", middleware.Protected())
+	exports.Get("/:id", h.SharedFolder.GetExport)
+
+	// Failed sync jobs (admin dead-letter list)
+	syncJobs := api.Group("/admin/sync-jobs", middleware.Protected(), middleware.AdminOnly())
+	syncJobs.Get("/failed", h.SharedFolder.ListFailedSyncJobs)
+	syncJobs.Post("/:id/retry", h.SharedFolder.RetrySyncJob)
+
+	// Maintenance: fix photos whose face_count drifted from their faces rows
+	api.Post("/admin/folders/:id/reconcile-face-counts", middleware.Protected(), middleware.AdminOnly(), h.SharedFolder.ReconcileFaceCounts)
+
+	// Per-job sync summaries aggregated by day (admin analytics)
+	if h.SyncSummary != nil {
+		api.Get("/admin/sync-summaries", middleware.Protected(), middleware.AdminOnly(), h.SyncSummary.GetSyncSummaries)
+	}
 }
@@ -1,23 +1,32 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	App         AppConfig
-	Database    DatabaseConfig
-	Redis       RedisConfig
-	JWT         JWTConfig
-	Admin       AdminConfig
-	RateLimit   RateLimitConfig
-	Bunny       BunnyConfig
-	Google      GoogleOAuthConfig
-	GoogleDrive GoogleDriveConfig
-	FaceAPI     FaceAPIConfig
-	Gemini      GeminiConfig
+	App          AppConfig
+	Database     DatabaseConfig
+	Redis        RedisConfig
+	JWT          JWTConfig
+	Admin        AdminConfig
+	RateLimit    RateLimitConfig
+	Bunny        BunnyConfig
+	Google       GoogleOAuthConfig
+	GoogleDrive  GoogleDriveConfig
+	FaceAPI      FaceAPIConfig
+	Gemini       GeminiConfig
+	Folder       FolderConfig
+	Thumbnail    ThumbnailConfig
+	Worker       WorkerConfig
+	Curation     CurationConfig
+	Log          LogConfig
+	PublicSearch PublicSearchConfig
 }
 
 type AdminConfig struct {
@@ -31,12 +40,17 @@ type RateLimitConfig struct {
 	// Stricter limits for sensitive endpoints
 	AuthMaxRequests   int // Max auth requests per window (login, register, etc.)
 	AuthWindowSeconds int // Auth time window in seconds
+	// Anonymous public event search (face search is expensive)
+	PublicSearchMaxRequests   int
+	PublicSearchWindowSeconds int
 }
 
 type AppConfig struct {
 	Name string
 	Port string
 	Env  string
+
+	BodyLimitMB int // Max request body size (multi-photo uploads need room for several files)
 }
 
 type DatabaseConfig struct {
@@ -60,10 +74,14 @@ type JWTConfig struct {
 }
 
 type BunnyConfig struct {
-	StorageZone string
-	AccessKey   string
-	BaseURL     string
-	CDNUrl      string
+	StorageZone            string
+	AccessKey              string
+	BaseURL                string
+	CDNUrl                 string
+	MaxRetries             int // Retries on transient upload/delete failures (network, 429, 5xx)
+	RetryBaseMs            int // First backoff delay, doubled on each retry
+	BreakerThreshold       int // Consecutive failed operations before Bunny is skipped (0 = disabled)
+	BreakerCooldownSeconds int // How long Bunny is skipped before trying again
 }
 
 type GoogleOAuthConfig struct {
@@ -77,11 +95,144 @@ type GoogleDriveConfig struct {
 	ClientSecret string
 	RedirectURL  string
 	WebhookURL   string // URL for Drive push notifications
+
+	// Webhook channels expiring within this many hours are renewed (renewal check runs hourly)
+	WebhookRenewalWindowHours int
+
+	// HMAC secret signing webhook channel tokens (required while signed tokens are required)
+	WebhookSecret string
+
+	// Reject webhook calls with unsigned channel tokens (off only while legacy channels are re-registered)
+	WebhookRequireSigned bool
+
+	// Unsigned tokens of user root folder channels are still accepted until then (zero = never).
+	// Those legacy channels are never renewed, so they can only be waited out (at most 7 days)
+	WebhookUnsignedUserUntil time.Time
+
+	// MIME type prefixes that are synced (e.g. "image/", "video/")
+	SyncMimePrefixes []string
+
+	MaxAttempts int // Attempts per List/Get call on rate-limit (403/429) and 5xx errors (1 = no retry)
+	RetryBaseMs int // First backoff delay, doubled on each retry (plus jitter)
+
+	// Drive calls per second per Google account, shared by all workers (0 = unlimited)
+	AccountRatePerSecond int
+	AccountBurst         int
+
+	// Request image dimensions and EXIF capture time/camera when listing files/changes and store them on photos.
+	// Off by default: imageMediaMetadata noticeably grows Drive responses on large syncs
+	CaptureImageMetadata bool
+
+	// Limits of POST /drive/download (the zip is built in memory)
+	ZipMaxFiles   int // Files per zip download (below 1 = default 50)
+	ZipMaxTotalMB int // Combined size of the files in one zip (0 = unlimited)
+
+	ThumbnailTimeoutSeconds int // Limit for downloading one thumbnail image (below 1 = default 30)
 }
 
 type FaceAPIConfig struct {
 	BaseURL string // Base URL of the Python InsightFace service
 	Enabled bool   // Enable/disable face processing
+
+	// Face API calls: request timeout, worker retries per photo and the worker's circuit breaker
+	TimeoutSeconds      int // CPU-only inference of large images can take long
+	MaxRetries          int // Retries after the first attempt (0 = none)
+	BreakerThreshold    int // Consecutive failed photos that stop calls to the Face API
+	BreakerResetSeconds int // How long calls stay stopped before one photo is tried again
+
+	// Face search defaults (published via GET /faces/config so clients match server validation)
+	SearchDefaultLimit     int     // Used when limit is missing or out of range
+	SearchMaxLimit         int     // Largest accepted limit
+	SearchDefaultThreshold float64 // Used when threshold is missing or out of range (0-1)
+	SearchMinThreshold     float64 // Floor for requested thresholds, so near-zero values can't return masses of junk matches
+	MaxUploadMB            int     // Max image size for detect/search uploads
+
+	// Parallel per-folder search for users with many folders (results merged by similarity).
+	// Set the threshold from BenchmarkFaceSearch run against the deployment's database
+	SearchFanOutMinFolders  int // Fan out at this many folders or more (0 = always a single query)
+	SearchFanOutConcurrency int // Max per-folder queries in flight
+
+	SearchMaxConcurrentPerUser int // Face searches one user can run at once; more get 429 (0 = unlimited)
+
+	// Re-queued photos already found faceless by the current face model are completed without
+	// calling the Face API again (POST /faces/process with force=true always re-extracts)
+	SkipUnchangedFaceless bool
+
+	// Detected faces below these are not stored, so tiny blurry background faces stay out of search
+	MinFaceConfidence float64 // Detection confidence (0-1)
+	MinFaceArea       float64 // Bounding box area as a fraction of the image (0 = no size limit)
+}
+
+type FolderConfig struct {
+	MaxFoldersPerUser  int    // Max shared folders a non-admin user can add/join (0 = unlimited, the default)
+	OrphanPolicy       string // Default for photos missing from a full sync listing: "delete" or "trash"
+	TrashRetentionDays int    // Trashed photos older than this are purged (0 = never purge)
+	MaxPhotoUploadMB   int    // Max size of a photo uploaded directly to a folder
+}
+
+// WorkerConfig tunes background worker polling.
+// The face worker fetches one batch (20 photos) per tick, so its maximum throughput is
+// roughly one batch per poll interval; lowering the interval only helps while batches
+// finish faster than the interval. Intervals below 1 second are raised to 1 second.
+type WorkerConfig struct {
+	FacePollIntervalSeconds int // How often the face worker checks for pending photos
+	FacePauseSyncBacklog    int // Face worker skips polls while this many drive sync jobs are pending or running (0 = off)
+	SyncPollIntervalSeconds int // Safety-net poll for pending sync and export jobs (jobs are normally triggered immediately)
+
+	// Sync concurrency is capped per mode: full syncs (listing + batch inserts) are heavy,
+	// incremental syncs are light. Values below 1 are raised to 1.
+	SyncMaxConcurrentFull        int
+	SyncMaxConcurrentIncremental int
+
+	// Sync batching: photos per batch insert (1-500), checkpoint and progress broadcast frequency (files)
+	SyncBatchSize       int
+	SyncCheckpointEvery int
+	SyncBroadcastEvery  int
+	SyncAddedBatchSize  int // New photo IDs per photos:added message during incremental sync
+
+	// Files of one full sync checked against the database concurrently (1 = sequential, max 32)
+	SyncFileConcurrency int
+
+	// Drive changes an incremental sync reads and applies before saving the page token;
+	// the rest is left to a follow-up job so a large backlog isn't held in memory at once
+	SyncMaxChangesPerJob int
+
+	// Finished sync jobs older than this are deleted daily (0 = keep forever),
+	// except the most recent SyncJobKeepPerFolder of each folder
+	SyncJobRetentionDays int
+	SyncJobKeepPerFolder int
+}
+
+type LogConfig struct {
+	Output      string // "file" (default, ./logs + console), "stdout" (JSON lines only), "both"
+	Level       string // Minimum level written: DEBUG (default), INFO, WARN, ERROR
+	SampleEvery int    // Write 1 in N high-volume per-file/progress logs (1 = all)
+
+	// Per-category minimum levels from LOG_LEVEL_<CATEGORY> (e.g. LOG_LEVEL_DB=WARN), keyed by
+	// lowercase category name. Categories without an entry use Level
+	CategoryLevels map[string]string
+
+	MaxSizeMB     int // Category files rotate to .1, .2, ... past this size (0 = no rotation)
+	RetentionDays int // Category files older than this are deleted daily (0 = keep forever)
+}
+
+type CurationConfig struct {
+	UndoWindowMinutes int // How long a bulk curation action can be undone
+}
+
+// PublicSearchConfig controls anonymous face search via public event links
+type PublicSearchConfig struct {
+	MaxExpiryHours int     // Longest lifetime an owner can give a link
+	MaxResults     int     // Upper bound for a link's per-search result cap
+	Threshold      float64 // Similarity threshold for public searches
+}
+
+type ThumbnailConfig struct {
+	SignedURLEnabled    bool   // Return short-lived signed thumbnail URLs with photo responses
+	SignedURLTTLMinutes int    // How long a signed thumbnail URL stays valid
+	SigningSecret       string // HMAC secret for signed URLs (falls back to JWT secret if not set)
+	CacheEnabled        bool   // Persist thumbnails to Bunny CDN and redirect subsequent requests
+	CacheTTLHours       int    // How long the cache index is kept
 }
 
 type GeminiConfig struct {
@@ -100,6 +251,8 @@ func LoadConfig() (*Config, error) {
 			Name: getEnv("APP_NAME", "GoFiber Template"),
 			Port: getEnv("APP_PORT", "3000"),
 			Env:  getEnv("APP_ENV", "development"),
+
+			BodyLimitMB: getEnvInt("APP_BODY_LIMIT_MB", 100),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -122,10 +275,14 @@ func LoadConfig() (*Config, error) {
 			Token: getEnv("ADMIN_TOKEN", ""), // Will fall back to JWT_SECRET in handler if empty
 		},
 		Bunny: BunnyConfig{
-			StorageZone: getEnv("BUNNY_STORAGE_ZONE", ""),
-			AccessKey:   getEnv("BUNNY_ACCESS_KEY", ""),
-			BaseURL:     getEnv("BUNNY_BASE_URL", "https://storage.bunnycdn.com"),
-			CDNUrl:      getEnv("BUNNY_CDN_URL", ""),
+			StorageZone:            getEnv("BUNNY_STORAGE_ZONE", ""),
+			AccessKey:              getEnv("BUNNY_ACCESS_KEY", ""),
+			BaseURL:                getEnv("BUNNY_BASE_URL", "https://storage.bunnycdn.com"),
+			CDNUrl:                 getEnv("BUNNY_CDN_URL", ""),
+			MaxRetries:             getEnvInt("BUNNY_MAX_RETRIES", 3),
+			RetryBaseMs:            getEnvInt("BUNNY_RETRY_BASE_MS", 200),
+			BreakerThreshold:       getEnvInt("BUNNY_BREAKER_THRESHOLD", 5),
+			BreakerCooldownSeconds: getEnvInt("BUNNY_BREAKER_COOLDOWN_SECONDS", 30),
 		},
 		Google: GoogleOAuthConfig{
 			ClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
@@ -137,24 +294,124 @@ func LoadConfig() (*Config, error) {
 			ClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""), // Same as Google OAuth
 			RedirectURL:  getEnv("GOOGLE_DRIVE_REDIRECT_URL", "http://localhost:8080/api/v1/drive/callback"),
 			WebhookURL:   getEnv("GOOGLE_DRIVE_WEBHOOK_URL", ""),
+
+			WebhookRenewalWindowHours: getEnvInt("GOOGLE_DRIVE_WEBHOOK_RENEWAL_WINDOW_HOURS", 24),
+			WebhookSecret:             getEnv("GOOGLE_DRIVE_WEBHOOK_SECRET", ""),
+			WebhookRequireSigned:      getEnv("GOOGLE_DRIVE_WEBHOOK_REQUIRE_SIGNED", "true") == "true",
+			WebhookUnsignedUserUntil:  getEnvTime("GOOGLE_DRIVE_WEBHOOK_UNSIGNED_USER_UNTIL"),
+
+			SyncMimePrefixes: splitList(getEnv("GOOGLE_DRIVE_SYNC_MIME_PREFIXES", "image/")),
+
+			MaxAttempts: getEnvInt("GOOGLE_DRIVE_MAX_ATTEMPTS", 5),
+			RetryBaseMs: getEnvInt("GOOGLE_DRIVE_RETRY_BASE_MS", 1000),
+
+			AccountRatePerSecond: getEnvInt("GOOGLE_DRIVE_ACCOUNT_RATE_PER_SECOND", 10),
+			AccountBurst:         getEnvInt("GOOGLE_DRIVE_ACCOUNT_BURST", 20),
+
+			CaptureImageMetadata: getEnv("GOOGLE_DRIVE_CAPTURE_IMAGE_METADATA", "false") == "true",
+
+			ZipMaxFiles:   getEnvInt("GOOGLE_DRIVE_ZIP_MAX_FILES", 50),
+			ZipMaxTotalMB: getEnvInt("GOOGLE_DRIVE_ZIP_MAX_TOTAL_MB", 500),
+
+			ThumbnailTimeoutSeconds: getEnvInt("GOOGLE_DRIVE_THUMBNAIL_TIMEOUT_SECONDS", 30),
 		},
 		FaceAPI: FaceAPIConfig{
 			BaseURL: getEnv("FACE_API_URL", "http://localhost:5000"),
 			Enabled: getEnv("FACE_API_ENABLED", "true") == "true",
+
+			TimeoutSeconds:      getEnvInt("FACE_API_TIMEOUT_SECONDS", 120),
+			MaxRetries:          getEnvInt("FACE_API_MAX_RETRIES", 3),
+			BreakerThreshold:    getEnvInt("FACE_API_BREAKER_THRESHOLD", 10),
+			BreakerResetSeconds: getEnvInt("FACE_API_BREAKER_RESET_SECONDS", 60),
+
+			SearchDefaultLimit:     getEnvInt("FACE_SEARCH_DEFAULT_LIMIT", 20),
+			SearchMaxLimit:         getEnvInt("FACE_SEARCH_MAX_LIMIT", 100),
+			SearchDefaultThreshold: float64(getEnvInt("FACE_SEARCH_DEFAULT_THRESHOLD_PERCENT", 60)) / 100,
+			SearchMinThreshold:     float64(getEnvInt("FACE_SEARCH_MIN_THRESHOLD_PERCENT", 30)) / 100,
+			MaxUploadMB:            getEnvInt("FACE_MAX_UPLOAD_MB", 10),
+
+			SearchFanOutMinFolders:  getEnvInt("FACE_SEARCH_FANOUT_MIN_FOLDERS", 8),
+			SearchFanOutConcurrency: getEnvInt("FACE_SEARCH_FANOUT_CONCURRENCY", 4),
+
+			SearchMaxConcurrentPerUser: getEnvInt("FACE_SEARCH_MAX_CONCURRENT_PER_USER", 2),
+
+			SkipUnchangedFaceless: getEnv("FACE_SKIP_UNCHANGED_FACELESS", "true") == "true",
+
+			MinFaceConfidence: float64(getEnvInt("FACE_MIN_CONFIDENCE_PERCENT", 50)) / 100,
+			MinFaceArea:       float64(getEnvInt("FACE_MIN_AREA_PERMILLE", 0)) / 1000,
 		},
 		Gemini: GeminiConfig{
 			APIKey: getEnv("GEMINI_API_KEY", ""),
 			Model:  getEnv("GEMINI_MODEL", "gemini-2.0-flash"),
 		},
+		Folder: FolderConfig{
+			MaxFoldersPerUser:  getEnvInt("MAX_FOLDERS_PER_USER", 0),
+			OrphanPolicy:       getEnv("ORPHAN_POLICY", "delete"),
+			TrashRetentionDays: getEnvInt("TRASH_RETENTION_DAYS", 30),
+			MaxPhotoUploadMB:   getEnvInt("PHOTO_UPLOAD_MAX_MB", 20),
+		},
+		Thumbnail: ThumbnailConfig{
+			SignedURLEnabled:    getEnv("THUMBNAIL_SIGNED_URL_ENABLED", "false") == "true",
+			SignedURLTTLMinutes: getEnvInt("THUMBNAIL_SIGNED_URL_TTL_MINUTES", 60),
+			SigningSecret:       getEnv("THUMBNAIL_SIGNING_SECRET", ""),
+			CacheEnabled:        getEnv("THUMBNAIL_CACHE_ENABLED", "false") == "true",
+			CacheTTLHours:       getEnvInt("THUMBNAIL_CACHE_TTL_HOURS", 720),
+		},
+		Worker: WorkerConfig{
+			FacePollIntervalSeconds: getEnvInt("FACE_WORKER_POLL_INTERVAL_SECONDS", 10),
+			FacePauseSyncBacklog:    getEnvInt("FACE_WORKER_PAUSE_SYNC_BACKLOG", 0),
+			SyncPollIntervalSeconds: getEnvInt("SYNC_WORKER_POLL_INTERVAL_SECONDS", 60),
+
+			SyncMaxConcurrentFull:        getEnvInt("SYNC_WORKER_MAX_CONCURRENT_FULL", 1),
+			SyncMaxConcurrentIncremental: getEnvInt("SYNC_WORKER_MAX_CONCURRENT_INCREMENTAL", 4),
+
+			SyncBatchSize:       getEnvInt("SYNC_BATCH_SIZE", 100),
+			SyncCheckpointEvery: getEnvInt("SYNC_CHECKPOINT_EVERY", 100),
+			SyncBroadcastEvery:  getEnvInt("SYNC_BROADCAST_EVERY", 50),
+			SyncAddedBatchSize:  getEnvInt("SYNC_ADDED_BATCH_SIZE", 50),
+
+			SyncFileConcurrency:  getEnvInt("SYNC_FILE_CONCURRENCY", 4),
+			SyncMaxChangesPerJob: getEnvInt("SYNC_MAX_CHANGES_PER_JOB", 1000),
+
+			SyncJobRetentionDays: getEnvInt("SYNC_JOB_RETENTION_DAYS", 30),
+			SyncJobKeepPerFolder: getEnvInt("SYNC_JOB_KEEP_PER_FOLDER", 20),
+		},
+		Log: LogConfig{
+			Output:      getEnv("LOG_OUTPUT", "file"),
+			Level:       getEnv("LOG_LEVEL", "DEBUG"),
+			SampleEvery: getEnvInt("LOG_SAMPLE_EVERY", 1),
+
+			CategoryLevels: getEnvWithPrefix("LOG_LEVEL_"),
+
+			MaxSizeMB:     getEnvInt("LOG_MAX_SIZE_MB", 100),
+			RetentionDays: getEnvInt("LOG_RETENTION_DAYS", 14),
+		},
+		Curation: CurationConfig{
+			UndoWindowMinutes: getEnvInt("CURATION_UNDO_WINDOW_MINUTES", 30),
+		},
+		PublicSearch: PublicSearchConfig{
+			MaxExpiryHours: getEnvInt("PUBLIC_SEARCH_MAX_EXPIRY_HOURS", 720),
+			MaxResults:     getEnvInt("PUBLIC_SEARCH_MAX_RESULTS", 50),
+			Threshold:      float64(getEnvInt("PUBLIC_SEARCH_THRESHOLD_PERCENT", 60)) / 100,
+		},
 		RateLimit: RateLimitConfig{
 			Enabled:           getEnv("RATE_LIMIT_ENABLED", "true") == "true",
 			MaxRequests:       getEnvInt("RATE_LIMIT_MAX_REQUESTS", 100),
 			WindowSeconds:     getEnvInt("RATE_LIMIT_WINDOW_SECONDS", 60),
 			AuthMaxRequests:   getEnvInt("RATE_LIMIT_AUTH_MAX_REQUESTS", 10),
 			AuthWindowSeconds: getEnvInt("RATE_LIMIT_AUTH_WINDOW_SECONDS", 60),
+
+			PublicSearchMaxRequests:   getEnvInt("RATE_LIMIT_PUBLIC_SEARCH_MAX_REQUESTS", 5),
+			PublicSearchWindowSeconds: getEnvInt("RATE_LIMIT_PUBLIC_SEARCH_WINDOW_SECONDS", 60),
 		},
 	}
 
+	// Without a secret anyone could sign channel tokens. It is not borrowed from JWT_SECRET either,
+	// so a leak of one can't be used to forge the other
+	if config.GoogleDrive.WebhookRequireSigned && config.GoogleDrive.WebhookSecret == "" {
+		return nil, errors.New("GOOGLE_DRIVE_WEBHOOK_SECRET must be set when GOOGLE_DRIVE_WEBHOOK_REQUIRE_SIGNED=true")
+	}
+
 	return config, nil
 }
 
@@ -176,4 +433,40 @@ func getEnvInt(key string, defaultValue int) int {
 		return defaultValue
 	}
 	return intValue
-}
\ No newline at end of file
+}
+
+// getEnvTime parses an RFC 3339 time; unset or invalid values give the zero time
+func getEnvTime(key string) time.Time {
+	value, err := time.Parse(time.RFC3339, os.Getenv(key))
+	if err != nil {
+		return time.Time{}
+	}
+	return value
+}
+
+// getEnvWithPrefix returns the non-empty variables starting with prefix, keyed by the rest of
+// the name in lowercase
+func getEnvWithPrefix(prefix string) map[string]string {
+	values := make(map[string]string)
+	for _, env := range os.Environ() {
+		key, value, ok := strings.Cut(env, "=")
+		if !ok || !strings.HasPrefix(key, prefix) || value == "" {
+			continue
+		}
+		if name := strings.ToLower(strings.TrimPrefix(key, prefix)); name != "" {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -2,10 +2,13 @@ package di
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 
 	"gofiber-template/application/serviceimpl"
+	"gofiber-template/domain/models"
 	"gofiber-template/domain/repositories"
 	"gofiber-template/domain/services"
 	"gofiber-template/infrastructure/faceapi"
@@ -35,16 +38,19 @@ type Container struct {
 	GoogleDrive    *googledrive.DriveClient
 
 	// Repositories
-	UserRepository         repositories.UserRepository
-	TaskRepository         repositories.TaskRepository
-	FileRepository         repositories.FileRepository
-	JobRepository          repositories.JobRepository
-	PhotoRepository        repositories.PhotoRepository
-	SyncJobRepository      repositories.SyncJobRepository
-	FaceRepository         repositories.FaceRepository
-	PersonRepository       repositories.PersonRepository
-	SharedFolderRepository repositories.SharedFolderRepository
-	ActivityLogRepository  repositories.ActivityLogRepository
+	UserRepository             repositories.UserRepository
+	TaskRepository             repositories.TaskRepository
+	FileRepository             repositories.FileRepository
+	JobRepository              repositories.JobRepository
+	PhotoRepository            repositories.PhotoRepository
+	SyncJobRepository          repositories.SyncJobRepository
+	FaceRepository             repositories.FaceRepository
+	PersonRepository           repositories.PersonRepository
+	SharedFolderRepository     repositories.SharedFolderRepository
+	ActivityLogRepository      repositories.ActivityLogRepository
+	CurationActionRepository   repositories.CurationActionRepository
+	SyncSummaryRepository      repositories.SyncSummaryRepository
+	PublicSearchLinkRepository repositories.PublicSearchLinkRepository
 
 	// Services
 	UserService         services.UserService
@@ -57,10 +63,13 @@ type Container struct {
 	NewsService         services.NewsService
 	SharedFolderService services.SharedFolderService
 	ActivityLogService  services.ActivityLogService
+	CurationService     services.CurationService
+	PublicSearchService services.PublicSearchService
 
 	// Workers
-	SyncWorker *worker.SyncWorker
-	FaceWorker *worker.FaceWorker
+	SyncWorker   *worker.SyncWorker
+	ExportWorker *worker.ExportWorker
+	FaceWorker   *worker.FaceWorker
 
 	// Clients
 	FaceClient   *faceapi.FaceClient
@@ -105,6 +114,24 @@ func (c *Container) initConfig() error {
 		return err
 	}
 	c.Config = cfg
+
+	// Apply log output mode before anything else is logged
+	logger.SetOutput(logger.OutputMode(cfg.Log.Output))
+	if level, ok := logger.ParseLevel(cfg.Log.Level); ok {
+		logger.SetMinLevel(level)
+	}
+	logger.SetSampleRate(cfg.Log.SampleEvery)
+	logger.SetMaxFileSizeMB(cfg.Log.MaxSizeMB)
+	for category, level := range cfg.Log.CategoryLevels {
+		logger.SetCategoryLevel(logger.Category(category), logger.Level(strings.ToUpper(level)))
+	}
+	logger.Startup("logger_init", "Logger initialized", map[string]interface{}{
+		"output":          logger.Default().Output(),
+		"level":           cfg.Log.Level,
+		"category_levels": cfg.Log.CategoryLevels,
+		"sample_every":    cfg.Log.SampleEvery,
+	})
+
 	logger.Startup("config_loaded", "Configuration loaded", nil)
 	return nil
 }
@@ -151,10 +178,14 @@ func (c *Container) initInfrastructure() error {
 
 	// Initialize Bunny Storage
 	bunnyConfig := storage.BunnyConfig{
-		StorageZone: c.Config.Bunny.StorageZone,
-		AccessKey:   c.Config.Bunny.AccessKey,
-		BaseURL:     c.Config.Bunny.BaseURL,
-		CDNUrl:      c.Config.Bunny.CDNUrl,
+		StorageZone:      c.Config.Bunny.StorageZone,
+		AccessKey:        c.Config.Bunny.AccessKey,
+		BaseURL:          c.Config.Bunny.BaseURL,
+		CDNUrl:           c.Config.Bunny.CDNUrl,
+		MaxRetries:       c.Config.Bunny.MaxRetries,
+		RetryBaseDelay:   time.Duration(c.Config.Bunny.RetryBaseMs) * time.Millisecond,
+		BreakerThreshold: c.Config.Bunny.BreakerThreshold,
+		BreakerCooldown:  time.Duration(c.Config.Bunny.BreakerCooldownSeconds) * time.Second,
 	}
 	c.BunnyStorage = storage.NewBunnyStorage(bunnyConfig)
 	logger.Startup("bunny_storage_initialized", "Bunny Storage initialized", nil)
@@ -202,6 +233,9 @@ func (c *Container) initRepositories() error {
 	c.PersonRepository = postgres.NewPersonRepository(c.DB)
 	c.SharedFolderRepository = postgres.NewSharedFolderRepository(c.DB)
 	c.ActivityLogRepository = postgres.NewActivityLogRepository(c.DB)
+	c.CurationActionRepository = postgres.NewCurationActionRepository(c.DB)
+	c.SyncSummaryRepository = postgres.NewSyncSummaryRepository(c.DB)
+	c.PublicSearchLinkRepository = postgres.NewPublicSearchLinkRepository(c.DB)
 	logger.Startup("repositories_initialized", "Repositories initialized", nil)
 	return nil
 }
@@ -211,12 +245,25 @@ func (c *Container) initServices() error {
 	c.TaskService = serviceimpl.NewTaskService(c.TaskRepository, c.UserRepository)
 	c.FileService = serviceimpl.NewFileService(c.FileRepository, c.UserRepository, c.BunnyStorage)
 	c.AuthService = serviceimpl.NewAuthService(c.UserRepository, c.GoogleOAuth, c.Config.JWT.Secret)
-	c.DriveService = serviceimpl.NewDriveService(c.GoogleDrive, c.UserRepository, c.PhotoRepository, c.SyncJobRepository)
+	// Thumbnail CDN cache (optional, needs Bunny storage)
+	var thumbnailCache *storage.ThumbnailCache
+	if c.Config.Thumbnail.CacheEnabled && c.Config.Bunny.StorageZone != "" {
+		thumbnailCache = storage.NewThumbnailCache(c.BunnyStorage, c.RedisClient, time.Duration(c.Config.Thumbnail.CacheTTLHours)*time.Hour)
+		logger.Startup("thumbnail_cache_enabled", "Thumbnail CDN cache enabled", nil)
+	}
+	c.DriveService = serviceimpl.NewDriveService(c.GoogleDrive, c.UserRepository, c.PhotoRepository, c.SyncJobRepository, c.SharedFolderRepository, thumbnailCache)
+	if driveService, ok := c.DriveService.(*serviceimpl.DriveServiceImpl); ok {
+		driveService.SetZipLimits(c.Config.GoogleDrive.ZipMaxFiles, c.Config.GoogleDrive.ZipMaxTotalMB)
+	}
 
 	// Initialize Face Client (needed for FaceService)
 	if c.Config.FaceAPI.Enabled {
-		c.FaceClient = faceapi.NewFaceClient(c.Config.FaceAPI.BaseURL)
-		c.FaceService = serviceimpl.NewFaceService(c.FaceRepository, c.PhotoRepository, c.PersonRepository, c.UserRepository, c.SharedFolderRepository, c.FaceClient)
+		c.FaceClient = faceapi.NewFaceClient(c.Config.FaceAPI.BaseURL, time.Duration(c.Config.FaceAPI.TimeoutSeconds)*time.Second)
+		c.FaceService = serviceimpl.NewFaceService(c.FaceRepository, c.PhotoRepository, c.PersonRepository, c.UserRepository, c.SharedFolderRepository, c.FaceClient, serviceimpl.FaceSearchFanOut{
+			MinFolders:  c.Config.FaceAPI.SearchFanOutMinFolders,
+			Concurrency: c.Config.FaceAPI.SearchFanOutConcurrency,
+		})
+		c.PublicSearchService = serviceimpl.NewPublicSearchService(c.PublicSearchLinkRepository, c.FaceRepository, c.SharedFolderRepository, c.FaceClient)
 	}
 
 	// Initialize News Service (requires Google Drive - Gemini credentials are per-user)
@@ -229,6 +276,15 @@ func (c *Container) initServices() error {
 	c.ActivityLogService = serviceimpl.NewActivityLogService(c.ActivityLogRepository)
 	logger.Startup("activity_log_service_initialized", "Activity log service initialized", nil)
 
+	// Initialize Curation Service (bulk curation with undo)
+	c.CurationService = serviceimpl.NewCurationService(
+		c.CurationActionRepository,
+		c.FaceRepository,
+		c.PersonRepository,
+		c.SharedFolderRepository,
+		time.Duration(c.Config.Curation.UndoWindowMinutes)*time.Minute,
+	)
+
 	// SharedFolderService will be initialized after workers (needs SyncWorker)
 
 	logger.Startup("services_initialized", "Services initialized", nil)
@@ -280,11 +336,40 @@ func (c *Container) initWorkers() error {
 		c.PhotoRepository,
 		c.SyncJobRepository,
 		c.ActivityLogRepository,
+		c.SyncSummaryRepository,
 	)
+	c.SyncWorker.Configure(worker.SyncWorkerConfig{
+		PollInterval:             time.Duration(c.Config.Worker.SyncPollIntervalSeconds) * time.Second,
+		MaxConcurrentFull:        c.Config.Worker.SyncMaxConcurrentFull,
+		MaxConcurrentIncremental: c.Config.Worker.SyncMaxConcurrentIncremental,
+		BatchSize:                c.Config.Worker.SyncBatchSize,
+		CheckpointEvery:          c.Config.Worker.SyncCheckpointEvery,
+		BroadcastEvery:           c.Config.Worker.SyncBroadcastEvery,
+		AddedBatchSize:           c.Config.Worker.SyncAddedBatchSize,
+		FileConcurrency:          c.Config.Worker.SyncFileConcurrency,
+		MaxChangesPerJob:         c.Config.Worker.SyncMaxChangesPerJob,
+		OrphanPolicy:             models.OrphanPolicy(c.Config.Folder.OrphanPolicy),
+	})
+	logger.Startup("sync_worker_configured", "Sync worker configured", c.SyncWorker.GetStats())
 
 	// Start the sync worker
 	c.SyncWorker.Start()
 
+	// Initialize Export Worker (only if Bunny storage is configured - archives are uploaded there)
+	if c.Config.Bunny.StorageZone != "" && c.Config.Bunny.AccessKey != "" {
+		c.ExportWorker = worker.NewExportWorker(
+			c.GoogleDrive,
+			c.BunnyStorage,
+			c.SharedFolderRepository,
+			c.PhotoRepository,
+			c.SyncJobRepository,
+		)
+		c.ExportWorker.SetPollInterval(time.Duration(c.Config.Worker.SyncPollIntervalSeconds) * time.Second)
+		c.ExportWorker.Start()
+	} else {
+		logger.StartupWarn("export_worker_disabled", "Bunny storage not configured, zip export disabled", nil)
+	}
+
 	// NOTE: Disabled auto sync on startup - users should manually trigger sync when needed
 	// c.autoSyncOnStartup()
 
@@ -297,7 +382,17 @@ func (c *Container) initWorkers() error {
 			c.PhotoRepository,
 			c.FaceRepository,
 			c.SharedFolderRepository,
+			worker.FaceAPIRetry{
+				MaxRetries:       c.Config.FaceAPI.MaxRetries,
+				BreakerThreshold: c.Config.FaceAPI.BreakerThreshold,
+				BreakerReset:     time.Duration(c.Config.FaceAPI.BreakerResetSeconds) * time.Second,
+			},
 		)
+		c.FaceWorker.SetPollInterval(time.Duration(c.Config.Worker.FacePollIntervalSeconds) * time.Second)
+		c.FaceWorker.SetUploadStorage(c.BunnyStorage)
+		c.FaceWorker.SetSkipUnchangedFaceless(c.Config.FaceAPI.SkipUnchangedFaceless)
+		c.FaceWorker.SetFaceQualityFilter(c.Config.FaceAPI.MinFaceConfidence, c.Config.FaceAPI.MinFaceArea)
+		c.FaceWorker.SetSyncBacklogPause(c.SyncJobRepository, c.Config.Worker.FacePauseSyncBacklog)
 
 		// Start the face worker
 		c.FaceWorker.Start()
@@ -313,15 +408,41 @@ func (c *Container) initWorkers() error {
 		c.UserRepository,
 		c.GoogleDrive,
 		c.SyncWorker,
+		c.ExportWorker,
+		c.EventScheduler,
+		c.BunnyStorage,
+		c.Config.Folder.MaxFoldersPerUser,
+		c.Config.GoogleDrive.WebhookRenewalWindowHours,
 	)
 	logger.Startup("shared_folder_service_initialized", "SharedFolder service initialized", nil)
 
-	// Schedule webhook renewal job (runs every 6 hours)
+	// Root folders set via /drive/root-folder are registered as shared folders
+	if driveService, ok := c.DriveService.(*serviceimpl.DriveServiceImpl); ok {
+		driveService.SetSharedFolderService(c.SharedFolderService)
+	}
+
+	// Schedule webhook renewal job (runs hourly)
 	c.scheduleWebhookRenewal()
 
+	// Register per-folder sync schedules
+	c.scheduleFolderSyncs()
+
 	// Schedule auto reset stuck photos job (runs every 10 minutes)
 	c.scheduleAutoResetStuck()
 
+	// Schedule face count reconciliation job (runs daily)
+	c.scheduleFaceCountReconcile()
+
+	// Schedule folder photo count reconciliation job (runs hourly, once at startup)
+	c.scheduleFolderPhotoCountReconcile()
+
+	// Schedule purge of long-trashed photos (runs daily)
+	c.scheduleTrashedPhotoPurge()
+
+	// Schedule cleanup of old finished sync jobs (runs daily)
+	c.scheduleSyncJobCleanup()
+	c.scheduleLogCleanup()
+
 	return nil
 }
 
@@ -332,8 +453,8 @@ func (c *Container) scheduleWebhookRenewal() {
 		return
 	}
 
-	// Run every 6 hours: "0 */6 * * *"
-	err := c.EventScheduler.AddJob("webhook-renewal", "0 */6 * * *", func() {
+	// Run hourly so every channel is renewed well inside its renewal window
+	err := c.EventScheduler.AddJob("webhook-renewal", "0 * * * *", func() {
 		ctx := context.Background()
 		renewed, failed, err := c.SharedFolderService.RenewExpiringWebhooks(ctx)
 		if err != nil {
@@ -351,7 +472,26 @@ func (c *Container) scheduleWebhookRenewal() {
 	if err != nil {
 		logger.StartupWarn("webhook_renewal_schedule_failed", "Failed to schedule webhook renewal job", map[string]interface{}{"error": err.Error()})
 	} else {
-		logger.Startup("webhook_renewal_scheduled", "Webhook renewal job scheduled (every 6 hours)", nil)
+		logger.Startup("webhook_renewal_scheduled", "Webhook renewal job scheduled (hourly)", map[string]interface{}{
+			"renewal_window_hours": c.Config.GoogleDrive.WebhookRenewalWindowHours,
+		})
+	}
+}
+
+// scheduleFolderSyncs registers the periodic sync jobs of folders that have a sync schedule
+func (c *Container) scheduleFolderSyncs() {
+	if c.EventScheduler == nil || c.SharedFolderService == nil {
+		logger.StartupWarn("folder_sync_schedule_skip", "Scheduler or SharedFolderService not available, skipping folder sync schedules", nil)
+		return
+	}
+
+	registered, err := c.SharedFolderService.RegisterSyncSchedules(context.Background())
+	if err != nil {
+		logger.StartupWarn("folder_sync_schedule_failed", "Failed to register folder sync schedules", map[string]interface{}{"error": err.Error()})
+	} else {
+		logger.Startup("folder_sync_schedules_registered", "Folder sync schedules registered", map[string]interface{}{
+			"folders": registered,
+		})
 	}
 }
 
@@ -389,6 +529,191 @@ func (c *Container) scheduleAutoResetStuck() {
 	}
 }
 
+// scheduleFaceCountReconcile sets up a scheduled job to fix photos whose face_count drifted from their faces rows
+func (c *Container) scheduleFaceCountReconcile() {
+	if c.EventScheduler == nil || c.PhotoRepository == nil {
+		logger.StartupWarn("face_count_reconcile_skip", "Scheduler or PhotoRepository not available, skipping face count reconcile job", nil)
+		return
+	}
+
+	// Run daily at 03:30 UTC: "30 3 * * *"
+	err := c.EventScheduler.AddJob("face-count-reconcile", "30 3 * * *", func() {
+		fixed, err := c.PhotoRepository.ReconcileFaceCounts(context.Background(), nil)
+		if err != nil {
+			logger.SchedulerError("face_count_reconcile_error", "Failed to reconcile face counts", err, nil)
+			return
+		}
+
+		if fixed > 0 {
+			logger.Scheduler("face_count_reconcile_done", "Face count reconciliation completed", map[string]interface{}{
+				"fixed": fixed,
+			})
+		}
+	})
+
+	if err != nil {
+		logger.StartupWarn("face_count_reconcile_schedule_failed", "Failed to schedule face count reconcile job", map[string]interface{}{"error": err.Error()})
+	} else {
+		logger.Startup("face_count_reconcile_scheduled", "Face count reconcile job scheduled (daily at 03:30 UTC)", nil)
+	}
+}
+
+// scheduleFolderPhotoCountReconcile sets up a scheduled job to correct drift in shared folders' cached photo counts
+// Also runs once at startup so existing folders get their count after the column is added
+func (c *Container) scheduleFolderPhotoCountReconcile() {
+	if c.EventScheduler == nil || c.SharedFolderRepository == nil {
+		logger.StartupWarn("folder_photo_count_reconcile_skip", "Scheduler or SharedFolderRepository not available, skipping folder photo count reconcile job", nil)
+		return
+	}
+
+	reconcile := func() {
+		fixed, err := c.SharedFolderRepository.ReconcilePhotoCounts(context.Background())
+		if err != nil {
+			logger.SchedulerError("folder_photo_count_reconcile_error", "Failed to reconcile folder photo counts", err, nil)
+			return
+		}
+
+		if fixed > 0 {
+			logger.Scheduler("folder_photo_count_reconcile_done", "Folder photo count reconciliation completed", map[string]interface{}{
+				"fixed": fixed,
+			})
+		}
+	}
+
+	go reconcile()
+
+	// Run every hour at minute 15: "15 * * * *"
+	err := c.EventScheduler.AddJob("folder-photo-count-reconcile", "15 * * * *", reconcile)
+
+	if err != nil {
+		logger.StartupWarn("folder_photo_count_reconcile_schedule_failed", "Failed to schedule folder photo count reconcile job", map[string]interface{}{"error": err.Error()})
+	} else {
+		logger.Startup("folder_photo_count_reconcile_scheduled", "Folder photo count reconcile job scheduled (hourly)", nil)
+	}
+}
+
+// scheduleTrashedPhotoPurge sets up a scheduled job to hard delete photos trashed longer than the retention period
+func (c *Container) scheduleTrashedPhotoPurge() {
+	if c.EventScheduler == nil || c.PhotoRepository == nil {
+		logger.StartupWarn("trash_purge_skip", "Scheduler or PhotoRepository not available, skipping trashed photo purge job", nil)
+		return
+	}
+
+	retentionDays := c.Config.Folder.TrashRetentionDays
+	if retentionDays <= 0 {
+		logger.Startup("trash_purge_disabled", "Trashed photo purge disabled (TRASH_RETENTION_DAYS=0)", nil)
+		return
+	}
+
+	// Run daily at 04:00 UTC: "0 4 * * *"
+	err := c.EventScheduler.AddJob("trashed-photo-purge", "0 4 * * *", func() {
+		cutoff := time.Now().AddDate(0, 0, -retentionDays)
+		purged, err := c.PhotoRepository.PurgeTrashedBefore(context.Background(), cutoff)
+		if err != nil {
+			logger.SchedulerError("trash_purge_error", "Failed to purge trashed photos", err, nil)
+			return
+		}
+
+		if purged > 0 {
+			logger.Scheduler("trash_purge_done", "Purged long-trashed photos", map[string]interface{}{
+				"purged":         purged,
+				"retention_days": retentionDays,
+			})
+		}
+	})
+
+	if err != nil {
+		logger.StartupWarn("trash_purge_schedule_failed", "Failed to schedule trashed photo purge job", map[string]interface{}{"error": err.Error()})
+	} else {
+		logger.Startup("trash_purge_scheduled", "Trashed photo purge job scheduled (daily at 04:00 UTC)", map[string]interface{}{
+			"retention_days": retentionDays,
+		})
+	}
+}
+
+// scheduleSyncJobCleanup sets up a scheduled job to delete finished sync jobs older than the retention period
+func (c *Container) scheduleSyncJobCleanup() {
+	if c.EventScheduler == nil || c.SyncJobRepository == nil {
+		logger.StartupWarn("sync_job_cleanup_skip", "Scheduler or SyncJobRepository not available, skipping sync job cleanup job", nil)
+		return
+	}
+
+	retentionDays := c.Config.Worker.SyncJobRetentionDays
+	if retentionDays <= 0 {
+		logger.Startup("sync_job_cleanup_disabled", "Sync job cleanup disabled (SYNC_JOB_RETENTION_DAYS=0)", nil)
+		return
+	}
+	keepPerFolder := c.Config.Worker.SyncJobKeepPerFolder
+	if keepPerFolder < 0 {
+		keepPerFolder = 0
+	}
+
+	// Run daily at 04:30 UTC: "30 4 * * *"
+	err := c.EventScheduler.AddJob("sync-job-cleanup", "30 4 * * *", func() {
+		cutoff := time.Now().AddDate(0, 0, -retentionDays)
+		deleted, err := c.SyncJobRepository.DeleteFinishedBefore(context.Background(), cutoff, keepPerFolder)
+		if err != nil {
+			logger.SchedulerError("sync_job_cleanup_error", "Failed to delete old sync jobs", err, nil)
+			return
+		}
+
+		if deleted > 0 {
+			logger.Scheduler("sync_job_cleanup_done", "Deleted old finished sync jobs", map[string]interface{}{
+				"deleted":         deleted,
+				"retention_days":  retentionDays,
+				"keep_per_folder": keepPerFolder,
+			})
+		}
+	})
+
+	if err != nil {
+		logger.StartupWarn("sync_job_cleanup_schedule_failed", "Failed to schedule sync job cleanup job", map[string]interface{}{"error": err.Error()})
+	} else {
+		logger.Startup("sync_job_cleanup_scheduled", "Sync job cleanup job scheduled (daily at 04:30 UTC)", map[string]interface{}{
+			"retention_days":  retentionDays,
+			"keep_per_folder": keepPerFolder,
+		})
+	}
+}
+
+// scheduleLogCleanup deletes category log files older than LOG_RETENTION_DAYS once a day
+func (c *Container) scheduleLogCleanup() {
+	if c.EventScheduler == nil {
+		logger.StartupWarn("log_cleanup_skip", "Scheduler not available, skipping log cleanup job", nil)
+		return
+	}
+
+	retentionDays := c.Config.Log.RetentionDays
+	if retentionDays <= 0 {
+		logger.Startup("log_cleanup_disabled", "Log cleanup disabled (LOG_RETENTION_DAYS=0)", nil)
+		return
+	}
+
+	// Run daily at 04:45 UTC: "45 4 * * *"
+	err := c.EventScheduler.AddJob("log-cleanup", "45 4 * * *", func() {
+		deleted, err := logger.CleanupOldFiles(retentionDays)
+		if err != nil {
+			logger.SchedulerError("log_cleanup_error", "Failed to delete old log files", err, nil)
+			return
+		}
+
+		if deleted > 0 {
+			logger.Scheduler("log_cleanup_done", "Deleted old log files", map[string]interface{}{
+				"deleted":        deleted,
+				"retention_days": retentionDays,
+			})
+		}
+	})
+
+	if err != nil {
+		logger.StartupWarn("log_cleanup_schedule_failed", "Failed to schedule log cleanup job", map[string]interface{}{"error": err.Error()})
+	} else {
+		logger.Startup("log_cleanup_scheduled", "Log cleanup job scheduled (daily at 04:45 UTC)", map[string]interface{}{
+			"retention_days": retentionDays,
+		})
+	}
+}
+
 // autoSyncOnStartup creates sync jobs for all users with Drive connected
 func (c *Container) autoSyncOnStartup() {
 	ctx := context.Background()
@@ -443,6 +768,13 @@ func (c *Container) Cleanup() error {
 		}
 	}
 
+	// Stop export worker
+	if c.ExportWorker != nil {
+		if c.ExportWorker.IsRunning() {
+			c.ExportWorker.Stop()
+		}
+	}
+
 	// Stop scheduler
 	if c.EventScheduler != nil {
 		if c.EventScheduler.IsRunning() {
@@ -498,6 +830,8 @@ func (c *Container) GetHandlerServices() *handlers.Services {
 		NewsService:         c.NewsService,
 		SharedFolderService: c.SharedFolderService,
 		ActivityLogService:  c.ActivityLogService,
+		CurationService:     c.CurationService,
+		PublicSearchService: c.PublicSearchService,
 	}
 }
 
@@ -506,5 +840,6 @@ func (c *Container) GetHandlerRepositories() *handlers.Repositories {
 		PhotoRepository:        c.PhotoRepository,
 		SharedFolderRepository: c.SharedFolderRepository,
 		UserRepository:         c.UserRepository,
+		SyncSummaryRepository:  c.SyncSummaryRepository,
 	}
 }
\ No newline at end of file
@@ -3,9 +3,9 @@ package logger
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
@@ -36,6 +36,15 @@ const (
 	LevelError Level = "ERROR"
 )
 
+// OutputMode controls where log entries are written
+type OutputMode string
+
+const (
+	OutputFile   OutputMode = "file"   // JSON per-category files in logDir (+ human console if enabled)
+	OutputStdout OutputMode = "stdout" // JSON lines on stdout only (for containers / log aggregators)
+	OutputBoth   OutputMode = "both"   // JSON per-category files and JSON lines on stdout
+)
+
 // LogEntry represents a structured log entry
 type LogEntry struct {
 	Timestamp time.Time              `json:"timestamp"`
@@ -56,7 +65,27 @@ type Logger struct {
 	logDir   string
 	writers  map[Category]*os.File
 	console  bool
+	output   OutputMode
 	minLevel Level
+
+	// Per-category overrides of minLevel (unset categories use minLevel)
+	categoryLevels map[Category]Level
+
+	// Size rotation of category files (see writeFile)
+	maxFileSize int64              // Rotate a file before it grows past this many bytes (0 = never)
+	fileSizes   map[Category]int64 // Current size of each open category file
+
+	// Sampling for high-volume per-item logs (see SyncSampled)
+	sampleEvery  int               // Log 1 in N sampled entries (<=1 = log all)
+	sampleCounts map[string]uint64 // Per category/action counters
+}
+
+// levelOrder ranks levels for minLevel filtering
+var levelOrder = map[Level]int{
+	LevelDebug: 0,
+	LevelInfo:  1,
+	LevelWarn:  2,
+	LevelError: 3,
 }
 
 var (
@@ -65,34 +94,136 @@ var (
 )
 
 // Init initializes the default logger
+// LOG_LEVEL from the process environment applies right away, so entries logged before the
+// config is loaded are filtered too (the container re-applies it once .env is read)
 func Init(logDir string, console bool) error {
 	var err error
 	once.Do(func() {
 		defaultLogger, err = NewLogger(logDir, console)
+		if err == nil {
+			if level, ok := ParseLevel(os.Getenv("LOG_LEVEL")); ok {
+				defaultLogger.SetMinLevel(level)
+			}
+		}
 	})
 	return err
 }
 
+// ParseLevel parses a level name case-insensitively (e.g. "warn"); ok is false for unknown names
+func ParseLevel(s string) (Level, bool) {
+	level := Level(strings.ToUpper(strings.TrimSpace(s)))
+	_, ok := levelOrder[level]
+	return level, ok
+}
+
 // NewLogger creates a new logger
+// The log directory is created on first file write, so stdout-only mode
+// works on read-only filesystems.
 func NewLogger(logDir string, console bool) (*Logger, error) {
-	// Create log directory if not exists
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create log directory: %w", err)
-	}
-
 	return &Logger{
 		logDir:   logDir,
 		writers:  make(map[Category]*os.File),
 		console:  console,
+		output:   OutputFile,
 		minLevel: LevelDebug,
+
+		categoryLevels: make(map[Category]Level),
+
+		fileSizes: make(map[Category]int64),
+
+		sampleEvery:  1,
+		sampleCounts: make(map[string]uint64),
 	}, nil
 }
 
-// getWriter returns or creates a file writer for the category
-func (l *Logger) getWriter(category Category) (io.Writer, error) {
+// SetMinLevel sets the lowest level written by the default logger
+func SetMinLevel(level Level) {
+	Default().SetMinLevel(level)
+}
+
+// SetMinLevel sets the lowest level written (unknown levels fall back to DEBUG)
+func (l *Logger) SetMinLevel(level Level) {
+	if _, ok := levelOrder[level]; !ok {
+		level = LevelDebug
+	}
+	l.mu.Lock()
+	l.minLevel = level
+	l.mu.Unlock()
+}
+
+// SetCategoryLevel sets the lowest level written for one category of the default logger
+func SetCategoryLevel(category Category, level Level) {
+	Default().SetCategoryLevel(category, level)
+}
+
+// SetCategoryLevel sets the lowest level written for one category, overriding the global
+// minimum in either direction (unknown levels remove the override)
+func (l *Logger) SetCategoryLevel(category Category, level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := levelOrder[level]; !ok {
+		delete(l.categoryLevels, category)
+		return
+	}
+	l.categoryLevels[category] = level
+}
+
+// SetSampleRate makes sampled logs write only 1 in every n entries per action (n <= 1 logs all)
+func SetSampleRate(n int) {
+	Default().SetSampleRate(n)
+}
+
+// SetSampleRate makes sampled logs write only 1 in every n entries per action (n <= 1 logs all)
+func (l *Logger) SetSampleRate(n int) {
+	if n < 1 {
+		n = 1
+	}
+	l.mu.Lock()
+	l.sampleEvery = n
+	l.mu.Unlock()
+}
+
+// shouldSample reports whether a sampled entry should be written and the current rate
+func (l *Logger) shouldSample(category Category, action string) (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.sampleEvery <= 1 {
+		return true, 1
+	}
+	key := string(category) + ":" + action
+	count := l.sampleCounts[key]
+	l.sampleCounts[key] = count + 1
+	return count%uint64(l.sampleEvery) == 0, l.sampleEvery
+}
+
+// SetOutput sets where the default logger writes (unknown modes fall back to file)
+func SetOutput(mode OutputMode) {
+	Default().SetOutput(mode)
+}
+
+// SetOutput sets where this logger writes (unknown modes fall back to file)
+func (l *Logger) SetOutput(mode OutputMode) {
+	switch mode {
+	case OutputStdout, OutputBoth:
+	default:
+		mode = OutputFile
+	}
+
+	l.mu.Lock()
+	l.output = mode
+	l.mu.Unlock()
+}
+
+// Output returns the current output mode
+func (l *Logger) Output() OutputMode {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	return l.output
+}
 
+// getWriter returns or creates a file writer for the category (the caller holds l.mu)
+func (l *Logger) getWriter(category Category) (*os.File, error) {
 	// Check if writer exists and is for today
 	today := time.Now().Format("2006-01-02")
 	filename := fmt.Sprintf("%s_%s.log", category, today)
@@ -108,17 +239,36 @@ func (l *Logger) getWriter(category Category) (io.Writer, error) {
 		writer.Close()
 	}
 
+	// Create log directory if not exists
+	if err := os.MkdirAll(l.logDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
 	// Create new file
 	file, err := os.OpenFile(filepath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return nil, err
 	}
 	l.writers[category] = file
+	l.fileSizes[category] = 0
+	if info, err := file.Stat(); err == nil {
+		l.fileSizes[category] = info.Size()
+	}
 	return file, nil
 }
 
 // Log writes a log entry
 func (l *Logger) Log(entry LogEntry) {
+	l.mu.Lock()
+	minLevel := l.minLevel
+	if level, ok := l.categoryLevels[entry.Category]; ok {
+		minLevel = level
+	}
+	l.mu.Unlock()
+	if levelOrder[entry.Level] < levelOrder[minLevel] {
+		return
+	}
+
 	entry.Timestamp = time.Now()
 
 	// Format as JSON
@@ -128,12 +278,21 @@ func (l *Logger) Log(entry LogEntry) {
 		return
 	}
 
+	output := l.Output()
+
 	// Write to file
-	writer, err := l.getWriter(entry.Category)
-	if err != nil {
-		fmt.Printf("Error getting log writer: %v\n", err)
-	} else {
-		fmt.Fprintln(writer, string(jsonData))
+	if output != OutputStdout {
+		if err := l.writeFile(entry.Category, jsonData); err != nil {
+			fmt.Printf("Error writing log file: %v\n", err)
+		}
+	}
+
+	// JSON lines on stdout replace the human console format
+	if output == OutputStdout || output == OutputBoth {
+		l.mu.Lock()
+		fmt.Fprintln(os.Stdout, string(jsonData))
+		l.mu.Unlock()
+		return
 	}
 
 	// Also write to console if enabled
@@ -245,6 +404,17 @@ func Webhook(action, message string, data map[string]interface{}) {
 	})
 }
 
+// WebhookWarn logs webhook warnings
+func WebhookWarn(action, message string, data map[string]interface{}) {
+	Default().Log(LogEntry{
+		Level:    LevelWarn,
+		Category: CategoryWebhook,
+		Action:   action,
+		Message:  message,
+		Data:     data,
+	})
+}
+
 // WebhookError logs webhook errors
 func WebhookError(action, message string, err error, data map[string]interface{}) {
 	errStr := ""
@@ -321,6 +491,17 @@ func Sync(action, message string, data map[string]interface{}) {
 	})
 }
 
+// SyncWarn logs sync warnings
+func SyncWarn(action, message string, data map[string]interface{}) {
+	Default().Log(LogEntry{
+		Level:    LevelWarn,
+		Category: CategorySync,
+		Action:   action,
+		Message:  message,
+		Data:     data,
+	})
+}
+
 // SyncError logs sync errors
 func SyncError(action, message string, err error, data map[string]interface{}) {
 	errStr := ""
@@ -337,6 +518,30 @@ func SyncError(action, message string, err error, data map[string]interface{}) {
 	})
 }
 
+// SyncSampled logs high-volume per-item sync events (per file / per progress step).
+// Written at DEBUG level and only 1 in N per action when sampling is enabled,
+// so start/complete/error logs stay readable during large syncs.
+func SyncSampled(action, message string, data map[string]interface{}) {
+	l := Default()
+	ok, every := l.shouldSample(CategorySync, action)
+	if !ok {
+		return
+	}
+	if every > 1 {
+		if data == nil {
+			data = map[string]interface{}{}
+		}
+		data["sampled_1_in"] = every
+	}
+	l.Log(LogEntry{
+		Level:    LevelDebug,
+		Category: CategorySync,
+		Action:   action,
+		Message:  message,
+		Data:     data,
+	})
+}
+
 // API logs API request/response events
 func API(action, message string, data map[string]interface{}) {
 	Default().Log(LogEntry{
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   Level
+		wantOK bool
+	}{
+		{in: "DEBUG", want: LevelDebug, wantOK: true},
+		{in: "info", want: LevelInfo, wantOK: true},
+		{in: "Warn", want: LevelWarn, wantOK: true},
+		{in: " error ", want: LevelError, wantOK: true},
+		{in: "", wantOK: false},
+		{in: "verbose", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			level, ok := ParseLevel(tt.in)
+			if ok != tt.wantOK || (ok && level != tt.want) {
+				t.Errorf("ParseLevel(%q) = (%q, %v), want (%q, %v)", tt.in, level, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// writtenActions logs one entry per level for category and returns the actions found in its file
+func writtenActions(t *testing.T, l *Logger, category Category) []string {
+	t.Helper()
+
+	for _, level := range []Level{LevelDebug, LevelInfo, LevelWarn, LevelError} {
+		l.Log(LogEntry{Level: level, Category: category, Action: string(level), Message: "test"})
+	}
+	l.Close()
+
+	name := string(category) + "_" + time.Now().Format("2006-01-02") + ".log"
+	file, err := os.Open(filepath.Join(l.logDir, name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatalf("open log file: %v", err)
+	}
+	defer file.Close()
+
+	var actions []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("log line is not JSON: %s", scanner.Bytes())
+		}
+		actions = append(actions, entry.Action)
+	}
+	return actions
+}
+
+func TestLoggerLevelFiltering(t *testing.T) {
+	tests := []struct {
+		name          string
+		minLevel      Level
+		categoryLevel Level // Override for CategorySync ("" = none)
+		want          []string
+	}{
+		{name: "default writes every level", want: []string{"DEBUG", "INFO", "WARN", "ERROR"}},
+		{name: "info hides debug", minLevel: LevelInfo, want: []string{"INFO", "WARN", "ERROR"}},
+		{name: "error keeps only errors", minLevel: LevelError, want: []string{"ERROR"}},
+		{name: "unknown level falls back to debug", minLevel: "verbose", want: []string{"DEBUG", "INFO", "WARN", "ERROR"}},
+		{name: "category raises the minimum", minLevel: LevelInfo, categoryLevel: LevelWarn, want: []string{"WARN", "ERROR"}},
+		{name: "category lowers the minimum", minLevel: LevelError, categoryLevel: LevelDebug, want: []string{"DEBUG", "INFO", "WARN", "ERROR"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewLogger(t.TempDir(), false)
+			if err != nil {
+				t.Fatalf("NewLogger: %v", err)
+			}
+			if tt.minLevel != "" {
+				l.SetMinLevel(tt.minLevel)
+			}
+			if tt.categoryLevel != "" {
+				l.SetCategoryLevel(CategorySync, tt.categoryLevel)
+			}
+
+			got := writtenActions(t, l, CategorySync)
+			if len(got) != len(tt.want) {
+				t.Fatalf("written = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("written = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoggerCategoryLevelOnlyAffectsItsCategory(t *testing.T) {
+	l, err := NewLogger(t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	l.SetMinLevel(LevelInfo)
+	l.SetCategoryLevel(CategoryDB, LevelError)
+
+	got := writtenActions(t, l, CategoryAPI)
+	if len(got) != 3 || got[0] != "INFO" {
+		t.Errorf("api written = %v, want INFO, WARN, ERROR", got)
+	}
+}
@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SetMaxFileSizeMB makes the default logger rotate category files at this size (0 = never)
+func SetMaxFileSizeMB(mb int) {
+	Default().SetMaxFileSizeMB(mb)
+}
+
+// SetMaxFileSizeMB makes category files rotate to .1, .2, ... before they grow past mb (0 = never)
+func (l *Logger) SetMaxFileSizeMB(mb int) {
+	if mb < 0 {
+		mb = 0
+	}
+	l.mu.Lock()
+	l.maxFileSize = int64(mb) * 1024 * 1024
+	l.mu.Unlock()
+}
+
+// writeFile appends one line to the category's file of today, rotating it first if the line
+// would take it past maxFileSize. The whole write runs under l.mu, so a rotation never closes
+// a file another goroutine is writing to
+func (l *Logger) writeFile(category Category, line []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := l.getWriter(category)
+	if err != nil {
+		return err
+	}
+
+	size := l.fileSizes[category]
+	if l.maxFileSize > 0 && size > 0 && size+int64(len(line))+1 > l.maxFileSize {
+		if file, err = l.rotate(category, file); err != nil {
+			return err
+		}
+	}
+
+	n, err := fmt.Fprintln(file, string(line))
+	l.fileSizes[category] += int64(n)
+	return err
+}
+
+// rotate renames the category's current file to .1 (older rotations move up to .2, .3, ...)
+// and opens a new one. The caller holds l.mu
+func (l *Logger) rotate(category Category, file *os.File) (*os.File, error) {
+	path := file.Name()
+	file.Close()
+	delete(l.writers, category)
+
+	last := 0
+	for {
+		if _, err := os.Stat(fmt.Sprintf("%s.%d", path, last+1)); err != nil {
+			break
+		}
+		last++
+	}
+	for i := last; i >= 1; i-- {
+		if err := os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1)); err != nil {
+			return nil, fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+	if err := os.Rename(path, path+".1"); err != nil {
+		return nil, fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	return l.getWriter(category)
+}
+
+// CleanupOldFiles deletes category log files (rotations included) of the default logger
+// dated more than retentionDays days ago
+func CleanupOldFiles(retentionDays int) (int, error) {
+	return Default().CleanupOldFiles(retentionDays)
+}
+
+// CleanupOldFiles deletes category log files (rotations included) dated more than
+// retentionDays days ago. Other files in the log directory are left alone (0 = keep all)
+func (l *Logger) CleanupOldFiles(retentionDays int) (int, error) {
+	if retentionDays <= 0 {
+		return 0, nil
+	}
+
+	entries, err := os.ReadDir(l.logDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -retentionDays).Format("2006-01-02")
+	deleted := 0
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		date, ok := logFileDate(e.Name())
+		if !ok || date >= cutoff {
+			continue
+		}
+		if err := os.Remove(filepath.Join(l.logDir, e.Name())); err == nil {
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// logFileDate returns the date of a category log file name: <category>_<YYYY-MM-DD>.log with
+// an optional .N rotation suffix
+func logFileDate(name string) (string, bool) {
+	base, suffix, ok := strings.Cut(name, ".log")
+	if !ok {
+		return "", false
+	}
+	if suffix != "" {
+		if _, err := strconv.Atoi(strings.TrimPrefix(suffix, ".")); err != nil || !strings.HasPrefix(suffix, ".") {
+			return "", false
+		}
+	}
+
+	i := strings.LastIndex(base, "_")
+	if i < 0 {
+		return "", false
+	}
+	date := base[i+1:]
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return "", false
+	}
+	return date, true
+}
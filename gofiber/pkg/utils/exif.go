@@ -0,0 +1,222 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+// ExifMetadata is the capture information read from a JPEG's EXIF block.
+// Fields the image doesn't carry are left empty.
+type ExifMetadata struct {
+	TakenAt     *time.Time // DateTimeOriginal (falls back to DateTime)
+	CameraMake  string
+	CameraModel string
+	Width       int // PixelXDimension/PixelYDimension
+	Height      int
+}
+
+// ExifTimeLayout is how EXIF (and Drive's imageMediaMetadata.time) writes timestamps
+const ExifTimeLayout = "2006:01:02 15:04:05"
+
+// EXIF tags and value types read by ParseExif
+const (
+	exifTagMake             = 0x010F
+	exifTagModel            = 0x0110
+	exifTagDateTime         = 0x0132
+	exifTagExifIFD          = 0x8769
+	exifTagDateTimeOriginal = 0x9003
+	exifTagOffsetTimeOrig   = 0x9011
+	exifTagPixelXDimension  = 0xA002
+	exifTagPixelYDimension  = 0xA003
+
+	exifTypeASCII = 2
+	exifTypeShort = 3
+	exifTypeLong  = 4
+
+	exifMaxEntriesPerIFD = 1000 // Corrupt IFDs can claim up to 65535 entries
+)
+
+// JPEG markers walked by findExifTIFF
+const (
+	jpegMarkerPrefix       = 0xFF
+	jpegMarkerStartOfImage = 0xD8
+	jpegMarkerEndOfImage   = 0xD9
+	jpegMarkerStartOfScan  = 0xDA
+	jpegMarkerApp1         = 0xE1
+)
+
+// exifHeader starts the APP1 segment holding EXIF (XMP also uses APP1)
+const exifHeader = "Exif\x00\x00"
+
+// ParseExif reads capture metadata from JPEG bytes. ok is false when the data is not a JPEG or
+// carries no readable EXIF block; malformed blocks never panic, they just yield fewer fields.
+// Timestamps without an offset tag are the camera's wall clock, stored as UTC.
+func ParseExif(data []byte) (meta *ExifMetadata, ok bool) {
+	tiff := findExifTIFF(data)
+	if tiff == nil {
+		return nil, false
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, false
+	}
+	if order.Uint16(tiff[2:4]) != 42 {
+		return nil, false
+	}
+
+	r := exifReader{tiff: tiff, order: order}
+	ifd0 := r.readIFD(order.Uint32(tiff[4:8]))
+	if ifd0 == nil {
+		return nil, false
+	}
+
+	meta = &ExifMetadata{
+		CameraMake:  r.ascii(ifd0[exifTagMake]),
+		CameraModel: r.ascii(ifd0[exifTagModel]),
+	}
+	dateTime := r.ascii(ifd0[exifTagDateTime])
+	offset := ""
+
+	if ptr, found := ifd0[exifTagExifIFD]; found {
+		if sub := r.readIFD(r.uint(ptr)); sub != nil {
+			if original := r.ascii(sub[exifTagDateTimeOriginal]); original != "" {
+				dateTime = original
+				offset = r.ascii(sub[exifTagOffsetTimeOrig])
+			}
+			meta.Width = int(r.uint(sub[exifTagPixelXDimension]))
+			meta.Height = int(r.uint(sub[exifTagPixelYDimension]))
+		}
+	}
+
+	if dateTime != "" {
+		if t, err := ParseExifTime(dateTime, offset); err == nil {
+			meta.TakenAt = &t
+		}
+	}
+
+	return meta, true
+}
+
+// ParseExifTime parses an EXIF timestamp with an optional "+07:00" style offset (UTC if empty).
+// Cameras without a clock write all zeros, which is rejected.
+func ParseExifTime(value, offset string) (time.Time, error) {
+	if offset != "" {
+		if t, err := time.Parse(ExifTimeLayout+"-07:00", value+offset); err == nil {
+			return t, nil
+		}
+	}
+	return time.Parse(ExifTimeLayout, value)
+}
+
+// findExifTIFF returns the TIFF structure of a JPEG's APP1 Exif segment, or nil
+func findExifTIFF(data []byte) []byte {
+	if len(data) < 4 || data[0] != jpegMarkerPrefix || data[1] != jpegMarkerStartOfImage {
+		return nil
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != jpegMarkerPrefix {
+			return nil
+		}
+		marker := data[pos+1]
+		if marker == jpegMarkerPrefix {
+			pos++ // Fill byte
+			continue
+		}
+		if marker == jpegMarkerStartOfScan || marker == jpegMarkerEndOfImage {
+			return nil // Image data starts: metadata segments come before it
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil
+		}
+		segment := data[pos+4 : end]
+		if marker == jpegMarkerApp1 && bytes.HasPrefix(segment, []byte(exifHeader)) {
+			if tiff := segment[len(exifHeader):]; len(tiff) >= 8 {
+				return tiff
+			}
+			return nil
+		}
+		pos = end
+	}
+	return nil
+}
+
+// exifEntry is one raw IFD entry: its type, count and the 4-byte value/offset field
+type exifEntry struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// exifReader reads IFDs of a TIFF structure with bounds checks on every access
+type exifReader struct {
+	tiff  []byte
+	order binary.ByteOrder
+}
+
+// readIFD returns the entries of the IFD at offset by tag, or nil if it is out of bounds
+func (r exifReader) readIFD(offset uint32) map[uint16]exifEntry {
+	start := int(offset)
+	if offset == 0 || start+2 > len(r.tiff) {
+		return nil
+	}
+
+	n := int(r.order.Uint16(r.tiff[start : start+2]))
+	if n > exifMaxEntriesPerIFD || start+2+n*12 > len(r.tiff) {
+		return nil
+	}
+
+	entries := make(map[uint16]exifEntry, n)
+	for i := 0; i < n; i++ {
+		e := r.tiff[start+2+i*12 : start+14+i*12]
+		entries[r.order.Uint16(e[0:2])] = exifEntry{
+			typ:   r.order.Uint16(e[2:4]),
+			count: r.order.Uint32(e[4:8]),
+			value: e[8:12],
+		}
+	}
+	return entries
+}
+
+// ascii returns an ASCII entry's string, trimmed of NULs and spaces ("" if unusable)
+func (r exifReader) ascii(e exifEntry) string {
+	if e.typ != exifTypeASCII || e.count == 0 {
+		return ""
+	}
+
+	var raw []byte
+	if e.count <= 4 {
+		raw = e.value[:e.count]
+	} else {
+		start := int(r.order.Uint32(e.value))
+		end := start + int(e.count)
+		if start < 0 || end > len(r.tiff) || end < start {
+			return ""
+		}
+		raw = r.tiff[start:end]
+	}
+	return strings.TrimSpace(strings.TrimRight(string(raw), "\x00"))
+}
+
+// uint returns a single SHORT or LONG entry's value (0 if unusable)
+func (r exifReader) uint(e exifEntry) uint32 {
+	switch e.typ {
+	case exifTypeShort:
+		return uint32(r.order.Uint16(e.value[0:2]))
+	case exifTypeLong:
+		return r.order.Uint32(e.value)
+	}
+	return 0
+}
@@ -1,14 +1,18 @@
 package utils
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 )
 
 type Response struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Data    interface{}       `json:"data,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	Errors  map[string]string `json:"errors,omitempty"` // Field -> message for validation failures
 }
 
 type PaginatedResponse struct {
@@ -67,6 +71,23 @@ func ValidationErrorResponse(c *fiber.Ctx, message string) error {
 	})
 }
 
+// FieldErrorsResponse is a 400 listing what is wrong with each field of a request body.
+// "error" carries all messages in one string for clients that only read that field.
+func FieldErrorsResponse(c *fiber.Ctx, fieldErrors map[string]string) error {
+	messages := make([]string, 0, len(fieldErrors))
+	for _, msg := range fieldErrors {
+		messages = append(messages, msg)
+	}
+	sort.Strings(messages)
+
+	return c.Status(fiber.StatusBadRequest).JSON(Response{
+		Success: false,
+		Message: "Validation failed",
+		Error:   strings.Join(messages, "; "),
+		Errors:  fieldErrors,
+	})
+}
+
 func UnauthorizedResponse(c *fiber.Ctx, message string) error {
 	return c.Status(fiber.StatusUnauthorized).JSON(Response{
 		Success: false,
@@ -1,9 +1,13 @@
 package utils
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"reflect"
 	"strings"
 	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
 )
 
 var validate *validator.Validate
@@ -23,6 +27,57 @@ func ValidateStruct(s interface{}) error {
 	return validate.Struct(s)
 }
 
+// ParseBody parses the request body into out and validates its `validate` tags.
+// Returns nil on success, otherwise field -> message errors for FieldErrorsResponse
+// (body-level problems such as malformed JSON are reported under "body").
+func ParseBody(c *fiber.Ctx, out interface{}) map[string]string {
+	if err := c.BodyParser(out); err != nil {
+		return getParseErrors(err)
+	}
+	if err := ValidateStruct(out); err != nil {
+		return GetValidationErrors(err)
+	}
+	return nil
+}
+
+// getParseErrors describes why a body could not be parsed
+func getParseErrors(err error) map[string]string {
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return map[string]string{typeErr.Field: fmt.Sprintf("%s must be a %s", typeErr.Field, jsonTypeName(typeErr.Type))}
+	case errors.As(err, &syntaxErr):
+		return map[string]string{"body": fmt.Sprintf("malformed JSON at position %d", syntaxErr.Offset)}
+	case errors.Is(err, fiber.ErrUnprocessableEntity):
+		return map[string]string{"body": "Content-Type must be application/json"}
+	default:
+		return map[string]string{"body": "invalid request body: " + err.Error()}
+	}
+}
+
+// jsonTypeName names a Go type the way API clients see it in JSON
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return t.String()
+	}
+}
+
 func GetValidationErrors(err error) map[string]string {
 	errors := make(map[string]string)
 
@@ -42,9 +97,21 @@ func getErrorMessage(e validator.FieldError) string {
 	case "email":
 		return e.Field() + " must be a valid email"
 	case "min":
+		if isCollection(e.Kind()) {
+			return e.Field() + " must contain at least " + e.Param() + " items"
+		}
 		return e.Field() + " must be at least " + e.Param() + " characters"
 	case "max":
+		if isCollection(e.Kind()) {
+			return e.Field() + " must contain at most " + e.Param() + " items"
+		}
 		return e.Field() + " must be at most " + e.Param() + " characters"
+	case "uuid":
+		return e.Field() + " must be a valid UUID"
+	case "oneof":
+		return e.Field() + " must be one of: " + strings.Join(strings.Fields(e.Param()), ", ")
+	case "url":
+		return e.Field() + " must be a valid URL"
 	case "gte":
 		return e.Field() + " must be greater than or equal to " + e.Param()
 	case "lte":
@@ -52,4 +119,8 @@ func getErrorMessage(e validator.FieldError) string {
 	default:
 		return e.Field() + " is invalid"
 	}
+}
+
+func isCollection(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
 }
\ No newline at end of file
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type testParseRequest struct {
+	Name  string   `json:"name" validate:"required"`
+	Mode  string   `json:"mode" validate:"omitempty,oneof=full incremental"`
+	Limit int      `json:"limit" validate:"gte=0,lte=100"`
+	Tags  []string `json:"tags" validate:"max=2"`
+}
+
+// newParseApp returns an app whose POST / parses testParseRequest the way handlers do
+func newParseApp() *fiber.App {
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		var req testParseRequest
+		if fieldErrors := ParseBody(c, &req); fieldErrors != nil {
+			return FieldErrorsResponse(c, fieldErrors)
+		}
+		return SuccessResponse(c, "ok", req)
+	})
+	return app
+}
+
+func doParse(t *testing.T, app *fiber.App, contentType, body string) (int, Response) {
+	t.Helper()
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	var out Response
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("response is not JSON: %s", raw)
+	}
+	return resp.StatusCode, out
+}
+
+func TestParseBodyFieldErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErrors  map[string]string
+	}{
+		{
+			name:        "valid body",
+			contentType: fiber.MIMEApplicationJSON,
+			body:        `{"name":"a","mode":"full","limit":10}`,
+		},
+		{
+			name:        "missing required field",
+			contentType: fiber.MIMEApplicationJSON,
+			body:        `{}`,
+			wantErrors:  map[string]string{"name": "name is required"},
+		},
+		{
+			name:        "several validation failures",
+			contentType: fiber.MIMEApplicationJSON,
+			body:        `{"mode":"partial","limit":101,"tags":["a","b","c"]}`,
+			wantErrors: map[string]string{
+				"name":  "name is required",
+				"mode":  "mode must be one of: full, incremental",
+				"limit": "limit must be less than or equal to 100",
+				"tags":  "tags must contain at most 2 items",
+			},
+		},
+		{
+			name:        "wrong JSON type",
+			contentType: fiber.MIMEApplicationJSON,
+			body:        `{"name":5}`,
+			wantErrors:  map[string]string{"name": "name must be a string"},
+		},
+		{
+			name:        "malformed JSON",
+			contentType: fiber.MIMEApplicationJSON,
+			body:        `{"name":`,
+			wantErrors:  map[string]string{"body": ""},
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "text/plain",
+			body:        `name=a`,
+			wantErrors:  map[string]string{"body": "Content-Type must be application/json"},
+		},
+	}
+
+	app := newParseApp()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := doParse(t, app, tt.contentType, tt.body)
+
+			if tt.wantErrors == nil {
+				if status != fiber.StatusOK || !resp.Success {
+					t.Fatalf("status = %d, success = %v, errors = %v; want 200 and success", status, resp.Success, resp.Errors)
+				}
+				return
+			}
+
+			if status != fiber.StatusBadRequest || resp.Success {
+				t.Fatalf("status = %d, success = %v; want 400 and no success", status, resp.Success)
+			}
+			if len(resp.Errors) != len(tt.wantErrors) {
+				t.Fatalf("errors = %v, want keys of %v", resp.Errors, tt.wantErrors)
+			}
+			for field, want := range tt.wantErrors {
+				got, ok := resp.Errors[field]
+				if !ok {
+					t.Errorf("missing error for %q in %v", field, resp.Errors)
+					continue
+				}
+				// "" only checks the field is reported (the message carries a parser offset)
+				if want != "" && got != want {
+					t.Errorf("errors[%q] = %q, want %q", field, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFieldErrorsResponseShape(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return FieldErrorsResponse(c, map[string]string{
+			"name": "name is required",
+			"mode": "mode must be one of: full, incremental",
+		})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if body["success"] != false {
+		t.Errorf("success = %v, want false", body["success"])
+	}
+	if body["message"] != "Validation failed" {
+		t.Errorf("message = %v, want %q", body["message"], "Validation failed")
+	}
+	// All messages, sorted, so clients reading only "error" see every problem
+	wantError := "mode must be one of: full, incremental; name is required"
+	if body["error"] != wantError {
+		t.Errorf("error = %v, want %q", body["error"], wantError)
+	}
+	errs, ok := body["errors"].(map[string]interface{})
+	if !ok || len(errs) != 2 || errs["name"] != "name is required" {
+		t.Errorf("errors = %v, want the field map", body["errors"])
+	}
+	if _, ok := body["data"]; ok {
+		t.Errorf("data should be omitted, got %v", body["data"])
+	}
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// WebhookTokenSigner signs the channel token Google echoes back on every Drive push notification
+// Channel token = webhookToken + "." + HMAC-SHA256(webhookToken + "." + channelID)
+// Binding the signature to the channel ID means a leaked token can't be replayed on another channel,
+// and calls forged without the secret are rejected before any database lookup.
+type WebhookTokenSigner struct {
+	secret []byte
+}
+
+// NewWebhookTokenSigner creates a new webhook channel token signer
+func NewWebhookTokenSigner(secret string) *WebhookTokenSigner {
+	return &WebhookTokenSigner{secret: []byte(secret)}
+}
+
+// Sign returns the channel token to register for a webhook channel
+func (s *WebhookTokenSigner) Sign(webhookToken, channelID string) string {
+	return webhookToken + "." + s.sign(webhookToken, channelID)
+}
+
+// Verify checks a channel token received from Google and returns the webhook token it carries.
+// signed is false for tokens without a signature (channels registered before signing was added,
+// replaced on their next renewal); those are returned as-is for the caller to decide.
+func (s *WebhookTokenSigner) Verify(channelToken, channelID string) (webhookToken string, signed bool, err error) {
+	dot := strings.LastIndex(channelToken, ".")
+	if dot < 0 {
+		return channelToken, false, nil
+	}
+
+	webhookToken, sig := channelToken[:dot], channelToken[dot+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.sign(webhookToken, channelID))) {
+		return "", true, ErrInvalidSignature
+	}
+	return webhookToken, true, nil
+}
+
+func (s *WebhookTokenSigner) sign(webhookToken, channelID string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(webhookToken + "." + channelID))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
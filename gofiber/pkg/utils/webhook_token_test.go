@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWebhookTokenSignerRoundTrip(t *testing.T) {
+	signer := NewWebhookTokenSigner("secret")
+
+	channelToken := signer.Sign("webhook-token", "channel-1")
+	if !strings.HasPrefix(channelToken, "webhook-token.") {
+		t.Fatalf("Sign = %q, want the webhook token followed by a signature", channelToken)
+	}
+
+	token, signed, err := signer.Verify(channelToken, "channel-1")
+	if err != nil || !signed || token != "webhook-token" {
+		t.Errorf("Verify = (%q, %v, %v), want (%q, true, nil)", token, signed, err, "webhook-token")
+	}
+}
+
+func TestWebhookTokenSignerRejectsTampering(t *testing.T) {
+	signer := NewWebhookTokenSigner("secret")
+	channelToken := signer.Sign("webhook-token", "channel-1")
+	sig := channelToken[strings.LastIndex(channelToken, ".")+1:]
+
+	tests := []struct {
+		name         string
+		channelToken string
+		channelID    string
+		verifier     *WebhookTokenSigner
+	}{
+		{name: "other channel", channelToken: channelToken, channelID: "channel-2", verifier: signer},
+		{name: "other webhook token", channelToken: "other-token." + sig, channelID: "channel-1", verifier: signer},
+		{name: "altered signature", channelToken: channelToken[:len(channelToken)-1] + "x", channelID: "channel-1", verifier: signer},
+		{name: "empty signature", channelToken: "webhook-token.", channelID: "channel-1", verifier: signer},
+		{name: "other secret", channelToken: channelToken, channelID: "channel-1", verifier: NewWebhookTokenSigner("other")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, signed, err := tt.verifier.Verify(tt.channelToken, tt.channelID)
+			if !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("Verify err = %v, want ErrInvalidSignature", err)
+			}
+			if !signed || token != "" {
+				t.Errorf("Verify = (%q, %v), want (\"\", true)", token, signed)
+			}
+		})
+	}
+}
+
+func TestWebhookTokenSignerUnsignedToken(t *testing.T) {
+	signer := NewWebhookTokenSigner("secret")
+
+	// Legacy channels carry the bare webhook token; the caller decides whether to accept it
+	token, signed, err := signer.Verify("legacy-token", "channel-1")
+	if err != nil || signed || token != "legacy-token" {
+		t.Errorf("Verify = (%q, %v, %v), want (%q, false, nil)", token, signed, err, "legacy-token")
+	}
+}